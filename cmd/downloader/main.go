@@ -83,6 +83,8 @@ func main() {
 
 var (
 	webseeds                       string
+	webseedsOnly                   bool
+	serveAddr                      string
 	datadirCli, chain              string
 	filePath                       string
 	forceRebuild                   bool
@@ -115,6 +117,8 @@ func init() {
 	withChainFlag(rootCmd)
 
 	rootCmd.Flags().StringVar(&webseeds, utils.WebSeedsFlag.Name, utils.WebSeedsFlag.Value, utils.WebSeedsFlag.Usage)
+	rootCmd.Flags().BoolVar(&webseedsOnly, utils.WebSeedsOnlyFlag.Name, utils.WebSeedsOnlyFlag.Value, utils.WebSeedsOnlyFlag.Usage)
+	rootCmd.Flags().StringVar(&serveAddr, utils.SnapServeAddrFlag.Name, utils.SnapServeAddrFlag.Value, utils.SnapServeAddrFlag.Usage)
 	rootCmd.Flags().StringVar(&natSetting, "nat", utils.NATFlag.Value, utils.NATFlag.Usage)
 	rootCmd.Flags().StringVar(&downloaderApiAddr, "downloader.api.addr", "127.0.0.1:9093", "external downloader api network address, for example: 127.0.0.1:9093 serves remote downloader interface")
 	rootCmd.Flags().StringVar(&downloadRateStr, "torrent.download.rate", utils.TorrentDownloadRateFlag.Value, utils.TorrentDownloadRateFlag.Usage)
@@ -251,7 +255,7 @@ func Downloader(ctx context.Context, logger log.Logger) error {
 	version := "erigon: " + params.VersionWithCommit(params.GitCommit)
 
 	webseedsList := common.CliString2Array(webseeds)
-	if known, ok := snapcfg.KnownWebseeds[chain]; ok {
+	if known, ok := snapcfg.KnownWebseeds[chain]; ok && !webseedsOnly {
 		webseedsList = append(webseedsList, known...)
 	}
 	if seedbox {
@@ -273,6 +277,7 @@ func Downloader(ctx context.Context, logger log.Logger) error {
 		downloadercfg.NewCfgOpts{
 			DownloadRateLimit: downloadRate.TorrentRateLimit(),
 			UploadRateLimit:   uploadRate.TorrentRateLimit(),
+			WebseedsOnly:      webseedsOnly,
 		},
 	)
 	if err != nil {
@@ -351,6 +356,14 @@ func Downloader(ctx context.Context, logger log.Logger) error {
 	}
 	defer grpcServer.GracefulStop()
 
+	if serveAddr != "" {
+		go func() {
+			if err := downloader.ServeWebSeed(ctx, serveAddr, dirs.Snap, logger); err != nil {
+				logger.Error("[snapshots] webseed server stopped", "err", err)
+			}
+		}()
+	}
+
 	<-ctx.Done()
 	return nil
 }
@@ -1248,6 +1248,7 @@ func newSync(ctx context.Context, db kv.TemporalRwDB, miningConfig *params.Minin
 
 	statusDataProvider := sentry.NewStatusDataProvider(
 		db,
+		blockReader,
 		chainConfig,
 		genesisBlock,
 		chainConfig.ChainID.Uint64(),
@@ -0,0 +1,463 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/datadir"
+	"github.com/erigontech/erigon-lib/common/dir"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/common/length"
+	"github.com/erigontech/erigon-lib/crypto"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/order"
+	"github.com/erigontech/erigon-lib/kv/rawdbv3"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/types/accounts"
+	"github.com/erigontech/erigon/turbo/debug"
+	"github.com/erigontech/erigon/turbo/services"
+)
+
+// stateDiffBatchBlocks - max amount of blocks exported into one file, it's also the unit of resume
+const stateDiffBatchBlocks = 10_000
+
+const stateDiffCheckpointFile = "checkpoint.json"
+
+var (
+	stateDiffFrom, stateDiffTo uint64
+	stateDiffOut               string
+)
+
+func init() {
+	withDataDir(exportStateDiff)
+	withWorkers(exportStateDiff)
+	exportStateDiff.Flags().Uint64Var(&stateDiffFrom, "from", 0, "first block to export")
+	exportStateDiff.Flags().Uint64Var(&stateDiffTo, "to", 0, "last block to export, 0 means the last executed block")
+	exportStateDiff.Flags().StringVar(&stateDiffOut, "out", "statediff", "directory to write the jsonl files and the checkpoint to")
+
+	rootCmd.AddCommand(exportStateDiff)
+}
+
+var exportStateDiff = &cobra.Command{
+	Use:     "export_state_diff",
+	Short:   "Export per-block account/storage/code diffs of a block range to jsonl files, from the state history",
+	Example: "go run ./cmd/integration export_state_diff --datadir=... --from=1000000 --to=2000000 --out=/data/statediff",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := debug.SetupCobra(cmd, "integration")
+		ctx, _ := common.RootContext()
+		dirs := datadir.New(datadirCli)
+
+		db, err := openDB(dbCfg(kv.ChainDB, dirs.Chaindata), true, logger)
+		if err != nil {
+			logger.Error("Opening DB", "error", err)
+			return
+		}
+		defer db.Close()
+
+		if err := exportStateDiffs(ctx, db, stateDiffFrom, stateDiffTo, stateDiffOut, syncCfg.ExecWorkerCount, logger); err != nil {
+			if !errors.Is(err, context.Canceled) {
+				logger.Error(err.Error())
+			}
+			return
+		}
+	},
+}
+
+// blockStateDiff - one line of the export: the state changed by a block, including system txns.
+// Values before the block are taken from the history, values after it - as of the next block.
+type blockStateDiff struct {
+	Number   uint64             `json:"number"`
+	Hash     common.Hash        `json:"hash"`
+	Accounts []accountStateDiff `json:"accounts,omitempty"`
+	Storage  []storageStateDiff `json:"storage,omitempty"`
+	Code     []codeStateDiff    `json:"code,omitempty"`
+}
+
+type accountStateDiff struct {
+	Address common.Address    `json:"address"`
+	Before  *stateDiffAccount `json:"before"` // nil if the account didn't exist
+	After   *stateDiffAccount `json:"after"`  // nil if the account was deleted
+}
+
+type stateDiffAccount struct {
+	Nonce    hexutil.Uint64 `json:"nonce"`
+	Balance  *hexutil.Big   `json:"balance"`
+	CodeHash common.Hash    `json:"codeHash"`
+}
+
+type storageStateDiff struct {
+	Address common.Address `json:"address"`
+	Slot    common.Hash    `json:"slot"`
+	Before  common.Hash    `json:"before"`
+	After   common.Hash    `json:"after"`
+}
+
+// codeStateDiff - only the hash of the previous code is exported, it was exported in full by the block which set it
+type codeStateDiff struct {
+	Address    common.Address `json:"address"`
+	BeforeHash *common.Hash   `json:"beforeHash"`
+	After      hexutil.Bytes  `json:"after"`
+}
+
+// blockRange - [From, To]
+type blockRange struct {
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+}
+
+// stateDiffCheckpoint - block ranges already exported, kept in the output dir to resume an interrupted export
+type stateDiffCheckpoint struct {
+	Done []blockRange `json:"done"`
+
+	lock sync.Mutex
+	path string
+}
+
+func loadStateDiffCheckpoint(outDir string) (*stateDiffCheckpoint, error) {
+	c := &stateDiffCheckpoint{path: filepath.Join(outDir, stateDiffCheckpointFile)}
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", c.path, err)
+	}
+	return c, nil
+}
+
+// markDone - atomically persists the checkpoint with one more exported range
+func (c *stateDiffCheckpoint) markDone(r blockRange) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Done = append(c.Done, r)
+	sort.Slice(c.Done, func(i, j int) bool { return c.Done[i].From < c.Done[j].From })
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := dir.WriteFileWithFsync(c.path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(c.path+".tmp", c.path)
+}
+
+// remaining - parts of r which are not exported yet
+func (c *stateDiffCheckpoint) remaining(r blockRange) (res []blockRange) {
+	for _, done := range c.Done {
+		if done.To < r.From || done.From > r.To {
+			continue
+		}
+		if done.From > r.From {
+			res = append(res, blockRange{From: r.From, To: done.From - 1})
+		}
+		if done.To >= r.To {
+			return res
+		}
+		r.From = done.To + 1
+	}
+	return append(res, r)
+}
+
+// stateDiffFileBoundaries - first blocks of the state files (except the first one), sorted
+func stateDiffFileBoundaries(tx kv.TemporalTx, txNums rawdbv3.TxNumsReader) ([]uint64, error) {
+	var boundaries []uint64
+	for _, f := range tx.Debug().DomainFiles(kv.AccountsDomain) {
+		blockNum, ok, err := txNums.FindBlockNum(tx, f.EndRootNum())
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		boundaries = append(boundaries, blockNum)
+	}
+	sort.Slice(boundaries, func(i, j int) bool { return boundaries[i] < boundaries[j] })
+	return boundaries, nil
+}
+
+// stateDiffBatches - splits [from, to] into batches which don't cross the state files boundaries, so
+// that every worker reads the history of a single file (or of the DB) at a time
+func stateDiffBatches(from, to uint64, boundaries []uint64) []blockRange {
+	var batches []blockRange
+	for batchFrom := from; batchFrom <= to; {
+		batchTo := min(to, batchFrom+stateDiffBatchBlocks-1)
+		for _, boundary := range boundaries {
+			if boundary > batchFrom && boundary <= batchTo {
+				batchTo = boundary - 1
+				break
+			}
+		}
+		batches = append(batches, blockRange{From: batchFrom, To: batchTo})
+		batchFrom = batchTo + 1
+	}
+	return batches
+}
+
+func exportStateDiffs(ctx context.Context, db kv.TemporalRoDB, from, to uint64, outDir string, workers int, logger log.Logger) error {
+	blockReader, _ := blocksIO(db, logger)
+	txNums := blockReader.TxnumReader(ctx)
+
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return err
+	}
+	// leftovers of the batches interrupted by the previous run
+	tmpFiles, err := filepath.Glob(filepath.Join(outDir, "*.tmp"))
+	if err != nil {
+		return err
+	}
+	for _, f := range tmpFiles {
+		if err := os.Remove(f); err != nil {
+			return err
+		}
+	}
+	checkpoint, err := loadStateDiffCheckpoint(outDir)
+	if err != nil {
+		return err
+	}
+
+	var batches []blockRange
+	if err := db.ViewTemporal(ctx, func(tx kv.TemporalTx) error {
+		lastBlock, _, err := txNums.FindBlockNum(tx, tx.Debug().DomainProgress(kv.AccountsDomain))
+		if err != nil {
+			return err
+		}
+		if to == 0 || to > lastBlock {
+			to = lastBlock
+		}
+		historyStart, _, err := txNums.FindBlockNum(tx, tx.Debug().HistoryStartFrom(kv.AccountsDomain))
+		if err != nil {
+			return err
+		}
+		if from < historyStart {
+			return fmt.Errorf("history is pruned before block %d, can't export from block %d", historyStart, from)
+		}
+		if from > to {
+			return fmt.Errorf("nothing to export: from=%d > to=%d", from, to)
+		}
+		boundaries, err := stateDiffFileBoundaries(tx, txNums)
+		if err != nil {
+			return err
+		}
+		for _, batch := range stateDiffBatches(from, to, boundaries) {
+			batches = append(batches, checkpoint.remaining(batch)...)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	logger.Info("[export_state_diff] start", "from", from, "to", to, "batches", len(batches), "workers", workers)
+
+	var exported atomic.Int64
+	logEvery := time.NewTicker(30 * time.Second)
+	defer logEvery.Stop()
+
+	work := make(chan blockRange)
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		defer close(work)
+		for _, batch := range batches {
+			select {
+			case work <- batch:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+	for i := 0; i < max(workers, 1); i++ {
+		g.Go(func() error {
+			for batch := range work {
+				if err := exportStateDiffBatch(ctx, db, blockReader, txNums, batch, outDir); err != nil {
+					return fmt.Errorf("batch %d-%d: %w", batch.From, batch.To, err)
+				}
+				if err := checkpoint.markDone(batch); err != nil {
+					return err
+				}
+				exported.Add(1)
+				select {
+				case <-logEvery.C:
+					logger.Info("[export_state_diff] progress", "batches", fmt.Sprintf("%d/%d", exported.Load(), len(batches)), "block", batch.To)
+				default:
+				}
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	logger.Info("[export_state_diff] done", "from", from, "to", to, "out", outDir)
+	return nil
+}
+
+// exportStateDiffBatch - writes the diffs of the batch into a .tmp file, renamed once it's complete
+func exportStateDiffBatch(ctx context.Context, db kv.TemporalRoDB, blockReader services.FullBlockReader, txNums rawdbv3.TxNumsReader, batch blockRange, outDir string) error {
+	path := filepath.Join(outDir, fmt.Sprintf("statediff-%09d-%09d.jsonl", batch.From, batch.To))
+	f, err := os.Create(path + ".tmp")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := bufio.NewWriterSize(f, 1024*1024)
+	enc := json.NewEncoder(w)
+
+	if err := db.ViewTemporal(ctx, func(tx kv.TemporalTx) error {
+		for blockNum := batch.From; blockNum <= batch.To; blockNum++ {
+			diff, err := readBlockStateDiff(ctx, tx, blockReader, txNums, blockNum)
+			if err != nil {
+				return fmt.Errorf("block %d: %w", blockNum, err)
+			}
+			if err := enc.Encode(diff); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+func readBlockStateDiff(ctx context.Context, tx kv.TemporalTx, blockReader services.FullBlockReader, txNums rawdbv3.TxNumsReader, blockNum uint64) (*blockStateDiff, error) {
+	hash, ok, err := blockReader.CanonicalHash(ctx, tx, blockNum)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("canonical hash not found")
+	}
+	fromTxNum, err := txNums.Min(tx, blockNum)
+	if err != nil {
+		return nil, err
+	}
+	toTxNum, err := txNums.Max(tx, blockNum)
+	if err != nil {
+		return nil, err
+	}
+	toTxNum++
+
+	diff := &blockStateDiff{Number: blockNum, Hash: hash}
+	err = forEachStateChange(tx, kv.AccountsDomain, fromTxNum, toTxNum, func(k, before, after []byte) error {
+		d := accountStateDiff{Address: common.BytesToAddress(k)}
+		if d.Before, err = decodeStateDiffAccount(before); err != nil {
+			return err
+		}
+		if d.After, err = decodeStateDiffAccount(after); err != nil {
+			return err
+		}
+		diff.Accounts = append(diff.Accounts, d)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = forEachStateChange(tx, kv.StorageDomain, fromTxNum, toTxNum, func(k, before, after []byte) error {
+		diff.Storage = append(diff.Storage, storageStateDiff{
+			Address: common.BytesToAddress(k[:length.Addr]),
+			Slot:    common.BytesToHash(k[length.Addr:]),
+			Before:  common.BytesToHash(before),
+			After:   common.BytesToHash(after),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = forEachStateChange(tx, kv.CodeDomain, fromTxNum, toTxNum, func(k, before, after []byte) error {
+		d := codeStateDiff{Address: common.BytesToAddress(k), After: common.CopyBytes(after)}
+		if len(before) > 0 {
+			beforeHash := crypto.Keccak256Hash(before)
+			d.BeforeHash = &beforeHash
+		}
+		diff.Code = append(diff.Code, d)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+// forEachStateChange - calls f for every key of the domain changed in [fromTxNum, toTxNum) with its values before
+// and after the range. Keys changed back to the value they had before the range are skipped.
+func forEachStateChange(tx kv.TemporalTx, domain kv.Domain, fromTxNum, toTxNum uint64, f func(k, before, after []byte) error) error {
+	it, err := tx.HistoryRange(domain, int(fromTxNum), int(toTxNum), order.Asc, -1)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.HasNext() {
+		k, before, err := it.Next()
+		if err != nil {
+			return err
+		}
+		after, _, err := tx.GetAsOf(domain, k, toTxNum)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(before, after) {
+			continue
+		}
+		if err := f(k, before, after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeStateDiffAccount(v []byte) (*stateDiffAccount, error) {
+	if len(v) == 0 {
+		return nil, nil
+	}
+	var acc accounts.Account
+	if err := accounts.DeserialiseV3(&acc, v); err != nil {
+		return nil, err
+	}
+	return &stateDiffAccount{
+		Nonce:    hexutil.Uint64(acc.Nonce),
+		Balance:  (*hexutil.Big)(acc.Balance.ToBig()),
+		CodeHash: acc.CodeHash,
+	}, nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateDiffBatches(t *testing.T) {
+	// no files: only the batch size limit
+	require.Equal(t, []blockRange{{0, 9_999}, {10_000, 19_999}, {20_000, 25_000}}, stateDiffBatches(0, 25_000, nil))
+	require.Equal(t, []blockRange{{7, 7}}, stateDiffBatches(7, 7, nil))
+	require.Empty(t, stateDiffBatches(8, 7, nil))
+
+	// batches are split at the first block of every file
+	require.Equal(t, []blockRange{
+		{100, 4_999},
+		{5_000, 14_999},
+		{15_000, 15_499},
+		{15_500, 15_600},
+	}, stateDiffBatches(100, 15_600, []uint64{5_000, 15_500, 100_000}))
+
+	// boundary equal to from - doesn't split
+	require.Equal(t, []blockRange{{5_000, 5_100}}, stateDiffBatches(5_000, 5_100, []uint64{5_000}))
+	// boundary equal to to - the last block is a separate batch
+	require.Equal(t, []blockRange{{4_000, 4_999}, {5_000, 5_000}}, stateDiffBatches(4_000, 5_000, []uint64{5_000}))
+
+	// every batch lies within a single file
+	boundaries := []uint64{1_234, 20_000, 20_001, 37_777}
+	batches := stateDiffBatches(0, 50_000, boundaries)
+	require.Equal(t, uint64(0), batches[0].From)
+	require.Equal(t, uint64(50_000), batches[len(batches)-1].To)
+	for i, b := range batches {
+		require.LessOrEqual(t, b.From, b.To)
+		require.Less(t, b.To-b.From, uint64(stateDiffBatchBlocks))
+		if i > 0 {
+			require.Equal(t, batches[i-1].To+1, b.From)
+		}
+		for _, boundary := range boundaries {
+			require.False(t, b.From < boundary && boundary <= b.To, "batch %v crosses %d", b, boundary)
+		}
+	}
+}
+
+func TestStateDiffCheckpoint(t *testing.T) {
+	outDir := t.TempDir()
+	c, err := loadStateDiffCheckpoint(outDir)
+	require.NoError(t, err)
+	require.Equal(t, []blockRange{{0, 99}}, c.remaining(blockRange{0, 99}))
+
+	require.NoError(t, c.markDone(blockRange{50, 59}))
+	require.NoError(t, c.markDone(blockRange{10, 19}))
+	require.Equal(t, []blockRange{{10, 19}, {50, 59}}, c.Done)
+
+	// resume from the persisted checkpoint
+	c, err = loadStateDiffCheckpoint(outDir)
+	require.NoError(t, err)
+	require.Equal(t, []blockRange{{10, 19}, {50, 59}}, c.Done)
+
+	require.Equal(t, []blockRange{{0, 9}, {20, 49}, {60, 99}}, c.remaining(blockRange{0, 99}))
+	require.Equal(t, []blockRange{{20, 49}}, c.remaining(blockRange{15, 55}))
+	require.Equal(t, []blockRange{{0, 9}}, c.remaining(blockRange{0, 19}))
+	require.Empty(t, c.remaining(blockRange{10, 19}))
+	require.Empty(t, c.remaining(blockRange{12, 15}))
+	require.Equal(t, []blockRange{{20, 30}}, c.remaining(blockRange{20, 30}))
+
+	// all done
+	require.NoError(t, c.markDone(blockRange{0, 9}))
+	require.NoError(t, c.markDone(blockRange{20, 49}))
+	require.NoError(t, c.markDone(blockRange{60, 99}))
+	require.Empty(t, c.remaining(blockRange{0, 99}))
+	require.Equal(t, []blockRange{{100, 120}}, c.remaining(blockRange{0, 120}))
+}
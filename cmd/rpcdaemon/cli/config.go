@@ -124,8 +124,10 @@ func RootCommand() (*cobra.Command, *httpcfg.HttpCfg) {
 	rootCmd.PersistentFlags().BoolVar(&cfg.GraphQLEnabled, "graphql", false, "enables graphql endpoint (disabled by default)")
 	rootCmd.PersistentFlags().Uint64Var(&cfg.Gascap, "rpc.gascap", 50_000_000, "Sets a cap on gas that can be used in eth_call/estimateGas")
 	rootCmd.PersistentFlags().Uint64Var(&cfg.MaxTraces, "trace.maxtraces", 200, "Sets a limit on traces that can be returned in trace_filter")
+	rootCmd.PersistentFlags().Uint64Var(&cfg.MaxTraceChainBlocks, "trace.chain.maxblocks", 10_000, "Sets a limit on blocks that can be traced by one debug_subscribe(traceChain), 0 - no limit")
 
 	rootCmd.PersistentFlags().StringVar(&cfg.RpcAllowListFilePath, utils.RpcAccessListFlag.Name, "", "Specify granular (method-by-method) API allowlist")
+	rootCmd.PersistentFlags().StringVar(&cfg.RpcRateLimitFilePath, utils.RpcRateLimitFlag.Name, "", utils.RpcRateLimitFlag.Usage)
 	rootCmd.PersistentFlags().UintVar(&cfg.RpcBatchConcurrency, utils.RpcBatchConcurrencyFlag.Name, 2, utils.RpcBatchConcurrencyFlag.Usage)
 	rootCmd.PersistentFlags().BoolVar(&cfg.RpcStreamingDisable, utils.RpcStreamingDisableFlag.Name, false, utils.RpcStreamingDisableFlag.Usage)
 	rootCmd.PersistentFlags().BoolVar(&cfg.DebugSingleRequest, utils.HTTPDebugSingleFlag.Name, false, utils.HTTPDebugSingleFlag.Usage)
@@ -177,6 +179,8 @@ func RootCommand() (*cobra.Command, *httpcfg.HttpCfg) {
 	rootCmd.PersistentFlags().IntVar(&cfg.RpcFiltersConfig.RpcSubscriptionFiltersMaxTxs, "rpc.subscription.filters.maxtxs", rpchelper.DefaultFiltersConfig.RpcSubscriptionFiltersMaxTxs, "Maximum number of transactions to store per subscription.")
 	rootCmd.PersistentFlags().IntVar(&cfg.RpcFiltersConfig.RpcSubscriptionFiltersMaxAddresses, "rpc.subscription.filters.maxaddresses", rpchelper.DefaultFiltersConfig.RpcSubscriptionFiltersMaxAddresses, "Maximum number of addresses per subscription to filter logs by.")
 	rootCmd.PersistentFlags().IntVar(&cfg.RpcFiltersConfig.RpcSubscriptionFiltersMaxTopics, "rpc.subscription.filters.maxtopics", rpchelper.DefaultFiltersConfig.RpcSubscriptionFiltersMaxTopics, "Maximum number of topics per subscription to filter logs by.")
+	rootCmd.PersistentFlags().StringVar(&cfg.RpcFiltersConfig.RpcFiltersStoreDir, "rpc.filters.store", rpchelper.DefaultFiltersConfig.RpcFiltersStoreDir, "Directory to persist filters installed with eth_new*Filter to, so that they survive restarts. Can be shared by several rpcdaemons. Pending transactions of eth_newPendingTransactionFilter are not persisted: each rpcdaemon returns only the ones it received itself.")
+	rootCmd.PersistentFlags().DurationVar(&cfg.RpcFiltersConfig.RpcFiltersStoreTTL, "rpc.filters.store.ttl", rpchelper.DefaultFiltersConfig.RpcFiltersStoreTTL, "Time after which persisted filters that weren't polled are removed.")
 	rootCmd.PersistentFlags().IntVar(&cfg.BatchLimit, utils.RpcBatchLimit.Name, utils.RpcBatchLimit.Value, utils.RpcBatchLimit.Usage)
 	rootCmd.PersistentFlags().IntVar(&cfg.ReturnDataLimit, utils.RpcReturnDataLimit.Name, utils.RpcReturnDataLimit.Value, utils.RpcReturnDataLimit.Usage)
 	rootCmd.PersistentFlags().BoolVar(&cfg.AllowUnprotectedTxs, utils.AllowUnprotectedTxs.Name, utils.AllowUnprotectedTxs.Value, utils.AllowUnprotectedTxs.Usage)
@@ -686,6 +690,12 @@ func startRegularRpcServer(ctx context.Context, cfg *httpcfg.HttpCfg, rpcAPI []r
 	}
 	srv.SetAllowList(allowListForRPC)
 
+	rateLimiter, err := parseRateLimitForRPC(cfg.RpcRateLimitFilePath)
+	if err != nil {
+		return err
+	}
+	srv.SetRateLimiter(rateLimiter)
+
 	srv.SetBatchLimit(cfg.BatchLimit)
 
 	defer srv.Stop()
@@ -940,8 +950,12 @@ func createHandler(cfg *httpcfg.HttpCfg, apiList []rpc.API, httpHandler http.Han
 			return
 		}
 
-		if jwtSecret != nil && !rpc.CheckJwtSecret(w, r, jwtSecret) {
-			return
+		if jwtSecret != nil {
+			ctx, ok := rpc.CheckJwtSecret(w, r, jwtSecret)
+			if !ok {
+				return
+			}
+			r = r.WithContext(ctx)
 		}
 
 		httpHandler.ServeHTTP(w, r)
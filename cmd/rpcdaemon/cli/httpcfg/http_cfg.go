@@ -61,11 +61,13 @@ type HttpCfg struct {
 	Gascap                            uint64
 	Feecap                            float64
 	MaxTraces                         uint64
+	MaxTraceChainBlocks               uint64
 	WebsocketPort                     int
 	WebsocketEnabled                  bool
 	WebsocketCompression              bool
 	WebsocketSubscribeLogsChannelSize int
 	RpcAllowListFilePath              string
+	RpcRateLimitFilePath              string
 	RpcBatchConcurrency               uint
 	RpcStreamingDisable               bool
 	RpcFiltersConfig                  rpchelper.FiltersConfig
@@ -1,10 +1,14 @@
 package graph
 
 import (
+	"context"
 	"encoding/hex"
 	"fmt"
+	"math/big"
 	"reflect"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/erigontech/erigon-lib/common"
 	hexutil2 "github.com/erigontech/erigon-lib/common/hexutil"
@@ -14,6 +18,9 @@ import (
 	"github.com/erigontech/erigon-lib/common/hexutil"
 
 	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon/cmd/rpcdaemon/graphql/graph/model"
+	"github.com/erigontech/erigon/rpc"
+	"github.com/erigontech/erigon/rpc/ethapi"
 )
 
 func convertDataToStringP(abstractMap map[string]interface{}, field string) *string {
@@ -135,3 +142,342 @@ func convertStrHexToDec(hexString *string) *string {
 
 	return &result
 }
+
+// convertBlock builds the block model from the result of GraphQLAPI.GetBlockDetails. Transactions
+// and logs point back to their block and transaction so nested queries can be answered
+// without going back to the database.
+func convertBlock(res map[string]interface{}) *model.Block {
+	absBlk := res["block"]
+	if absBlk == nil {
+		return nil
+	}
+	blk := absBlk.(map[string]interface{})
+
+	block := convertHeader(blk)
+	block.TransactionCount = convertDataToIntP(blk, "transactionCount")
+	block.RawHeader = *convertDataToStringP(blk, "rawHeader")
+	block.Raw = *convertDataToStringP(blk, "raw")
+	if _, ok := blk["nextBaseFeePerGas"]; ok {
+		block.NextBaseFeePerGas = convertDataToStringP(blk, "nextBaseFeePerGas")
+	}
+
+	// Ommers
+	block.Ommers = []*model.Block{}
+	for _, ommer := range blk["ommers"].([]map[string]interface{}) {
+		block.Ommers = append(block.Ommers, convertHeader(ommer))
+	}
+	ommerCount := len(block.Ommers)
+	block.OmmerCount = &ommerCount
+
+	// Transactions
+	block.Transactions = []*model.Transaction{}
+	for _, transReceipt := range res["receipts"].([]map[string]interface{}) {
+		trans := convertTransaction(transReceipt["transaction"].(*ethapi.RPCTransaction))
+		trans.Block = block
+		trans.CumulativeGasUsed = convertDataToUint64P(transReceipt, "cumulativeGasUsed")
+		trans.EffectiveGasPrice = convertDataToStringP(transReceipt, "effectiveGasPrice")
+		trans.GasUsed = convertDataToUint64P(transReceipt, "gasUsed")
+		trans.Status = convertDataToUint64P(transReceipt, "status")
+		trans.Raw = *convertDataToStringP(transReceipt, "raw")
+		trans.RawReceipt = *convertDataToStringP(transReceipt, "rawReceipt")
+		if block.BaseFeePerGas != nil && trans.EffectiveGasPrice != nil {
+			tip := new(big.Int).Sub(hexutil.MustDecodeBig(*trans.EffectiveGasPrice), hexutil.MustDecodeBig(*block.BaseFeePerGas))
+			trans.EffectiveTip = convertBigP(tip)
+		}
+		if contract, ok := transReceipt["contractAddress"].(common.Address); ok {
+			trans.CreatedContract = &model.Account{Address: strings.ToLower(contract.String())}
+		}
+
+		trans.Logs = make([]*model.Log, 0)
+		for _, rlog := range transReceipt["logs"].(types.Logs) {
+			tlog := convertLog(rlog)
+			tlog.Transaction = trans
+			trans.Logs = append(trans.Logs, tlog)
+		}
+
+		block.Transactions = append(block.Transactions, trans)
+	}
+
+	// Withdrawals
+	block.Withdrawals = []*model.Withdrawal{}
+	for _, withdrawal := range res["withdrawals"].([]map[string]interface{}) {
+		wthd := &model.Withdrawal{}
+		wthd.Index = *convertDataToIntP(withdrawal, "index")
+		wthd.Validator = *convertDataToIntP(withdrawal, "validator")
+		wthd.Address = *convertDataToStringP(withdrawal, "address")
+		wthd.Amount = *convertDataToStringP(withdrawal, "amount")
+
+		block.Withdrawals = append(block.Withdrawals, wthd)
+	}
+
+	return block
+}
+
+// convertHeader fills the header fields of a block model, it's used for both blocks and ommers.
+func convertHeader(blk map[string]interface{}) *model.Block {
+	block := &model.Block{}
+	block.Difficulty = *convertDataToStringP(blk, "difficulty")
+	block.ExtraData = *convertDataToStringP(blk, "extraData")
+	block.GasLimit = *convertDataToUint64P(blk, "gasLimit")
+	block.GasUsed = *convertDataToUint64P(blk, "gasUsed")
+	block.Hash = *convertDataToStringP(blk, "hash")
+	block.Miner = &model.Account{}
+	if address := convertDataToStringP(blk, "miner"); address != nil {
+		block.Miner.Address = strings.ToLower(*address)
+	}
+	if mixHash := convertDataToStringP(blk, "mixHash"); mixHash != nil {
+		block.MixHash = *mixHash
+	}
+	if blockNonce := convertDataToStringP(blk, "nonce"); blockNonce != nil {
+		block.Nonce = *blockNonce
+	}
+	block.Number = *convertDataToUint64P(blk, "number")
+	block.Parent = &model.Block{}
+	block.Parent.Hash = *convertDataToStringP(blk, "parentHash")
+	block.ReceiptsRoot = *convertDataToStringP(blk, "receiptsRoot")
+	block.StateRoot = *convertDataToStringP(blk, "stateRoot")
+	block.Timestamp = *convertDataToStringP(blk, "timestamp")
+	block.TransactionsRoot = *convertDataToStringP(blk, "transactionsRoot")
+	if _, ok := blk["baseFeePerGas"]; ok {
+		block.BaseFeePerGas = convertDataToStringP(blk, "baseFeePerGas")
+	}
+	block.LogsBloom = "0x" + *convertDataToStringP(blk, "logsBloom")
+	block.OmmerHash = *convertDataToStringP(blk, "sha3Uncles")
+	return block
+}
+
+// convertTransaction fills the fields of a transaction model which don't depend on its receipt.
+func convertTransaction(txn *ethapi.RPCTransaction) *model.Transaction {
+	trans := &model.Transaction{
+		Hash:      txn.Hash.String(),
+		Nonce:     txn.Nonce.String(),
+		From:      &model.Account{Address: strings.ToLower(txn.From.String())},
+		Value:     txn.Value.String(),
+		Gas:       uint64(txn.Gas),
+		InputData: txn.Input.String(),
+		V:         txn.V.String(),
+		R:         txn.R.String(),
+		S:         txn.S.String(),
+	}
+	if txn.TransactionIndex != nil {
+		index := int(*txn.TransactionIndex)
+		trans.Index = &index
+	}
+	// To address could be nil in case of contract creation
+	if txn.To != nil {
+		trans.To = &model.Account{Address: strings.ToLower(txn.To.String())}
+	}
+	if txn.GasPrice != nil {
+		trans.GasPrice = txn.GasPrice.String()
+	}
+	if txn.MaxFeePerGas != nil {
+		trans.MaxFeePerGas = convertBigP(txn.MaxFeePerGas.ToInt())
+	}
+	if txn.MaxPriorityFeePerGas != nil {
+		trans.MaxPriorityFeePerGas = convertBigP(txn.MaxPriorityFeePerGas.ToInt())
+	}
+	txType := int(txn.Type)
+	trans.Type = &txType
+	if txn.Accesses != nil {
+		trans.AccessList = make([]*model.AccessTuple, 0, len(*txn.Accesses))
+		for _, tuple := range *txn.Accesses {
+			accessTuple := &model.AccessTuple{Address: strings.ToLower(tuple.Address.String()), StorageKeys: []string{}}
+			for _, key := range tuple.StorageKeys {
+				accessTuple.StorageKeys = append(accessTuple.StorageKeys, key.String())
+			}
+			trans.AccessList = append(trans.AccessList, accessTuple)
+		}
+	}
+	return trans
+}
+
+func convertLog(rlog *types.Log) *model.Log {
+	tlog := &model.Log{
+		Index:   int(rlog.Index),
+		Data:    "0x" + hex.EncodeToString(rlog.Data),
+		Account: &model.Account{Address: strings.ToLower(rlog.Address.String())},
+		Topics:  []string{},
+	}
+	for _, rtopic := range rlog.Topics {
+		tlog.Topics = append(tlog.Topics, rtopic.String())
+	}
+	return tlog
+}
+
+func convertBigP(v *big.Int) *string {
+	result := (*hexutil.Big)(v).String()
+	return &result
+}
+
+// convertCallData converts the GraphQL call arguments to the ones eth_call accepts.
+func convertCallData(data model.CallData) (ethapi.CallArgs, error) {
+	var args ethapi.CallArgs
+	var err error
+	if data.From != nil {
+		from := common.HexToAddress(*data.From)
+		args.From = &from
+	}
+	if data.To != nil {
+		to := common.HexToAddress(*data.To)
+		args.To = &to
+	}
+	if data.Gas != nil {
+		args.Gas = (*hexutil.Uint64)(data.Gas)
+	}
+	if args.GasPrice, err = decodeBigP(data.GasPrice); err != nil {
+		return args, err
+	}
+	if args.MaxFeePerGas, err = decodeBigP(data.MaxFeePerGas); err != nil {
+		return args, err
+	}
+	if args.MaxPriorityFeePerGas, err = decodeBigP(data.MaxPriorityFeePerGas); err != nil {
+		return args, err
+	}
+	if args.Value, err = decodeBigP(data.Value); err != nil {
+		return args, err
+	}
+	if data.Data != nil {
+		input, err := hexutil.Decode(*data.Data)
+		if err != nil {
+			return args, err
+		}
+		args.Data = (*hexutil.Bytes)(&input)
+	}
+	return args, nil
+}
+
+// decodeBigP accepts BigInt scalars either as 0x prefixed hex or as decimal strings.
+func decodeBigP(v *string) (*hexutil.Big, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if strings.HasPrefix(*v, "0x") {
+		b, err := hexutil.DecodeBig(*v)
+		return (*hexutil.Big)(b), err
+	}
+	b, ok := new(big.Int).SetString(*v, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid BigInt %q", *v)
+	}
+	return (*hexutil.Big)(b), nil
+}
+
+// decodeBlockNumber accepts BlockNum scalars either as decimal or as 0x prefixed hex strings.
+func decodeBlockNumber(number string) (uint64, error) {
+	// Block number is not null, test for a positive long integer
+	if bNum, err := strconv.ParseUint(number, 10, 64); err == nil {
+		return bNum, nil
+	}
+	// Hexadecimal, 0x prefixed
+	return hexutil.DecodeUint64(number)
+}
+
+// accountAt returns the account at the given block number, falling back to the given block.
+func accountAt(address string, block *uint64, fallback *rpc.BlockNumberOrHash) *model.Account {
+	account := &model.Account{Address: strings.ToLower(address), Block: fallback}
+	if block != nil {
+		blockRef := rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(*block))
+		account.Block = &blockRef
+	}
+	return account
+}
+
+func transactionBlock(trans *model.Transaction) *rpc.BlockNumberOrHash {
+	if trans == nil || trans.Block == nil {
+		return nil
+	}
+	return blockRef(trans.Block)
+}
+
+func blockRef(block *model.Block) *rpc.BlockNumberOrHash {
+	ref := rpc.BlockNumberOrHashWithHash(common.HexToHash(block.Hash), false)
+	return &ref
+}
+
+func filterLogs(transactions []*model.Transaction, addresses []string, topics [][]string) []*model.Log {
+	logs := []*model.Log{}
+	for _, trans := range transactions {
+		for _, tlog := range trans.Logs {
+			if logMatches(tlog, addresses, topics) {
+				logs = append(logs, tlog)
+			}
+		}
+	}
+	return logs
+}
+
+func logMatches(tlog *model.Log, addresses []string, topics [][]string) bool {
+	if len(addresses) > 0 && !slices.ContainsFunc(addresses, func(address string) bool {
+		return strings.EqualFold(address, tlog.Account.Address)
+	}) {
+		return false
+	}
+	if len(topics) > len(tlog.Topics) {
+		return false
+	}
+	for i, alternatives := range topics {
+		if len(alternatives) > 0 && !slices.ContainsFunc(alternatives, func(topic string) bool {
+			return strings.EqualFold(topic, tlog.Topics[i])
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Resolver) blockByHash(ctx context.Context, hash string) (*model.Block, error) {
+	res, err := r.GraphQLAPI.GetBlockDetailsByHash(ctx, common.HexToHash(hash))
+	if err != nil || res == nil {
+		return nil, err
+	}
+	return convertBlock(res), ctx.Err()
+}
+
+func (r *Resolver) call(ctx context.Context, data model.CallData, blockNrOrHash rpc.BlockNumberOrHash) (*model.CallResult, error) {
+	args, err := convertCallData(data)
+	if err != nil {
+		return nil, err
+	}
+	result, err := r.GraphQLAPI.Call(ctx, args, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	status := uint64(types.ReceiptStatusSuccessful)
+	if result.Failed() {
+		status = types.ReceiptStatusFailed
+	}
+	return &model.CallResult{
+		Data:    hexutil.Bytes(result.ReturnData).String(),
+		GasUsed: result.GasUsed,
+		Status:  status,
+	}, nil
+}
+
+func (r *Resolver) estimateGas(ctx context.Context, data model.CallData, blockNrOrHash rpc.BlockNumberOrHash) (uint64, error) {
+	args, err := convertCallData(data)
+	if err != nil {
+		return 0, err
+	}
+	gas, err := r.GraphQLAPI.EstimateGas(ctx, args, blockNrOrHash)
+	return uint64(gas), err
+}
+
+// accountBlock returns the block the account state is read at.
+func accountBlock(account *model.Account) rpc.BlockNumberOrHash {
+	if account.Block == nil {
+		return rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	}
+	return *account.Block
+}
+
+// findLog returns the log model of the given block matching the log returned by eth_getLogs.
+func findLog(block *model.Block, rlog *types.Log) *model.Log {
+	if block != nil && int(rlog.TxIndex) < len(block.Transactions) {
+		for _, tlog := range block.Transactions[rlog.TxIndex].Logs {
+			if tlog.Index == int(rlog.Index) {
+				return tlog
+			}
+		}
+	}
+	return convertLog(rlog)
+}
@@ -0,0 +1,13 @@
+package model
+
+import (
+	"github.com/erigontech/erigon/rpc"
+)
+
+// Account is an account as of a given block. Its state fields are resolved on
+// demand, so a query only reads the parts of the state it asks for.
+type Account struct {
+	Address string `json:"address"`
+	// Block is the block the state is read at, nil means the latest block.
+	Block *rpc.BlockNumberOrHash `json:"-"`
+}
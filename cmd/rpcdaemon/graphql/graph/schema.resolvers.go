@@ -6,180 +6,162 @@ package graph
 
 import (
 	"context"
-	"encoding/hex"
-	"fmt"
+	"errors"
+	"math/big"
 	"strconv"
-	"strings"
 
 	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/hexutil"
-	"github.com/erigontech/erigon-lib/types"
 	"github.com/erigontech/erigon/cmd/rpcdaemon/graphql/graph/model"
+	"github.com/erigontech/erigon/eth/filters"
 	"github.com/erigontech/erigon/rpc"
 )
 
-// SendRawTransaction is the resolver for the sendRawTransaction field.
-func (r *mutationResolver) SendRawTransaction(ctx context.Context, data string) (string, error) {
-	panic("not implemented: SendRawTransaction - sendRawTransaction")
+// Balance is the resolver for the balance field.
+func (r *accountResolver) Balance(ctx context.Context, obj *model.Account) (string, error) {
+	balance, err := r.GraphQLAPI.GetBalance(ctx, common.HexToAddress(obj.Address), accountBlock(obj))
+	if err != nil {
+		return "", err
+	}
+	return balance.String(), nil
 }
 
-// Block is the resolver for the block field.
-func (r *queryResolver) Block(ctx context.Context, number *string, hash *string) (*model.Block, error) {
-	var blockNumber rpc.BlockNumber
+// TransactionCount is the resolver for the transactionCount field.
+func (r *accountResolver) TransactionCount(ctx context.Context, obj *model.Account) (uint64, error) {
+	nonce, err := r.GraphQLAPI.GetTransactionCount(ctx, common.HexToAddress(obj.Address), accountBlock(obj))
+	if err != nil {
+		return 0, err
+	}
+	return uint64(*nonce), nil
+}
 
-	if number != nil {
-		// Block number is not null, test for a positive long integer
-		bNum, err := strconv.ParseUint(*number, 10, 64)
-		if err == nil {
-			// Positive integer, go ahead
-			blockNumber = rpc.BlockNumber(bNum)
-		} else {
-			bNum, err := hexutil.DecodeUint64(*number)
-			if err == nil {
-				// Hexadecimal, 0x prefixed
-				blockNumber = rpc.BlockNumber(bNum)
-			} else {
-				var err error
-				return nil, err
-			}
-		}
-	} else {
-		if hash != nil {
-			blockHash, _ := hexutil.DecodeBig(*hash)
-			fmt.Println("TODO/GraphQL/Implement me, get Block by hash=", blockHash)
-			hash = nil
-		}
+// Code is the resolver for the code field.
+func (r *accountResolver) Code(ctx context.Context, obj *model.Account) (string, error) {
+	code, err := r.GraphQLAPI.GetCode(ctx, common.HexToAddress(obj.Address), accountBlock(obj))
+	if err != nil {
+		return "", err
 	}
+	return code.String(), nil
+}
+
+// Storage is the resolver for the storage field.
+func (r *accountResolver) Storage(ctx context.Context, obj *model.Account, slot string) (string, error) {
+	return r.GraphQLAPI.GetStorageAt(ctx, common.HexToAddress(obj.Address), slot, accountBlock(obj))
+}
 
-	if number == nil && hash == nil {
-		// If neither number or hash is specified (nil), we should deliver "latest" block
-		/*
-			rpc.LatestExecutedBlockNumber = BlockNumber(-5)
-			rpc.FinalizedBlockNumber      = BlockNumber(-4)
-			rpc.SafeBlockNumber           = BlockNumber(-3)
-			rpc.PendingBlockNumber        = BlockNumber(-2)
-			rpc.LatestBlockNumber         = BlockNumber(-1)
-		*/
-		blockNumber = rpc.LatestBlockNumber
+// Parent is the resolver for the parent field.
+func (r *blockResolver) Parent(ctx context.Context, obj *model.Block) (*model.Block, error) {
+	if obj.Number == 0 || obj.Parent == nil {
+		return nil, nil
 	}
+	return r.blockByHash(ctx, obj.Parent.Hash)
+}
 
-	res, err := r.GraphQLAPI.GetBlockDetails(ctx, blockNumber)
-	if err != nil {
-		return nil, err
+// Miner is the resolver for the miner field.
+func (r *blockResolver) Miner(ctx context.Context, obj *model.Block, block *uint64) (*model.Account, error) {
+	return accountAt(obj.Miner.Address, block, blockRef(obj)), nil
+}
+
+// OmmerAt is the resolver for the ommerAt field.
+func (r *blockResolver) OmmerAt(ctx context.Context, obj *model.Block, index int) (*model.Block, error) {
+	if index < 0 || index >= len(obj.Ommers) {
+		return nil, nil
 	}
+	return obj.Ommers[index], nil
+}
 
-	block := &model.Block{}
-	absBlk := res["block"]
+// TransactionAt is the resolver for the transactionAt field.
+func (r *blockResolver) TransactionAt(ctx context.Context, obj *model.Block, index int) (*model.Transaction, error) {
+	// Negative indexes count from the end of the block
+	if index < 0 {
+		index += len(obj.Transactions)
+	}
+	if index < 0 || index >= len(obj.Transactions) {
+		return nil, nil
+	}
+	return obj.Transactions[index], nil
+}
 
-	if absBlk != nil {
-		blk := absBlk.(map[string]interface{})
+// Logs is the resolver for the logs field.
+func (r *blockResolver) Logs(ctx context.Context, obj *model.Block, filter model.BlockFilterCriteria) ([]*model.Log, error) {
+	return filterLogs(obj.Transactions, filter.Addresses, filter.Topics), nil
+}
 
-		block.Difficulty = *convertDataToStringP(blk, "difficulty")
-		block.ExtraData = *convertDataToStringP(blk, "extraData")
-		block.GasLimit = uint64(*convertDataToUint64P(blk, "gasLimit"))
-		block.GasUsed = *convertDataToUint64P(blk, "gasUsed")
-		block.Hash = *convertDataToStringP(blk, "hash")
-		block.Miner = &model.Account{}
-		address := convertDataToStringP(blk, "miner")
-		if address != nil {
-			block.Miner.Address = strings.ToLower(*address)
-		}
-		mixHash := convertDataToStringP(blk, "mixHash")
-		if mixHash != nil {
-			block.MixHash = *mixHash
-		}
-		blockNonce := convertDataToStringP(blk, "nonce")
-		if blockNonce != nil {
-			block.Nonce = *blockNonce
-		}
-		block.Number = *convertDataToUint64P(blk, "number")
-		block.Parent = &model.Block{}
-		block.Parent.Hash = *convertDataToStringP(blk, "parentHash")
-		block.ReceiptsRoot = *convertDataToStringP(blk, "receiptsRoot")
-		block.StateRoot = *convertDataToStringP(blk, "stateRoot")
-		block.Timestamp = *convertDataToStringP(blk, "timestamp")
-		block.TransactionCount = convertDataToIntP(blk, "transactionCount")
-		block.TransactionsRoot = *convertDataToStringP(blk, "transactionsRoot")
-		block.BaseFeePerGas = convertDataToStringP(blk, "baseFeePerGas")
-		block.Transactions = []*model.Transaction{}
-
-		block.LogsBloom = "0x" + *convertDataToStringP(blk, "logsBloom")
-		block.OmmerHash = *convertDataToStringP(blk, "sha3Uncles")
-
-		// Ommers
-		block.Ommers = []*model.Block{}
-		for _, ommerHash := range blk["uncles"].([]common.Hash) {
-			block.Ommers = append(block.Ommers, &model.Block{Hash: ommerHash.String()})
-		}
+// Account is the resolver for the account field.
+func (r *blockResolver) Account(ctx context.Context, obj *model.Block, address string) (*model.Account, error) {
+	return accountAt(address, nil, blockRef(obj)), nil
+}
 
-		ommerCount := len(block.Ommers)
-		block.OmmerCount = &ommerCount
-
-		// Transactions
-		absRcp := res["receipts"]
-		rcp := absRcp.([]map[string]interface{})
-		for _, transReceipt := range rcp {
-			trans := &model.Transaction{}
-			trans.CumulativeGasUsed = convertDataToUint64P(transReceipt, "cumulativeGasUsed")
-			trans.InputData = *convertDataToStringP(transReceipt, "data")
-			trans.EffectiveGasPrice = convertDataToStringP(transReceipt, "effectiveGasPrice")
-			trans.GasPrice = *convertDataToStringP(transReceipt, "effectiveGasPrice")
-			trans.GasUsed = convertDataToUint64P(transReceipt, "gasUsed")
-			trans.Hash = *convertDataToStringP(transReceipt, "transactionHash")
-			trans.Index = convertDataToIntP(transReceipt, "transactionIndex")
-			transNonce := convertDataToStringP(transReceipt, "nonce")
-			if transNonce != nil {
-				trans.Nonce = *transNonce
-			}
-			trans.Status = convertDataToUint64P(transReceipt, "status")
-			trans.Type = convertDataToIntP(transReceipt, "type")
-			trans.Value = *convertDataToStringP(transReceipt, "value")
-
-			trans.Logs = make([]*model.Log, 0)
-			for _, rlog := range transReceipt["logs"].(types.Logs) {
-				tlog := model.Log{
-					Index: int(rlog.Index),
-					Data:  "0x" + hex.EncodeToString(rlog.Data),
-				}
-				tlog.Account = &model.Account{}
-				tlog.Account.Address = strings.ToLower(rlog.Address.String())
-
-				for _, rtopic := range rlog.Topics {
-					tlog.Topics = append(tlog.Topics, rtopic.String())
-				}
-
-				trans.Logs = append(trans.Logs, &tlog)
-			}
+// Call is the resolver for the call field.
+func (r *blockResolver) Call(ctx context.Context, obj *model.Block, data model.CallData) (*model.CallResult, error) {
+	return r.call(ctx, data, *blockRef(obj))
+}
 
-			trans.From = &model.Account{}
-			trans.From.Address = strings.ToLower(*convertDataToStringP(transReceipt, "from"))
+// EstimateGas is the resolver for the estimateGas field.
+func (r *blockResolver) EstimateGas(ctx context.Context, obj *model.Block, data model.CallData) (uint64, error) {
+	return r.estimateGas(ctx, data, *blockRef(obj))
+}
 
-			trans.To = &model.Account{}
-			address := convertDataToStringP(transReceipt, "to")
-			// To address could be nil in case of contract creation
-			if address != nil {
-				trans.To.Address = strings.ToLower(*convertDataToStringP(transReceipt, "to"))
-			}
+// Account is the resolver for the account field.
+func (r *logResolver) Account(ctx context.Context, obj *model.Log, block *uint64) (*model.Account, error) {
+	return accountAt(obj.Account.Address, block, transactionBlock(obj.Transaction)), nil
+}
 
-			block.Transactions = append(block.Transactions, trans)
-		}
+// SendRawTransaction is the resolver for the sendRawTransaction field.
+func (r *mutationResolver) SendRawTransaction(ctx context.Context, data string) (string, error) {
+	encodedTx, err := hexutil.Decode(data)
+	if err != nil {
+		return "", err
+	}
+	hash, err := r.GraphQLAPI.SendRawTransaction(ctx, encodedTx)
+	if err != nil {
+		return "", err
+	}
+	return hash.String(), nil
+}
+
+// Account is the resolver for the account field.
+func (r *pendingResolver) Account(ctx context.Context, obj *model.Pending, address string) (*model.Account, error) {
+	pending := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
+	return accountAt(address, nil, &pending), nil
+}
+
+// Call is the resolver for the call field.
+func (r *pendingResolver) Call(ctx context.Context, obj *model.Pending, data model.CallData) (*model.CallResult, error) {
+	return r.call(ctx, data, rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber))
+}
+
+// EstimateGas is the resolver for the estimateGas field.
+func (r *pendingResolver) EstimateGas(ctx context.Context, obj *model.Pending, data model.CallData) (uint64, error) {
+	return r.estimateGas(ctx, data, rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber))
+}
+
+// Block is the resolver for the block field.
+func (r *queryResolver) Block(ctx context.Context, number *string, hash *string) (*model.Block, error) {
+	if number != nil && hash != nil {
+		return nil, errors.New("only one of number or hash must be specified")
+	}
 
-		// Withdrawals
-		block.Withdrawals = []*model.Withdrawal{}
-		absWthd := res["withdrawals"]
-		wthd := absWthd.([]map[string]interface{})
-		for _, withdrawal := range wthd {
-			wthd := &model.Withdrawal{}
-			wthd.Index = *convertDataToIntP(withdrawal, "index")
-			wthd.Validator = *convertDataToIntP(withdrawal, "validator")
-			wthd.Address = *convertDataToStringP(withdrawal, "address")
-			wthd.Amount = *convertDataToStringP(withdrawal, "amount")
-
-			block.Withdrawals = append(block.Withdrawals, wthd)
+	if hash != nil {
+		return r.blockByHash(ctx, *hash)
+	}
+
+	// If neither number or hash is specified (nil), we should deliver "latest" block
+	blockNumber := rpc.LatestBlockNumber
+	if number != nil {
+		bNum, err := decodeBlockNumber(*number)
+		if err != nil {
+			return nil, err
 		}
+		blockNumber = rpc.BlockNumber(bNum)
+	}
+
+	res, err := r.GraphQLAPI.GetBlockDetails(ctx, blockNumber)
+	if err != nil {
+		return nil, err
 	}
 
-	return block, ctx.Err()
+	return convertBlock(res), ctx.Err()
 }
 
 // Blocks is the resolver for the blocks field.
@@ -207,32 +189,118 @@ func (r *queryResolver) Blocks(ctx context.Context, from *uint64, to *uint64) ([
 
 // Pending is the resolver for the pending field.
 func (r *queryResolver) Pending(ctx context.Context) (*model.Pending, error) {
-	panic("not implemented: Pending - pending")
+	txns, err := r.GraphQLAPI.GetPendingTransactions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := &model.Pending{Transactions: make([]*model.Transaction, 0, len(txns))}
+	for _, txn := range txns {
+		pending.Transactions = append(pending.Transactions, convertTransaction(txn))
+	}
+	pending.TransactionCount = len(pending.Transactions)
+
+	return pending, nil
 }
 
 // Transaction is the resolver for the transaction field.
 func (r *queryResolver) Transaction(ctx context.Context, hash string) (*model.Transaction, error) {
-	panic("not implemented: Transaction - transaction")
+	txn, err := r.GraphQLAPI.GetTransactionByHash(ctx, common.HexToHash(hash))
+	if err != nil || txn == nil {
+		return nil, err
+	}
+
+	// Transactions still in the pool have no block and no receipt
+	if txn.BlockHash == nil || txn.TransactionIndex == nil {
+		return convertTransaction(txn), nil
+	}
+
+	block, err := r.blockByHash(ctx, txn.BlockHash.String())
+	if err != nil || block == nil {
+		return nil, err
+	}
+	index := int(*txn.TransactionIndex)
+	if index >= len(block.Transactions) {
+		return nil, nil
+	}
+	return block.Transactions[index], nil
 }
 
 // Logs is the resolver for the logs field.
 func (r *queryResolver) Logs(ctx context.Context, filter model.FilterCriteria) ([]*model.Log, error) {
-	panic("not implemented: Logs - logs")
+	crit := filters.FilterCriteria{}
+	if filter.FromBlock != nil {
+		crit.FromBlock = new(big.Int).SetUint64(*filter.FromBlock)
+	}
+	if filter.ToBlock != nil {
+		crit.ToBlock = new(big.Int).SetUint64(*filter.ToBlock)
+	}
+	for _, address := range filter.Addresses {
+		crit.Addresses = append(crit.Addresses, common.HexToAddress(address))
+	}
+	for _, alternatives := range filter.Topics {
+		topics := make([]common.Hash, 0, len(alternatives))
+		for _, topic := range alternatives {
+			topics = append(topics, common.HexToHash(topic))
+		}
+		crit.Topics = append(crit.Topics, topics)
+	}
+
+	rlogs, err := r.GraphQLAPI.GetLogs(ctx, crit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Logs are served from their blocks, so that the transaction of each log is fully populated
+	blocks := map[common.Hash]*model.Block{}
+	logs := make([]*model.Log, 0, len(rlogs))
+	for _, rlog := range rlogs {
+		block, ok := blocks[rlog.BlockHash]
+		if !ok {
+			if block, err = r.blockByHash(ctx, rlog.BlockHash.String()); err != nil {
+				return nil, err
+			}
+			blocks[rlog.BlockHash] = block
+		}
+		logs = append(logs, findLog(block, rlog))
+	}
+
+	return logs, ctx.Err()
 }
 
 // GasPrice is the resolver for the gasPrice field.
 func (r *queryResolver) GasPrice(ctx context.Context) (string, error) {
-	panic("not implemented: GasPrice - gasPrice")
+	price, err := r.GraphQLAPI.GasPrice(ctx)
+	if err != nil {
+		return "", err
+	}
+	return price.String(), nil
 }
 
 // MaxPriorityFeePerGas is the resolver for the maxPriorityFeePerGas field.
 func (r *queryResolver) MaxPriorityFeePerGas(ctx context.Context) (string, error) {
-	panic("not implemented: MaxPriorityFeePerGas - maxPriorityFeePerGas")
+	tipcap, err := r.GraphQLAPI.MaxPriorityFeePerGas(ctx)
+	if err != nil {
+		return "", err
+	}
+	return tipcap.String(), nil
 }
 
 // Syncing is the resolver for the syncing field.
 func (r *queryResolver) Syncing(ctx context.Context) (*model.SyncState, error) {
-	panic("not implemented: Syncing - syncing")
+	res, err := r.GraphQLAPI.Syncing(ctx)
+	if err != nil {
+		return nil, err
+	}
+	progress, ok := res.(map[string]interface{})
+	if !ok {
+		// Not syncing
+		return nil, nil
+	}
+	return &model.SyncState{
+		CurrentBlock: *convertDataToUint64P(progress, "currentBlock"),
+		HighestBlock: *convertDataToUint64P(progress, "highestBlock"),
+	}, nil
 }
 
 // ChainID is the resolver for the chainID field.
@@ -242,11 +310,52 @@ func (r *queryResolver) ChainID(ctx context.Context) (string, error) {
 	return "0x" + strconv.FormatUint(chainID.Uint64(), 16), err
 }
 
+// From is the resolver for the from field.
+func (r *transactionResolver) From(ctx context.Context, obj *model.Transaction, block *uint64) (*model.Account, error) {
+	return accountAt(obj.From.Address, block, transactionBlock(obj)), nil
+}
+
+// To is the resolver for the to field.
+func (r *transactionResolver) To(ctx context.Context, obj *model.Transaction, block *uint64) (*model.Account, error) {
+	if obj.To == nil {
+		return nil, nil
+	}
+	return accountAt(obj.To.Address, block, transactionBlock(obj)), nil
+}
+
+// CreatedContract is the resolver for the createdContract field.
+func (r *transactionResolver) CreatedContract(ctx context.Context, obj *model.Transaction, block *uint64) (*model.Account, error) {
+	if obj.CreatedContract == nil {
+		return nil, nil
+	}
+	return accountAt(obj.CreatedContract.Address, block, transactionBlock(obj)), nil
+}
+
+// Account returns AccountResolver implementation.
+func (r *Resolver) Account() AccountResolver { return &accountResolver{r} }
+
+// Block returns BlockResolver implementation.
+func (r *Resolver) Block() BlockResolver { return &blockResolver{r} }
+
+// Log returns LogResolver implementation.
+func (r *Resolver) Log() LogResolver { return &logResolver{r} }
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
+// Pending returns PendingResolver implementation.
+func (r *Resolver) Pending() PendingResolver { return &pendingResolver{r} }
+
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// Transaction returns TransactionResolver implementation.
+func (r *Resolver) Transaction() TransactionResolver { return &transactionResolver{r} }
+
+type accountResolver struct{ *Resolver }
+type blockResolver struct{ *Resolver }
+type logResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
+type pendingResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type transactionResolver struct{ *Resolver }
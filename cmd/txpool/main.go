@@ -74,7 +74,10 @@ var (
 
 	mdbxWriteMap bool
 
-	commitEvery time.Duration
+	commitEvery      time.Duration
+	localsLifetime   time.Duration
+	localSlots       int
+	rebroadcastEvery time.Duration
 )
 
 func init() {
@@ -100,6 +103,9 @@ func init() {
 	rootCmd.PersistentFlags().Uint64Var(&priceBump, "txpool.pricebump", txpoolcfg.DefaultConfig.PriceBump, "Price bump percentage to replace an already existing transaction")
 	rootCmd.PersistentFlags().Uint64Var(&blobPriceBump, "txpool.blobpricebump", txpoolcfg.DefaultConfig.BlobPriceBump, "Price bump percentage to replace an existing blob (type-3) transaction")
 	rootCmd.PersistentFlags().DurationVar(&commitEvery, utils.TxPoolCommitEveryFlag.Name, utils.TxPoolCommitEveryFlag.Value, utils.TxPoolCommitEveryFlag.Usage)
+	rootCmd.PersistentFlags().DurationVar(&localsLifetime, utils.TxPoolLifetimeFlag.Name, utils.TxPoolLifetimeFlag.Value, utils.TxPoolLifetimeFlag.Usage)
+	rootCmd.PersistentFlags().IntVar(&localSlots, utils.TxPoolLocalSlotsFlag.Name, utils.TxPoolLocalSlotsFlag.Value, utils.TxPoolLocalSlotsFlag.Usage)
+	rootCmd.PersistentFlags().DurationVar(&rebroadcastEvery, utils.TxPoolRebroadcastEveryFlag.Name, utils.TxPoolRebroadcastEveryFlag.Value, utils.TxPoolRebroadcastEveryFlag.Usage)
 	rootCmd.PersistentFlags().BoolVar(&noTxGossip, utils.TxPoolGossipDisableFlag.Name, utils.TxPoolGossipDisableFlag.Value, utils.TxPoolGossipDisableFlag.Usage)
 	rootCmd.PersistentFlags().BoolVar(&mdbxWriteMap, utils.DbWriteMapFlag.Name, utils.DbWriteMapFlag.Value, utils.DbWriteMapFlag.Usage)
 	rootCmd.Flags().StringSliceVar(&traceSenders, utils.TxPoolTraceSendersFlag.Name, []string{}, utils.TxPoolTraceSendersFlag.Usage)
@@ -161,6 +167,9 @@ func doTxpool(ctx context.Context, logger log.Logger) error {
 	cfg.DBDir = dirs.TxPool
 
 	cfg.CommitEvery = common.RandomizeDuration(commitEvery)
+	cfg.LocalsLifetime = localsLifetime
+	cfg.LocalSlots = localSlots
+	cfg.RebroadcastEvery = rebroadcastEvery
 	cfg.PendingSubPoolLimit = pendingPoolLimit
 	cfg.BaseFeeSubPoolLimit = baseFeePoolLimit
 	cfg.QueuedSubPoolLimit = queuedPoolLimit
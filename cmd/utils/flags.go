@@ -216,6 +216,26 @@ var (
 		Usage: "How often transactions should be committed to the storage",
 		Value: txpoolcfg.DefaultConfig.CommitEvery,
 	}
+	TxPoolLifetimeFlag = cli.DurationFlag{
+		Name:  "txpool.lifetime",
+		Usage: "How long local transactions are journaled and kept exempt from the pool limits",
+		Value: txpoolcfg.DefaultConfig.LocalsLifetime,
+	}
+	TxPoolLocalSlotsFlag = cli.IntFlag{
+		Name:  "txpool.localslots",
+		Usage: "Maximum number of local transactions which are journaled and exempt from the pool limits",
+		Value: txpoolcfg.DefaultConfig.LocalSlots,
+	}
+	TxPoolRebroadcastEveryFlag = cli.DurationFlag{
+		Name:  "txpool.rebroadcast.every",
+		Usage: "How often local transactions are rebroadcast until mined or replaced (0 to disable)",
+		Value: txpoolcfg.DefaultConfig.RebroadcastEvery,
+	}
+	TxPoolPrivateLifetimeFlag = cli.Uint64Flag{
+		Name:  "txpool.private.lifetime",
+		Usage: "Number of blocks a private transaction (eth_sendPrivateRawTransaction) is kept in the pool for",
+		Value: txpoolcfg.DefaultConfig.PrivateTxnLifetime,
+	}
 	// Miner settings
 	MiningEnabledFlag = cli.BoolFlag{
 		Name:  "mine",
@@ -406,6 +426,10 @@ var (
 		Name:  "rpc.accessList",
 		Usage: "Specify granular (method-by-method) API allowlist",
 	}
+	RpcRateLimitFlag = cli.StringFlag{
+		Name:  "rpc.ratelimit",
+		Usage: "Specify a JSON file with per-client rate limits and method costs, e.g. {\"rate\": 100, \"burst\": 500, \"methodCosts\": {\"trace_filter\": 200}}. Clients are told apart by JWT subject or by connection IP address: clients behind one proxy share the limit",
+	}
 
 	RpcGasCapFlag = cli.UintFlag{
 		Name:  "rpc.gascap",
@@ -428,6 +452,12 @@ var (
 		Value: 200,
 	}
 
+	TraceChainMaxBlocksFlag = cli.Uint64Flag{
+		Name:  "trace.chain.maxblocks",
+		Usage: "Sets a limit on blocks that can be traced by one debug_subscribe(traceChain), 0 - no limit",
+		Value: 10_000,
+	}
+
 	HTTPPathPrefixFlag = cli.StringFlag{
 		Name:  "http.rpcprefix",
 		Usage: "HTTP path prefix on which JSON-RPC is served. Use '/' to serve on all paths.",
@@ -679,6 +709,21 @@ var (
 		Usage: "Skip state download and start from genesis block",
 		Value: false,
 	}
+	SnapScrubIntervalFlag = cli.DurationFlag{
+		Name:  "snap.scrub.interval",
+		Usage: "How often to check all blocks of snapshot files against their checksums (or piece hashes of .torrent files), example: 24h. Damaged preverified files are downloaded again. 0 - disabled",
+		Value: 7 * 24 * time.Hour,
+	}
+	SnapScrubRateFlag = cli.StringFlag{
+		Name:  "snap.scrub.rate",
+		Usage: "Bytes per second to read by snapshot files checks, example: 32mb. 0 - unlimited",
+		Value: "16mb",
+	}
+	SnapServeAddrFlag = cli.StringFlag{
+		Name:  "snap.serve.addr",
+		Usage: "Serve snapshot files over HTTP (range requests, manifest.txt) at this address, for example 0.0.0.0:42070. Other nodes can download them with --webseed=http://<addr>/ --webseed.only. Empty - disabled",
+		Value: "",
+	}
 	TorrentVerbosityFlag = cli.IntFlag{
 		Name:  "torrent.verbosity",
 		Value: 1,
@@ -777,6 +822,10 @@ var (
 		Usage: "Comma-separated URL's, holding metadata about network-support infrastructure (like S3 buckets with snapshots, bootnodes, etc...)",
 		Value: "",
 	}
+	WebSeedsOnlyFlag = cli.BoolFlag{
+		Name:  "webseed.only",
+		Usage: "Download snapshots only from --webseed URL's (for example other node with --snap.serve.addr), without BitTorrent peers, trackers and default webseeds",
+	}
 
 	HeimdallURLFlag = cli.StringFlag{
 		Name:  "bor.heimdall",
@@ -1260,6 +1309,8 @@ func NewP2PConfig(
 		enodeDBPath = filepath.Join(dirs.Nodes, "eth67")
 	case direct.ETH68:
 		enodeDBPath = filepath.Join(dirs.Nodes, "eth68")
+	case direct.ETH69:
+		enodeDBPath = filepath.Join(dirs.Nodes, "eth69")
 	default:
 		return nil, fmt.Errorf("unknown protocol: %v", protocol)
 	}
@@ -1597,6 +1648,10 @@ func setTxPool(ctx *cli.Context, dbDir string, fullCfg *ethconfig.Config) {
 	cfg.AllowAA = ctx.Bool(AAFlag.Name)
 	cfg.LogEvery = 3 * time.Minute
 	cfg.CommitEvery = common.RandomizeDuration(ctx.Duration(TxPoolCommitEveryFlag.Name))
+	cfg.LocalsLifetime = ctx.Duration(TxPoolLifetimeFlag.Name)
+	cfg.LocalSlots = ctx.Int(TxPoolLocalSlotsFlag.Name)
+	cfg.RebroadcastEvery = ctx.Duration(TxPoolRebroadcastEveryFlag.Name)
+	cfg.PrivateTxnLifetime = ctx.Uint64(TxPoolPrivateLifetimeFlag.Name)
 	cfg.DBDir = dbDir
 	fullCfg.TxPool = cfg
 }
@@ -1955,6 +2010,11 @@ func SetEthConfig(ctx *cli.Context, nodeConfig *nodecfg.Config, cfg *ethconfig.C
 	cfg.Snapshot.NoDownloader = ctx.Bool(NoDownloaderFlag.Name)
 	cfg.Snapshot.DownloaderAddr = strings.TrimSpace(ctx.String(DownloaderAddrFlag.Name))
 	cfg.Snapshot.ChainName = chain
+	cfg.Snapshot.ScrubInterval = ctx.Duration(SnapScrubIntervalFlag.Name)
+	if cfg.Snapshot.ScrubRate, err = datasize.ParseString(ctx.String(SnapScrubRateFlag.Name)); err != nil {
+		Fatalf("Option %s: %v", SnapScrubRateFlag.Name, err)
+	}
+	cfg.Snapshot.ServeAddr = strings.TrimSpace(ctx.String(SnapServeAddrFlag.Name))
 	nodeConfig.Http.Snap = cfg.Snapshot
 
 	if ctx.Command.Name == "import" {
@@ -2060,7 +2120,7 @@ func SetEthConfig(ctx *cli.Context, nodeConfig *nodecfg.Config, cfg *ethconfig.C
 		}
 		version := "erigon: " + params2.VersionWithCommit(params2.GitCommit)
 		webseedsList := common.CliString2Array(ctx.String(WebSeedsFlag.Name))
-		if known, ok := snapcfg.KnownWebseeds[chain]; ok {
+		if known, ok := snapcfg.KnownWebseeds[chain]; ok && !ctx.Bool(WebSeedsOnlyFlag.Name) {
 			webseedsList = append(webseedsList, known...)
 		}
 		cfg.Downloader, err = downloadercfg.New(
@@ -2079,6 +2139,7 @@ func SetEthConfig(ctx *cli.Context, nodeConfig *nodecfg.Config, cfg *ethconfig.C
 				DownloadRateLimit:        MustGetStringFlagDownloaderRateLimit(ctx.String(TorrentDownloadRateFlag.Name)),
 				UploadRateLimit:          MustGetStringFlagDownloaderRateLimit(ctx.String(TorrentUploadRateFlag.Name)),
 				WebseedDownloadRateLimit: MustGetStringFlagDownloaderRateLimit(ctx.String(TorrentWebseedDownloadRateFlag.Name)),
+				WebseedsOnly:             ctx.Bool(WebSeedsOnlyFlag.Name),
 			},
 		)
 		if err != nil {
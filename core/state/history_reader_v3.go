@@ -23,6 +23,7 @@ import (
 	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/kv/order"
+	"github.com/erigontech/erigon-lib/kv/prune"
 	"github.com/erigontech/erigon-lib/state"
 	"github.com/erigontech/erigon-lib/types/accounts"
 	"github.com/holiman/uint256"
@@ -36,6 +37,12 @@ type HistoryReaderV3 struct {
 	trace     bool
 	ttx       kv.TemporalTx
 	composite []byte
+
+	// with --prune.history.watch history before horizon is kept only for watched accounts,
+	// lazily loaded from ttx: see checkKept
+	retentionLoaded bool
+	horizon         uint64
+	watched         []common.Address
 }
 
 func NewHistoryReaderV3() *HistoryReaderV3 {
@@ -45,10 +52,13 @@ func NewHistoryReaderV3() *HistoryReaderV3 {
 func (hr *HistoryReaderV3) String() string {
 	return fmt.Sprintf("txNum:%d", hr.txNum)
 }
-func (hr *HistoryReaderV3) SetTx(tx kv.TemporalTx) { hr.ttx = tx }
-func (hr *HistoryReaderV3) SetTxNum(txNum uint64)  { hr.txNum = txNum }
-func (hr *HistoryReaderV3) GetTxNum() uint64       { return hr.txNum }
-func (hr *HistoryReaderV3) SetTrace(trace bool)    { hr.trace = trace }
+func (hr *HistoryReaderV3) SetTx(tx kv.TemporalTx) {
+	hr.ttx = tx
+	hr.retentionLoaded = false
+}
+func (hr *HistoryReaderV3) SetTxNum(txNum uint64) { hr.txNum = txNum }
+func (hr *HistoryReaderV3) GetTxNum() uint64      { return hr.txNum }
+func (hr *HistoryReaderV3) SetTrace(trace bool)   { hr.trace = trace }
 
 // Gets the txNum where Account, Storage and Code history begins.
 // If the node is an archive node all history will be available therefore
@@ -65,11 +75,40 @@ func (hr *HistoryReaderV3) StateHistoryStartFrom() uint64 {
 	)
 }
 
+// checkKept - returns PrunedError if history of the account is not kept at hr.txNum. Without it
+// GetAsOf of not-watched account before --prune.history.watch horizon finds no history and
+// silently returns its latest state.
+func (hr *HistoryReaderV3) checkKept(address common.Address) error {
+	if !hr.retentionLoaded {
+		var err error
+		if hr.horizon, err = prune.GetWatchedHorizon(hr.ttx); err != nil {
+			return err
+		}
+		hr.watched = nil
+		if hr.horizon > 0 {
+			if hr.watched, err = prune.GetWatched(hr.ttx); err != nil {
+				return err
+			}
+		}
+		hr.retentionLoaded = true
+	}
+	if hr.txNum >= hr.horizon {
+		return nil
+	}
+	if prune.IsWatched(hr.watched, address) {
+		return nil
+	}
+	return fmt.Errorf("%w: history before txNum %d is kept only for --prune.history.watch accounts, %x is not watched", PrunedError, hr.horizon, address)
+}
+
 func (hr *HistoryReaderV3) ReadSet() map[string]*state.KvList { return nil }
 func (hr *HistoryReaderV3) ResetReadSet()                     {}
 func (hr *HistoryReaderV3) DiscardReadList()                  {}
 
 func (hr *HistoryReaderV3) ReadAccountData(address common.Address) (*accounts.Account, error) {
+	if err := hr.checkKept(address); err != nil {
+		return nil, err
+	}
 	enc, ok, err := hr.ttx.GetAsOf(kv.AccountsDomain, address[:], hr.txNum)
 	if err != nil || !ok || len(enc) == 0 {
 		if hr.trace {
@@ -94,6 +133,9 @@ func (hr *HistoryReaderV3) ReadAccountDataForDebug(address common.Address) (*acc
 }
 
 func (hr *HistoryReaderV3) ReadAccountStorage(address common.Address, key common.Hash) (uint256.Int, bool, error) {
+	if err := hr.checkKept(address); err != nil {
+		return uint256.Int{}, false, err
+	}
 	hr.composite = append(append(hr.composite[:0], address[:]...), key[:]...)
 	enc, ok, err := hr.ttx.GetAsOf(kv.StorageDomain, hr.composite, hr.txNum)
 	if hr.trace {
@@ -107,6 +149,9 @@ func (hr *HistoryReaderV3) ReadAccountStorage(address common.Address, key common
 }
 
 func (hr *HistoryReaderV3) HasStorage(address common.Address) (bool, error) {
+	if err := hr.checkKept(address); err != nil {
+		return false, err
+	}
 	to, ok := kv.NextSubtree(address.Bytes())
 	if !ok {
 		to = nil
@@ -137,6 +182,9 @@ func (hr *HistoryReaderV3) HasStorage(address common.Address) (bool, error) {
 }
 
 func (hr *HistoryReaderV3) ReadAccountCode(address common.Address) ([]byte, error) {
+	if err := hr.checkKept(address); err != nil {
+		return nil, err
+	}
 	//  must pass key2=Nil here: because Erigon4 does concatinate key1+key2 under the hood
 	//code, _, err := hr.ttx.GetAsOf(kv.CodeDomain, address.Bytes(), codeHash.Bytes(), hr.txNum)
 	code, _, err := hr.ttx.GetAsOf(kv.CodeDomain, address[:], hr.txNum)
@@ -147,11 +195,17 @@ func (hr *HistoryReaderV3) ReadAccountCode(address common.Address) ([]byte, erro
 }
 
 func (hr *HistoryReaderV3) ReadAccountCodeSize(address common.Address) (int, error) {
+	if err := hr.checkKept(address); err != nil {
+		return 0, err
+	}
 	enc, _, err := hr.ttx.GetAsOf(kv.CodeDomain, address[:], hr.txNum)
 	return len(enc), err
 }
 
 func (hr *HistoryReaderV3) ReadAccountIncarnation(address common.Address) (uint64, error) {
+	if err := hr.checkKept(address); err != nil {
+		return 0, err
+	}
 	enc, ok, err := hr.ttx.GetAsOf(kv.AccountsDomain, address.Bytes(), hr.txNum)
 	if err != nil || !ok || len(enc) == 0 {
 		if hr.trace {
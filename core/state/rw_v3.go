@@ -276,6 +276,10 @@ func (rs *ParallelExecutionState) ReadsValid(readLists map[string]*libstate.KvLi
 	return rs.domains.ReadsValid(readLists)
 }
 
+func (rs *ParallelExecutionState) StaleReads(readLists map[string]*libstate.KvList) []libstate.StaleRead {
+	return rs.domains.StaleReads(readLists)
+}
+
 // StateWriterBufferedV3 - used by parallel workers to accumulate updates and then send them to conflict-resolution.
 type StateWriterBufferedV3 struct {
 	rs           *ParallelExecutionState
@@ -298,9 +302,9 @@ func NewStateWriterBufferedV3(rs *ParallelExecutionState, accumulator *shards.Ac
 	}
 }
 
-func (w *StateWriterBufferedV3) SetTxNum(ctx context.Context, txNum uint64) {
+// SetTxNum - unlike Writer it doesn't touch the shared domains: parallel workers only read them
+func (w *StateWriterBufferedV3) SetTxNum(txNum uint64) {
 	w.txNum = txNum
-	w.rs.domains.SetTxNum(txNum)
 }
 func (w *StateWriterBufferedV3) SetTx(tx kv.Tx) {}
 
@@ -326,9 +330,7 @@ func (w *StateWriterBufferedV3) UpdateAccountData(address common.Address, origin
 	}
 	if original.Incarnation > account.Incarnation {
 		//del, before create: to clanup code/storage
-		if err := w.rs.domains.DomainDel(kv.CodeDomain, w.rs.tx, address[:], w.txNum, nil, 0); err != nil {
-			return err
-		}
+		w.writeLists[kv.CodeDomain.String()].Push(string(address[:]), nil)
 
 		if err := w.rs.domains.IterateStoragePrefix(address[:], w.rs.tx, func(k, v []byte, step uint64) (bool, error) {
 			w.writeLists[kv.StorageDomain.String()].Push(string(k), nil)
@@ -125,6 +125,18 @@ type (
 	// GenesisBlockHook is called when the genesis block is being processed.
 	GenesisBlockHook = func(genesis *types.Block, alloc types.GenesisAlloc)
 
+	// BlockUnwindHook is called after the execution of blocks `from` down to
+	// `unwindPoint` (exclusive) has been undone, e.g. because of a reorg. Blocks
+	// above `unwindPoint` that were previously reported are no longer canonical.
+	// Like the blocks reported by BlockEndHook, an unwind of the stage loop is
+	// reported before it's committed: if the node stops first, the blocks above
+	// `unwindPoint` stay canonical and aren't reported again.
+	BlockUnwindHook = func(from, unwindPoint uint64)
+
+	// CloseHook is called when the node shuts down and no more events will be
+	// delivered to the tracer.
+	CloseHook = func()
+
 	// OnSystemCallStartHook is called when a system call is about to be executed. Today,
 	// this hook is invoked when the EIP-4788 system call is about to be executed to set the
 	// beacon block root.
@@ -175,6 +187,8 @@ type Hooks struct {
 	OnBlockStart      BlockStartHook
 	OnBlockEnd        BlockEndHook
 	OnGenesisBlock    GenesisBlockHook
+	OnBlockUnwind     BlockUnwindHook
+	OnClose           CloseHook
 	OnSystemCallStart OnSystemCallStartHook
 	OnSystemCallEnd   OnSystemCallEndHook
 	// State events
@@ -66,6 +66,12 @@ type Contract struct {
 	CodeAddr *common.Address
 	Input    []byte
 
+	// Container is the decoded EOF code, Code is then the code section
+	// being executed and returnStack holds the CALLF frames to return to.
+	Container   *Container
+	codeSection uint64
+	returnStack []returnFrame
+
 	Gas   uint64
 	value *uint256.Int
 }
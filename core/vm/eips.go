@@ -346,3 +346,143 @@ func enable7939(jt *JumpTable) {
 		numPush:     1,
 	}
 }
+
+// enableEOF turns an instruction set into the one of EOF code (EIP-7692):
+//   - the instructions observing the code or the gas, and the legacy calls,
+//     creations and jumps are undefined
+//   - RJUMP, RJUMPI and RJUMPV static relative jumps (EIP-4200)
+//   - CALLF, RETF and JUMPF functions (EIP-4750, EIP-6206)
+//   - DATALOAD, DATALOADN, DATASIZE and DATACOPY data section access (EIP-7480)
+//   - DUPN, SWAPN and EXCHANGE (EIP-663)
+//   - EXTCALL, EXTDELEGATECALL, EXTSTATICCALL and RETURNDATALOAD (EIP-7069)
+//   - EOFCREATE and RETURNCONTRACT (EIP-7620)
+func enableEOF(jt *JumpTable) {
+	for _, op := range []OpCode{
+		CALL, CALLCODE, DELEGATECALL, STATICCALL, SELFDESTRUCT, JUMP, JUMPI, PC,
+		CREATE, CREATE2, CODESIZE, CODECOPY, EXTCODESIZE, EXTCODECOPY, EXTCODEHASH, GAS,
+	} {
+		jt[op] = &operation{execute: opUndefined, undefined: true}
+	}
+	// INVALID is a valid terminating instruction of EOF code
+	jt[INVALID] = &operation{execute: opUndefined}
+	jt[RETURNDATACOPY].execute = opReturnDataCopyEOF
+
+	jt[RJUMP] = &operation{
+		execute:     opRjump,
+		constantGas: GasQuickStep,
+		string:      stImmediate,
+	}
+	jt[RJUMPI] = &operation{
+		execute:     opRjumpi,
+		constantGas: 4,
+		numPop:      1,
+		string:      stImmediate,
+	}
+	jt[RJUMPV] = &operation{
+		execute:     opRjumpv,
+		constantGas: 4,
+		numPop:      1,
+		string:      stImmediate,
+	}
+	jt[CALLF] = &operation{
+		execute:     opCallf,
+		constantGas: GasFastStep,
+		string:      stImmediate,
+	}
+	jt[RETF] = &operation{
+		execute:     opRetf,
+		constantGas: GasFastestStep,
+	}
+	jt[JUMPF] = &operation{
+		execute:     opJumpf,
+		constantGas: GasFastStep,
+		string:      stImmediate,
+	}
+	jt[DATALOAD] = &operation{
+		execute:     opDataLoad,
+		constantGas: 4,
+		numPop:      1,
+		numPush:     1,
+	}
+	jt[DATALOADN] = &operation{
+		execute:     opDataLoadN,
+		constantGas: GasFastestStep,
+		numPush:     1,
+		string:      stImmediate,
+	}
+	jt[DATASIZE] = &operation{
+		execute:     opDataSize,
+		constantGas: GasQuickStep,
+		numPush:     1,
+	}
+	jt[DATACOPY] = &operation{
+		execute:     opDataCopy,
+		constantGas: GasFastestStep,
+		dynamicGas:  gasDataCopy,
+		numPop:      3,
+		memorySize:  memoryDataCopy,
+	}
+	// The stack of DUPN, SWAPN and EXCHANGE is checked by the code validation
+	jt[DUPN] = &operation{
+		execute:     opDupN,
+		constantGas: GasFastestStep,
+		numPush:     1,
+		string:      stImmediate,
+	}
+	jt[SWAPN] = &operation{
+		execute:     opSwapN,
+		constantGas: GasFastestStep,
+		string:      stImmediate,
+	}
+	jt[EXCHANGE] = &operation{
+		execute:     opExchange,
+		constantGas: GasFastestStep,
+		string:      stImmediate,
+	}
+	jt[RETURNDATALOAD] = &operation{
+		execute:     opReturnDataLoad,
+		constantGas: GasFastestStep,
+		numPop:      1,
+		numPush:     1,
+	}
+	jt[EXTCALL] = &operation{
+		execute:     opExtCall,
+		constantGas: params.WarmStorageReadCostEIP2929,
+		dynamicGas:  gasExtCall,
+		numPop:      4,
+		numPush:     1,
+		memorySize:  memoryExtCall,
+	}
+	jt[EXTDELEGATECALL] = &operation{
+		execute:     opExtDelegateCall,
+		constantGas: params.WarmStorageReadCostEIP2929,
+		dynamicGas:  gasExtDelegateCall,
+		numPop:      3,
+		numPush:     1,
+		memorySize:  memoryExtCall,
+	}
+	jt[EXTSTATICCALL] = &operation{
+		execute:     opExtStaticCall,
+		constantGas: params.WarmStorageReadCostEIP2929,
+		dynamicGas:  gasExtStaticCall,
+		numPop:      3,
+		numPush:     1,
+		memorySize:  memoryExtCall,
+	}
+	jt[EOFCREATE] = &operation{
+		execute:     opEOFCreate,
+		constantGas: params.CreateGas,
+		dynamicGas:  gasEOFCreate,
+		numPop:      4,
+		numPush:     1,
+		memorySize:  memoryEOFCreate,
+		string:      stImmediate,
+	}
+	jt[RETURNCONTRACT] = &operation{
+		execute:    opReturnContract,
+		dynamicGas: gasReturnContract,
+		numPop:     2,
+		memorySize: memoryReturnContract,
+		string:     stImmediate,
+	}
+}
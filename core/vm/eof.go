@@ -0,0 +1,425 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/golang-lru/v2/simplelru"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/crypto"
+)
+
+// EOF v1 container format, see EIP-3540:
+//
+//	container := header, body
+//	header    := magic, version,
+//	             kind_type, type_size,
+//	             kind_code, num_code_sections, code_size+,
+//	             [kind_container, num_container_sections, container_size+,]
+//	             kind_data, data_size,
+//	             terminator
+//	body      := types_section, code_section+, container_section*, data_section
+const (
+	eofFormatByte = 0xef
+	eof1Version   = 1
+
+	kindTypes     = 0x01
+	kindCode      = 0x02
+	kindContainer = 0x03
+	kindData      = 0xff
+	terminator    = 0x00
+
+	eofTypeSize = 4 // inputs, outputs and max_stack_increase of a code section
+
+	maxInputItems        = 0x7f
+	maxOutputItems       = 0x7f
+	nonReturningFunction = 0x80
+	maxStackHeight       = 1023
+	maxCodeSections      = 1024
+	maxContainerSections = 256
+	maxReturnStackDepth  = 1024
+)
+
+var (
+	eofMagic     = []byte{eofFormatByte, 0x00}
+	eofMagicHash = crypto.Keccak256Hash(eofMagic)
+)
+
+var (
+	ErrInvalidMagic           = errors.New("invalid magic")
+	ErrInvalidVersion         = errors.New("invalid version")
+	ErrMissingTypeHeader      = errors.New("missing type header")
+	ErrInvalidTypeSize        = errors.New("invalid type section size")
+	ErrMissingCodeHeader      = errors.New("missing code header")
+	ErrInvalidCodeSize        = errors.New("invalid code size")
+	ErrInvalidContainerSize   = errors.New("invalid container size")
+	ErrMissingDataHeader      = errors.New("missing data header")
+	ErrMissingTerminator      = errors.New("missing header terminator")
+	ErrTooManyInputs          = errors.New("invalid type content, too many inputs")
+	ErrTooManyOutputs         = errors.New("invalid type content, too many outputs")
+	ErrInvalidSection0Type    = errors.New("invalid section 0 type, input and output should be zero and non-returning (0x80)")
+	ErrTooLargeMaxStackHeight = errors.New("invalid type content, max stack height exceeds limit")
+	ErrInvalidContainerLength = errors.New("invalid container length")
+	ErrTruncatedData          = errors.New("data section is truncated")
+)
+
+// HasEOFMagic reports whether code starts with the EOF magic, which is
+// reserved by EIP-3541 and selects the EOF interpreter once EOF is enabled.
+func HasEOFMagic(code []byte) bool {
+	return bytes.HasPrefix(code, eofMagic)
+}
+
+// legacyCodeView returns the code of an account as seen by EXTCODESIZE and
+// EXTCODECOPY of legacy code: EOF code is replaced by the magic alone.
+func legacyCodeView(code []byte) []byte {
+	if HasEOFMagic(code) {
+		return eofMagic
+	}
+	return code
+}
+
+// functionMetadata is an entry of the types section describing a code section.
+type functionMetadata struct {
+	inputs           uint8
+	outputs          uint8 // nonReturningFunction for sections which never return
+	maxStackIncrease uint16
+}
+
+func (meta *functionMetadata) returning() bool {
+	return meta.outputs != nonReturningFunction
+}
+
+// Container is a decoded EOF v1 container.
+type Container struct {
+	types         []*functionMetadata
+	codeSections  [][]byte
+	subContainers []*Container
+	data          []byte
+	// dataSize is the size declared in the header, which may exceed len(data)
+	// in a subcontainer deployed by RETURNCONTRACT with auxiliary data.
+	dataSize int
+}
+
+// CodeSection returns the code of the given section.
+func (c *Container) CodeSection(i int) []byte { return c.codeSections[i] }
+
+// Data returns the data section.
+func (c *Container) Data() []byte { return c.data }
+
+// MarshalBinary encodes the container.
+func (c *Container) MarshalBinary() []byte {
+	b := make([]byte, 0, 64)
+	b = append(b, eofMagic...)
+	b = append(b, eof1Version)
+
+	b = append(b, kindTypes)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(c.types)*eofTypeSize))
+	b = append(b, kindCode)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(c.codeSections)))
+	for _, code := range c.codeSections {
+		b = binary.BigEndian.AppendUint16(b, uint16(len(code)))
+	}
+	subContainers := make([][]byte, len(c.subContainers))
+	if len(c.subContainers) > 0 {
+		b = append(b, kindContainer)
+		b = binary.BigEndian.AppendUint16(b, uint16(len(c.subContainers)))
+		for i, sub := range c.subContainers {
+			subContainers[i] = sub.MarshalBinary()
+			b = binary.BigEndian.AppendUint32(b, uint32(len(subContainers[i])))
+		}
+	}
+	b = append(b, kindData)
+	b = binary.BigEndian.AppendUint16(b, uint16(c.dataSize))
+	b = append(b, terminator)
+
+	for _, typ := range c.types {
+		b = append(b, typ.inputs, typ.outputs)
+		b = binary.BigEndian.AppendUint16(b, typ.maxStackIncrease)
+	}
+	for _, code := range c.codeSections {
+		b = append(b, code...)
+	}
+	for _, sub := range subContainers {
+		b = append(b, sub...)
+	}
+	return append(b, c.data...)
+}
+
+// UnmarshalBinary decodes a top-level container, which has to span b exactly
+// and to hold its whole data section.
+func (c *Container) UnmarshalBinary(b []byte) error {
+	size, err := c.unmarshal(b, true)
+	if err != nil {
+		return err
+	}
+	if size != len(b) {
+		return ErrInvalidContainerLength
+	}
+	return nil
+}
+
+// unmarshal decodes the container at the start of b and returns its size. The
+// data section of a container which isn't top-level may be truncated, it has
+// to end b then.
+func (c *Container) unmarshal(b []byte, topLevel bool) (int, error) {
+	if !HasEOFMagic(b) {
+		return 0, ErrInvalidMagic
+	}
+	if len(b) < 3 || b[2] != eof1Version {
+		return 0, ErrInvalidVersion
+	}
+	r := &headerReader{b: b, pos: 3}
+
+	if !r.kind(kindTypes) {
+		return 0, ErrMissingTypeHeader
+	}
+	typesSize, ok := r.uint16()
+	if !ok || typesSize < eofTypeSize || typesSize%eofTypeSize != 0 {
+		return 0, fmt.Errorf("%w: %d", ErrInvalidTypeSize, typesSize)
+	}
+
+	if !r.kind(kindCode) {
+		return 0, ErrMissingCodeHeader
+	}
+	codeSizes, err := r.sizes(2, maxCodeSections)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidCodeSize, err)
+	}
+	if len(codeSizes) != typesSize/eofTypeSize {
+		return 0, fmt.Errorf("%w: %d code sections, %d types", ErrInvalidTypeSize, len(codeSizes), typesSize/eofTypeSize)
+	}
+
+	var containerSizes []int
+	if r.kind(kindContainer) {
+		if containerSizes, err = r.sizes(4, maxContainerSections); err != nil {
+			return 0, fmt.Errorf("%w: %w", ErrInvalidContainerSize, err)
+		}
+	}
+
+	if !r.kind(kindData) {
+		return 0, ErrMissingDataHeader
+	}
+	dataSize, ok := r.uint16()
+	if !ok {
+		return 0, ErrMissingDataHeader
+	}
+	if !r.kind(terminator) {
+		return 0, ErrMissingTerminator
+	}
+
+	// The body is read sequentially after the header.
+	pos := r.pos
+	section := func(size int) ([]byte, bool) {
+		if pos+size > len(b) {
+			return nil, false
+		}
+		s := b[pos : pos+size]
+		pos += size
+		return s, true
+	}
+
+	types, ok := section(typesSize)
+	if !ok {
+		return 0, fmt.Errorf("%w: types section is truncated", ErrInvalidContainerLength)
+	}
+	c.types = make([]*functionMetadata, 0, len(codeSizes))
+	for i := 0; i < len(types); i += eofTypeSize {
+		meta := &functionMetadata{
+			inputs:           types[i],
+			outputs:          types[i+1],
+			maxStackIncrease: binary.BigEndian.Uint16(types[i+2:]),
+		}
+		if meta.inputs > maxInputItems {
+			return 0, fmt.Errorf("%w: section %d, %d inputs", ErrTooManyInputs, i/eofTypeSize, meta.inputs)
+		}
+		if meta.outputs > maxOutputItems && meta.outputs != nonReturningFunction {
+			return 0, fmt.Errorf("%w: section %d, %d outputs", ErrTooManyOutputs, i/eofTypeSize, meta.outputs)
+		}
+		if int(meta.inputs)+int(meta.maxStackIncrease) > maxStackHeight {
+			return 0, fmt.Errorf("%w: section %d, %d", ErrTooLargeMaxStackHeight, i/eofTypeSize, int(meta.inputs)+int(meta.maxStackIncrease))
+		}
+		c.types = append(c.types, meta)
+	}
+	if c.types[0].inputs != 0 || c.types[0].returning() {
+		return 0, ErrInvalidSection0Type
+	}
+
+	c.codeSections = make([][]byte, len(codeSizes))
+	for i, size := range codeSizes {
+		if c.codeSections[i], ok = section(size); !ok {
+			return 0, fmt.Errorf("%w: code section %d is truncated", ErrInvalidContainerLength, i)
+		}
+	}
+
+	c.subContainers = make([]*Container, len(containerSizes))
+	for i, size := range containerSizes {
+		code, ok := section(size)
+		if !ok {
+			return 0, fmt.Errorf("%w: container section %d is truncated", ErrInvalidContainerLength, i)
+		}
+		sub := new(Container)
+		subSize, err := sub.unmarshal(code, false)
+		if err != nil {
+			return 0, fmt.Errorf("container section %d: %w", i, err)
+		}
+		if subSize != len(code) {
+			return 0, fmt.Errorf("container section %d: %w", i, ErrInvalidContainerLength)
+		}
+		c.subContainers[i] = sub
+	}
+
+	c.dataSize = dataSize
+	if pos+dataSize > len(b) {
+		if topLevel {
+			return 0, ErrTruncatedData
+		}
+		c.data = b[pos:]
+		return len(b), nil
+	}
+	c.data = b[pos : pos+dataSize]
+	return pos + dataSize, nil
+}
+
+// headerReader reads the fields of a container header.
+type headerReader struct {
+	b   []byte
+	pos int
+}
+
+func (r *headerReader) kind(kind byte) bool {
+	if r.pos >= len(r.b) || r.b[r.pos] != kind {
+		return false
+	}
+	r.pos++
+	return true
+}
+
+func (r *headerReader) uint16() (int, bool) {
+	if r.pos+2 > len(r.b) {
+		return 0, false
+	}
+	v := binary.BigEndian.Uint16(r.b[r.pos:])
+	r.pos += 2
+	return int(v), true
+}
+
+// sizes reads a 2-byte count of sections followed by their non-zero sizes of
+// the given width.
+func (r *headerReader) sizes(width int, limit int) ([]int, error) {
+	num, ok := r.uint16()
+	if !ok {
+		return nil, errors.New("missing number of sections")
+	}
+	if num == 0 || num > limit {
+		return nil, fmt.Errorf("invalid number of sections %d", num)
+	}
+	if r.pos+num*width > len(r.b) {
+		return nil, errors.New("truncated section sizes")
+	}
+	sizes := make([]int, num)
+	for i := range sizes {
+		if width == 2 {
+			sizes[i] = int(binary.BigEndian.Uint16(r.b[r.pos:]))
+		} else {
+			sizes[i] = int(binary.BigEndian.Uint32(r.b[r.pos:]))
+		}
+		r.pos += width
+		if sizes[i] == 0 {
+			return nil, fmt.Errorf("section %d is empty", i)
+		}
+	}
+	return sizes, nil
+}
+
+// ValidateEOF decodes and validates a top-level EOF container for the EOF
+// instruction set.
+func ValidateEOF(code []byte, isInitcode bool) error {
+	var c Container
+	if err := c.UnmarshalBinary(code); err != nil {
+		return err
+	}
+	return c.ValidateCode(&eofInstructionSet, isInitcode)
+}
+
+// eofContainerCacheLimit is the number of containers of deployed code an EVM
+// keeps, see EVM.eofContainer.
+const eofContainerCacheLimit = 128
+
+// eofContainer decodes and validates the EOF container of deployed code. The
+// containers of code with a hash are kept for the following calls, the ones
+// of accounts are validated when deployed already.
+func (evm *EVM) eofContainer(codeHash common.Hash, code []byte, jt *JumpTable) (*Container, error) {
+	if codeHash != (common.Hash{}) && evm.eofContainers != nil {
+		if c, ok := evm.eofContainers.Get(codeHash); ok {
+			return c, nil
+		}
+	}
+	c := new(Container)
+	if err := c.UnmarshalBinary(code); err != nil {
+		return nil, err
+	}
+	if err := c.ValidateCode(jt, false); err != nil {
+		return nil, err
+	}
+	if codeHash != (common.Hash{}) {
+		if evm.eofContainers == nil {
+			cache, err := simplelru.NewLRU[common.Hash, *Container](eofContainerCacheLimit, nil)
+			if err != nil {
+				panic(err)
+			}
+			evm.eofContainers = cache
+		}
+		evm.eofContainers.Add(codeHash, c)
+	}
+	return c, nil
+}
+
+// parseEOFInitcode splits the data of a creation transaction into the
+// initcontainer it starts with and the calldata following it (EIP-7698), and
+// validates the initcontainer.
+func parseEOFInitcode(b []byte, jt *JumpTable) (*Container, []byte, error) {
+	c := new(Container)
+	size, err := c.unmarshal(b, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := c.ValidateCode(jt, true); err != nil {
+		return nil, nil, err
+	}
+	return c, b[size:], nil
+}
+
+// withAuxData returns the encoding of the container to deploy with the given
+// auxiliary data appended to its data section, which has to fill at least the
+// declared data size (EIP-7620).
+func (c *Container) withAuxData(aux []byte) ([]byte, error) {
+	deployed := *c
+	deployed.data = append(append(make([]byte, 0, len(c.data)+len(aux)), c.data...), aux...)
+	if len(deployed.data) < c.dataSize {
+		return nil, ErrTruncatedData
+	}
+	if len(deployed.data) > 0xffff {
+		return nil, fmt.Errorf("%w: data section of %d bytes", ErrInvalidContainerLength, len(deployed.data))
+	}
+	deployed.dataSize = len(deployed.data)
+	return deployed.MarshalBinary(), nil
+}
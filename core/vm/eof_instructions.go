@@ -0,0 +1,346 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/holiman/uint256"
+
+	"github.com/erigontech/erigon-lib/chain/params"
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon/core/tracing"
+)
+
+// The instructions of EOF code are executed within a code section of the
+// container: Contract.Code is the code section being executed and pc is
+// relative to its start. Like JUMP, the instructions changing the control
+// flow set pc to their destination minus one, the interpreter loop increments
+// it afterwards.
+
+// returnFrame is the place to resume the execution of the caller of CALLF.
+type returnFrame struct {
+	section uint64
+	pc      uint64
+}
+
+// setCodeSection switches the execution to the given code section.
+func (c *Contract) setCodeSection(section uint64) {
+	c.codeSection = section
+	c.Code = c.Container.codeSections[section]
+}
+
+func opRjump(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	offset := int16(binary.BigEndian.Uint16(scope.Contract.Code[*pc+1:]))
+	*pc = uint64(int64(*pc) + 3 + int64(offset) - 1)
+	return nil, nil
+}
+
+func opRjumpi(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	cond := scope.Stack.pop()
+	if cond.IsZero() {
+		*pc += 2
+		return nil, nil
+	}
+	return opRjump(pc, interpreter, scope)
+}
+
+func opRjumpv(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	var (
+		code  = scope.Contract.Code
+		index = scope.Stack.pop()
+		count = uint64(code[*pc+1]) + 1
+		end   = *pc + 2 + 2*count
+	)
+	if !index.IsUint64() || index.Uint64() >= count {
+		// fall through to the next instruction
+		*pc = end - 1
+		return nil, nil
+	}
+	offset := int16(binary.BigEndian.Uint16(code[*pc+2+2*index.Uint64():]))
+	*pc = uint64(int64(end) + int64(offset) - 1)
+	return nil, nil
+}
+
+func opCallf(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	section := uint64(binary.BigEndian.Uint16(scope.Contract.Code[*pc+1:]))
+	meta := scope.Contract.Container.types[section]
+	if limit := int(params.StackLimit) - int(meta.maxStackIncrease); scope.Stack.len() > limit {
+		return nil, &ErrStackOverflow{stackLen: scope.Stack.len(), limit: limit}
+	}
+	if len(scope.Contract.returnStack) >= maxReturnStackDepth {
+		return nil, ErrReturnStackExceeded
+	}
+	scope.Contract.returnStack = append(scope.Contract.returnStack, returnFrame{section: scope.Contract.codeSection, pc: *pc + 3})
+	scope.Contract.setCodeSection(section)
+	*pc = ^uint64(0)
+	return nil, nil
+}
+
+func opRetf(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	frame := scope.Contract.returnStack[len(scope.Contract.returnStack)-1]
+	scope.Contract.returnStack = scope.Contract.returnStack[:len(scope.Contract.returnStack)-1]
+	scope.Contract.setCodeSection(frame.section)
+	*pc = frame.pc - 1
+	return nil, nil
+}
+
+func opJumpf(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	section := uint64(binary.BigEndian.Uint16(scope.Contract.Code[*pc+1:]))
+	meta := scope.Contract.Container.types[section]
+	if limit := int(params.StackLimit) - int(meta.maxStackIncrease); scope.Stack.len() > limit {
+		return nil, &ErrStackOverflow{stackLen: scope.Stack.len(), limit: limit}
+	}
+	scope.Contract.setCodeSection(section)
+	*pc = ^uint64(0)
+	return nil, nil
+}
+
+func opDataLoad(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	offset := scope.Stack.peek()
+	offset.SetBytes32(getDataBig(scope.Contract.Container.data, offset, 32))
+	return nil, nil
+}
+
+func opDataLoadN(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	offset := uint64(binary.BigEndian.Uint16(scope.Contract.Code[*pc+1:]))
+	scope.Stack.push(new(uint256.Int).SetBytes32(getData(scope.Contract.Container.data, offset, 32)))
+	*pc += 2
+	return nil, nil
+}
+
+func opDataSize(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	scope.Stack.push(new(uint256.Int).SetUint64(uint64(len(scope.Contract.Container.data))))
+	return nil, nil
+}
+
+func opDataCopy(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	var (
+		memOffset = scope.Stack.pop()
+		offset    = scope.Stack.pop()
+		size      = scope.Stack.pop()
+	)
+	scope.Memory.Set(memOffset.Uint64(), size.Uint64(), getDataBig(scope.Contract.Container.data, &offset, size.Uint64()))
+	return nil, nil
+}
+
+func opDupN(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	n := int(scope.Contract.Code[*pc+1]) + 1
+	scope.Stack.dup(n)
+	*pc += 1
+	return nil, nil
+}
+
+func opSwapN(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	n := int(scope.Contract.Code[*pc+1]) + 1
+	data := scope.Stack.data
+	data[len(data)-1], data[len(data)-1-n] = data[len(data)-1-n], data[len(data)-1]
+	*pc += 1
+	return nil, nil
+}
+
+func opExchange(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	imm := scope.Contract.Code[*pc+1]
+	n, m := int(imm>>4)+1, int(imm&0x0f)+1
+	data := scope.Stack.data
+	data[len(data)-1-n], data[len(data)-1-n-m] = data[len(data)-1-n-m], data[len(data)-1-n]
+	*pc += 1
+	return nil, nil
+}
+
+func stImmediate(pc uint64, scope *ScopeContext) string {
+	code := scope.Contract.Code
+	op := OpCode(code[pc])
+	imm := code[pc+1 : pc+1+uint64(eofImmediates[op])]
+	if op == RJUMPV {
+		imm = code[pc+1 : pc+2+2*(uint64(code[pc+1])+1)]
+	}
+	return fmt.Sprintf("%s 0x%x", op, imm)
+}
+
+func opReturnDataLoad(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	offset := scope.Stack.peek()
+	offset.SetBytes32(getDataBig(interpreter.returnData, offset, 32))
+	return nil, nil
+}
+
+// opReturnDataCopyEOF is RETURNDATACOPY in EOF code, which pads the return
+// data with zeros instead of failing on out of bounds reads (EIP-7069).
+func opReturnDataCopyEOF(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	var (
+		memOffset  = scope.Stack.pop()
+		dataOffset = scope.Stack.pop()
+		length     = scope.Stack.pop()
+	)
+	scope.Memory.Set(memOffset.Uint64(), length.Uint64(), getDataBig(interpreter.returnData, &dataOffset, length.Uint64()))
+	return nil, nil
+}
+
+// ErrInvalidEOFAddress is returned when the target of an EXTCALL,
+// EXTDELEGATECALL or EXTSTATICCALL has any of its high 12 bytes set.
+var ErrInvalidEOFAddress = errors.New("invalid address with non-zero high bytes")
+
+// EXTCALL, EXTDELEGATECALL and EXTSTATICCALL push a status instead of a flag.
+const (
+	extCallSuccess = 0
+	extCallRevert  = 1 // reverted or not executed
+	extCallFailure = 2
+)
+
+// extCallGas returns the gas passed to the callee of EXTCALL, EXTDELEGATECALL
+// and EXTSTATICCALL, which retains at least 1/64th of the available gas and
+// isn't called when it would get less than MIN_CALLEE_GAS.
+func extCallGas(available uint64) (uint64, bool) {
+	retained := max(available/64, params.ExtCallMinRetainedGas)
+	if available < retained+params.ExtCallMinCalleeGas {
+		return 0, false
+	}
+	return available - retained, true
+}
+
+func extCallStatus(err error) uint64 {
+	switch {
+	case err == nil:
+		return extCallSuccess
+	case errors.Is(err, ErrExecutionReverted), errors.Is(err, ErrDepth), errors.Is(err, ErrInsufficientBalance):
+		return extCallRevert
+	default:
+		return extCallFailure
+	}
+}
+
+func isExtCallAddress(addr *uint256.Int) bool {
+	b := addr.Bytes32()
+	return allZero(b[:12])
+}
+
+func opExtCall(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	return extCall(EXTCALL, interpreter, scope)
+}
+
+func opExtDelegateCall(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	return extCall(EXTDELEGATECALL, interpreter, scope)
+}
+
+func opExtStaticCall(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	return extCall(EXTSTATICCALL, interpreter, scope)
+}
+
+func extCall(typ OpCode, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	stack := scope.Stack
+	addr, inOffset, inSize := stack.pop(), stack.pop(), stack.pop()
+	var value uint256.Int
+	if typ == EXTCALL {
+		value = stack.pop()
+	}
+	if !isExtCallAddress(&addr) {
+		return nil, ErrInvalidEOFAddress
+	}
+	if !value.IsZero() && interpreter.readOnly {
+		return nil, ErrWriteProtection
+	}
+	toAddr := common.Address(addr.Bytes20())
+	status := new(uint256.Int).SetUint64(extCallRevert)
+	interpreter.returnData = nil
+
+	gas, ok := extCallGas(scope.Contract.Gas)
+	if !ok {
+		stack.push(status)
+		return nil, nil
+	}
+	if typ == EXTDELEGATECALL {
+		// only EOF code can be delegated to
+		code, err := interpreter.evm.IntraBlockState().ResolveCode(toAddr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrIntraBlockStateFailed, err)
+		}
+		if !HasEOFMagic(code) {
+			stack.push(status)
+			return nil, nil
+		}
+	}
+	scope.Contract.UseGas(gas, interpreter.evm.Config().Tracer, tracing.GasChangeCallOpCode)
+
+	args := scope.Memory.GetPtr(inOffset.Uint64(), inSize.Uint64())
+	var (
+		ret       []byte
+		returnGas uint64
+		err       error
+	)
+	switch typ {
+	case EXTCALL:
+		ret, returnGas, err = interpreter.evm.Call(scope.Contract, toAddr, args, gas, &value, false /* bailout */)
+	case EXTDELEGATECALL:
+		ret, returnGas, err = interpreter.evm.DelegateCall(scope.Contract, toAddr, args, gas)
+	default:
+		ret, returnGas, err = interpreter.evm.StaticCall(scope.Contract, toAddr, args, gas)
+	}
+	stack.push(status.SetUint64(extCallStatus(err)))
+	scope.Contract.RefundGas(returnGas, interpreter.evm.config.Tracer, tracing.GasChangeCallLeftOverRefunded)
+
+	interpreter.returnData = ret
+	return ret, nil
+}
+
+func opEOFCreate(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	if interpreter.readOnly {
+		return nil, ErrWriteProtection
+	}
+	var (
+		initContainer = scope.Contract.Container.subContainers[scope.Contract.Code[*pc+1]]
+		value         = scope.Stack.pop()
+		salt          = scope.Stack.pop()
+		offset        = scope.Stack.pop()
+		size          = scope.Stack.peek()
+		input         = scope.Memory.GetCopy(offset.Uint64(), size.Uint64())
+		gas           = scope.Contract.Gas
+	)
+	*pc += 1
+	gas -= gas / 64
+	scope.Contract.UseGas(gas, interpreter.evm.Config().Tracer, tracing.GasChangeCallContractCreation)
+
+	res, addr, returnGas, suberr := interpreter.evm.EOFCreate(scope.Contract, initContainer, input, gas, &value, &salt)
+	// reuse size int for stackvalue
+	if suberr != nil {
+		size.Clear()
+	} else {
+		size.SetBytes(addr.Bytes())
+	}
+	scope.Contract.RefundGas(returnGas, interpreter.evm.config.Tracer, tracing.GasChangeCallLeftOverRefunded)
+
+	if suberr == ErrExecutionReverted {
+		interpreter.returnData = res // set REVERT data to return data buffer
+		return res, nil
+	}
+	interpreter.returnData = nil // clear dirty return data buffer
+	return nil, nil
+}
+
+func opReturnContract(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	var (
+		container = scope.Contract.Container.subContainers[scope.Contract.Code[*pc+1]]
+		offset    = scope.Stack.pop()
+		size      = scope.Stack.pop()
+	)
+	deployed, err := container.withAuxData(scope.Memory.GetPtr(offset.Uint64(), size.Uint64()))
+	if err != nil {
+		return nil, err
+	}
+	return deployed, errStopToken
+}
@@ -0,0 +1,178 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/crypto"
+)
+
+func newTestContainer(data []byte, sections ...[]byte) *Container {
+	c := &Container{data: data, dataSize: len(data)}
+	for i, code := range sections {
+		meta := &functionMetadata{}
+		if i == 0 {
+			meta.outputs = nonReturningFunction
+		}
+		c.types = append(c.types, meta)
+		c.codeSections = append(c.codeSections, code)
+	}
+	return c
+}
+
+func TestEOFContainerRoundTrip(t *testing.T) {
+	t.Parallel()
+	c := newTestContainer([]byte{0xaa, 0xbb},
+		[]byte{byte(CALLF), 0x00, 0x01, byte(STOP)},
+		[]byte{byte(PUSH1), 0x01, byte(POP), byte(RETF)},
+	)
+	c.types[1].maxStackIncrease = 1
+	c.subContainers = []*Container{newTestContainer(nil, []byte{byte(INVALID)})}
+	b := c.MarshalBinary()
+
+	var decoded Container
+	if err := decoded.UnmarshalBinary(b); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !bytes.Equal(decoded.MarshalBinary(), b) {
+		t.Fatalf("round trip mismatch: %x != %x", decoded.MarshalBinary(), b)
+	}
+	if len(decoded.codeSections) != 2 || len(decoded.subContainers) != 1 || !bytes.Equal(decoded.Data(), []byte{0xaa, 0xbb}) {
+		t.Fatalf("unexpected container %+v", decoded)
+	}
+
+	// trailing bytes
+	if err := decoded.UnmarshalBinary(append(b, 0x00)); !errors.Is(err, ErrInvalidContainerLength) {
+		t.Fatalf("expected %v, got %v", ErrInvalidContainerLength, err)
+	}
+	// truncated data section
+	if err := decoded.UnmarshalBinary(b[:len(b)-1]); err == nil {
+		t.Fatal("expected an error for a truncated data section")
+	}
+	if err := decoded.UnmarshalBinary([]byte{0xef, 0x01, 0x01}); !errors.Is(err, ErrInvalidMagic) {
+		t.Fatalf("expected %v, got %v", ErrInvalidMagic, err)
+	}
+}
+
+func TestEOFValidateCode(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name       string
+		sections   [][]byte
+		maxStack   uint16 // of section 0
+		isInitcode bool
+		err        error
+	}{
+		{
+			name:     "stop",
+			sections: [][]byte{{byte(STOP)}},
+		},
+		{
+			name:     "rjumpi",
+			sections: [][]byte{{byte(PUSH1), 0x01, byte(RJUMPI), 0x00, 0x01, byte(STOP), byte(STOP)}},
+			maxStack: 1,
+		},
+		{
+			name:     "callf",
+			sections: [][]byte{{byte(CALLF), 0x00, 0x01, byte(STOP)}, {byte(RETF)}},
+		},
+		{
+			name:     "legacy jump",
+			sections: [][]byte{{byte(PUSH1), 0x00, byte(JUMP)}},
+			maxStack: 1,
+			err:      ErrUndefinedInstruction,
+		},
+		{
+			name:     "truncated push",
+			sections: [][]byte{{byte(PUSH2), 0x00}},
+			maxStack: 1,
+			err:      ErrTruncatedImmediate,
+		},
+		{
+			name:     "rjump into immediate",
+			sections: [][]byte{{byte(RJUMP), 0x00, 0x01, byte(PUSH1), 0x00, byte(STOP)}},
+			maxStack: 1,
+			err:      ErrInvalidJumpDest,
+		},
+		{
+			name:     "missing termination",
+			sections: [][]byte{{byte(PUSH1), 0x00, byte(POP)}},
+			maxStack: 1,
+			err:      ErrInvalidCodeTermination,
+		},
+		{
+			name:     "stack underflow",
+			sections: [][]byte{{byte(POP), byte(STOP)}},
+			err:      ErrEOFStackUnderflow,
+		},
+		{
+			name:     "unreachable section",
+			sections: [][]byte{{byte(STOP)}, {byte(RETF)}},
+			err:      ErrUnreachableCode,
+		},
+		{
+			name:       "stop in initcode",
+			sections:   [][]byte{{byte(STOP)}},
+			isInitcode: true,
+			err:        ErrIncompatibleContainerKind,
+		},
+	} {
+		c := newTestContainer(nil, test.sections...)
+		c.types[0].maxStackIncrease = test.maxStack
+		err := c.ValidateCode(&eofInstructionSet, test.isInitcode)
+		if test.err == nil && err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		} else if test.err != nil && !errors.Is(err, test.err) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.err, err)
+		}
+	}
+}
+
+func TestEOFContainerCache(t *testing.T) {
+	t.Parallel()
+	code := newTestContainer(nil, []byte{byte(STOP)}).MarshalBinary()
+	codeHash := crypto.Keccak256Hash(code)
+	evm := &EVM{}
+
+	c, err := evm.eofContainer(codeHash, code, &eofInstructionSet)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// deployed code is decoded and validated once
+	if cached, err := evm.eofContainer(codeHash, code, &eofInstructionSet); err != nil || cached != c {
+		t.Fatalf("container of %x not kept: %v", codeHash, err)
+	}
+	// code without a hash isn't kept
+	if c, err := evm.eofContainer(common.Hash{}, code, &eofInstructionSet); err != nil || c == nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if evm.eofContainers.Len() != 1 {
+		t.Fatalf("expected 1 container kept, got %d", evm.eofContainers.Len())
+	}
+	// invalid code isn't kept
+	invalid := newTestContainer(nil, []byte{byte(POP), byte(STOP)}).MarshalBinary()
+	if _, err := evm.eofContainer(crypto.Keccak256Hash(invalid), invalid, &eofInstructionSet); !errors.Is(err, ErrEOFStackUnderflow) {
+		t.Fatalf("expected %v, got %v", ErrEOFStackUnderflow, err)
+	}
+	if evm.eofContainers.Len() != 1 {
+		t.Fatalf("expected 1 container kept, got %d", evm.eofContainers.Len())
+	}
+}
@@ -0,0 +1,346 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrUndefinedInstruction        = errors.New("undefined instruction")
+	ErrTruncatedImmediate          = errors.New("truncated immediate")
+	ErrInvalidSectionArgument      = errors.New("invalid section argument")
+	ErrInvalidCallArgument         = errors.New("callf into non-returning section")
+	ErrInvalidDataloadNArgument    = errors.New("invalid dataloadN argument")
+	ErrInvalidJumpDest             = errors.New("invalid jump destination")
+	ErrInvalidBackwardJump         = errors.New("invalid backward jump")
+	ErrInvalidOutputs              = errors.New("invalid number of outputs")
+	ErrInvalidMaxStackHeight       = errors.New("invalid max stack height")
+	ErrInvalidCodeTermination      = errors.New("invalid code termination")
+	ErrEOFStackUnderflow           = errors.New("stack underflow")
+	ErrEOFStackOverflow            = errors.New("stack overflow")
+	ErrUnreachableCode             = errors.New("unreachable code")
+	ErrInvalidNonReturning         = errors.New("invalid non-returning flag")
+	ErrIncompatibleContainerKind   = errors.New("incompatible container kind")
+	ErrUnreferencedSubcontainer    = errors.New("unreferenced subcontainer")
+	ErrAmbiguousContainerReference = errors.New("subcontainer referenced by both EOFCREATE and RETURNCONTRACT")
+)
+
+// eofImmediates holds the size of the immediate arguments of the instructions
+// of the EOF instruction set, RJUMPV has a variable size.
+var eofImmediates = func() (immediates [256]int) {
+	for op := PUSH1; op <= PUSH32; op++ {
+		immediates[op] = int(op-PUSH1) + 1
+	}
+	immediates[RJUMP], immediates[RJUMPI] = 2, 2
+	immediates[CALLF], immediates[JUMPF] = 2, 2
+	immediates[DATALOADN] = 2
+	immediates[DUPN], immediates[SWAPN], immediates[EXCHANGE] = 1, 1, 1
+	immediates[EOFCREATE], immediates[RETURNCONTRACT] = 1, 1
+	return immediates
+}()
+
+// isTerminal reports whether the instruction ends the execution of its code
+// section. Code sections have to end with one of them or with RJUMP.
+func isTerminal(op OpCode) bool {
+	switch op {
+	case STOP, RETURN, REVERT, INVALID, RETF, JUMPF, RETURNCONTRACT:
+		return true
+	}
+	return false
+}
+
+// subContainer references
+const (
+	refEOFCreate = 1 << iota
+	refReturnContract
+)
+
+// ValidateCode validates the code sections of the container and of its
+// subcontainers against the given instruction set. An initcode container can
+// end its execution with RETURNCONTRACT only, a runtime container can't use it.
+func (c *Container) ValidateCode(jt *JumpTable, isInitcode bool) error {
+	refs := make([]int, len(c.subContainers))
+	visited := make([]bool, len(c.codeSections))
+	visited[0] = true
+	for queue := []int{0}; len(queue) > 0; queue = queue[1:] {
+		calls, err := c.validateSection(queue[0], jt, isInitcode, refs)
+		if err != nil {
+			return fmt.Errorf("code section %d: %w", queue[0], err)
+		}
+		for _, section := range calls {
+			if !visited[section] {
+				visited[section] = true
+				queue = append(queue, section)
+			}
+		}
+	}
+	for i, v := range visited {
+		if !v {
+			return fmt.Errorf("%w: code section %d", ErrUnreachableCode, i)
+		}
+	}
+	for i, sub := range c.subContainers {
+		var err error
+		switch refs[i] {
+		case refEOFCreate:
+			if sub.dataSize > len(sub.data) {
+				err = ErrTruncatedData
+			} else {
+				err = sub.ValidateCode(jt, true)
+			}
+		case refReturnContract:
+			err = sub.ValidateCode(jt, false)
+		case refEOFCreate | refReturnContract:
+			err = ErrAmbiguousContainerReference
+		default:
+			err = ErrUnreferencedSubcontainer
+		}
+		if err != nil {
+			return fmt.Errorf("container section %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateSection validates a code section and returns the code sections it
+// calls or jumps to. The subcontainers it references are recorded in refs.
+func (c *Container) validateSection(section int, jt *JumpTable, isInitcode bool, refs []int) ([]int, error) {
+	var (
+		code       = c.codeSections[section]
+		meta       = c.types[section]
+		calls      []int
+		returns    bool                      // whether the section has RETF or a JUMPF to a returning section
+		boundaries = make([]bool, len(code)) // instruction starts
+		jumps      []int                     // positions of the relative jumps to check
+		op         OpCode
+	)
+	for pos := 0; pos < len(code); {
+		op = OpCode(code[pos])
+		if jt[op].undefined {
+			return nil, fmt.Errorf("%w: %v at %d", ErrUndefinedInstruction, op, pos)
+		}
+		boundaries[pos] = true
+		size := eofImmediates[op]
+		if op == RJUMPV {
+			if pos+1 >= len(code) {
+				return nil, fmt.Errorf("%w: %v at %d", ErrTruncatedImmediate, op, pos)
+			}
+			size = 1 + 2*(int(code[pos+1])+1)
+		}
+		if pos+1+size > len(code) {
+			return nil, fmt.Errorf("%w: %v at %d", ErrTruncatedImmediate, op, pos)
+		}
+		imm := code[pos+1 : pos+1+size]
+		switch op {
+		case RJUMP, RJUMPI, RJUMPV:
+			jumps = append(jumps, pos)
+		case CALLF:
+			target := int(binary.BigEndian.Uint16(imm))
+			if target >= len(c.types) {
+				return nil, fmt.Errorf("%w: %v %d at %d", ErrInvalidSectionArgument, op, target, pos)
+			}
+			if !c.types[target].returning() {
+				return nil, fmt.Errorf("%w: %d at %d", ErrInvalidCallArgument, target, pos)
+			}
+			calls = append(calls, target)
+		case RETF:
+			if !meta.returning() {
+				return nil, fmt.Errorf("%w: RETF at %d", ErrInvalidNonReturning, pos)
+			}
+			returns = true
+		case JUMPF:
+			target := int(binary.BigEndian.Uint16(imm))
+			if target >= len(c.types) {
+				return nil, fmt.Errorf("%w: %v %d at %d", ErrInvalidSectionArgument, op, target, pos)
+			}
+			if c.types[target].returning() {
+				if !meta.returning() {
+					return nil, fmt.Errorf("%w: JUMPF to returning section %d at %d", ErrInvalidNonReturning, target, pos)
+				}
+				if c.types[target].outputs > meta.outputs {
+					return nil, fmt.Errorf("%w: JUMPF to section %d at %d", ErrInvalidOutputs, target, pos)
+				}
+				returns = true
+			}
+			calls = append(calls, target)
+		case DATALOADN:
+			if offset := int(binary.BigEndian.Uint16(imm)); offset+32 > c.dataSize {
+				return nil, fmt.Errorf("%w: %d at %d", ErrInvalidDataloadNArgument, offset, pos)
+			}
+		case EOFCREATE, RETURNCONTRACT:
+			index := int(imm[0])
+			if index >= len(c.subContainers) {
+				return nil, fmt.Errorf("%w: %v %d at %d", ErrInvalidSectionArgument, op, index, pos)
+			}
+			if op == RETURNCONTRACT {
+				if !isInitcode {
+					return nil, fmt.Errorf("%w: RETURNCONTRACT at %d", ErrIncompatibleContainerKind, pos)
+				}
+				refs[index] |= refReturnContract
+			} else {
+				refs[index] |= refEOFCreate
+			}
+		case RETURN, STOP:
+			if isInitcode {
+				return nil, fmt.Errorf("%w: %v at %d", ErrIncompatibleContainerKind, op, pos)
+			}
+		}
+		pos += 1 + size
+	}
+	if !isTerminal(op) && op != RJUMP {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCodeTermination, op)
+	}
+	if meta.returning() && !returns {
+		return nil, fmt.Errorf("%w: returning section without RETF", ErrInvalidNonReturning)
+	}
+	for _, pos := range jumps {
+		for _, dest := range relativeJumpTargets(code, pos) {
+			if dest < 0 || dest >= len(code) || !boundaries[dest] {
+				return nil, fmt.Errorf("%w: %v at %d to %d", ErrInvalidJumpDest, OpCode(code[pos]), pos, dest)
+			}
+		}
+	}
+	return calls, c.validateStack(section, jt)
+}
+
+// relativeJumpTargets returns the destinations of the RJUMP, RJUMPI or RJUMPV
+// at pos, which are relative to the end of its immediates.
+func relativeJumpTargets(code []byte, pos int) []int {
+	switch OpCode(code[pos]) {
+	case RJUMP, RJUMPI:
+		return []int{pos + 3 + int(int16(binary.BigEndian.Uint16(code[pos+1:])))}
+	default: // RJUMPV
+		count := int(code[pos+1]) + 1
+		end := pos + 2 + 2*count
+		targets := make([]int, count)
+		for i := range targets {
+			targets[i] = end + int(int16(binary.BigEndian.Uint16(code[pos+2+2*i:])))
+		}
+		return targets
+	}
+}
+
+// stackBounds are the minimal and maximal stack heights at an instruction.
+type stackBounds struct {
+	min, max int
+}
+
+// validateStack checks in one forward pass that the instructions of a code
+// section, whose immediates were already validated, are all reachable and
+// neither underflow nor overflow the stack, and that the declared maximal
+// stack increase is exact (EIP-5450).
+func (c *Container) validateStack(section int, jt *JumpTable) error {
+	var (
+		code      = c.codeSections[section]
+		meta      = c.types[section]
+		heights   = make([]*stackBounds, len(code))
+		maxHeight = int(meta.inputs)
+	)
+	heights[0] = &stackBounds{min: int(meta.inputs), max: int(meta.inputs)}
+	for pos := 0; pos < len(code); {
+		op := OpCode(code[pos])
+		size := eofImmediates[op]
+		if op == RJUMPV {
+			size = 1 + 2*(int(code[pos+1])+1)
+		}
+		next := pos + 1 + size
+		cur := heights[pos]
+		if cur == nil {
+			return fmt.Errorf("%w: instruction at %d", ErrUnreachableCode, pos)
+		}
+
+		in, out := jt[op].numPop, jt[op].numPush
+		switch op {
+		case CALLF:
+			target := c.types[binary.BigEndian.Uint16(code[pos+1:])]
+			in, out = int(target.inputs), int(target.outputs)
+			if cur.max+int(target.maxStackIncrease) > maxStackHeight {
+				return fmt.Errorf("%w: CALLF at %d", ErrEOFStackOverflow, pos)
+			}
+		case RETF:
+			if cur.min != cur.max || cur.max != int(meta.outputs) {
+				return fmt.Errorf("%w: RETF at %d with stack height %d-%d", ErrInvalidOutputs, pos, cur.min, cur.max)
+			}
+		case JUMPF:
+			target := c.types[binary.BigEndian.Uint16(code[pos+1:])]
+			if cur.max+int(target.maxStackIncrease) > maxStackHeight {
+				return fmt.Errorf("%w: JUMPF at %d", ErrEOFStackOverflow, pos)
+			}
+			if target.returning() {
+				expected := int(meta.outputs) + int(target.inputs) - int(target.outputs)
+				if cur.min != cur.max || cur.max != expected {
+					return fmt.Errorf("%w: JUMPF at %d with stack height %d-%d", ErrInvalidOutputs, pos, cur.min, cur.max)
+				}
+			} else if cur.min < int(target.inputs) {
+				return fmt.Errorf("%w: JUMPF at %d", ErrEOFStackUnderflow, pos)
+			}
+		case DUPN:
+			in, out = int(code[pos+1])+1, int(code[pos+1])+2
+		case SWAPN:
+			in, out = int(code[pos+1])+2, int(code[pos+1])+2
+		case EXCHANGE:
+			n, m := int(code[pos+1]>>4)+1, int(code[pos+1]&0x0f)+1
+			in, out = n+m+1, n+m+1
+		}
+		if cur.min < in {
+			return fmt.Errorf("%w: %v at %d requires %d items, has %d", ErrEOFStackUnderflow, op, pos, in, cur.min)
+		}
+		after := stackBounds{min: cur.min - in + out, max: cur.max - in + out}
+		if after.max > maxHeight {
+			maxHeight = after.max
+		}
+		if maxHeight > maxStackHeight {
+			return fmt.Errorf("%w: %v at %d", ErrEOFStackOverflow, op, pos)
+		}
+
+		var successors []int
+		switch {
+		case op == RJUMP:
+			successors = relativeJumpTargets(code, pos)
+		case op == RJUMPI || op == RJUMPV:
+			successors = append(relativeJumpTargets(code, pos), next)
+		case !isTerminal(op):
+			successors = []int{next}
+		}
+		for _, dest := range successors {
+			if dest >= len(code) {
+				return fmt.Errorf("%w: %v at %d", ErrInvalidCodeTermination, op, pos)
+			}
+			if dest <= pos {
+				// Backward jumps have to keep the stack height of their destination
+				if target := heights[dest]; target == nil || *target != after {
+					return fmt.Errorf("%w: %v at %d to %d", ErrInvalidBackwardJump, op, pos, dest)
+				}
+				continue
+			}
+			if target := heights[dest]; target == nil {
+				heights[dest] = &stackBounds{min: after.min, max: after.max}
+			} else {
+				target.min = min(target.min, after.min)
+				target.max = max(target.max, after.max)
+			}
+		}
+		pos = next
+	}
+	if maxHeight-int(meta.inputs) != int(meta.maxStackIncrease) {
+		return fmt.Errorf("%w: computed %d, declared %d", ErrInvalidMaxStackHeight, maxHeight-int(meta.inputs), meta.maxStackIncrease)
+	}
+	return nil
+}
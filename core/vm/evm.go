@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"sync/atomic"
 
+	"github.com/hashicorp/golang-lru/v2/simplelru"
 	"github.com/holiman/uint256"
 
 	"github.com/erigontech/erigon-lib/common/empty"
@@ -78,6 +79,8 @@ type EVM struct {
 	// available gas is calculated in gasCall* according to the 63/64 rule and later
 	// applied in opCall*.
 	callGasTemp uint64
+	// eofContainers are the validated EOF containers of deployed code by code hash
+	eofContainers *simplelru.LRU[common.Hash, *Container]
 }
 
 // NewEVM returns a new EVM. The returned EVM is not thread safe and should
@@ -326,6 +329,11 @@ func (evm *EVM) StaticCall(caller ContractRef, addr common.Address, input []byte
 type codeAndHash struct {
 	code []byte
 	hash common.Hash
+
+	// EOF initcode only: the validated initcontainer and the calldata it is
+	// executed with.
+	container *Container
+	input     []byte
 }
 
 func NewCodeAndHash(code []byte) *codeAndHash {
@@ -418,12 +426,19 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gasRemainin
 	// The contract is a scoped environment for this execution context only.
 	contract := NewContract(caller, address, value, gasRemaining, evm.config.SkipAnalysis, evm.config.JumpDestCache)
 	contract.SetCodeOptionalHash(&address, codeAndHash)
+	contract.Container = codeAndHash.container
 
 	if evm.config.NoRecursion && depth > 0 {
 		return nil, address, gasRemaining, nil
 	}
 
-	ret, err = evm.interpreter.Run(contract, nil, false)
+	if evm.chainRules.IsEOF && codeAndHash.container == nil && HasEOFMagic(codeAndHash.code) {
+		// EOF initcode is only run by EOFCREATE and by creation transactions
+		// starting with a valid initcontainer, it fails in CREATE and CREATE2.
+		err = ErrInvalidCode
+	} else {
+		ret, err = evm.interpreter.Run(contract, codeAndHash.input, false)
+	}
 
 	// EIP-170: Contract code size limit
 	if err == nil && evm.chainRules.IsSpuriousDragon && len(ret) > evm.maxCodeSize() {
@@ -434,8 +449,9 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gasRemainin
 		}
 	}
 
-	// Reject code starting with 0xEF if EIP-3541 is enabled.
-	if err == nil && evm.chainRules.IsLondon && len(ret) >= 1 && ret[0] == 0xEF {
+	// Reject code starting with 0xEF if EIP-3541 is enabled. EOF initcode
+	// returns the EOF container to deploy.
+	if err == nil && evm.chainRules.IsLondon && len(ret) >= 1 && ret[0] == 0xEF && codeAndHash.container == nil {
 		err = ErrInvalidCode
 	}
 	// If the contract creation ran successfully and no errors were returned,
@@ -483,7 +499,15 @@ func (evm *EVM) Create(caller ContractRef, code []byte, gasRemaining uint64, end
 		return nil, common.Address{}, 0, err
 	}
 	contractAddr = crypto.CreateAddress(caller.Address(), nonce)
-	return evm.create(caller, &codeAndHash{code: code}, gasRemaining, endowment, contractAddr, CREATE, true /* incrementNonce */, bailout)
+	initCode := &codeAndHash{code: code}
+	if in, ok := evm.interpreter.(*EVMInterpreter); ok && in.eofJt != nil && in.Depth() == 0 && HasEOFMagic(code) {
+		// EIP-7698: the data of a creation transaction may be an initcontainer
+		// followed by its calldata. An invalid one fails the creation.
+		if container, input, err := parseEOFInitcode(code, in.eofJt); err == nil {
+			initCode = &codeAndHash{code: code[:len(code)-len(input)], container: container, input: input}
+		}
+	}
+	return evm.create(caller, initCode, gasRemaining, endowment, contractAddr, CREATE, true /* incrementNonce */, bailout)
 }
 
 // Create2 creates a new contract using code as deployment code.
@@ -497,6 +521,17 @@ func (evm *EVM) Create2(caller ContractRef, code []byte, gasRemaining uint64, en
 	return evm.create(caller, codeAndHash, gasRemaining, endowment, contractAddr, CREATE2, true /* incrementNonce */, bailout)
 }
 
+// EOFCreate creates a new contract from an initcontainer of the caller, which
+// is run with the given input. Unlike Create2, the address is derived from the
+// caller and the salt only (EIP-7620).
+func (evm *EVM) EOFCreate(caller ContractRef, initContainer *Container, input []byte, gasRemaining uint64, endowment *uint256.Int, salt *uint256.Int) (ret []byte, contractAddr common.Address, leftOverGas uint64, err error) {
+	saltBytes := salt.Bytes32()
+	callerHash := common.BytesToHash(caller.Address().Bytes())
+	contractAddr = common.BytesToAddress(crypto.Keccak256([]byte{0xff}, callerHash[:], saltBytes[:])[12:])
+	initCode := &codeAndHash{code: initContainer.MarshalBinary(), container: initContainer, input: input}
+	return evm.create(caller, initCode, gasRemaining, endowment, contractAddr, EOFCREATE, true /* incrementNonce */, false)
+}
+
 // SysCreate is a special (system) contract creation methods for genesis constructors.
 // Unlike the normal Create & Create2, it doesn't increment caller's nonce.
 func (evm *EVM) SysCreate(caller ContractRef, code []byte, gas uint64, endowment *uint256.Int, contractAddr common.Address) (ret []byte, leftOverGas uint64, err error) {
@@ -99,6 +99,7 @@ var (
 	gasMcopy          = memoryCopierGas(2)
 	gasExtCodeCopy    = memoryCopierGas(3)
 	gasReturnDataCopy = memoryCopierGas(2)
+	gasDataCopy       = memoryCopierGas(2)
 )
 
 func gasSStore(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
@@ -289,6 +290,9 @@ var (
 	gasMStore8 = pureMemoryGascost
 	gasMStore  = pureMemoryGascost
 	gasCreate  = pureMemoryGascost
+
+	gasEOFCreate      = pureMemoryGascost
+	gasReturnContract = pureMemoryGascost
 )
 
 func gasCreate2(_ *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
@@ -538,3 +542,41 @@ func gasSelfdestruct(evm *EVM, contract *Contract, stack *Stack, mem *Memory, me
 	}
 	return gas, nil
 }
+
+// makeGasExtCall creates the dynamic gas function of EXTCALL, EXTDELEGATECALL
+// and EXTSTATICCALL: memory expansion, cold access of the target and, for the
+// value transfers of EXTCALL, the transfer and new account costs. The gas of
+// the callee is taken on execution, it's not part of the dynamic gas.
+func makeGasExtCall(transfersValue bool) gasFunc {
+	return func(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+		gas, err := memoryGasCost(mem, memorySize)
+		if err != nil {
+			return 0, err
+		}
+		// A target with non-zero high bytes fails on execution
+		if !isExtCallAddress(stack.Back(0)) {
+			return gas, nil
+		}
+		address := common.Address(stack.Back(0).Bytes20())
+		if evm.IntraBlockState().AddAddressToAccessList(address) {
+			gas += params.ColdAccountAccessCostEIP2929 - params.WarmStorageReadCostEIP2929
+		}
+		if transfersValue && !stack.Back(3).IsZero() {
+			gas += params.CallValueTransferGas
+			empty, err := evm.IntraBlockState().Empty(address)
+			if err != nil {
+				return 0, err
+			}
+			if empty {
+				gas += params.CallNewAccountGas
+			}
+		}
+		return gas, nil
+	}
+}
+
+var (
+	gasExtCall         = makeGasExtCall(true)
+	gasExtDelegateCall = makeGasExtCall(false)
+	gasExtStaticCall   = makeGasExtCall(false)
+)
@@ -524,6 +524,14 @@ func stReturnDataCopy(_ uint64, scope *ScopeContext) string {
 func opExtCodeSize(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
 	slot := scope.Stack.peek()
 	addr := slot.Bytes20()
+	if interpreter.eofJt != nil {
+		code, err := interpreter.evm.IntraBlockState().GetCode(addr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrIntraBlockStateFailed, err)
+		}
+		slot.SetUint64(uint64(len(legacyCodeView(code))))
+		return nil, nil
+	}
 	codeSize, err := interpreter.evm.IntraBlockState().GetCodeSize(addr)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrIntraBlockStateFailed, err)
@@ -569,6 +577,9 @@ func opExtCodeCopy(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrIntraBlockStateFailed, err)
 	}
+	if interpreter.eofJt != nil {
+		code = legacyCodeView(code)
+	}
 
 	codeCopy := getDataBig(code, &codeOffset, len64)
 	scope.Memory.Set(memOffset.Uint64(), len64, codeCopy)
@@ -628,6 +639,15 @@ func opExtCodeHash(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext)
 		if err != nil {
 			return nil, fmt.Errorf("%w: %w", ErrIntraBlockStateFailed, err)
 		}
+		if interpreter.eofJt != nil {
+			code, err := interpreter.evm.IntraBlockState().GetCode(address)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrIntraBlockStateFailed, err)
+			}
+			if HasEOFMagic(code) {
+				codeHash = eofMagicHash
+			}
+		}
 		slot.SetBytes(codeHash.Bytes())
 	}
 	return nil, nil
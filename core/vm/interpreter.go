@@ -175,6 +175,7 @@ func copyJumpTable(jt *JumpTable) *JumpTable {
 type EVMInterpreter struct {
 	*VM
 	jt    *JumpTable // EVM instruction table
+	eofJt *JumpTable // instruction table of EOF code, nil before EOF
 	depth int
 }
 
@@ -224,12 +225,18 @@ func NewEVMInterpreter(evm *EVM, cfg Config) *EVMInterpreter {
 		}
 	}
 
+	var eofJt *JumpTable
+	if evm.ChainRules().IsEOF {
+		eofJt = &eofInstructionSet
+	}
+
 	return &EVMInterpreter{
 		VM: &VM{
 			evm: evm,
 			cfg: cfg,
 		},
-		jt: jt,
+		jt:    jt,
+		eofJt: eofJt,
 	}
 }
 
@@ -245,6 +252,23 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 		return nil, nil
 	}
 
+	// EOF code is executed with its own instruction table, starting from its
+	// first code section. The containers of EOFCREATE and creation transactions
+	// are validated beforehand, the ones of accounts are validated on their
+	// first call and kept by code hash.
+	jt := in.jt
+	if in.eofJt != nil && (contract.Container != nil || HasEOFMagic(contract.Code)) {
+		if contract.Container == nil {
+			container, err := in.evm.eofContainer(contract.CodeHash, contract.Code, in.eofJt)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidCode, err)
+			}
+			contract.Container = container
+		}
+		contract.Code = contract.Container.codeSections[0]
+		jt = in.eofJt
+	}
+
 	// Reset the previous call's return data. It's unimportant to preserve the old buffer
 	// as every returning call will return new data anyway.
 	in.returnData = nil
@@ -320,7 +344,7 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 		// Get the operation from the jump table and validate the stack to ensure there are
 		// enough stack items available to perform the operation.
 		op = contract.GetOp(_pc)
-		operation := in.jt[op]
+		operation := jt[op]
 		cost = operation.constantGas // For tracing
 		// Validate stack
 		if sLen := locStack.len(); sLen < operation.numPop {
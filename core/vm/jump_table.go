@@ -52,6 +52,7 @@ type operation struct {
 	// memorySize returns the memory size required for the operation
 	memorySize memorySizeFunc
 	string     stringer
+	undefined  bool // rejected by the validation of EOF code
 }
 
 var (
@@ -70,6 +71,7 @@ var (
 	cancunInstructionSet           = newCancunInstructionSet()
 	pragueInstructionSet           = newPragueInstructionSet()
 	osakaInstructionSet            = newOsakaInstructionSet()
+	eofInstructionSet              = newEOFInstructionSet()
 )
 
 // JumpTable contains the EVM opcodes supported at a given fork.
@@ -298,6 +300,15 @@ func newOsakaInstructionSet() JumpTable {
 	return instructionSet
 }
 
+// newEOFInstructionSet returns the instructions of EOF code, which is executed
+// once EOF is enabled on top of osaka.
+func newEOFInstructionSet() JumpTable {
+	instructionSet := newOsakaInstructionSet()
+	enableEOF(&instructionSet)
+	validateAndFillMaxStack(&instructionSet)
+	return instructionSet
+}
+
 // newFrontierInstructionSet returns the frontier instructions
 // that can be executed during the frontier phase.
 func newFrontierInstructionSet() JumpTable {
@@ -1330,7 +1341,7 @@ func newFrontierInstructionSet() JumpTable {
 	// Fill all unassigned slots with opUndefined.
 	for i, entry := range tbl {
 		if entry == nil {
-			tbl[i] = &operation{execute: opUndefined}
+			tbl[i] = &operation{execute: opUndefined, undefined: true}
 		}
 	}
 
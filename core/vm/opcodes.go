@@ -207,6 +207,29 @@ const (
 	LOG4
 )
 
+// 0xd0 range - EOF data section ops.
+const (
+	DATALOAD OpCode = 0xd0 + iota
+	DATALOADN
+	DATASIZE
+	DATACOPY
+)
+
+// 0xe0 range - EOF control flow and stack ops.
+const (
+	RJUMP OpCode = 0xe0 + iota
+	RJUMPI
+	RJUMPV
+	CALLF
+	RETF
+	JUMPF
+	DUPN
+	SWAPN
+	EXCHANGE
+	EOFCREATE      OpCode = 0xec
+	RETURNCONTRACT OpCode = 0xee
+)
+
 // 0xf0 range - closures.
 const (
 	CREATE OpCode = 0xf0 + iota
@@ -215,10 +238,14 @@ const (
 	RETURN
 	DELEGATECALL
 	CREATE2
-	STATICCALL   OpCode = 0xfa
-	REVERT       OpCode = 0xfd
-	INVALID      OpCode = 0xfe
-	SELFDESTRUCT OpCode = 0xff
+	RETURNDATALOAD  OpCode = 0xf7
+	EXTCALL         OpCode = 0xf8
+	EXTDELEGATECALL OpCode = 0xf9
+	STATICCALL      OpCode = 0xfa
+	EXTSTATICCALL   OpCode = 0xfb
+	REVERT          OpCode = 0xfd
+	INVALID         OpCode = 0xfe
+	SELFDESTRUCT    OpCode = 0xff
 )
 
 // Since the opcodes aren't all in order we can't use a regular slice.
@@ -381,17 +408,40 @@ var opCodeToString = map[OpCode]string{
 	LOG3:   "LOG3",
 	LOG4:   "LOG4",
 
+	// 0xd0 range.
+	DATALOAD:  "DATALOAD",
+	DATALOADN: "DATALOADN",
+	DATASIZE:  "DATASIZE",
+	DATACOPY:  "DATACOPY",
+
+	// 0xe0 range.
+	RJUMP:          "RJUMP",
+	RJUMPI:         "RJUMPI",
+	RJUMPV:         "RJUMPV",
+	CALLF:          "CALLF",
+	RETF:           "RETF",
+	JUMPF:          "JUMPF",
+	DUPN:           "DUPN",
+	SWAPN:          "SWAPN",
+	EXCHANGE:       "EXCHANGE",
+	EOFCREATE:      "EOFCREATE",
+	RETURNCONTRACT: "RETURNCONTRACT",
+
 	// 0xf0 range.
-	CREATE:       "CREATE",
-	CALL:         "CALL",
-	RETURN:       "RETURN",
-	CALLCODE:     "CALLCODE",
-	DELEGATECALL: "DELEGATECALL",
-	CREATE2:      "CREATE2",
-	STATICCALL:   "STATICCALL",
-	REVERT:       "REVERT",
-	INVALID:      "INVALID",
-	SELFDESTRUCT: "SELFDESTRUCT",
+	CREATE:          "CREATE",
+	CALL:            "CALL",
+	RETURN:          "RETURN",
+	CALLCODE:        "CALLCODE",
+	DELEGATECALL:    "DELEGATECALL",
+	CREATE2:         "CREATE2",
+	RETURNDATALOAD:  "RETURNDATALOAD",
+	EXTCALL:         "EXTCALL",
+	EXTDELEGATECALL: "EXTDELEGATECALL",
+	STATICCALL:      "STATICCALL",
+	EXTSTATICCALL:   "EXTSTATICCALL",
+	REVERT:          "REVERT",
+	INVALID:         "INVALID",
+	SELFDESTRUCT:    "SELFDESTRUCT",
 }
 
 func (op OpCode) String() string {
@@ -404,156 +454,175 @@ func (op OpCode) String() string {
 }
 
 var stringToOp = map[string]OpCode{
-	"STOP":           STOP,
-	"ADD":            ADD,
-	"MUL":            MUL,
-	"SUB":            SUB,
-	"DIV":            DIV,
-	"SDIV":           SDIV,
-	"MOD":            MOD,
-	"SMOD":           SMOD,
-	"EXP":            EXP,
-	"NOT":            NOT,
-	"LT":             LT,
-	"GT":             GT,
-	"SLT":            SLT,
-	"SGT":            SGT,
-	"EQ":             EQ,
-	"ISZERO":         ISZERO,
-	"SIGNEXTEND":     SIGNEXTEND,
-	"AND":            AND,
-	"OR":             OR,
-	"XOR":            XOR,
-	"BYTE":           BYTE,
-	"SHL":            SHL,
-	"SHR":            SHR,
-	"SAR":            SAR,
-	"CLZ":            CLZ,
-	"ADDMOD":         ADDMOD,
-	"MULMOD":         MULMOD,
-	"KECCAK256":      KECCAK256,
-	"ADDRESS":        ADDRESS,
-	"BALANCE":        BALANCE,
-	"ORIGIN":         ORIGIN,
-	"CALLER":         CALLER,
-	"CALLVALUE":      CALLVALUE,
-	"CALLDATALOAD":   CALLDATALOAD,
-	"CALLDATASIZE":   CALLDATASIZE,
-	"CALLDATACOPY":   CALLDATACOPY,
-	"CHAINID":        CHAINID,
-	"BASEFEE":        BASEFEE,
-	"BLOBHASH":       BLOBHASH,
-	"BLOBBASEFEE":    BLOBBASEFEE,
-	"DELEGATECALL":   DELEGATECALL,
-	"STATICCALL":     STATICCALL,
-	"CODESIZE":       CODESIZE,
-	"CODECOPY":       CODECOPY,
-	"GASPRICE":       GASPRICE,
-	"EXTCODESIZE":    EXTCODESIZE,
-	"EXTCODECOPY":    EXTCODECOPY,
-	"RETURNDATASIZE": RETURNDATASIZE,
-	"RETURNDATACOPY": RETURNDATACOPY,
-	"EXTCODEHASH":    EXTCODEHASH,
-	"BLOCKHASH":      BLOCKHASH,
-	"COINBASE":       COINBASE,
-	"TIMESTAMP":      TIMESTAMP,
-	"NUMBER":         NUMBER,
-	"DIFFICULTY":     DIFFICULTY,
-	"GASLIMIT":       GASLIMIT,
-	"SELFBALANCE":    SELFBALANCE,
-	"POP":            POP,
-	"MLOAD":          MLOAD,
-	"MSTORE":         MSTORE,
-	"MSTORE8":        MSTORE8,
-	"SLOAD":          SLOAD,
-	"SSTORE":         SSTORE,
-	"JUMP":           JUMP,
-	"JUMPI":          JUMPI,
-	"PC":             PC,
-	"MSIZE":          MSIZE,
-	"GAS":            GAS,
-	"JUMPDEST":       JUMPDEST,
-	"TLOAD":          TLOAD,
-	"TSTORE":         TSTORE,
-	"MCOPY":          MCOPY,
-	"PUSH0":          PUSH0,
-	"PUSH1":          PUSH1,
-	"PUSH2":          PUSH2,
-	"PUSH3":          PUSH3,
-	"PUSH4":          PUSH4,
-	"PUSH5":          PUSH5,
-	"PUSH6":          PUSH6,
-	"PUSH7":          PUSH7,
-	"PUSH8":          PUSH8,
-	"PUSH9":          PUSH9,
-	"PUSH10":         PUSH10,
-	"PUSH11":         PUSH11,
-	"PUSH12":         PUSH12,
-	"PUSH13":         PUSH13,
-	"PUSH14":         PUSH14,
-	"PUSH15":         PUSH15,
-	"PUSH16":         PUSH16,
-	"PUSH17":         PUSH17,
-	"PUSH18":         PUSH18,
-	"PUSH19":         PUSH19,
-	"PUSH20":         PUSH20,
-	"PUSH21":         PUSH21,
-	"PUSH22":         PUSH22,
-	"PUSH23":         PUSH23,
-	"PUSH24":         PUSH24,
-	"PUSH25":         PUSH25,
-	"PUSH26":         PUSH26,
-	"PUSH27":         PUSH27,
-	"PUSH28":         PUSH28,
-	"PUSH29":         PUSH29,
-	"PUSH30":         PUSH30,
-	"PUSH31":         PUSH31,
-	"PUSH32":         PUSH32,
-	"DUP1":           DUP1,
-	"DUP2":           DUP2,
-	"DUP3":           DUP3,
-	"DUP4":           DUP4,
-	"DUP5":           DUP5,
-	"DUP6":           DUP6,
-	"DUP7":           DUP7,
-	"DUP8":           DUP8,
-	"DUP9":           DUP9,
-	"DUP10":          DUP10,
-	"DUP11":          DUP11,
-	"DUP12":          DUP12,
-	"DUP13":          DUP13,
-	"DUP14":          DUP14,
-	"DUP15":          DUP15,
-	"DUP16":          DUP16,
-	"SWAP1":          SWAP1,
-	"SWAP2":          SWAP2,
-	"SWAP3":          SWAP3,
-	"SWAP4":          SWAP4,
-	"SWAP5":          SWAP5,
-	"SWAP6":          SWAP6,
-	"SWAP7":          SWAP7,
-	"SWAP8":          SWAP8,
-	"SWAP9":          SWAP9,
-	"SWAP10":         SWAP10,
-	"SWAP11":         SWAP11,
-	"SWAP12":         SWAP12,
-	"SWAP13":         SWAP13,
-	"SWAP14":         SWAP14,
-	"SWAP15":         SWAP15,
-	"SWAP16":         SWAP16,
-	"LOG0":           LOG0,
-	"LOG1":           LOG1,
-	"LOG2":           LOG2,
-	"LOG3":           LOG3,
-	"LOG4":           LOG4,
-	"CREATE":         CREATE,
-	"CREATE2":        CREATE2,
-	"CALL":           CALL,
-	"RETURN":         RETURN,
-	"CALLCODE":       CALLCODE,
-	"REVERT":         REVERT,
-	"INVALID":        INVALID,
-	"SELFDESTRUCT":   SELFDESTRUCT,
+	"STOP":            STOP,
+	"ADD":             ADD,
+	"MUL":             MUL,
+	"SUB":             SUB,
+	"DIV":             DIV,
+	"SDIV":            SDIV,
+	"MOD":             MOD,
+	"SMOD":            SMOD,
+	"EXP":             EXP,
+	"NOT":             NOT,
+	"LT":              LT,
+	"GT":              GT,
+	"SLT":             SLT,
+	"SGT":             SGT,
+	"EQ":              EQ,
+	"ISZERO":          ISZERO,
+	"SIGNEXTEND":      SIGNEXTEND,
+	"AND":             AND,
+	"OR":              OR,
+	"XOR":             XOR,
+	"BYTE":            BYTE,
+	"SHL":             SHL,
+	"SHR":             SHR,
+	"SAR":             SAR,
+	"CLZ":             CLZ,
+	"ADDMOD":          ADDMOD,
+	"MULMOD":          MULMOD,
+	"KECCAK256":       KECCAK256,
+	"ADDRESS":         ADDRESS,
+	"BALANCE":         BALANCE,
+	"ORIGIN":          ORIGIN,
+	"CALLER":          CALLER,
+	"CALLVALUE":       CALLVALUE,
+	"CALLDATALOAD":    CALLDATALOAD,
+	"CALLDATASIZE":    CALLDATASIZE,
+	"CALLDATACOPY":    CALLDATACOPY,
+	"CHAINID":         CHAINID,
+	"BASEFEE":         BASEFEE,
+	"BLOBHASH":        BLOBHASH,
+	"BLOBBASEFEE":     BLOBBASEFEE,
+	"DELEGATECALL":    DELEGATECALL,
+	"STATICCALL":      STATICCALL,
+	"CODESIZE":        CODESIZE,
+	"CODECOPY":        CODECOPY,
+	"GASPRICE":        GASPRICE,
+	"EXTCODESIZE":     EXTCODESIZE,
+	"EXTCODECOPY":     EXTCODECOPY,
+	"RETURNDATASIZE":  RETURNDATASIZE,
+	"RETURNDATACOPY":  RETURNDATACOPY,
+	"EXTCODEHASH":     EXTCODEHASH,
+	"BLOCKHASH":       BLOCKHASH,
+	"COINBASE":        COINBASE,
+	"TIMESTAMP":       TIMESTAMP,
+	"NUMBER":          NUMBER,
+	"DIFFICULTY":      DIFFICULTY,
+	"GASLIMIT":        GASLIMIT,
+	"SELFBALANCE":     SELFBALANCE,
+	"POP":             POP,
+	"MLOAD":           MLOAD,
+	"MSTORE":          MSTORE,
+	"MSTORE8":         MSTORE8,
+	"SLOAD":           SLOAD,
+	"SSTORE":          SSTORE,
+	"JUMP":            JUMP,
+	"JUMPI":           JUMPI,
+	"PC":              PC,
+	"MSIZE":           MSIZE,
+	"GAS":             GAS,
+	"JUMPDEST":        JUMPDEST,
+	"TLOAD":           TLOAD,
+	"TSTORE":          TSTORE,
+	"MCOPY":           MCOPY,
+	"PUSH0":           PUSH0,
+	"PUSH1":           PUSH1,
+	"PUSH2":           PUSH2,
+	"PUSH3":           PUSH3,
+	"PUSH4":           PUSH4,
+	"PUSH5":           PUSH5,
+	"PUSH6":           PUSH6,
+	"PUSH7":           PUSH7,
+	"PUSH8":           PUSH8,
+	"PUSH9":           PUSH9,
+	"PUSH10":          PUSH10,
+	"PUSH11":          PUSH11,
+	"PUSH12":          PUSH12,
+	"PUSH13":          PUSH13,
+	"PUSH14":          PUSH14,
+	"PUSH15":          PUSH15,
+	"PUSH16":          PUSH16,
+	"PUSH17":          PUSH17,
+	"PUSH18":          PUSH18,
+	"PUSH19":          PUSH19,
+	"PUSH20":          PUSH20,
+	"PUSH21":          PUSH21,
+	"PUSH22":          PUSH22,
+	"PUSH23":          PUSH23,
+	"PUSH24":          PUSH24,
+	"PUSH25":          PUSH25,
+	"PUSH26":          PUSH26,
+	"PUSH27":          PUSH27,
+	"PUSH28":          PUSH28,
+	"PUSH29":          PUSH29,
+	"PUSH30":          PUSH30,
+	"PUSH31":          PUSH31,
+	"PUSH32":          PUSH32,
+	"DUP1":            DUP1,
+	"DUP2":            DUP2,
+	"DUP3":            DUP3,
+	"DUP4":            DUP4,
+	"DUP5":            DUP5,
+	"DUP6":            DUP6,
+	"DUP7":            DUP7,
+	"DUP8":            DUP8,
+	"DUP9":            DUP9,
+	"DUP10":           DUP10,
+	"DUP11":           DUP11,
+	"DUP12":           DUP12,
+	"DUP13":           DUP13,
+	"DUP14":           DUP14,
+	"DUP15":           DUP15,
+	"DUP16":           DUP16,
+	"SWAP1":           SWAP1,
+	"SWAP2":           SWAP2,
+	"SWAP3":           SWAP3,
+	"SWAP4":           SWAP4,
+	"SWAP5":           SWAP5,
+	"SWAP6":           SWAP6,
+	"SWAP7":           SWAP7,
+	"SWAP8":           SWAP8,
+	"SWAP9":           SWAP9,
+	"SWAP10":          SWAP10,
+	"SWAP11":          SWAP11,
+	"SWAP12":          SWAP12,
+	"SWAP13":          SWAP13,
+	"SWAP14":          SWAP14,
+	"SWAP15":          SWAP15,
+	"SWAP16":          SWAP16,
+	"LOG0":            LOG0,
+	"LOG1":            LOG1,
+	"LOG2":            LOG2,
+	"LOG3":            LOG3,
+	"LOG4":            LOG4,
+	"DATALOAD":        DATALOAD,
+	"DATALOADN":       DATALOADN,
+	"DATASIZE":        DATASIZE,
+	"DATACOPY":        DATACOPY,
+	"RJUMP":           RJUMP,
+	"RJUMPI":          RJUMPI,
+	"RJUMPV":          RJUMPV,
+	"CALLF":           CALLF,
+	"RETF":            RETF,
+	"JUMPF":           JUMPF,
+	"DUPN":            DUPN,
+	"SWAPN":           SWAPN,
+	"EXCHANGE":        EXCHANGE,
+	"EOFCREATE":       EOFCREATE,
+	"RETURNCONTRACT":  RETURNCONTRACT,
+	"CREATE":          CREATE,
+	"CREATE2":         CREATE2,
+	"CALL":            CALL,
+	"RETURN":          RETURN,
+	"RETURNDATALOAD":  RETURNDATALOAD,
+	"EXTCALL":         EXTCALL,
+	"EXTDELEGATECALL": EXTDELEGATECALL,
+	"EXTSTATICCALL":   EXTSTATICCALL,
+	"CALLCODE":        CALLCODE,
+	"REVERT":          REVERT,
+	"INVALID":         INVALID,
+	"SELFDESTRUCT":    SELFDESTRUCT,
 }
 
 // StringToOp finds the opcode whose name is stored in `str`.
@@ -152,6 +152,26 @@ func TestExecute(t *testing.T) {
 	}
 }
 
+func TestExecuteEOF(t *testing.T) {
+	t.Parallel()
+	// Section 0 calls section 1, which stores 10 in memory, and returns it.
+	code := common.FromHex("ef0001" + "010008" + "020002" + "0008" + "0006" + "ff0000" + "00" +
+		"00800002" + "00000002" +
+		"e30001" + "6020" + "6000" + "f3" +
+		"600a" + "6000" + "52" + "e4")
+
+	cfg := new(Config)
+	setDefaults(cfg)
+	cfg.ChainConfig.EOFTime = new(big.Int)
+	ret, _, err := Execute(code, nil, cfg, t.TempDir())
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), new(big.Int).SetBytes(ret).Uint64())
+
+	// Before the fork the magic is an invalid instruction.
+	_, _, err = Execute(code, nil, nil, t.TempDir())
+	require.Error(t, err)
+}
+
 func TestCall(t *testing.T) {
 	t.Parallel()
 	_, tx, _ := NewTestTemporalDb(t)
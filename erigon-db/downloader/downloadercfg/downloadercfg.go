@@ -113,6 +113,9 @@ type NewCfgOpts struct {
 	UploadRateLimit          g.Option[rate.Limit]
 	DownloadRateLimit        g.Option[rate.Limit]
 	WebseedDownloadRateLimit g.Option[rate.Limit]
+	// Download only from webseeds (for example other node serving its files by WebSeedServer), when
+	// BitTorrent is not reachable: no peer connections, trackers and DHT.
+	WebseedsOnly bool
 }
 
 func New(
@@ -131,6 +134,12 @@ func New(
 	for value := range opts.DisableTrackers.Iter() {
 		torrentConfig.DisableTrackers = value
 	}
+	if opts.WebseedsOnly {
+		torrentConfig.DisableTrackers = true
+		torrentConfig.NoDHT = true
+		torrentConfig.DialForPeerConns = false
+		torrentConfig.AcceptPeerConnections = false
+	}
 
 	//torrentConfig.PieceHashersPerTorrent = runtime.NumCPU()
 	torrentConfig.DataDir = dirs.Snap // `DataDir` of torrent-client-lib is different from Erigon's `DataDir`. Just same naming.
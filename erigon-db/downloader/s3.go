@@ -0,0 +1,1009 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anacrolix/torrent/metainfo"
+	"github.com/c2h5oh/datasize"
+	"github.com/spaolacci/murmur3"
+	"golang.org/x/time/rate"
+
+	"github.com/erigontech/erigon-lib/common/dir"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/snaptype"
+)
+
+// S3 - native client of S3-compatible object storage (AWS, MinIO, R2, ...), uploads snapshots without rclone.
+// Objects are addressed path-style: <Endpoint>/<Bucket>/<Prefix>/<file>. Uploaded bucket with its manifest.txt
+// can be used as webseed by its public http url.
+//
+// Files bigger than part size are uploaded by multipart upload, which is resumed after restart: parts already
+// uploaded are skipped if their MD5 matches. Content of files with .torrent is checked against torrent piece
+// hashes while it is read for upload (and after download) - damaged local file is never published.
+
+const (
+	DefaultS3PartSize = 64 * datasize.MB
+	s3MaxParts        = 10_000
+	s3Retries         = 3
+
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3EmptyPayload    = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" // sha256 of empty body
+	s3TimeFormat      = "20060102T150405Z"
+)
+
+var ErrPieceHashMismatch = errors.New("piece hash mismatch")
+
+type S3Config struct {
+	Endpoint     string // http(s)://host[:port]
+	Region       string
+	Bucket       string
+	Prefix       string
+	AccessKey    string // empty - anonymous access
+	SecretKey    string
+	SessionToken string
+	PartSize     datasize.ByteSize // 0 - DefaultS3PartSize
+}
+
+func IsS3Location(location string) bool { return strings.HasPrefix(location, "s3://") }
+
+// ParseS3Location - parses `s3://bucket/prefix?endpoint=http://host:9000&region=eu-west-1`.
+// Credentials, region and endpoint (if not in location) are taken from standard AWS_* env variables.
+func ParseS3Location(location string) (S3Config, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return S3Config{}, err
+	}
+	if u.Scheme != "s3" || len(u.Host) == 0 {
+		return S3Config{}, fmt.Errorf("invalid s3 location: %s, expected s3://bucket/prefix", location)
+	}
+	cfg := S3Config{
+		Endpoint:     u.Query().Get("endpoint"),
+		Region:       u.Query().Get("region"),
+		Bucket:       u.Host,
+		Prefix:       strings.Trim(u.Path, "/"),
+		AccessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if len(cfg.Region) == 0 {
+		cfg.Region = os.Getenv("AWS_REGION")
+	}
+	if len(cfg.Endpoint) == 0 {
+		cfg.Endpoint = os.Getenv("AWS_ENDPOINT_URL_S3")
+	}
+	if len(cfg.Endpoint) == 0 {
+		cfg.Endpoint = os.Getenv("AWS_ENDPOINT_URL")
+	}
+	if partSize := u.Query().Get("part_size"); len(partSize) > 0 {
+		if err := cfg.PartSize.UnmarshalText([]byte(partSize)); err != nil {
+			return S3Config{}, fmt.Errorf("invalid s3 part_size: %w", err)
+		}
+	}
+	return cfg, nil
+}
+
+type S3Client struct {
+	cfg      S3Config
+	endpoint *url.URL
+	client   *http.Client
+	limiter  *rate.Limiter
+	logger   log.Logger
+}
+
+func NewS3Client(cfg S3Config, logger log.Logger) (*S3Client, error) {
+	if len(cfg.Bucket) == 0 {
+		return nil, errors.New("s3: bucket undefined")
+	}
+	if len(cfg.Region) == 0 {
+		cfg.Region = "us-east-1"
+	}
+	if len(cfg.Endpoint) == 0 {
+		cfg.Endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	if cfg.PartSize == 0 {
+		cfg.PartSize = DefaultS3PartSize
+	}
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("s3: invalid endpoint: %w", err)
+	}
+	cfg.Prefix = strings.Trim(cfg.Prefix, "/")
+	return &S3Client{
+		cfg:      cfg,
+		endpoint: endpoint,
+		client:   &http.Client{Transport: http.DefaultTransport},
+		limiter:  rate.NewLimiter(rate.Inf, int(datasize.MB)),
+		logger:   logger,
+	}, nil
+}
+
+func (c *S3Client) Root() string {
+	return c.endpoint.JoinPath(c.cfg.Bucket, c.cfg.Prefix).String()
+}
+
+func (c *S3Client) GetBwLimit() rate.Limit {
+	if limit := c.limiter.Limit(); limit != rate.Inf {
+		return limit
+	}
+
+	return 0
+}
+
+// SetBwLimit - limits upload and download speed, bytes/sec. 0 - unlimited
+func (c *S3Client) SetBwLimit(ctx context.Context, limit rate.Limit) {
+	if limit <= 0 {
+		limit = rate.Inf
+	}
+	if limit != c.limiter.Limit() {
+		c.logger.Trace("Setting s3 bw limit", "kbytes", int64(datasize.ByteSize(limit).KBytes()))
+		// burst - amount transferred without waiting: not more than 1 second of limit
+		c.limiter.SetBurst(int(max(min(limit, rate.Limit(datasize.MB)), 1)))
+		c.limiter.SetLimit(limit)
+	}
+}
+
+type throttledReader struct {
+	ctx     context.Context
+	r       io.Reader
+	limiter *rate.Limiter
+}
+
+func (t *throttledReader) Read(p []byte) (int, error) {
+	if burst := t.limiter.Burst(); len(p) > burst {
+		p = p[:burst]
+	}
+	n, err := t.r.Read(p)
+	if n > 0 {
+		if werr := t.limiter.WaitN(t.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+type S3Error struct {
+	StatusCode int
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e *S3Error) Error() string {
+	return fmt.Sprintf("s3: %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+func isS3NotFound(err error) bool {
+	var s3Err *S3Error
+	return errors.As(err, &s3Err) && s3Err.StatusCode == http.StatusNotFound
+}
+
+func (c *S3Client) key(file string) string {
+	return path.Join(c.cfg.Prefix, filepath.ToSlash(file))
+}
+
+// do - sends signed request, retrying connection errors and 5xx. body is re-read from start on retry.
+// Response with status other than 2xx is returned as *S3Error.
+func (c *S3Client) do(ctx context.Context, method, key string, query url.Values, header http.Header, body io.ReadSeeker, size int64) (*http.Response, error) {
+	var lastErr error
+	for attempt := 0; attempt < s3Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Duration(attempt) * time.Second):
+			}
+		}
+
+		req, err := c.newRequest(ctx, method, key, query, header, body, size)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return resp, nil
+		}
+
+		s3Err := &S3Error{StatusCode: resp.StatusCode, Code: resp.Status}
+		respBody, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		_ = xml.Unmarshal(respBody, s3Err)
+		lastErr = fmt.Errorf("%s %s: %w", method, key, s3Err)
+		if resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return nil, lastErr
+		}
+	}
+	return nil, lastErr
+}
+
+func (c *S3Client) newRequest(ctx context.Context, method, key string, query url.Values, header http.Header, body io.ReadSeeker, size int64) (*http.Request, error) {
+	u := *c.endpoint
+	u.Path = path.Join("/", u.Path, c.cfg.Bucket, key)
+	u.RawPath = s3Escape(u.Path, false)
+	u.RawQuery = s3CanonicalQuery(query)
+
+	var reqBody io.Reader
+	if body != nil {
+		if _, err := body.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		reqBody = &throttledReader{ctx, body, c.limiter}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reqBody)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.ContentLength = size
+		if size == 0 {
+			req.Body = http.NoBody
+		}
+	}
+
+	payloadHash := s3EmptyPayload
+	if body != nil {
+		payloadHash = s3UnsignedPayload
+	}
+	c.sign(req, payloadHash, time.Now())
+	return req, nil
+}
+
+// sign - AWS Signature Version 4: https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html
+func (c *S3Client) sign(req *http.Request, payloadHash string, t time.Time) {
+	if len(c.cfg.AccessKey) == 0 {
+		return
+	}
+
+	amzDate := t.UTC().Format(s3TimeFormat)
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+	if len(c.cfg.SessionToken) > 0 {
+		req.Header.Set("x-amz-security-token", c.cfg.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		k = strings.ToLower(k)
+		if strings.HasPrefix(k, "x-amz-") || k == "content-md5" || k == "content-type" || k == "range" {
+			headers[k] = strings.TrimSpace(strings.Join(v, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := amzDate[:8] + "/" + c.cfg.Region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+c.cfg.SecretKey), amzDate[:8])
+	key = hmacSHA256(key, c.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+c.cfg.AccessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3Escape - URI encoding of SigV4: everything except unreserved characters (and '/' in paths)
+func s3Escape(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if ('A' <= ch && ch <= 'Z') || ('a' <= ch && ch <= 'z') || ('0' <= ch && ch <= '9') ||
+			ch == '-' || ch == '_' || ch == '.' || ch == '~' || (ch == '/' && !encodeSlash) {
+			b.WriteByte(ch)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", ch)
+	}
+	return b.String()
+}
+
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values := slices.Clone(query[k])
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, s3Escape(k, true)+"="+s3Escape(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+type s3Object struct {
+	Key          string    `xml:"Key"`
+	Size         int64     `xml:"Size"`
+	LastModified time.Time `xml:"LastModified"`
+	ETag         string    `xml:"ETag"`
+}
+
+type s3ListBucketResult struct {
+	Contents              []s3Object `xml:"Contents"`
+	IsTruncated           bool       `xml:"IsTruncated"`
+	NextContinuationToken string     `xml:"NextContinuationToken"`
+}
+
+type s3InitiateMultipartUploadResult struct {
+	UploadId string `xml:"UploadId"`
+}
+
+type s3ListMultipartUploadsResult struct {
+	Uploads []struct {
+		Key       string    `xml:"Key"`
+		UploadId  string    `xml:"UploadId"`
+		Initiated time.Time `xml:"Initiated"`
+	} `xml:"Upload"`
+}
+
+type s3Part struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+	Size       int64  `xml:"Size,omitempty"`
+}
+
+type s3ListPartsResult struct {
+	Parts                []s3Part `xml:"Part"`
+	IsTruncated          bool     `xml:"IsTruncated"`
+	NextPartNumberMarker int      `xml:"NextPartNumberMarker"`
+}
+
+type s3CompleteMultipartUpload struct {
+	XMLName xml.Name `xml:"CompleteMultipartUpload"`
+	Parts   []s3Part `xml:"Part"`
+}
+
+func decodeS3Response(resp *http.Response, v any) error {
+	defer resp.Body.Close()
+	return xml.NewDecoder(resp.Body).Decode(v)
+}
+
+// ListObjects - objects under prefix, without sub-directories. Names are relative to prefix.
+func (c *S3Client) ListObjects(ctx context.Context) ([]s3Object, error) {
+	prefix := c.cfg.Prefix
+	if len(prefix) > 0 {
+		prefix += "/"
+	}
+
+	var objects []s3Object
+	query := url.Values{"list-type": {"2"}, "prefix": {prefix}, "delimiter": {"/"}}
+	for {
+		resp, err := c.do(ctx, http.MethodGet, "", query, nil, nil, 0)
+		if err != nil {
+			return nil, fmt.Errorf("can't get remote list: %w", err)
+		}
+		var result s3ListBucketResult
+		if err := decodeS3Response(resp, &result); err != nil {
+			return nil, fmt.Errorf("can't decode remote list: %w", err)
+		}
+		for _, o := range result.Contents {
+			o.Key = strings.TrimPrefix(o.Key, prefix)
+			if len(o.Key) > 0 {
+				objects = append(objects, o)
+			}
+		}
+		if !result.IsTruncated || len(result.NextContinuationToken) == 0 {
+			return objects, nil
+		}
+		query.Set("continuation-token", result.NextContinuationToken)
+	}
+}
+
+// Get - object content starting from offset
+func (c *S3Client) Get(ctx context.Context, file string, offset int64) (io.ReadCloser, error) {
+	var header http.Header
+	if offset > 0 {
+		header = http.Header{"Range": {fmt.Sprintf("bytes=%d-", offset)}}
+	}
+	resp, err := c.do(ctx, http.MethodGet, c.key(file), nil, header, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 && resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return nil, fmt.Errorf("s3: range request of %s not supported, status: %s", file, resp.Status)
+	}
+	return &readCloser{&throttledReader{ctx, resp.Body, c.limiter}, resp.Body}, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// UploadFile - uploads local file as `file` object. info - metainfo of its .torrent (nil - no piece verification).
+func (c *S3Client) UploadFile(ctx context.Context, localPath, file string, info *metainfo.Info) error {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	size := st.Size()
+
+	var pieces *pieceHasher
+	if info != nil {
+		if pieces, err = newPieceHasher(info, size); err != nil {
+			return fmt.Errorf("can't upload: %s: %w", file, err)
+		}
+	}
+
+	partSize := c.partSize(size)
+	if size <= partSize {
+		section := io.NewSectionReader(f, 0, size)
+		sum, err := hashPart(section, pieces)
+		if err == nil && pieces != nil {
+			err = pieces.Close()
+		}
+		if err != nil {
+			return fmt.Errorf("can't upload: %s: %w", file, err)
+		}
+		resp, err := c.do(ctx, http.MethodPut, c.key(file), nil, md5Header(sum), section, size)
+		if err != nil {
+			return fmt.Errorf("can't upload: %s: %w", file, err)
+		}
+		resp.Body.Close()
+		return nil
+	}
+
+	if err := c.uploadMultipart(ctx, f, size, partSize, c.key(file), pieces); err != nil {
+		return fmt.Errorf("can't upload: %s: %w", file, err)
+	}
+	return nil
+}
+
+// partSize - must be same for same file between restarts, to resume upload
+func (c *S3Client) partSize(size int64) int64 {
+	partSize := int64(c.cfg.PartSize)
+	if minSize := (size + s3MaxParts - 1) / s3MaxParts; partSize < minSize {
+		partSize = (minSize + int64(datasize.MB) - 1) / int64(datasize.MB) * int64(datasize.MB)
+	}
+	return partSize
+}
+
+func md5Header(sum []byte) http.Header {
+	return http.Header{"Content-Md5": {base64.StdEncoding.EncodeToString(sum)}}
+}
+
+// hashPart - md5 of part, and feeds part to piece hasher
+func hashPart(part *io.SectionReader, pieces *pieceHasher) ([]byte, error) {
+	h := md5.New()
+	w := io.Writer(h)
+	if pieces != nil {
+		w = io.MultiWriter(h, pieces)
+	}
+	if _, err := io.Copy(w, part); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+func (c *S3Client) uploadMultipart(ctx context.Context, f *os.File, size, partSize int64, key string, pieces *pieceHasher) error {
+	uploadID, uploaded, err := c.resumeMultipart(ctx, key)
+	if err != nil {
+		return err
+	}
+	if len(uploadID) == 0 {
+		resp, err := c.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, nil, nil, 0)
+		if err != nil {
+			return err
+		}
+		var result s3InitiateMultipartUploadResult
+		if err := decodeS3Response(resp, &result); err != nil {
+			return err
+		}
+		uploadID = result.UploadId
+	} else {
+		c.logger.Debug("[s3] resuming upload", "key", key, "parts", len(uploaded))
+	}
+
+	parts := make([]s3Part, 0, (size+partSize-1)/partSize)
+	for n, offset := 1, int64(0); offset < size; n, offset = n+1, offset+partSize {
+		part := io.NewSectionReader(f, offset, min(partSize, size-offset))
+		sum, err := hashPart(part, pieces)
+		if err != nil {
+			if errors.Is(err, ErrPieceHashMismatch) {
+				c.abortMultipart(ctx, key, uploadID)
+			}
+			return err
+		}
+
+		etag := hex.EncodeToString(sum)
+		if p, ok := uploaded[n]; !ok || p.Size != part.Size() || strings.Trim(p.ETag, `"`) != etag {
+			query := url.Values{"partNumber": {strconv.Itoa(n)}, "uploadId": {uploadID}}
+			resp, err := c.do(ctx, http.MethodPut, key, query, md5Header(sum), part, part.Size())
+			if err != nil {
+				return err
+			}
+			resp.Body.Close()
+			if respEtag := strings.Trim(resp.Header.Get("ETag"), `"`); len(respEtag) > 0 {
+				etag = respEtag
+			}
+		}
+		parts = append(parts, s3Part{PartNumber: n, ETag: `"` + etag + `"`})
+	}
+	if pieces != nil {
+		if err := pieces.Close(); err != nil {
+			c.abortMultipart(ctx, key, uploadID)
+			return err
+		}
+	}
+
+	body, err := xml.Marshal(s3CompleteMultipartUpload{Parts: parts})
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(ctx, http.MethodPost, key, url.Values{"uploadId": {uploadID}}, nil, bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return err
+	}
+	// complete may fail after 200 OK response: error is in body
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	var result struct {
+		XMLName xml.Name
+		S3Error
+	}
+	if err := xml.Unmarshal(respBody, &result); err == nil && result.XMLName.Local == "Error" {
+		result.StatusCode = resp.StatusCode
+		return fmt.Errorf("complete %s: %w", key, &result.S3Error)
+	}
+	return nil
+}
+
+// resumeMultipart - latest unfinished upload of key and its parts. Empty uploadID - nothing to resume.
+func (c *S3Client) resumeMultipart(ctx context.Context, key string) (uploadID string, parts map[int]s3Part, err error) {
+	resp, err := c.do(ctx, http.MethodGet, "", url.Values{"uploads": {""}, "prefix": {key}}, nil, nil, 0)
+	if err != nil {
+		return "", nil, err
+	}
+	var uploads s3ListMultipartUploadsResult
+	if err := decodeS3Response(resp, &uploads); err != nil {
+		return "", nil, err
+	}
+	var initiated time.Time
+	for _, u := range uploads.Uploads {
+		if u.Key == key && (len(uploadID) == 0 || u.Initiated.After(initiated)) {
+			uploadID, initiated = u.UploadId, u.Initiated
+		}
+	}
+	if len(uploadID) == 0 {
+		return "", nil, nil
+	}
+
+	parts = map[int]s3Part{}
+	query := url.Values{"uploadId": {uploadID}}
+	for {
+		resp, err := c.do(ctx, http.MethodGet, key, query, nil, nil, 0)
+		if err != nil {
+			if isS3NotFound(err) { // completed or aborted meanwhile
+				return "", nil, nil
+			}
+			return "", nil, err
+		}
+		var result s3ListPartsResult
+		if err := decodeS3Response(resp, &result); err != nil {
+			return "", nil, err
+		}
+		for _, p := range result.Parts {
+			parts[p.PartNumber] = p
+		}
+		if !result.IsTruncated {
+			return uploadID, parts, nil
+		}
+		query.Set("part-number-marker", strconv.Itoa(result.NextPartNumberMarker))
+	}
+}
+
+func (c *S3Client) abortMultipart(ctx context.Context, key, uploadID string) {
+	resp, err := c.do(ctx, http.MethodDelete, key, url.Values{"uploadId": {uploadID}}, nil, nil, 0)
+	if err != nil {
+		c.logger.Debug("[s3] abort upload", "key", key, "err", err)
+		return
+	}
+	resp.Body.Close()
+}
+
+// Verify - reads whole object and checks it against torrent piece hashes
+func (c *S3Client) Verify(ctx context.Context, file string, info *metainfo.Info) error {
+	r, err := c.Get(ctx, file, 0)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	pieces, err := newPieceHasher(info, info.TotalLength())
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	if _, err := io.Copy(pieces, r); err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	if err := pieces.Close(); err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	return nil
+}
+
+// pieceHasher - checks stream of file content against piece hashes of its torrent
+type pieceHasher struct {
+	info   *metainfo.Info
+	h      hash.Hash
+	piece  int
+	filled int64
+}
+
+func newPieceHasher(info *metainfo.Info, size int64) (*pieceHasher, error) {
+	if info.PieceLength <= 0 || len(info.Pieces)%sha1.Size != 0 {
+		return nil, errors.New("invalid torrent metainfo")
+	}
+	if info.TotalLength() != size {
+		return nil, fmt.Errorf("%w: size %d, torrent has %d", ErrPieceHashMismatch, size, info.TotalLength())
+	}
+	return &pieceHasher{info: info, h: sha1.New()}, nil
+}
+
+func (p *pieceHasher) Write(b []byte) (int, error) {
+	n := len(b)
+	for len(b) > 0 {
+		l := min(int64(len(b)), p.info.PieceLength-p.filled)
+		p.h.Write(b[:l])
+		p.filled += l
+		b = b[l:]
+		if p.filled == p.info.PieceLength {
+			if err := p.check(); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return n, nil
+}
+
+func (p *pieceHasher) check() error {
+	from := p.piece * sha1.Size
+	if from+sha1.Size > len(p.info.Pieces) || !bytes.Equal(p.h.Sum(nil), p.info.Pieces[from:from+sha1.Size]) {
+		return fmt.Errorf("%w: piece %d", ErrPieceHashMismatch, p.piece)
+	}
+	p.piece++
+	p.filled = 0
+	p.h.Reset()
+	return nil
+}
+
+// Close - checks last piece
+func (p *pieceHasher) Close() error {
+	if p.filled > 0 {
+		if err := p.check(); err != nil {
+			return err
+		}
+	}
+	if p.piece != p.info.NumPieces() {
+		return fmt.Errorf("%w: %d pieces of %d", ErrPieceHashMismatch, p.piece, p.info.NumPieces())
+	}
+	return nil
+}
+
+// UploadSession - remote copy of local snapshots dir: rclone remote (RCloneSession) or S3 bucket (S3Session)
+type UploadSession interface {
+	Upload(ctx context.Context, files ...string) error
+	Download(ctx context.Context, files ...string) error
+	Cat(ctx context.Context, file string) (io.Reader, error)
+	ReadRemoteDir(ctx context.Context, refresh bool) ([]fs.DirEntry, error)
+	SetBwLimit(ctx context.Context, limit rate.Limit)
+	GetBwLimit() rate.Limit
+	Stop()
+}
+
+var (
+	_ UploadSession = (*RCloneSession)(nil)
+	_ UploadSession = (*S3Session)(nil)
+)
+
+// S3Session - local snapshots dir and its copy in S3 bucket
+type S3Session struct {
+	*S3Client
+	localFs string
+}
+
+func (c *S3Client) NewSession(localFs string) *S3Session {
+	return &S3Session{S3Client: c, localFs: localFs}
+}
+
+func (c *S3Session) RemoteFsRoot() string {
+	return c.Root()
+}
+
+func (c *S3Session) LocalFsRoot() string {
+	return c.localFs
+}
+
+func (c *S3Session) Stop() {}
+
+func (c *S3Session) Label() string {
+	return strconv.FormatUint(murmur3.Sum64([]byte(c.localFs+"<->"+c.Root())), 36)
+}
+
+// torrentInfo - metainfo of local .torrent of file, nil if file has no .torrent
+func (c *S3Session) torrentInfo(file string) (*metainfo.Info, error) {
+	if filepath.Ext(file) == ".torrent" {
+		return nil, nil
+	}
+	mi, err := metainfo.LoadFromFile(filepath.Join(c.localFs, file+".torrent"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	info, err := mi.UnmarshalInfo()
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Upload - uploads files, .torrent of file is uploaded after the file: published .torrent means file is complete
+func (c *S3Session) Upload(ctx context.Context, files ...string) error {
+	files = slices.Clone(files)
+	slices.SortStableFunc(files, func(a, b string) int {
+		return boolCmp(filepath.Ext(a) == ".torrent", filepath.Ext(b) == ".torrent")
+	})
+
+	for _, file := range files {
+		localPath := filepath.Join(c.localFs, file)
+		localInfo, err := os.Stat(localPath)
+		if err != nil {
+			return fmt.Errorf("can't upload: %s: %w", file, err)
+		}
+		if !localInfo.Mode().IsRegular() || localInfo.Size() == 0 {
+			return fmt.Errorf("can't upload: %s: %s", file, "file is not uploadable")
+		}
+
+		info, err := c.torrentInfo(file)
+		if err != nil {
+			return fmt.Errorf("can't upload: %s: %w", file, err)
+		}
+		if err := c.UploadFile(ctx, localPath, file, info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Download - downloads files, partially downloaded files are resumed. Files are checked against their .torrent
+// (downloaded first if requested, or existing local one).
+func (c *S3Session) Download(ctx context.Context, files ...string) error {
+	files = slices.Clone(files)
+	slices.SortStableFunc(files, func(a, b string) int {
+		return boolCmp(filepath.Ext(b) == ".torrent", filepath.Ext(a) == ".torrent")
+	})
+
+	for _, file := range files {
+		if err := c.download(ctx, file); err != nil {
+			return fmt.Errorf("can't download: %s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+func (c *S3Session) download(ctx context.Context, file string) error {
+	localPath := filepath.Join(c.localFs, file)
+	tmpPath := localPath + ".tmp"
+
+	var offset int64
+	if st, err := os.Stat(tmpPath); err == nil {
+		offset = st.Size()
+	}
+
+	var s3Err *S3Error
+	r, err := c.Get(ctx, file, offset)
+	switch {
+	case err == nil:
+		defer r.Close()
+		if err := appendFile(tmpPath, r); err != nil {
+			return err
+		}
+	case errors.As(err, &s3Err) && s3Err.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// tmp file is complete, but wasn't renamed
+	default:
+		return err
+	}
+
+	info, err := c.torrentInfo(file)
+	if err != nil {
+		return err
+	}
+	if info != nil {
+		if err := VerifyFile(ctx, tmpPath, info, 0); err != nil {
+			_ = os.Remove(tmpPath)
+			return err
+		}
+	}
+	return os.Rename(tmpPath, localPath)
+}
+
+func appendFile(filePath string, r io.Reader) error {
+	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// VerifyFile - checks local file against piece hashes of its torrent. bytesPerSec limits read speed (0 - unlimited),
+// to not compete with node for IO. Returns ErrPieceHashMismatch if file is damaged.
+func VerifyFile(ctx context.Context, filePath string, info *metainfo.Info, bytesPerSec uint64) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	pieces, err := newPieceHasher(info, st.Size())
+	if err != nil {
+		return err
+	}
+	var r io.Reader = f
+	if bytesPerSec > 0 {
+		r = &throttledReader{ctx: ctx, r: f, limiter: rate.NewLimiter(rate.Limit(bytesPerSec), int(min(bytesPerSec, uint64(datasize.MB))))}
+	}
+	if _, err := io.Copy(pieces, r); err != nil {
+		return err
+	}
+	return pieces.Close()
+}
+
+func boolCmp(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
+
+func (c *S3Session) Cat(ctx context.Context, file string) (io.Reader, error) {
+	r, err := c.Get(ctx, file, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(content), nil
+}
+
+func (c *S3Session) ReadLocalDir(ctx context.Context) ([]fs.DirEntry, error) {
+	return dir.ReadDir(c.localFs)
+}
+
+func (c *S3Session) ReadRemoteDir(ctx context.Context, refresh bool) ([]fs.DirEntry, error) {
+	objects, err := c.ListObjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]fs.DirEntry, 0, len(objects))
+	for _, o := range objects {
+		if o.Size == 0 {
+			continue
+		}
+		localInfo, _ := os.Stat(filepath.Join(c.localFs, o.Key))
+		info := &rcloneInfo{
+			file:       o.Key,
+			localInfo:  localInfo,
+			remoteInfo: remoteInfo{Name: o.Key, Size: uint64(o.Size), ModTime: o.LastModified},
+		}
+		if snapInfo, isStateFile, ok := snaptype.ParseFileName(c.localFs, o.Key); ok && !isStateFile {
+			info.snapInfo = &snapInfo
+		}
+		entries = append(entries, &dirEntry{&fileInfo{info}})
+	}
+
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+
+	return entries, nil
+}
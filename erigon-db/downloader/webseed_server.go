@@ -0,0 +1,197 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anacrolix/torrent/metainfo"
+
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/snaptype"
+)
+
+// WebSeedServer - serves local snapshot files over plain HTTP in the layout of webseed buckets (see WebSeeds):
+// manifest.txt, files and their .torrent. Then other nodes can download from this node without BitTorrent:
+// --webseed=http://<addr>/
+//
+// Only complete files are served: files which have .torrent. Files are immutable, so ETag of file is infohash
+// of its torrent, and range requests are served by http.ServeContent.
+type WebSeedServer struct {
+	snapDir string
+	logger  log.Logger
+}
+
+const manifestFileName = "manifest.txt"
+
+func NewWebSeedServer(snapDir string, logger log.Logger) *WebSeedServer {
+	return &WebSeedServer{snapDir: snapDir, logger: logger}
+}
+
+// Manifest - content of manifest.txt, same as produced by `snapshots manifest update`:
+// sorted list of files and their .torrent, one per line
+func (s *WebSeedServer) Manifest() ([]byte, error) {
+	var files []string
+	err := filepath.WalkDir(s.snapDir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !snaptype.IsSeedableExtension(d.Name()) {
+			return nil
+		}
+		name, err := filepath.Rel(s.snapDir, filePath)
+		if err != nil {
+			return err
+		}
+		name = filepath.ToSlash(name)
+		if !IsSnapNameAllowed(name) {
+			return nil
+		}
+		if _, err := os.Stat(filePath + ".torrent"); err != nil {
+			return nil // not complete
+		}
+		files = append(files, name, name+".torrent")
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+
+	manifest := bytes.Buffer{}
+	for _, file := range files {
+		fmt.Fprintln(&manifest, file)
+	}
+	return manifest.Bytes(), nil
+}
+
+func (s *WebSeedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+
+	if name == manifestFileName {
+		manifest, err := s.Manifest()
+		if err != nil {
+			s.logger.Warn("[snapshots.webseed] manifest", "err", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		sum := sha1.Sum(manifest)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		http.ServeContent(w, r, manifestFileName, time.Time{}, bytes.NewReader(manifest))
+		return
+	}
+
+	etag, err := s.etag(name)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			s.logger.Debug("[snapshots.webseed] serve", "file", name, "err", err)
+		}
+		http.NotFound(w, r)
+		return
+	}
+
+	f, err := os.Open(filepath.Join(s.snapDir, filepath.FromSlash(name)))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil || !st.Mode().IsRegular() {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("Content-Type", "application/octet-stream")
+	http.ServeContent(w, r, path.Base(name), st.ModTime(), f)
+}
+
+// etag - of seedable file: infohash of its .torrent, of .torrent: hash of its content.
+// fs.ErrNotExist if name is not served.
+func (s *WebSeedServer) etag(name string) (string, error) {
+	file, isTorrent := strings.CutSuffix(name, ".torrent")
+	if !snaptype.IsSeedableExtension(file) || !IsSnapNameAllowed(file) || !filepath.IsLocal(filepath.FromSlash(file)) {
+		return "", fs.ErrNotExist
+	}
+
+	torrentPath := filepath.Join(s.snapDir, filepath.FromSlash(file)) + ".torrent"
+	if isTorrent {
+		content, err := os.ReadFile(torrentPath)
+		if err != nil {
+			return "", err
+		}
+		sum := sha1.Sum(content)
+		return `"` + hex.EncodeToString(sum[:]) + `"`, nil
+	}
+
+	mi, err := metainfo.LoadFromFile(torrentPath)
+	if err != nil {
+		return "", err
+	}
+	return `"` + mi.HashInfoBytes().HexString() + `"`, nil
+}
+
+// ServeWebSeed - serves snapshot files at addr until ctx is done
+func ServeWebSeed(ctx context.Context, addr, snapDir string, logger log.Logger) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("webseed server: %w", err)
+	}
+
+	srv := &http.Server{
+		Handler:           NewWebSeedServer(snapDir, logger),
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	logger.Info("[snapshots.webseed] serving snapshot files", "url", "http://"+listener.Addr().String()+"/")
+	if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("webseed server: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"context"
+	"crypto/rand"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/anacrolix/torrent/metainfo"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-db/downloader/downloadercfg"
+	"github.com/erigontech/erigon-lib/common/datadir"
+	"github.com/erigontech/erigon-lib/log/v3"
+)
+
+func writeServedFile(t *testing.T, snapDir, name string, size int, withTorrent bool) []byte {
+	content := make([]byte, size)
+	_, _ = rand.Read(content)
+	require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(snapDir, name)), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(snapDir, name), content, 0644))
+	if withTorrent {
+		_, err := BuildTorrentIfNeed(context.Background(), name, snapDir, NewAtomicTorrentFS(snapDir))
+		require.NoError(t, err)
+	}
+	return content
+}
+
+func TestWebSeedServer(t *testing.T) {
+	snapDir := filepath.Join(t.TempDir(), "snapshots")
+	content := writeServedFile(t, snapDir, "v1.0-000000-000500-headers.seg", 100_000, true)
+	writeServedFile(t, snapDir, "v1.0-000500-001000-headers.seg", 1000, false) // not complete
+	writeServedFile(t, snapDir, "domain/v1.0-accounts.0-32.kv", 1000, true)
+	writeServedFile(t, snapDir, "salt-blocks.txt", 10, false)
+	writeServedFile(t, filepath.Dir(snapDir), "v1.0-001000-001500-headers.seg", 1000, true) // outside of snapDir
+
+	srv := httptest.NewServer(NewWebSeedServer(snapDir, log.New()))
+	defer srv.Close()
+
+	get := func(name string, header http.Header) (*http.Response, []byte) {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/"+name, nil)
+		require.NoError(t, err)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, body
+	}
+
+	resp, manifest := get("manifest.txt", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "domain/v1.0-accounts.0-32.kv\n"+
+		"domain/v1.0-accounts.0-32.kv.torrent\n"+
+		"v1.0-000000-000500-headers.seg\n"+
+		"v1.0-000000-000500-headers.seg.torrent\n", string(manifest))
+	require.NotEmpty(t, resp.Header.Get("ETag"))
+
+	// range request
+	mi, err := metainfo.LoadFromFile(filepath.Join(snapDir, "v1.0-000000-000500-headers.seg.torrent"))
+	require.NoError(t, err)
+	etag := `"` + mi.HashInfoBytes().HexString() + `"`
+
+	resp, body := get("v1.0-000000-000500-headers.seg", http.Header{"Range": {"bytes=1000-1999"}})
+	require.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	require.Equal(t, content[1000:2000], body)
+	require.Equal(t, etag, resp.Header.Get("ETag"))
+	require.Equal(t, "bytes", resp.Header.Get("Accept-Ranges"))
+
+	resp, _ = get("v1.0-000000-000500-headers.seg", http.Header{"If-None-Match": {etag}})
+	require.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+	resp, body = get("v1.0-000000-000500-headers.seg", http.Header{"Range": {"bytes=10-19"}, "If-Range": {`"other"`}})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, content, body)
+
+	resp, body = get("domain/v1.0-accounts.0-32.kv.torrent", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	torrent, err := os.ReadFile(filepath.Join(snapDir, "domain", "v1.0-accounts.0-32.kv.torrent"))
+	require.NoError(t, err)
+	require.Equal(t, torrent, body)
+
+	for _, name := range []string{"v1.0-000500-001000-headers.seg", "salt-blocks.txt", "domain/", "..%2Fv1.0-001000-001500-headers.seg", "missing.seg"} {
+		resp, _ = get(name, nil)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode, name)
+	}
+
+	resp, err = http.Post(srv.URL+"/manifest.txt", "text/plain", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestWebSeedServerAsSoleSource(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fix me on win please")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	const name = "v1.0-000000-000500-headers.seg"
+	source := datadir.New(t.TempDir())
+	content := writeServedFile(t, source.Snap, name, 5*downloadercfg.DefaultPieceSize+1000, true)
+	mi, err := metainfo.LoadFromFile(filepath.Join(source.Snap, name+".torrent"))
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(NewWebSeedServer(source.Snap, log.New()))
+	defer srv.Close()
+
+	dirs := datadir.New(t.TempDir())
+	cfg, err := downloadercfg.New(ctx, dirs, "", log.LvlInfo, 0, 0, []string{srv.URL}, "testnet", false,
+		downloadercfg.NewCfgOpts{WebseedsOnly: true})
+	require.NoError(t, err)
+	require.False(t, cfg.ClientConfig.DialForPeerConns)
+	d, err := New(ctx, cfg, log.New(), log.LvlInfo)
+	require.NoError(t, err)
+	defer d.Close()
+
+	require.NoError(t, d.RequestSnapshot(mi.HashInfoBytes(), name))
+	tt, ok := d.torrentClient.Torrent(mi.HashInfoBytes())
+	require.True(t, ok)
+
+	select {
+	case <-tt.Complete().On():
+	case <-ctx.Done():
+		t.Fatal("download from webseed server not completed")
+	}
+
+	downloaded, err := os.ReadFile(filepath.Join(dirs.Snap, name))
+	require.NoError(t, err)
+	require.Equal(t, content, downloaded)
+}
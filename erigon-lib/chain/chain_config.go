@@ -75,6 +75,9 @@ type Config struct {
 	PragueTime   *big.Int `json:"pragueTime,omitempty"`
 	OsakaTime    *big.Int `json:"osakaTime,omitempty"`
 
+	// EIP-7692: EVM Object Format (EOFv1), not scheduled on any public network yet
+	EOFTime *big.Int `json:"eofTime,omitempty"`
+
 	// Optional EIP-4844 parameters (see also EIP-7691, EIP-7840, EIP-7892)
 	MinBlobGasPrice       *uint64                       `json:"minBlobGasPrice,omitempty"`
 	BlobSchedule          map[string]*params.BlobConfig `json:"blobSchedule,omitempty"`
@@ -341,6 +344,11 @@ func (c *Config) IsOsaka(time uint64) bool {
 	return isForked(c.OsakaTime, time)
 }
 
+// IsEOF returns whether time is either equal to the EOF fork time or greater.
+func (c *Config) IsEOF(time uint64) bool {
+	return isForked(c.EOFTime, time)
+}
+
 func (c *Config) GetBurntContract(num uint64) *common.Address {
 	if len(c.BurntContract) == 0 {
 		return nil
@@ -680,7 +688,7 @@ type Rules struct {
 	IsByzantium, IsConstantinople, IsPetersburg       bool
 	IsIstanbul, IsBerlin, IsLondon, IsShanghai        bool
 	IsCancun, IsNapoli, IsBhilai                      bool
-	IsPrague, IsOsaka                                 bool
+	IsPrague, IsOsaka, IsEOF                          bool
 	IsAura                                            bool
 }
 
@@ -708,6 +716,7 @@ func (c *Config) Rules(num uint64, time uint64) *Rules {
 		IsBhilai:           c.IsBhilai(num),
 		IsPrague:           c.IsPrague(time) || c.IsBhilai(num),
 		IsOsaka:            c.IsOsaka(time),
+		IsEOF:              c.IsEOF(time),
 		IsAura:             c.Aura != nil,
 	}
 }
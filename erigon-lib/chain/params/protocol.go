@@ -44,6 +44,9 @@ const (
 	LogDataGas   uint64 = 8    // Per byte in a LOG* operation's data.
 	CallStipend  uint64 = 2300 // Free gas given at beginning of call.
 
+	ExtCallMinRetainedGas uint64 = 5000 // Minimum gas retained by the caller of EXTCALL, EXTDELEGATECALL and EXTSTATICCALL (EIP-7069).
+	ExtCallMinCalleeGas   uint64 = 2300 // Minimum gas passed to the callee of EXTCALL, EXTDELEGATECALL and EXTSTATICCALL (EIP-7069).
+
 	Keccak256Gas     uint64 = 30 // Once per KECCAK256 operation.
 	Keccak256WordGas uint64 = 6  // Once per word of the KECCAK256 operation's data.
 	InitCodeWordGas  uint64 = 2  // Once per word of the init code when creating a contract.
@@ -0,0 +1,191 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package commitment
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/erigontech/erigon-lib/common"
+)
+
+// ErrNoBranch is returned by WalkLeaves and PrevLeaf when the branch the walk
+// starts from isn't stored, which happens when the trie (or storage trie) under
+// the prefix has less than two children.
+var ErrNoBranch = errors.New("commitment: no branch for prefix")
+
+// WalkLeaves calls fn with the plain keys of the leaves of the hex patricia
+// trie stored in the branches of ctx, in the order of their hashed keys, which
+// are passed to fn as nibbles. The walk starts from the branch at the nibble
+// path prefix: an empty prefix walks the accounts and the 64 nibbles of a
+// hashed address walk the storage of that account. Leaves whose hashed key is
+// less than the nibbles of from are skipped and the walk stops when fn returns
+// false.
+func WalkLeaves(ctx PatriciaContext, prefix, from []byte, fn func(plainKey, hashedKey []byte) (bool, error)) error {
+	w, err := newLeafWalker(ctx, prefix)
+	if err != nil {
+		return err
+	}
+	_, err = w.walk(prefix, from, fn, true)
+	return err
+}
+
+// PrevLeaf returns the plain key of the last leaf under the nibble path prefix
+// whose hashed key is less than the nibbles of before, or nil if there is no
+// such leaf. A nil before returns the last leaf. See WalkLeaves for the prefix.
+func PrevLeaf(ctx PatriciaContext, prefix, before []byte) ([]byte, error) {
+	w, err := newLeafWalker(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return w.prev(prefix, before, true)
+}
+
+type leafWalker struct {
+	ctx     PatriciaContext
+	storage bool
+}
+
+func newLeafWalker(ctx PatriciaContext, prefix []byte) (*leafWalker, error) {
+	if len(prefix) != 0 && len(prefix) != 64 {
+		return nil, fmt.Errorf("commitment: walk prefix must be empty or a hashed address, got %d nibbles", len(prefix))
+	}
+	return &leafWalker{ctx: ctx, storage: len(prefix) == 64}, nil
+}
+
+// branch reads the cells of the branch at nibble path.
+func (w *leafWalker) branch(path []byte, top bool) (bitmap uint16, row [16]cell, err error) {
+	branchData, _, err := w.ctx.Branch(hexNibblesToCompactBytes(path))
+	if err != nil {
+		return 0, row, err
+	}
+	if len(branchData) < 4 {
+		if top {
+			return 0, row, ErrNoBranch
+		}
+		return 0, row, fmt.Errorf("commitment: missing branch for nibbles [%x]", path)
+	}
+	bitmap = binary.BigEndian.Uint16(branchData[2:]) // skip touch map
+	pos := 4
+	for bitset := bitmap; bitset != 0; {
+		bit := bitset & -bitset
+		nibble := bits.TrailingZeros16(bit)
+		bitset ^= bit
+
+		fieldBits := cellFields(branchData[pos])
+		pos++
+		if pos, err = row[nibble].fillFromFields(branchData, pos, fieldBits); err != nil {
+			return 0, row, fmt.Errorf("commitment: nibbles [%x] branch [%x]: %w", path, branchData, err)
+		}
+	}
+	return bitmap, row, nil
+}
+
+// leafKey returns the plain key of c if it's a leaf of the walked trie.
+func (w *leafWalker) leafKey(c *cell) []byte {
+	if w.storage && c.storageAddrLen > 0 {
+		return c.storageAddr[:c.storageAddrLen]
+	}
+	if !w.storage && c.accountAddrLen > 0 {
+		return c.accountAddr[:c.accountAddrLen]
+	}
+	return nil
+}
+
+// childPath returns the nibble path of the branch below the cell at nibble.
+func childPath(path []byte, nibble int, c *cell) []byte {
+	child := make([]byte, 0, len(path)+1+c.extLen)
+	return append(append(append(child, path...), byte(nibble)), c.extension[:c.extLen]...)
+}
+
+// comparePrefix compares path with the same length prefix of key.
+func comparePrefix(path, key []byte) int {
+	n := min(len(path), len(key))
+	return bytes.Compare(path[:n], key[:n])
+}
+
+// walk visits the branch at nibble path, it returns false once fn asked to stop.
+func (w *leafWalker) walk(path, from []byte, fn func(plainKey, hashedKey []byte) (bool, error), top bool) (bool, error) {
+	bitmap, row, err := w.branch(path, top)
+	if err != nil {
+		return false, err
+	}
+	for nibble := 0; nibble < 16; nibble++ {
+		if bitmap&(1<<nibble) == 0 {
+			continue
+		}
+		c := &row[nibble]
+		if plainKey := w.leafKey(c); plainKey != nil {
+			hashedKey := KeyToHexNibbleHash(plainKey)
+			if bytes.Compare(hashedKey, from) < 0 {
+				continue
+			}
+			if next, err := fn(common.CopyBytes(plainKey), hashedKey); err != nil || !next {
+				return false, err
+			}
+			continue
+		}
+		child := childPath(path, nibble, c)
+		if comparePrefix(child, from) < 0 {
+			continue // all the leaves below are before from
+		}
+		if next, err := w.walk(child, from, fn, false); err != nil || !next {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// prev visits the branch at nibble path from its last cell, before is nil when
+// all the leaves below path are before it.
+func (w *leafWalker) prev(path, before []byte, top bool) ([]byte, error) {
+	bitmap, row, err := w.branch(path, top)
+	if err != nil {
+		return nil, err
+	}
+	for nibble := 15; nibble >= 0; nibble-- {
+		if bitmap&(1<<nibble) == 0 {
+			continue
+		}
+		c := &row[nibble]
+		if plainKey := w.leafKey(c); plainKey != nil {
+			if before == nil || bytes.Compare(KeyToHexNibbleHash(plainKey), before) < 0 {
+				return common.CopyBytes(plainKey), nil
+			}
+			continue
+		}
+		child := childPath(path, nibble, c)
+		childBefore := before
+		if before != nil {
+			cmp := comparePrefix(child, before)
+			if cmp > 0 {
+				continue // all the leaves below are after before
+			}
+			if cmp < 0 {
+				childBefore = nil
+			}
+		}
+		plainKey, err := w.prev(child, childBefore, false)
+		if err != nil || plainKey != nil {
+			return plainKey, err
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package commitment
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common/length"
+)
+
+func TestWalkLeaves(t *testing.T) {
+	t.Parallel()
+
+	ms := NewMockState(t)
+	hph := NewHexPatriciaHashed(length.Addr, ms)
+	builder := NewUpdateBuilder()
+	var accounts []string
+	for i := 0; i < 300; i++ {
+		addr := fmt.Sprintf("%040x", i+1)
+		builder.Balance(addr, uint64(i+1))
+		accounts = append(accounts, addr)
+	}
+	contract := accounts[7]
+	var slots []string
+	for i := 0; i < 100; i++ {
+		slot := fmt.Sprintf("%064x", i)
+		builder.Storage(contract, slot, fmt.Sprintf("%02x", i+1))
+		slots = append(slots, contract+slot)
+	}
+	// a single slot isn't stored in a branch of its own
+	builder.Storage(accounts[8], fmt.Sprintf("%064x", 1), "01")
+
+	plainKeys, updates := builder.Build()
+	require.NoError(t, ms.applyPlainUpdates(plainKeys, updates))
+	upds := WrapKeyUpdates(t, ModeDirect, KeyToHexNibbleHash, plainKeys, updates)
+	defer upds.Close()
+	_, err := hph.Process(context.Background(), upds, "")
+	require.NoError(t, err)
+
+	sortedByHash := func(keys []string) []string {
+		sorted := append([]string(nil), keys...)
+		sort.Slice(sorted, func(i, j int) bool {
+			return bytes.Compare(KeyToHexNibbleHash(decodeHex(sorted[i])), KeyToHexNibbleHash(decodeHex(sorted[j]))) < 0
+		})
+		return sorted
+	}
+	walk := func(prefix, from []byte, limit int) (keys []string) {
+		err := WalkLeaves(ms, prefix, from, func(plainKey, hashedKey []byte) (bool, error) {
+			require.Equal(t, KeyToHexNibbleHash(plainKey), hashedKey)
+			keys = append(keys, hex.EncodeToString(plainKey))
+			return len(keys) < limit, nil
+		})
+		require.NoError(t, err)
+		return keys
+	}
+
+	expected := sortedByHash(accounts)
+	require.Equal(t, expected, walk(nil, nil, len(accounts)+1))
+	require.Equal(t, expected[:10], walk(nil, nil, 10))
+	require.Equal(t, expected[100:], walk(nil, KeyToHexNibbleHash(decodeHex(expected[100])), len(accounts)))
+	// from between two keys
+	from := KeyToHexNibbleHash(decodeHex(expected[100]))
+	from[len(from)-1]++
+	require.Equal(t, expected[101:105], walk(nil, from, 4))
+
+	prev := func(prefix, before []byte) string {
+		plainKey, err := PrevLeaf(ms, prefix, before)
+		require.NoError(t, err)
+		return hex.EncodeToString(plainKey)
+	}
+	require.Equal(t, expected[100], prev(nil, from))
+	require.Equal(t, expected[99], prev(nil, KeyToHexNibbleHash(decodeHex(expected[100]))))
+	require.Equal(t, expected[len(expected)-1], prev(nil, nil))
+	require.Empty(t, prev(nil, KeyToHexNibbleHash(decodeHex(expected[0]))))
+	for i := 1; i < len(expected); i += 17 {
+		require.Equal(t, expected[i-1], prev(nil, KeyToHexNibbleHash(decodeHex(expected[i]))))
+	}
+
+	contractPath := KeyToHexNibbleHash(decodeHex(contract))
+	expected = sortedByHash(slots)
+	require.Equal(t, expected, walk(contractPath, nil, len(slots)+1))
+	require.Equal(t, expected[50:60], walk(contractPath, KeyToHexNibbleHash(decodeHex(expected[50])), 10))
+	require.Equal(t, expected[49], prev(contractPath, KeyToHexNibbleHash(decodeHex(expected[50]))))
+
+	err = WalkLeaves(ms, KeyToHexNibbleHash(decodeHex(accounts[8])), nil, func(plainKey, hashedKey []byte) (bool, error) {
+		return true, nil
+	})
+	require.ErrorIs(t, err, ErrNoBranch)
+}
@@ -0,0 +1,349 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+// Package checksum - sidecar files with checksums of immutable files (.seg, .kv, .idx, .kvi, ...).
+//
+// seg.Decompressor and recsplit.Index mmap files and trust their bytes: bit-rot or truncated copy
+// shows up as panic or wrong answer far from the cause. Sidecar `<file>.sum` is written when file is built
+// and has checksums of:
+//   - sections - small parts which all reads depend on (dictionaries of .seg, header and params of .idx),
+//     they are checked when file is opened
+//   - blocks - every BlockSize bytes of file, they are checked on first read of them and by scrubber
+//
+// Sidecar format (big-endian):
+//
+//	magic "esum" | version u8 | block size u32 | file size u64 | sections count u8 |
+//	sections: name len u8, name, offset u64, length u64, CRC-32C u32 | CRC-32C of every block u32...
+package checksum
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/erigontech/erigon-lib/common/dir"
+)
+
+const (
+	Ext       = ".sum"
+	BlockSize = 64 * 1024
+
+	magic      = "esum"
+	version    = 2
+	headerSize = len(magic) + 1 + 4 + 8 + 1
+)
+
+var (
+	ErrMismatch = errors.New("checksum mismatch")
+	ErrNoSums   = errors.New("checksum sidecar not found")
+
+	castagnoli = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// Range - named part of file
+type Range struct {
+	Name           string
+	Offset, Length uint64
+}
+
+type Section struct {
+	Range
+	Sum uint32
+}
+
+// Sums - checksums of file
+type Sums struct {
+	Size      uint64
+	BlockSize uint32
+	Blocks    []uint32
+	Sections  []Section
+}
+
+func Path(filePath string) string { return filePath + Ext }
+
+func sum(data []byte) uint32 { return crc32.Checksum(data, castagnoli) }
+
+// Compute - checksums of all blocks of file. bytesPerSec limits read speed (0 - unlimited), to not compete with node for IO.
+func Compute(ctx context.Context, filePath string, blockSize uint32, bytesPerSec uint64) (*Sums, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s := &Sums{BlockSize: blockSize}
+	block := make([]byte, blockSize)
+	start := time.Now()
+	for {
+		n, err := io.ReadFull(f, block)
+		if n > 0 {
+			s.Blocks = append(s.Blocks, sum(block[:n]))
+			s.Size += uint64(n)
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return s, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if bytesPerSec > 0 {
+			ahead := time.Duration(float64(s.Size)/float64(bytesPerSec)*float64(time.Second)) - time.Since(start)
+			if ahead > 0 {
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-time.After(ahead):
+				}
+			}
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// BadBlocks - blocks of `other` which don't match `s`. Blocks beyond end of shorter file are bad.
+func (s *Sums) BadBlocks(other *Sums) (bad []uint64) {
+	for i := 0; i < max(len(s.Blocks), len(other.Blocks)); i++ {
+		if i >= len(s.Blocks) || i >= len(other.Blocks) || s.Blocks[i] != other.Blocks[i] {
+			bad = append(bad, uint64(i))
+		}
+	}
+	return bad
+}
+
+func (s *Sums) MarshalBinary() ([]byte, error) {
+	if len(s.Sections) > 255 {
+		return nil, fmt.Errorf("checksum file: too many sections %d", len(s.Sections))
+	}
+	buf := make([]byte, headerSize, headerSize+4*len(s.Blocks))
+	copy(buf, magic)
+	buf[len(magic)] = version
+	binary.BigEndian.PutUint32(buf[len(magic)+1:], s.BlockSize)
+	binary.BigEndian.PutUint64(buf[len(magic)+5:], s.Size)
+	buf[len(magic)+13] = byte(len(s.Sections))
+	for _, sec := range s.Sections {
+		if len(sec.Name) > 255 {
+			return nil, fmt.Errorf("checksum file: too long section name %q", sec.Name)
+		}
+		buf = append(buf, byte(len(sec.Name)))
+		buf = append(buf, sec.Name...)
+		buf = binary.BigEndian.AppendUint64(buf, sec.Offset)
+		buf = binary.BigEndian.AppendUint64(buf, sec.Length)
+		buf = binary.BigEndian.AppendUint32(buf, sec.Sum)
+	}
+	for _, sum := range s.Blocks {
+		buf = binary.BigEndian.AppendUint32(buf, sum)
+	}
+	return buf, nil
+}
+
+func (s *Sums) UnmarshalBinary(data []byte) error {
+	if len(data) < headerSize || !bytes.Equal(data[:len(magic)], []byte(magic)) {
+		return errors.New("not a checksum file")
+	}
+	if v := data[len(magic)]; v != version {
+		return fmt.Errorf("unsupported checksum file version %d", v)
+	}
+	s.BlockSize = binary.BigEndian.Uint32(data[len(magic)+1:])
+	s.Size = binary.BigEndian.Uint64(data[len(magic)+5:])
+	if s.BlockSize == 0 {
+		return errors.New("checksum file: zero block size")
+	}
+	sections := int(data[len(magic)+13])
+	data = data[headerSize:]
+	s.Sections = make([]Section, sections)
+	for i := range s.Sections {
+		if len(data) < 1 || len(data) < 1+int(data[0])+20 {
+			return errors.New("checksum file: truncated sections")
+		}
+		nameLen := int(data[0])
+		s.Sections[i].Name = string(data[1 : 1+nameLen])
+		data = data[1+nameLen:]
+		s.Sections[i].Offset = binary.BigEndian.Uint64(data)
+		s.Sections[i].Length = binary.BigEndian.Uint64(data[8:])
+		s.Sections[i].Sum = binary.BigEndian.Uint32(data[16:])
+		data = data[20:]
+		if s.Sections[i].Offset+s.Sections[i].Length > s.Size {
+			return fmt.Errorf("checksum file: section %s is out of file", s.Sections[i].Name)
+		}
+	}
+	blocks := (s.Size + uint64(s.BlockSize) - 1) / uint64(s.BlockSize)
+	if uint64(len(data)) != 4*blocks {
+		return fmt.Errorf("checksum file: %d bytes of checksums, expected %d blocks", len(data), blocks)
+	}
+	s.Blocks = make([]uint32, blocks)
+	for i := range s.Blocks {
+		s.Blocks[i] = binary.BigEndian.Uint32(data[4*i:])
+	}
+	return nil
+}
+
+// Read - reads sidecar of the file. Sidecar older than the file (file was replaced after sidecar was written)
+// is not used: returns ErrNoSums as if there is no sidecar.
+func Read(filePath string) (*Sums, error) {
+	sumStat, err := os.Stat(Path(filePath))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNoSums, filePath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	stat, err := os.Stat(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if stat.ModTime().After(sumStat.ModTime()) {
+		return nil, fmt.Errorf("%w: %s is older than file", ErrNoSums, Path(filePath))
+	}
+	data, err := os.ReadFile(Path(filePath))
+	if err != nil {
+		return nil, err
+	}
+	s := &Sums{}
+	if err := s.UnmarshalBinary(data); err != nil {
+		return nil, fmt.Errorf("%s: %w", Path(filePath), err)
+	}
+	return s, nil
+}
+
+// Write - atomically replaces sidecar of the file
+func Write(filePath string, s *Sums) error {
+	data, err := s.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	tmp := Path(filePath) + ".tmp"
+	if err := dir.WriteFileWithFsync(tmp, data, 0644); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, Path(filePath))
+}
+
+// Remove - removes sidecar of the file, if any
+func Remove(filePath string) {
+	_ = os.Remove(Path(filePath))
+}
+
+// Build - computes and writes sidecar of the file, with checksums of given sections of it
+func Build(ctx context.Context, filePath string, sections ...Range) error {
+	s, err := Compute(ctx, filePath, BlockSize, 0)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	for _, r := range sections {
+		if r.Offset+r.Length > s.Size {
+			return fmt.Errorf("%s: section %s [%d, %d) is out of file of size %d", filePath, r.Name, r.Offset, r.Offset+r.Length, s.Size)
+		}
+		h := crc32.New(castagnoli)
+		if _, err := io.Copy(h, io.NewSectionReader(f, int64(r.Offset), int64(r.Length))); err != nil {
+			return err
+		}
+		s.Sections = append(s.Sections, Section{Range: r, Sum: h.Sum32()})
+	}
+	return Write(filePath, s)
+}
+
+// Verify - checks all blocks of file against its sidecar. Returns ErrNoSums if there is no sidecar,
+// and ErrMismatch (with damaged blocks) if file doesn't match it.
+func Verify(ctx context.Context, filePath string, bytesPerSec uint64) error {
+	want, err := Read(filePath)
+	if err != nil {
+		return err
+	}
+	got, err := Compute(ctx, filePath, want.BlockSize, bytesPerSec)
+	if err != nil {
+		return err
+	}
+	if got.Size != want.Size {
+		return fmt.Errorf("%w: %s has size %d, expected %d", ErrMismatch, filePath, got.Size, want.Size)
+	}
+	if bad := want.BadBlocks(got); len(bad) > 0 {
+		return fmt.Errorf("%w: %s has %d damaged blocks of %d bytes, first at offset %d", ErrMismatch, filePath, len(bad), want.BlockSize, bad[0]*uint64(want.BlockSize))
+	}
+	return nil
+}
+
+// Verifier - checks mmapped file against its sidecar: sections when it's opened, blocks on first read of them
+type Verifier struct {
+	fileName string
+	data     []byte
+	sums     *Sums
+	checked  []atomic.Uint64 // bitmap of blocks which matched
+}
+
+// OpenVerifier - checks size and sections of mmapped file against its sidecar. Returns nil Verifier if file
+// has no sidecar: such files are not checked.
+func OpenVerifier(filePath, fileName string, data []byte) (*Verifier, error) {
+	sums, err := Read(filePath)
+	if err != nil {
+		if errors.Is(err, ErrNoSums) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if sums.Size != uint64(len(data)) {
+		return nil, fmt.Errorf("%w: %s has size %d, expected %d", ErrMismatch, fileName, len(data), sums.Size)
+	}
+	for _, sec := range sums.Sections {
+		if sum(data[sec.Offset:sec.Offset+sec.Length]) != sec.Sum {
+			return nil, fmt.Errorf("%w: %s has damaged %s at offset %d", ErrMismatch, fileName, sec.Name, sec.Offset)
+		}
+	}
+	return &Verifier{fileName: fileName, data: data, sums: sums, checked: make([]atomic.Uint64, (len(sums.Blocks)+63)/64)}, nil
+}
+
+// Check - checks blocks of bytes [from, to) of file, which were not checked yet. nil Verifier checks nothing.
+func (v *Verifier) Check(from, to uint64) error {
+	if v == nil || from >= to {
+		return nil
+	}
+	blockSize := uint64(v.sums.BlockSize)
+	for b := from / blockSize; b <= (min(to, uint64(len(v.data)))-1)/blockSize; b++ {
+		word, bit := &v.checked[b/64], uint64(1)<<(b%64)
+		if word.Load()&bit != 0 {
+			continue
+		}
+		if sum(v.data[b*blockSize:min((b+1)*blockSize, uint64(len(v.data)))]) != v.sums.Blocks[b] {
+			return fmt.Errorf("%w: %s has damaged block of %d bytes at offset %d", ErrMismatch, v.fileName, blockSize, b*blockSize)
+		}
+		word.Or(bit)
+	}
+	return nil
+}
+
+// MustCheck - Check for readers which can't return error: panics if file is damaged
+func (v *Verifier) MustCheck(from, to uint64) {
+	if v == nil {
+		return
+	}
+	if err := v.Check(from, to); err != nil {
+		panic(err)
+	}
+}
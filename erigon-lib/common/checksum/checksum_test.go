@@ -0,0 +1,112 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package checksum
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// damage - flips byte of file, keeping its modification time as bit-rot does
+func damage(t *testing.T, filePath string, offset int64) {
+	t.Helper()
+	stat, err := os.Stat(filePath)
+	require.NoError(t, err)
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	data[offset] ^= 0xff
+	require.NoError(t, os.WriteFile(filePath, data, 0644))
+	require.NoError(t, os.Chtimes(filePath, stat.ModTime(), stat.ModTime()))
+}
+
+func TestBuildVerify(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), "v1.0-000000-000500-headers.seg")
+	data := make([]byte, 3*BlockSize+100)
+	rand.New(rand.NewSource(1)).Read(data)
+	require.NoError(t, os.WriteFile(filePath, data, 0644))
+
+	_, err := Read(filePath)
+	require.ErrorIs(t, err, ErrNoSums)
+	require.ErrorIs(t, Verify(ctx, filePath, 0), ErrNoSums)
+
+	require.NoError(t, Build(ctx, filePath, Range{Name: "header", Length: 10}))
+	sums, err := Read(filePath)
+	require.NoError(t, err)
+	require.Equal(t, uint64(len(data)), sums.Size)
+	require.Len(t, sums.Blocks, 4)
+	require.Len(t, sums.Sections, 1)
+	require.Equal(t, "header", sums.Sections[0].Name)
+	require.NoError(t, Verify(ctx, filePath, 0))
+
+	damage(t, filePath, 2*BlockSize+1)
+	require.ErrorIs(t, Verify(ctx, filePath, 0), ErrMismatch)
+	got, err := Compute(ctx, filePath, BlockSize, 0)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{2}, sums.BadBlocks(got))
+
+	// sidecar of replaced file is not used
+	require.NoError(t, os.WriteFile(filePath, data, 0644))
+	future := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(filePath, future, future))
+	_, err = Read(filePath)
+	require.ErrorIs(t, err, ErrNoSums)
+}
+
+func TestVerifier(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), "v1.0-000000-000500-headers.idx")
+	data := make([]byte, 2*BlockSize+100)
+	rand.New(rand.NewSource(1)).Read(data)
+	require.NoError(t, os.WriteFile(filePath, data, 0644))
+
+	// files without sidecar are not checked
+	v, err := OpenVerifier(filePath, filepath.Base(filePath), data)
+	require.NoError(t, err)
+	require.Nil(t, v)
+	require.NoError(t, v.Check(0, uint64(len(data))))
+
+	require.NoError(t, Build(ctx, filePath, Range{Name: "header", Length: 10}))
+	v, err = OpenVerifier(filePath, filepath.Base(filePath), data)
+	require.NoError(t, err)
+	require.NoError(t, v.Check(0, uint64(len(data))))
+
+	_, err = OpenVerifier(filePath, filepath.Base(filePath), data[:len(data)-1])
+	require.ErrorIs(t, err, ErrMismatch)
+
+	damaged := append([]byte{}, data...)
+	damaged[5] ^= 0xff
+	_, err = OpenVerifier(filePath, filepath.Base(filePath), damaged)
+	require.ErrorIs(t, err, ErrMismatch)
+
+	damaged = append([]byte{}, data...)
+	damaged[BlockSize+5] ^= 0xff
+	v, err = OpenVerifier(filePath, filepath.Base(filePath), damaged)
+	require.NoError(t, err)
+	require.NoError(t, v.Check(0, BlockSize))
+	require.NoError(t, v.Check(2*BlockSize, 2*BlockSize+1))
+	require.ErrorIs(t, v.Check(BlockSize-1, BlockSize+1), ErrMismatch)
+	require.Panics(t, func() { v.MustCheck(BlockSize+5, BlockSize+6) })
+}
@@ -17,13 +17,18 @@
 package prune
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"math"
+	"os"
 	"reflect"
+	"slices"
 	"strings"
 
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/length"
 	"github.com/erigontech/erigon-lib/config3"
 	"github.com/erigontech/erigon-lib/kv"
 )
@@ -72,6 +77,45 @@ type Mode struct {
 	Initialised bool // Set when the values are initialised (not default)
 	History     BlockAmount
 	Blocks      BlockAmount
+
+	// Watched - if not empty, history older than History distance is kept only for these accounts (sorted).
+	// Unlike distances - can be changed after node creation (history of newly added accounts is kept from that moment).
+	Watched []common.Address
+}
+
+// IsWatched - whether addr is in sorted list of watched accounts
+func IsWatched(watched []common.Address, addr common.Address) bool {
+	_, found := slices.BinarySearchFunc(watched, addr, func(a, b common.Address) int { return a.Cmp(b) })
+	return found
+}
+
+// ParseWatched - parses value of --prune.history.watch: comma-separated list of addresses,
+// or path to file with one address per line (lines starting with # are ignored)
+func ParseWatched(s string) ([]common.Address, error) {
+	if s == "" {
+		return nil, nil
+	}
+	list := strings.Split(s, ",")
+	if _, err := os.Stat(s); err == nil {
+		content, err := os.ReadFile(s)
+		if err != nil {
+			return nil, err
+		}
+		list = strings.Split(string(content), "\n")
+	}
+	var watched []common.Address
+	for _, item := range list {
+		item = strings.TrimSpace(item)
+		if item == "" || strings.HasPrefix(item, "#") {
+			continue
+		}
+		if !common.IsHexAddress(item) {
+			return nil, fmt.Errorf("--prune.history.watch: invalid address %q", item)
+		}
+		watched = append(watched, common.HexToAddress(item))
+	}
+	slices.SortFunc(watched, func(a, b common.Address) int { return a.Cmp(b) })
+	return slices.Compact(watched), nil
 }
 
 func (m Mode) String() string {
@@ -143,6 +187,10 @@ func Get(db kv.Getter) (Mode, error) {
 		prune.Blocks = blockAmount
 	}
 
+	if prune.Watched, err = GetWatched(db); err != nil {
+		return prune, err
+	}
+
 	return prune, nil
 }
 
@@ -185,6 +233,11 @@ func EnsureNotChanged(tx kv.GetPut, pruneMode Mode) (Mode, error) {
 	if err := setIfNotExist(tx, pruneMode); err != nil {
 		return pruneMode, err
 	}
+	if pruneMode.Initialised {
+		if err := setWatched(tx, pruneMode.Watched); err != nil {
+			return pruneMode, err
+		}
+	}
 
 	pm, err := Get(tx)
 	if err != nil {
@@ -224,6 +277,52 @@ func setIfNotExist(db kv.GetPut, pm Mode) (err error) {
 	return nil
 }
 
+// setWatched - overwrites list of watched accounts: it's allowed to change
+func setWatched(db kv.GetPut, watched []common.Address) error {
+	if len(watched) == 0 {
+		return db.Delete(kv.DatabaseInfo, kv.PruneWatched)
+	}
+	var v bytes.Buffer
+	for _, addr := range watched {
+		v.Write(addr.Bytes())
+	}
+	return db.Put(kv.DatabaseInfo, kv.PruneWatched, v.Bytes())
+}
+
+// GetWatched - list of watched accounts (sorted), see Mode.Watched
+func GetWatched(db kv.Getter) ([]common.Address, error) {
+	v, err := db.GetOne(kv.DatabaseInfo, kv.PruneWatched)
+	if err != nil {
+		return nil, err
+	}
+	var watched []common.Address
+	for ; len(v) >= length.Addr; v = v[length.Addr:] {
+		watched = append(watched, common.BytesToAddress(v[:length.Addr]))
+	}
+	return watched, nil
+}
+
+// GetWatchedHorizon - txNum before which history is kept only for watched accounts. 0 - history of all accounts is kept.
+// Stays after the watched list is changed or removed: history files already built don't have other accounts.
+func GetWatchedHorizon(db kv.Getter) (uint64, error) {
+	v, err := db.GetOne(kv.DatabaseInfo, kv.PruneWatchedHorizon)
+	if err != nil || len(v) < 8 {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(v), nil
+}
+
+// SetWatchedHorizon - moves horizon forward (never backward), see GetWatchedHorizon
+func SetWatchedHorizon(db kv.GetPut, txNum uint64) error {
+	prev, err := GetWatchedHorizon(db)
+	if err != nil || txNum <= prev {
+		return err
+	}
+	var v [8]byte
+	binary.BigEndian.PutUint64(v[:], txNum)
+	return db.Put(kv.DatabaseInfo, kv.PruneWatchedHorizon, v[:])
+}
+
 func createBlockAmount(pruneType []byte, v []byte) (BlockAmount, error) {
 	var blockAmount BlockAmount
 
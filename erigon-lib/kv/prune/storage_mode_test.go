@@ -17,9 +17,12 @@
 package prune
 
 import (
+	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
 
+	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/math"
 	"github.com/erigontech/erigon-lib/kv/memdb"
 	"github.com/stretchr/testify/assert"
@@ -91,6 +94,66 @@ func TestParseCLIMode(t *testing.T) {
 	})
 }
 
+func TestWatched(t *testing.T) {
+	a, b := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+
+	t.Run("parse", func(t *testing.T) {
+		watched, err := ParseWatched(b.Hex() + ", " + a.Hex() + "," + b.Hex())
+		assert.NoError(t, err)
+		assert.Equal(t, []common.Address{a, b}, watched)
+
+		file := filepath.Join(t.TempDir(), "watched.txt")
+		assert.NoError(t, os.WriteFile(file, []byte("# contracts\n"+b.Hex()+"\n\n"+a.Hex()+"\n"), 0600))
+		watched, err = ParseWatched(file)
+		assert.NoError(t, err)
+		assert.Equal(t, []common.Address{a, b}, watched)
+
+		_, err = ParseWatched("0x01,garbage")
+		assert.Error(t, err)
+	})
+	t.Run("db", func(t *testing.T) {
+		_, tx := memdb.NewTestTx(t)
+		mode := FullMode
+		mode.Watched = []common.Address{a, b}
+		pm, err := EnsureNotChanged(tx, mode)
+		assert.NoError(t, err)
+		assert.Equal(t, mode, pm)
+
+		mode.Watched = []common.Address{b}
+		pm, err = EnsureNotChanged(tx, mode)
+		assert.NoError(t, err, "watched accounts can be changed")
+		assert.Equal(t, mode, pm)
+
+		mode.Watched = nil
+		pm, err = EnsureNotChanged(tx, mode)
+		assert.NoError(t, err)
+		assert.Equal(t, FullMode, pm)
+	})
+	t.Run("is watched", func(t *testing.T) {
+		watched := []common.Address{a}
+		assert.True(t, IsWatched(watched, a))
+		assert.False(t, IsWatched(watched, b))
+		assert.False(t, IsWatched(nil, a))
+	})
+	t.Run("horizon", func(t *testing.T) {
+		_, tx := memdb.NewTestTx(t)
+		horizon, err := GetWatchedHorizon(tx)
+		assert.NoError(t, err)
+		assert.Zero(t, horizon)
+
+		assert.NoError(t, SetWatchedHorizon(tx, 1000))
+		assert.NoError(t, SetWatchedHorizon(tx, 500), "horizon doesn't move backward")
+		horizon, err = GetWatchedHorizon(tx)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1000), horizon)
+
+		assert.NoError(t, setWatched(tx, nil))
+		horizon, err = GetWatchedHorizon(tx)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1000), horizon, "files built before are not changed by removal of watched list")
+	})
+}
+
 var distanceTests = []struct {
 	stageHead uint64
 	pruneTo   uint64
@@ -293,6 +293,9 @@ var (
 	PruneTypeOlder = []byte("older")
 	PruneHistory   = []byte("pruneHistory")
 	PruneBlocks    = []byte("pruneBlocks")
+	PruneWatched   = []byte("pruneHistoryWatched")
+	// PruneWatchedHorizon - txNum before which history files are built only with watched accounts
+	PruneWatchedHorizon = []byte("pruneHistoryWatchedHorizon")
 
 	DBSchemaVersionKey = []byte("dbVersion")
 	GenesisKey         = []byte("genesis")
@@ -454,12 +457,14 @@ const (
 	RecentLocalTransaction = "RecentLocalTransaction" // sequence_u64 -> tx_hash
 	PoolTransaction        = "PoolTransaction"        // txHash -> sender+tx_rlp
 	PoolInfo               = "PoolInfo"               // option_key -> option_value
+	PoolLocalJournal       = "PoolLocalJournal"       // txHash -> submitted_at_u64+nonce_u64+sender+tx_rlp
 )
 
 var TxPoolTables = []string{
 	RecentLocalTransaction,
 	PoolTransaction,
 	PoolInfo,
+	PoolLocalJournal,
 }
 var SentryTables = []string{
 	Inodes,
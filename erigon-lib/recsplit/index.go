@@ -19,6 +19,7 @@ package recsplit
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -33,6 +34,7 @@ import (
 
 	"github.com/c2h5oh/datasize"
 	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/checksum"
 	"github.com/erigontech/erigon-lib/common/dbg"
 	"github.com/erigontech/erigon-lib/datastruct/fusefilter"
 	"github.com/erigontech/erigon-lib/log/v3"
@@ -106,6 +108,11 @@ type Index struct {
 
 	readers         *sync.Pool
 	readAheadRefcnt atomic.Int32 // ref-counter: allow enable/disable read-ahead from goroutines. only when refcnt=0 - disable read-ahead once
+
+	verifier *checksum.Verifier // nil if file has no checksum sidecar
+	// layout of file: [paramsStart, paramsEnd) - bucket params, seeds, features; [grStart-12, grStart) - golomb rice sizes;
+	// [grStart, efStart) - golomb rice; [efStart, size) - double elias fano
+	paramsStart, paramsEnd, grStart, efStart int
 }
 
 func MustOpen(indexFile string) *Index {
@@ -137,6 +144,10 @@ func OpenIndex(indexFilePath string) (idx *Index, err error) {
 		return nil, err
 	}
 	idx.data = idx.mmapHandle1[:idx.size]
+	if idx.verifier, err = checksum.OpenVerifier(indexFilePath, fName, idx.data); err != nil {
+		idx.Close()
+		return nil, err
+	}
 
 	if err := idx.init(); err != nil {
 		return nil, err
@@ -193,6 +204,7 @@ func (idx *Index) init() (err error) {
 	if offset < 0 {
 		return fmt.Errorf("file %s %w. offset is: %d which is below zero", idx.fileName, IncompatibleErr, offset)
 	}
+	idx.paramsStart = offset
 
 	// Bucket count, bucketSize, leafSize
 	idx.bucketCount = binary.BigEndian.Uint64(idx.data[offset:])
@@ -226,6 +238,7 @@ func (idx *Index) init() (err error) {
 	idx.enums = features&Enums != No
 	idx.lessFalsePositives = features&LessFalsePositives != No
 	offset++
+	idx.paramsEnd = offset
 	if idx.enums && idx.keyCount > 0 {
 		var size int
 		idx.offsetEf, size = eliasfano32.ReadEliasFano(idx.data[offset:])
@@ -266,14 +279,38 @@ func (idx *Index) init() (err error) {
 
 	l := binary.BigEndian.Uint64(idx.data[offset:])
 	offset += 8
+	idx.grStart = offset
 	p := (*[maxDataSize / 8]uint64)(unsafe.Pointer(&idx.data[offset]))
 	idx.grData = p[:l]
 	offset += 8 * int(l)
+	idx.efStart = offset
 	idx.ef.Read(idx.data[offset:])
 	validationPassed = true
 	return nil
 }
 
+// checksumSections - parts of file which all lookups depend on, they are checked when file is opened.
+// Records and golomb rice are checked on first lookup of them.
+func (idx *Index) checksumSections() []checksum.Range {
+	return []checksum.Range{
+		{Name: "header", Length: 17},
+		{Name: "params", Offset: uint64(idx.paramsStart), Length: uint64(idx.paramsEnd - idx.paramsStart)},
+		{Name: "golomb rice sizes", Offset: uint64(idx.grStart - 12), Length: 12},
+		{Name: "elias fano", Offset: uint64(idx.efStart), Length: uint64(idx.size) - uint64(idx.efStart)},
+	}
+}
+
+// BuildChecksums - writes checksum sidecar of .idx file
+func BuildChecksums(ctx context.Context, indexFilePath string) error {
+	idx, err := OpenIndex(indexFilePath)
+	if err != nil {
+		return err
+	}
+	sections := idx.checksumSections()
+	idx.Close()
+	return checksum.Build(ctx, indexFilePath, sections...)
+}
+
 func onlyKnownFeatures(features Features) error {
 	for _, f := range SupportedFeatures {
 		features = features &^ f
@@ -363,6 +400,7 @@ func (idx *Index) Lookup(bucketHash, fingerprint uint64) (uint64, bool) {
 
 	bucket := remap(bucketHash, idx.bucketCount)
 	cumKeys, cumKeysNext, bitPos := idx.ef.Get3(bucket)
+	idx.verifier.MustCheck(uint64(idx.grStart)+bitPos/8, uint64(idx.grStart)+bitPos/8+1)
 	m := uint16(cumKeysNext - cumKeys) // Number of keys in this bucket
 	gr.ReadReset(int(bitPos), idx.skipBits(m))
 	var level int
@@ -414,6 +452,7 @@ func (idx *Index) Lookup(bucketHash, fingerprint uint64) (uint64, bool) {
 	rec := int(cumKeys) + int(remap16(remix(fingerprint+idx.startSeed[level]+b), m))
 
 	pos := 1 + 8 + idx.bytesPerRec*(rec+1)
+	idx.verifier.MustCheck(uint64(pos+8-idx.bytesPerRec), uint64(pos+8))
 
 	found := binary.BigEndian.Uint64(idx.data[pos:]) & idx.recMask
 	if idx.version == 0 && idx.lessFalsePositives && idx.enums && idx.keyCount > 0 {
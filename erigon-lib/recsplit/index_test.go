@@ -18,6 +18,7 @@ package recsplit
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
 	"os"
@@ -27,6 +28,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/erigontech/erigon-lib/common/checksum"
 	"github.com/erigontech/erigon-lib/log/v3"
 )
 
@@ -101,3 +103,46 @@ func TestForwardCompatibility(t *testing.T) {
 		assert.ErrorIs(t, err, IncompatibleErr)
 	})
 }
+
+func TestIndexChecksums(t *testing.T) {
+	logger := log.New()
+	tmpDir := t.TempDir()
+	indexFile := filepath.Join(tmpDir, "index")
+	salt := uint32(1)
+	rs, err := NewRecSplit(RecSplitArgs{
+		KeyCount:   100,
+		BucketSize: 10,
+		Salt:       &salt,
+		TmpDir:     tmpDir,
+		IndexFile:  indexFile,
+		LeafSize:   8,
+	}, logger)
+	require.NoError(t, err)
+	defer rs.Close()
+	for i := 0; i < 100; i++ {
+		require.NoError(t, rs.AddKey([]byte(fmt.Sprintf("key %d", i)), uint64(i*17)))
+	}
+	require.NoError(t, rs.Build(context.Background()))
+
+	stat, err := os.Stat(indexFile)
+	require.NoError(t, err)
+	data, err := os.ReadFile(indexFile)
+	require.NoError(t, err)
+	damage := func(offset int) {
+		t.Helper()
+		damaged := bytes.Clone(data)
+		damaged[offset] ^= 0xff
+		require.NoError(t, os.WriteFile(indexFile, damaged, 0644))
+		require.NoError(t, os.Chtimes(indexFile, stat.ModTime(), stat.ModTime())) // bit-rot doesn't change modification time
+	}
+
+	damage(len(data) - 1) // elias fano
+	_, err = OpenIndex(indexFile)
+	require.ErrorIs(t, err, checksum.ErrMismatch)
+
+	damage(17) // first record
+	idx, err := OpenIndex(indexFile)
+	require.NoError(t, err)
+	defer idx.Close()
+	require.Panics(t, func() { NewIndexReader(idx).Lookup([]byte("key 1")) })
+}
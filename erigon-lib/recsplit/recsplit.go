@@ -34,6 +34,7 @@ import (
 
 	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/assert"
+	"github.com/erigontech/erigon-lib/common/checksum"
 	"github.com/erigontech/erigon-lib/etl"
 	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon-lib/recsplit/eliasfano16"
@@ -773,10 +774,14 @@ func (rs *RecSplit) Build(ctx context.Context) error {
 		return err
 	}
 
+	checksum.Remove(rs.filePath)
 	if err = os.Rename(rs.tmpFilePath, rs.filePath); err != nil {
 		rs.logger.Warn("[index] rename", "file", rs.tmpFilePath, "err", err)
 		return err
 	}
+	if err = BuildChecksums(ctx, rs.filePath); err != nil {
+		return fmt.Errorf("checksums: %w", err)
+	}
 	rs.logger.Debug("[index] created", "file", rs.fileName)
 
 	return nil
@@ -36,6 +36,7 @@ import (
 	"github.com/c2h5oh/datasize"
 
 	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/checksum"
 	dir2 "github.com/erigontech/erigon-lib/common/dir"
 	"github.com/erigontech/erigon-lib/etl"
 	"github.com/erigontech/erigon-lib/log/v3"
@@ -279,9 +280,13 @@ func (c *Compressor) Compress() error {
 	if err = cf.Close(); err != nil {
 		return err
 	}
+	checksum.Remove(c.outputFile)
 	if err := os.Rename(c.tmpOutFilePath, c.outputFile); err != nil {
 		return fmt.Errorf("renaming: %w", err)
 	}
+	if err := BuildChecksums(c.ctx, c.outputFile); err != nil {
+		return fmt.Errorf("checksums: %w", err)
+	}
 
 	c.Ratio, err = Ratio(c.uncompressedFile.filePath, c.outputFile)
 	if err != nil {
@@ -68,7 +68,7 @@ func TestCompressEmptyDict(t *testing.T) {
 }
 
 // nolint
-func checksum(file string) uint32 {
+func fileChecksum(file string) uint32 {
 	hasher := crc32.NewIEEE()
 	f, err := os.Open(file)
 	if err != nil {
@@ -179,7 +179,7 @@ func TestCompressDict1(t *testing.T) {
 		i++
 	}
 
-	if cs := checksum(d.filePath); cs != 3153486123 {
+	if cs := fileChecksum(d.filePath); cs != 3153486123 {
 		// it's ok if hash changed, but need re-generate all existing snapshot hashes
 		// in https://github.com/erigontech/erigon-snapshot
 		t.Errorf("result file hash changed, %d", cs)
@@ -249,7 +249,7 @@ func TestCompressDictCmp(t *testing.T) {
 		i++
 	}
 
-	if cs := checksum(d.filePath); cs != 3153486123 {
+	if cs := fileChecksum(d.filePath); cs != 3153486123 {
 		// it's ok if hash changed, but need re-generate all existing snapshot hashes
 		// in https://github.com/erigontech/erigon-snapshot
 		t.Errorf("result file hash changed, %d", cs)
@@ -18,6 +18,7 @@ package seg
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -25,6 +26,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strconv"
+	"sync"
 	"sync/atomic"
 	"time"
 	"unsafe"
@@ -32,6 +34,7 @@ import (
 	"github.com/c2h5oh/datasize"
 
 	"github.com/erigontech/erigon-lib/common/assert"
+	"github.com/erigontech/erigon-lib/common/checksum"
 	"github.com/erigontech/erigon-lib/common/dbg"
 	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon-lib/mmap"
@@ -138,6 +141,12 @@ type Decompressor struct {
 	filePath, fileName string
 
 	readAheadRefcnt atomic.Int32 // ref-counter: allow enable/disable read-ahead from goroutines. only when refcnt=0 - disable read-ahead once
+
+	pagesHeaderOnce sync.Once // see PagesHeaderOf
+	pagesHeader     *PagesHeader
+	pagesHeaderErr  error
+
+	verifier *checksum.Verifier // nil if file has no checksum sidecar
 }
 
 const (
@@ -220,6 +229,9 @@ func NewDecompressor(compressedFilePath string) (*Decompressor, error) {
 	// read patterns from file
 	d.data = d.mmapHandle1[:d.size]
 	defer d.MadvNormal().DisableReadAhead() //speedup opening on slow drives
+	if d.verifier, err = checksum.OpenVerifier(compressedFilePath, fName, d.data); err != nil {
+		return nil, err
+	}
 
 	d.wordsCount = binary.BigEndian.Uint64(d.data[:8])
 	d.emptyWordsCount = binary.BigEndian.Uint64(d.data[8:16])
@@ -343,6 +355,18 @@ func NewDecompressor(compressedFilePath string) (*Decompressor, error) {
 	return d, nil
 }
 
+// BuildChecksums - writes checksum sidecar of .seg file: its dictionaries are checked when it's opened,
+// words - on first read of them
+func BuildChecksums(ctx context.Context, compressedFilePath string) error {
+	d, err := NewDecompressor(compressedFilePath)
+	if err != nil {
+		return err
+	}
+	wordsStart := d.wordsStart
+	d.Close()
+	return checksum.Build(ctx, compressedFilePath, checksum.Range{Name: "dictionaries", Length: wordsStart})
+}
+
 func buildCondensedPatternTable(table *patternTable, depths []uint64, patterns [][]byte, code uint16, bits int, depth uint64, maxDepth uint64) (int, error) {
 	if maxDepth > maxAllowedDepth {
 		return 0, fmt.Errorf("buildCondensedPatternTable: maxDepth=%d is too deep", maxDepth)
@@ -688,6 +712,7 @@ func (g *Getter) HasNext() bool {
 // After extracting next word, it moves to the beginning of the next one
 func (g *Getter) Next(buf []byte) ([]byte, uint64) {
 	savePos := g.dataP
+	g.d.verifier.MustCheck(g.d.wordsStart+savePos, g.d.wordsStart+savePos+1) // before decoding damaged bytes
 	wordLen := g.nextPos(true)
 	wordLen-- // because when create huffman tree we do ++ , because 0 is terminator
 	if wordLen == 0 {
@@ -749,10 +774,13 @@ func (g *Getter) Next(buf []byte) ([]byte, uint64) {
 	}
 	g.dataP = postLoopPos
 	g.dataBit = 0
+	g.d.verifier.MustCheck(g.d.wordsStart+savePos, g.d.wordsStart+postLoopPos)
 	return buf, postLoopPos
 }
 
 func (g *Getter) NextUncompressed() ([]byte, uint64) {
+	savePos := g.dataP
+	g.d.verifier.MustCheck(g.d.wordsStart+savePos, g.d.wordsStart+savePos+1)
 	wordLen := g.nextPos(true)
 	wordLen-- // because when create huffman tree we do ++ , because 0 is terminator
 	if wordLen == 0 {
@@ -769,6 +797,7 @@ func (g *Getter) NextUncompressed() ([]byte, uint64) {
 	}
 	pos := g.dataP
 	g.dataP += wordLen
+	g.d.verifier.MustCheck(g.d.wordsStart+savePos, g.d.wordsStart+g.dataP)
 	return g.data[pos:g.dataP], g.dataP
 }
 
@@ -17,11 +17,17 @@
 package seg
 
 import (
+	"context"
+	"encoding/binary"
 	"fmt"
+	"math/rand"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/log/v3"
 )
 
 func BenchmarkDecompress(b *testing.B) {
@@ -62,6 +68,74 @@ func BenchmarkDecompress(b *testing.B) {
 	})
 }
 
+// preparePagedHistory - paged file with values similar to accounts history: txNum+address -> encoded account
+func preparePagedHistory(tb testing.TB, pageSize int, compression FileCompression) *Decompressor {
+	tb.Helper()
+	tmpDir := tb.TempDir()
+	file := filepath.Join(tmpDir, "history.v")
+	cfg := DefaultCfg
+	cfg.Workers = 1
+	c, err := NewCompressor(context.Background(), "paged", file, tmpDir, cfg, log.LvlDebug, log.New())
+	require.NoError(tb, err)
+	defer c.Close()
+
+	w := NewPagedWriter(NewWriter(c, CompressNone), pageSize, true)
+	if compression.Has(CompressZstd) {
+		w.TrainDict()
+	}
+	rnd := rand.New(rand.NewSource(1))
+	addrs := make([][]byte, 1_000)
+	for i := range addrs {
+		addrs[i] = make([]byte, 20)
+		rnd.Read(addrs[i])
+	}
+	k, v := make([]byte, 28), make([]byte, 0, 64)
+	for txNum := uint64(0); txNum < 100_000; txNum++ {
+		binary.BigEndian.PutUint64(k, txNum)
+		copy(k[8:], addrs[rnd.Intn(len(addrs))])
+		v = binary.AppendUvarint(v[:0], txNum/3)                       // nonce
+		v = binary.AppendUvarint(v, uint64(rnd.Int63n(1_000_000_000))) // balance
+		v = append(v, 0xc5, 0xd2, 0x46, 0x01, 0x86, 0xf7, 0x23, 0x3c)  // code hash prefix
+		require.NoError(tb, w.Add(k, v))
+	}
+	require.NoError(tb, w.Compress())
+	d, err := NewDecompressor(file)
+	require.NoError(tb, err)
+	return d
+}
+
+func BenchmarkPagedZstd(b *testing.B) {
+	for _, compression := range []FileCompression{CompressNone, CompressZstd} {
+		d := preparePagedHistory(b, 16, compression)
+		b.Run(compression.String()+"/next", func(b *testing.B) {
+			b.ReportAllocs()
+			b.ReportMetric(float64(d.Size()), "file_bytes")
+			g := NewPagedReader(d.MakeGetter(), 16, true)
+			var buf []byte
+			for i := 0; i < b.N; i++ {
+				_, _, buf, _ = g.Next2(buf[:0])
+				if !g.HasNext() {
+					g.Reset(0)
+				}
+			}
+		})
+		b.Run(compression.String()+"/get_from_page", func(b *testing.B) {
+			b.ReportAllocs()
+			header, err := PagesHeaderOf(d.MakeGetter())
+			require.NoError(b, err)
+			g := d.MakeGetter()
+			g.Reset(header.FirstPage())
+			page, _ := g.Next(nil)
+			key := make([]byte, 28) // miss: scans whole page
+			var buf []byte
+			for i := 0; i < b.N; i++ {
+				_, buf = header.GetFromPage(key, page, buf, true)
+			}
+		})
+		d.Close()
+	}
+}
+
 func BenchmarkDecompressTorrent(t *testing.B) {
 	t.Skip()
 
@@ -30,6 +30,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/erigontech/erigon-lib/common/checksum"
 	"github.com/erigontech/erigon-lib/log/v3"
 )
 
@@ -755,3 +756,35 @@ func TestDecompressRandomMatchBool(t *testing.T) {
 		t.Fatalf("expected word count: %d, got %d\n", int(d.wordsCount), total)
 	}
 }
+
+func TestDecompressChecksums(t *testing.T) {
+	d := prepareLoremDict(t)
+	filePath, wordsStart, size := d.FilePath(), d.wordsStart, d.size
+	d.Close()
+	stat, err := os.Stat(filePath)
+	require.NoError(t, err)
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	damage := func(offset uint64) {
+		t.Helper()
+		damaged := bytes.Clone(data)
+		damaged[offset] ^= 0xff
+		require.NoError(t, os.WriteFile(filePath, damaged, 0644))
+		require.NoError(t, os.Chtimes(filePath, stat.ModTime(), stat.ModTime())) // bit-rot doesn't change modification time
+	}
+
+	damage(wordsStart - 1)
+	_, err = NewDecompressor(filePath)
+	require.ErrorIs(t, err, checksum.ErrMismatch)
+
+	damage(uint64(size) - 1)
+	d, err = NewDecompressor(filePath)
+	require.NoError(t, err)
+	defer d.Close()
+	g := d.MakeGetter()
+	require.PanicsWithError(t, fmt.Sprintf("%s: %s has damaged block of %d bytes at offset 0", checksum.ErrMismatch, d.FileName(), checksum.BlockSize), func() {
+		for g.HasNext() {
+			g.Next(nil)
+		}
+	})
+}
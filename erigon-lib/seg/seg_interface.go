@@ -16,17 +16,42 @@
 
 package seg
 
+import (
+	"fmt"
+	"strings"
+)
+
 type FileCompression uint8
 
 const (
 	CompressNone FileCompression = 0b1
 	CompressKeys FileCompression = 0b10
 	CompressVals FileCompression = 0b100
+	// CompressZstd - pages of paged files (see PagedWriter) compressed by zstd with dictionary trained
+	// on the file's pages. Dictionary stored in the file header. Doesn't affect not-paged files.
+	CompressZstd FileCompression = 0b1000
 )
 
+// ParseFileCompression - parses "none", "k", "v", "kv" with optional "+zstd" suffix
 func ParseFileCompression(s string) (FileCompression, error) {
-	// Implementation would be here
-	return CompressNone, nil
+	s, zstd := strings.CutSuffix(s, "+zstd")
+	var c FileCompression
+	switch s {
+	case "none", "":
+		c = CompressNone
+	case "k":
+		c = CompressKeys
+	case "v":
+		c = CompressVals
+	case "kv":
+		c = CompressKeys | CompressVals
+	default:
+		return 0, fmt.Errorf("invalid file compression type: %s", s)
+	}
+	if zstd {
+		c |= CompressZstd
+	}
+	return c, nil
 }
 
 func (c FileCompression) Has(flag FileCompression) bool {
@@ -34,7 +59,21 @@ func (c FileCompression) Has(flag FileCompression) bool {
 }
 
 func (c FileCompression) String() string {
-	return "none" // Simplified implementation
+	var s string
+	switch {
+	case c.Has(CompressKeys) && c.Has(CompressVals):
+		s = "kv"
+	case c.Has(CompressKeys):
+		s = "k"
+	case c.Has(CompressVals):
+		s = "v"
+	default:
+		s = "none"
+	}
+	if c.Has(CompressZstd) {
+		s += "+zstd"
+	}
+	return s
 }
 
 type ReaderI interface {
@@ -22,22 +22,23 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/compress"
 )
 
 var be = binary.BigEndian
 
 func GetFromPage(key, compressedPage []byte, compressionBuf []byte, compressionEnabled bool) (v []byte, compressionBufOut []byte) {
-	var err error
-	var page []byte
-	compressionBuf, page, err = compress.DecodeZstdIfNeed(compressionBuf[:0], compressedPage, compressionEnabled)
-	if err != nil {
-		panic(err)
-	}
+	var noHeader *PagesHeader
+	return noHeader.GetFromPage(key, compressedPage, compressionBuf, compressionEnabled)
+}
 
+func getFromPage(key, page []byte) (v []byte) {
 	cnt := int(page[0])
 	if cnt == 0 {
-		return nil, compressionBuf
+		return nil
 	}
 	meta, data := page[1:1+cnt*4*2], page[1+cnt*4*2:]
 	kLens, vLens := meta[:cnt*4], meta[cnt*4:]
@@ -53,14 +54,14 @@ func GetFromPage(key, compressedPage []byte, compressionBuf []byte, compressionE
 		kLen, vLen := be.Uint32(kLens[i:]), be.Uint32(vLens[i:])
 		foundKey := keys[kOffset : kOffset+kLen]
 		if bytes.Equal(key, foundKey) {
-			return vals[vOffset : vOffset+vLen], compressionBuf
+			return vals[vOffset : vOffset+vLen]
 		} else {
 			_ = data
 		}
 		kOffset += kLen
 		vOffset += vLen
 	}
-	return nil, compressionBuf
+	return nil
 }
 
 type Page struct {
@@ -69,6 +70,7 @@ type Page struct {
 	kOffset, vOffset   uint32
 
 	compressionBuf []byte
+	header         *PagesHeader
 }
 
 func FromBytes(buf []byte, compressionEnabled bool) *Page {
@@ -79,7 +81,7 @@ func FromBytes(buf []byte, compressionEnabled bool) *Page {
 
 func (r *Page) Reset(v []byte, compressionEnabled bool) (n int) {
 	var err error
-	r.compressionBuf, v, err = compress.DecodeZstdIfNeed(r.compressionBuf[:0], v, compressionEnabled)
+	r.compressionBuf, v, err = r.header.decode(r.compressionBuf[:0], v, compressionEnabled)
 	if err != nil {
 		panic(fmt.Errorf("len(v): %d, %w", len(v), err))
 	}
@@ -122,15 +124,29 @@ type PagedReader struct {
 	isCompressed bool
 	pageSize     int
 	page         *Page
+	header       *PagesHeader // see CompressZstd
 
 	currentPageOffset, nextPageOffset uint64
 }
 
+// NewPagedReader - pages compressed by zstd if `snappy`. Dictionary of file written with CompressZstd is read from file header.
 func NewPagedReader(r ReaderI, pageSize int, snappy bool) *PagedReader {
 	if pageSize == 0 {
 		pageSize = 1
 	}
-	return &PagedReader{file: r, pageSize: pageSize, isCompressed: snappy, page: &Page{}}
+	g := &PagedReader{file: r, pageSize: pageSize, isCompressed: snappy, page: &Page{}}
+	if pageSize > 1 {
+		header, err := PagesHeaderOf(r)
+		if err != nil {
+			panic(err)
+		}
+		if header != nil {
+			g.header, g.page.header = header, header
+			g.currentPageOffset, g.nextPageOffset = header.firstPage, header.firstPage
+			r.Reset(header.firstPage)
+		}
+	}
+	return g
 }
 
 func (g *PagedReader) Reset(offset uint64) {
@@ -138,14 +154,15 @@ func (g *PagedReader) Reset(offset uint64) {
 		g.file.Reset(offset)
 		return
 	}
-	if g.currentPageOffset == offset { // don't reset internal state in this case: likely user just iterating over all values
+	offset = max(offset, g.header.FirstPage()) // 0 - header
+	if g.currentPageOffset == offset {         // don't reset internal state in this case: likely user just iterating over all values
 		return
 	}
 
 	g.file.Reset(offset)
 	g.currentPageOffset = offset
 	g.nextPageOffset = offset
-	g.page = &Page{} // TODO: optimize
+	g.page = &Page{header: g.header} // TODO: optimize
 	if g.file.HasNext() {
 		g.NextPage()
 	}
@@ -245,9 +262,21 @@ type PagedWriter struct {
 	compressionBuf     []byte
 	compressionEnabled bool
 
+	// CompressZstd: pages are collected until enough samples to train dictionary, then written with header
+	zstdDict    bool
+	zstdEnc     *zstd.Encoder
+	samples     [][]byte
+	samplesSize int
+
 	pairs int
 }
 
+// TrainDict - compress pages by zstd with dictionary trained on first pages of the file (see CompressZstd)
+func (c *PagedWriter) TrainDict() *PagedWriter {
+	c.zstdDict = c.compressionEnabled && c.pageSize > 1
+	return c
+}
+
 func (c *PagedWriter) Empty() bool { return c.pairs == 0 }
 func (c *PagedWriter) Close()      { c.parent.Close() }
 func (c *PagedWriter) Compress() error {
@@ -270,9 +299,44 @@ func (c *PagedWriter) writePage() error {
 	if !ok {
 		return nil
 	}
-	_, err := c.parent.Write(bts)
+	if c.zstdDict && c.zstdEnc == nil {
+		c.samples = append(c.samples, common.Copy(bts))
+		c.samplesSize += len(bts)
+		if c.samplesSize < pagesDictSamplesSize {
+			return nil
+		}
+		return c.writeSamples()
+	}
+	_, err := c.parent.Write(c.compress(bts))
 	return err
 }
+
+func (c *PagedWriter) compress(page []byte) []byte {
+	if c.zstdEnc != nil {
+		c.compressionBuf = c.zstdEnc.EncodeAll(page, growslice(c.compressionBuf, len(page)+len(page)/255+16)[:0])
+		return c.compressionBuf
+	}
+	c.compressionBuf, page = compress.EncodeZstdIfNeed(c.compressionBuf[:0], page, c.compressionEnabled)
+	return page
+}
+
+// writeSamples - trains dictionary, writes header and collected pages
+func (c *PagedWriter) writeSamples() (err error) {
+	dict := trainPagesDict(c.samples)
+	if c.zstdEnc, err = newPagesEncoder(dict); err != nil {
+		return err
+	}
+	if _, err = c.parent.Write(append(common.Copy(pagesHeaderMagic), dict...)); err != nil {
+		return err
+	}
+	for _, page := range c.samples {
+		if _, err = c.parent.Write(c.compress(page)); err != nil {
+			return err
+		}
+	}
+	c.samples, c.samplesSize = nil, 0
+	return nil
+}
 func (c *PagedWriter) Add(k, v []byte) (err error) {
 	if c.pageSize <= 1 {
 		_, err = c.parent.Write(v)
@@ -300,7 +364,13 @@ func (c *PagedWriter) Flush() error {
 		return nil
 	}
 	defer c.resetPage()
-	return c.writePage()
+	if err := c.writePage(); err != nil {
+		return err
+	}
+	if len(c.samples) > 0 { // file is smaller than samples limit
+		return c.writeSamples()
+	}
+	return nil
 }
 
 func (c *PagedWriter) bytes() (wholePage []byte, notEmpty bool) {
@@ -326,8 +396,6 @@ func (c *PagedWriter) bytes() (wholePage []byte, notEmpty bool) {
 	}
 
 	wholePage = append(wholePage, keysAndVals...)
-	c.compressionBuf, wholePage = compress.EncodeZstdIfNeed(c.compressionBuf[:0], wholePage, c.compressionEnabled)
-
 	return wholePage, true
 }
 
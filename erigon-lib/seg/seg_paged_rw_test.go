@@ -17,7 +17,9 @@
 package seg
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -29,7 +31,7 @@ import (
 	"github.com/erigontech/erigon-lib/common"
 )
 
-func prepareLoremDictOnPagedWriter(t *testing.T, pageSize int, pageCompression bool) *Decompressor {
+func prepareLoremDictOnPagedWriter(t *testing.T, pageSize int, pageCompression bool, trainDict ...bool) *Decompressor {
 	t.Helper()
 	var loremStrings = append(strings.Split(rmNewLine(lorem), " "), "") // including emtpy string - to trigger corner cases
 	logger, require := log.New(), require.New(t)
@@ -43,6 +45,9 @@ func prepareLoremDictOnPagedWriter(t *testing.T, pageSize int, pageCompression b
 	defer c.Close()
 
 	p := NewPagedWriter(NewWriter(c, CompressNone), pageSize, pageCompression)
+	if len(trainDict) > 0 && trainDict[0] {
+		p.TrainDict()
+	}
 	for k, w := range loremStrings {
 		key := fmt.Sprintf("key %d", k)
 		val := fmt.Sprintf("%s %d", w, k)
@@ -93,6 +98,63 @@ func TestPagedReader(t *testing.T) {
 	require.Equal(82, int(offset))
 }
 
+func TestPagedReaderZstdDict(t *testing.T) {
+	var loremStrings = append(strings.Split(rmNewLine(lorem), " "), "")
+	require := require.New(t)
+
+	d := prepareLoremDictOnPagedWriter(t, 4, true)
+	defer d.Close()
+	header, err := PagesHeaderOf(d.MakeGetter())
+	require.NoError(err)
+	require.Nil(header, "no header without CompressZstd")
+
+	d = prepareLoremDictOnPagedWriter(t, 4, true, true)
+	defer d.Close()
+	header, err = PagesHeaderOf(d.MakeGetter())
+	require.NoError(err)
+	require.NotNil(header)
+	require.NotEmpty(header.dict)
+	require.NotZero(header.FirstPage())
+	require.Equal(1+(len(loremStrings)+3)/4, d.Count())
+
+	g := NewPagedReader(d.MakeGetter(), 4, true)
+	var buf []byte
+	var offsets []uint64
+	for i := 0; g.HasNext(); i++ {
+		var k, v []byte
+		var offset uint64
+		k, v, buf, offset = g.Next2(buf[:0])
+		require.Equal(fmt.Sprintf("key %d", i), string(k))
+		require.Equal(fmt.Sprintf("%s %d", loremStrings[i], i), string(v))
+		offsets = append(offsets, offset)
+	}
+	require.Len(offsets, len(loremStrings))
+	require.Equal(header.FirstPage(), offsets[0])
+
+	g.Reset(0)
+	_, v, _, _ := g.Next2(nil)
+	require.Equal(fmt.Sprintf("%s %d", loremStrings[0], 0), string(v))
+
+	// point lookup: offset from index, then key on the page
+	r := d.MakeGetter()
+	for i := range loremStrings {
+		r.Reset(offsets[i])
+		page, _ := r.Next(nil)
+		v, _ := header.GetFromPage([]byte(fmt.Sprintf("key %d", i)), page, nil, true)
+		require.Equal(fmt.Sprintf("%s %d", loremStrings[i], i), string(v))
+	}
+}
+
+func TestFileCompressionString(t *testing.T) {
+	for _, s := range []string{"none", "k", "v", "kv", "kv+zstd", "none+zstd"} {
+		c, err := ParseFileCompression(s)
+		require.NoError(t, err)
+		require.Equal(t, s, c.String())
+	}
+	_, err := ParseFileCompression("zstd")
+	require.Error(t, err)
+}
+
 // multyBytesWriter is a writer for [][]byte, similar to bytes.Writer.
 type multyBytesWriter struct {
 	buffer [][]byte
@@ -160,3 +222,42 @@ func BenchmarkName(b *testing.B) {
 	})
 
 }
+
+func TestPagesDictHistory(t *testing.T) {
+	page := func(i, size int) []byte {
+		p := bytes.Repeat([]byte{byte(i)}, size)
+		binary.BigEndian.PutUint32(p, uint32(i))
+		return p
+	}
+	pagesOf := func(history []byte, size int) (res []int) {
+		for i := 0; i+4 <= len(history); i += size {
+			res = append(res, int(binary.BigEndian.Uint32(history[i:])))
+		}
+		return res
+	}
+
+	// 16 pages fill history: every 64-th of 1024 pages
+	samples := make([][]byte, 1024)
+	for i := range samples {
+		samples[i] = page(i, pagesDictHistorySize/16)
+	}
+	history := pagesDictHistory(samples)
+	require.Len(t, history, pagesDictHistorySize)
+	pages := pagesOf(history, pagesDictHistorySize/16)
+	require.Len(t, pages, 16)
+	for i, p := range pages {
+		require.Equal(t, i*64, p)
+	}
+
+	// one walk over samples doesn't fill history: walk again
+	samples = make([][]byte, 1024)
+	for i := range samples {
+		size := pagesDictHistorySize / 8
+		if i%2 == 0 {
+			size = 16 // stride is even: pages of first walk are small
+		}
+		samples[i] = page(i, size)
+	}
+	history = pagesDictHistory(samples)
+	require.Len(t, history, pagesDictHistorySize)
+}
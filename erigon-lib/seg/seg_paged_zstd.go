@@ -0,0 +1,185 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package seg
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/erigontech/erigon-lib/common/compress"
+)
+
+// Paged file written with CompressZstd starts with header word: magic + zstd dictionary.
+// Dictionary is trained by PagedWriter on first pages of the file, and all pages compressed with it.
+// Files without header are read as before: pages compressed by zstd without dictionary.
+
+const (
+	pagesDictID          = 1
+	pagesDictHistorySize = 64 * 1024       // raw content of dictionary
+	pagesDictSamplesSize = 4 * 1024 * 1024 // uncompressed pages collected for training
+)
+
+// can't be a page: page starts with amount of values (>0) or with zstd frame magic
+var pagesHeaderMagic = []byte{0, 'z', 's', 't', 'd', 1}
+
+// PagesHeader - header of paged file: dictionary of pages and offset of first page
+type PagesHeader struct {
+	firstPage uint64
+	dict      []byte // empty - pages compressed without dictionary (too few samples to train)
+	decoders  sync.Pool
+}
+
+func newPagesHeader(firstPage uint64, dict []byte) (*PagesHeader, error) {
+	h := &PagesHeader{firstPage: firstPage, dict: dict}
+	if len(dict) == 0 {
+		return h, nil
+	}
+	dec, err := zstd.NewReader(nil, zstd.IgnoreChecksum(true), zstd.WithDecoderDicts(dict))
+	if err != nil {
+		return nil, fmt.Errorf("pages dictionary: %w", err)
+	}
+	h.decoders.Put(dec)
+	h.decoders.New = func() interface{} {
+		dec, _ := zstd.NewReader(nil, zstd.IgnoreChecksum(true), zstd.WithDecoderDicts(dict))
+		return dec
+	}
+	return h, nil
+}
+
+// PagesHeaderOf - header of paged file, read once per file. nil - file has no header.
+func PagesHeaderOf(r ReaderI) (*PagesHeader, error) {
+	var d *Decompressor
+	var newReader func() ReaderI
+	switch r := r.(type) {
+	case *Reader:
+		d, newReader = r.d, func() ReaderI { return NewReader(r.d.MakeGetter(), r.c) }
+	case *Getter:
+		d, newReader = r.d, func() ReaderI { return r.d.MakeGetter() }
+	default:
+		return nil, nil
+	}
+	d.pagesHeaderOnce.Do(func() {
+		d.pagesHeader, d.pagesHeaderErr = readPagesHeader(newReader())
+		if d.pagesHeaderErr != nil {
+			d.pagesHeaderErr = fmt.Errorf("%s: %w", d.FileName(), d.pagesHeaderErr)
+		}
+	})
+	return d.pagesHeader, d.pagesHeaderErr
+}
+
+func readPagesHeader(g ReaderI) (*PagesHeader, error) {
+	g.Reset(0)
+	if !g.HasNext() {
+		return nil, nil
+	}
+	w, nextOffset := g.Next(nil)
+	if !bytes.HasPrefix(w, pagesHeaderMagic) {
+		return nil, nil
+	}
+	return newPagesHeader(nextOffset, bytes.Clone(w[len(pagesHeaderMagic):]))
+}
+
+// FirstPage - offset of first page. nil-safe.
+func (h *PagesHeader) FirstPage() uint64 {
+	if h == nil {
+		return 0
+	}
+	return h.firstPage
+}
+
+// GetFromPage - same as GetFromPage, for pages of file with this header. nil-safe.
+func (h *PagesHeader) GetFromPage(key, compressedPage []byte, compressionBuf []byte, compressionEnabled bool) (v []byte, compressionBufOut []byte) {
+	compressionBuf, page, err := h.decode(compressionBuf[:0], compressedPage, compressionEnabled)
+	if err != nil {
+		panic(err)
+	}
+	return getFromPage(key, page), compressionBuf
+}
+
+func (h *PagesHeader) decode(buf, v []byte, enabled bool) ([]byte, []byte, error) {
+	if h == nil || len(h.dict) == 0 || !enabled {
+		return compress.DecodeZstdIfNeed(buf, v, enabled)
+	}
+	buf = growslice(buf, len(v))
+
+	dec := h.decoders.Get().(*zstd.Decoder)
+	defer func() {
+		_ = dec.Reset(nil)
+		h.decoders.Put(dec)
+	}()
+
+	out, err := dec.DecodeAll(v, buf[:0])
+	if err != nil {
+		return buf, nil, fmt.Errorf("zstd dict decode: %w", err)
+	}
+	return out, out, nil
+}
+
+// trainPagesDict - nil if samples are not enough to build dictionary
+func trainPagesDict(samples [][]byte) []byte {
+	if len(samples) < 2 {
+		return nil
+	}
+	history := pagesDictHistory(samples)
+	if len(history) < 8 {
+		return nil
+	}
+	dict, err := zstd.BuildDict(zstd.BuildDictOptions{
+		ID:       pagesDictID,
+		Contents: samples,
+		History:  history,
+		Offsets:  [3]int{1, 4, 8},
+		Level:    zstd.SpeedDefault,
+	})
+	if err != nil {
+		return nil
+	}
+	return dict
+}
+
+// pagesDictHistory - raw content of dictionary: whole pages evenly spread over samples - keys and values repeat between pages
+func pagesDictHistory(samples [][]byte) []byte {
+	if len(samples) == 0 {
+		return nil
+	}
+	totalSize := 0
+	for _, s := range samples {
+		totalSize += len(s)
+	}
+	pageBytes := max(totalSize/len(samples), 1)
+	pages := (pagesDictHistorySize + pageBytes - 1) / pageBytes // pages which fill history
+	stride := max(len(samples)/pages, 1)                        // in samples
+	history := make([]byte, 0, pagesDictHistorySize)
+	// pages are of different size: if one walk over samples didn't fill history - walk again, shifted
+	for start := 0; start < stride && len(history) < pagesDictHistorySize; start++ {
+		for i := start; i < len(samples) && len(history) < pagesDictHistorySize; i += stride {
+			history = append(history, samples[i][:min(len(samples[i]), pagesDictHistorySize-len(history))]...)
+		}
+	}
+	return history
+}
+
+func newPagesEncoder(dict []byte) (*zstd.Encoder, error) {
+	opts := []zstd.EOption{zstd.WithEncoderCRC(false), zstd.WithZeroFrames(true), zstd.WithEncoderConcurrency(1)}
+	if len(dict) > 0 {
+		opts = append(opts, zstd.WithEncoderDict(dict))
+	}
+	return zstd.NewWriter(nil, opts...)
+}
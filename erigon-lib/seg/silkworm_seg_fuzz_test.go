@@ -227,8 +227,8 @@ func FuzzSilkwormCompress(f *testing.F) {
 		}
 		require.NoError(t, err)
 
-		outPathCRC := checksum(outPath)
-		outPathSilkwormCRC := checksum(outPathSilkworm)
+		outPathCRC := fileChecksum(outPath)
+		outPathSilkwormCRC := fileChecksum(outPathSilkworm)
 		if outPathCRC != outPathSilkwormCRC {
 			assert.Equal(t, outPathCRC, outPathSilkwormCRC)
 			copyFiles([]string{path, outPath}, investigationDir)
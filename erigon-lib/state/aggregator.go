@@ -93,6 +93,8 @@ type Aggregator struct {
 	produce bool
 
 	checker *DependencyIntegrityChecker
+
+	historyRetention historyRetention
 }
 
 const AggregatorSqueezeCommitmentValues = true
@@ -1527,6 +1529,12 @@ func (a *Aggregator) KeepRecentTxnsOfHistoriesWithDisabledSnapshots(recentTxs ui
 	return a
 }
 
+// SetHistoryRetention - history of txNums before horizonTxNum is kept only for keys accepted by keep (nil - keep all history).
+// Affects histories of accounts, storage, code and indices of logs addresses, traces from/to: their keys start with address.
+func (a *Aggregator) SetHistoryRetention(keep func(k []byte) bool, horizonTxNum uint64) {
+	a.historyRetention.set(keep, horizonTxNum)
+}
+
 func (a *Aggregator) SetSnapshotBuildSema(semaphore *semaphore.Weighted) {
 	a.snapshotBuildSema = semaphore
 }
@@ -70,6 +70,14 @@ func NewAggregator2(ctx context.Context, dirs datadir.Dirs, aggregationStep uint
 	a.AddDependencyBtwnDomains(kv.AccountsDomain, kv.CommitmentDomain)
 	a.AddDependencyBtwnDomains(kv.StorageDomain, kv.CommitmentDomain)
 
+	// keys start with address - see SetHistoryRetention
+	for _, d := range []kv.Domain{kv.AccountsDomain, kv.StorageDomain, kv.CodeDomain} {
+		a.d[d].History.retention = &a.historyRetention
+	}
+	for _, ii := range []kv.InvertedIdx{kv.LogAddrIdx, kv.TracesFromIdx, kv.TracesToIdx} {
+		a.searchII(ii).retention = &a.historyRetention
+	}
+
 	a.KeepRecentTxnsOfHistoriesWithDisabledSnapshots(100_000) // ~1k blocks of history
 
 	a.dirtyFilesLock.Lock()
@@ -237,7 +245,7 @@ var Schema = SchemaGen{
 
 		hist: histCfg{
 			valuesTable:   kv.TblCommitmentHistoryVals,
-			CompressorCfg: HistoryCompressCfg, Compression: seg.CompressNone, // seg.CompressKeys | seg.CompressVals,
+			CompressorCfg: HistoryCompressCfg, Compression: seg.CompressNone | seg.CompressZstd, // seg.CompressKeys | seg.CompressVals,
 			historyIdx: kv.CommitmentHistoryIdx,
 
 			historyLargeValues:            false,
@@ -283,7 +291,7 @@ var Schema = SchemaGen{
 
 		hist: histCfg{
 			valuesTable: kv.TblRCacheHistoryVals,
-			Compression: seg.CompressNone, //seg.CompressKeys | seg.CompressVals,
+			Compression: seg.CompressNone | seg.CompressZstd, //seg.CompressKeys | seg.CompressVals,
 
 			historyLargeValues: true,
 			historyIdx:         kv.RCacheHistoryIdx,
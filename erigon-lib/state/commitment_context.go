@@ -38,7 +38,9 @@ type SharedDomainsCommitmentContext struct {
 }
 
 // Limits max txNum for read operations. If set to 0, all read operations will be from latest value.
-// If domainOnly=true and txNum > 0, then read operations will be limited to domain files only.
+// If domainOnly=true and txNum > 0, then only accounts and storage are read as of txNum, branches are read from the domain.
+// Otherwise branches are read from the commitment history too, so the trie is the one as of txNum, which requires
+// historical commitment to be enabled.
 func (sdc *SharedDomainsCommitmentContext) SetLimitReadAsOfTxNum(txNum uint64, domainOnly bool) {
 	sdc.mainTtx.SetLimitReadAsOfTxNum(txNum, domainOnly)
 }
@@ -108,6 +110,18 @@ func (sdc *SharedDomainsCommitmentContext) Witness(ctx context.Context, codeRead
 	return nil, nil, errors.New("shared domains commitment context doesn't have HexPatriciaHashed")
 }
 
+// WalkLeaves walks the plain keys of the accounts, or of the storage of an account, in the order of their
+// hashed keys. See commitment.WalkLeaves.
+func (sdc *SharedDomainsCommitmentContext) WalkLeaves(prefix, from []byte, fn func(plainKey, hashedKey []byte) (bool, error)) error {
+	return commitment.WalkLeaves(sdc.mainTtx, prefix, from, fn)
+}
+
+// PrevLeaf returns the plain key of the last account, or storage slot of an account, whose hashed key is less
+// than before. See commitment.PrevLeaf.
+func (sdc *SharedDomainsCommitmentContext) PrevLeaf(prefix, before []byte) ([]byte, error) {
+	return commitment.PrevLeaf(sdc.mainTtx, prefix, before)
+}
+
 // Evaluates commitment for gathered updates.
 func (sdc *SharedDomainsCommitmentContext) ComputeCommitment(ctx context.Context, saveState bool, blockNum uint64, txNum uint64, logPrefix string) (rootHash []byte, err error) {
 	mxCommitmentRunning.Inc()
@@ -393,7 +407,7 @@ type TrieContext struct {
 
 	limitReadAsOfTxNum uint64
 	stepSize           uint64
-	withHistory        bool // if true, do not use history reader and limit to domain files only
+	withHistory        bool // if true, branches are read from the commitment history as of limitReadAsOfTxNum
 	trace              bool
 }
 
@@ -405,20 +419,18 @@ func (sdc *TrieContext) Branch(pref []byte) ([]byte, uint64, error) {
 	// Trie reads prefix during unfold and after everything is ready reads it again to Merge update.
 	// Keep dereferenced version inside sd commitmentDomain map ready to read again
 	if sdc.withHistory && sdc.limitReadAsOfTxNum > 0 {
-		v, hOk, err := sdc.roTtx.HistorySeek(kv.CommitmentDomain, pref, sdc.limitReadAsOfTxNum)
+		// branches which didn't change since limitReadAsOfTxNum have no history yet and are read from the domain
+		v, _, err := sdc.roTtx.GetAsOf(kv.CommitmentDomain, pref, sdc.limitReadAsOfTxNum)
 		if err != nil {
 			return nil, 0, fmt.Errorf("branch failed: %w", err)
 		}
-		if hOk {
-			if len(v) == 0 { // if history successfuly found marker of key creation
-				return nil, 0, nil
-			}
-			if sdc.trace {
-				fmt.Printf("[SDC] Branch @%d: %x: %x\n%s\n", sdc.limitReadAsOfTxNum, pref, v, commitment.BranchData(v).String())
-			}
-			return v, 0, nil
+		if sdc.trace {
+			fmt.Printf("[SDC] Branch @%d: %x: %x\n%s\n", sdc.limitReadAsOfTxNum, pref, v, commitment.BranchData(v).String())
+		}
+		if len(v) == 0 {
+			return nil, 0, nil
 		}
-		return nil, 0, nil // no history found, so no branch
+		return v, 0, nil
 	}
 
 	// Trie reads prefix during unfold and after everything is ready reads it again to Merge update.
@@ -458,15 +470,7 @@ func (sdc *TrieContext) readDomain(d kv.Domain, plainKey []byte) (enc []byte, er
 	//}
 
 	if sdc.limitReadAsOfTxNum > 0 {
-		if sdc.withHistory {
-			var ok bool
-			enc, ok, _, _, err = sdc.roTtx.Debug().GetLatestFromFiles(d, plainKey, sdc.limitReadAsOfTxNum)
-			if !ok {
-				enc = nil
-			}
-		} else {
-			enc, _, err = sdc.roTtx.GetAsOf(d, plainKey, sdc.limitReadAsOfTxNum)
-		}
+		enc, _, err = sdc.roTtx.GetAsOf(d, plainKey, sdc.limitReadAsOfTxNum)
 	} else {
 		enc, _, err = sdc.getter.GetLatest(d, plainKey)
 	}
@@ -540,7 +544,7 @@ func (sdc *TrieContext) Storage(plainKey []byte) (u *commitment.Update, err erro
 }
 
 // Limits max txNum for read operations. If set to 0, all read operations will be from latest value.
-// If domainOnly=true and txNum > 0, then read operations will be limited to domain files only.
+// If domainOnly=true and txNum > 0, then only accounts and storage are read as of txNum, branches are read from the domain.
 func (sdc *TrieContext) SetLimitReadAsOfTxNum(txNum uint64, domainOnly bool) {
 	sdc.limitReadAsOfTxNum = txNum
 	sdc.withHistory = !domainOnly
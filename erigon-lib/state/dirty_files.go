@@ -29,6 +29,7 @@ import (
 
 	btree2 "github.com/tidwall/btree"
 
+	"github.com/erigontech/erigon-lib/common/checksum"
 	"github.com/erigontech/erigon-lib/common/dir"
 	"github.com/erigontech/erigon-lib/config3"
 	"github.com/erigontech/erigon-lib/datastruct/existence"
@@ -163,6 +164,7 @@ func (i *FilesItem) closeFilesAndRemove() {
 			if err := os.Remove(i.decompressor.FilePath() + ".torrent"); err != nil {
 				log.Trace("remove after close", "err", err, "file", i.decompressor.FileName()+".torrent")
 			}
+			checksum.Remove(i.decompressor.FilePath())
 		}
 		i.decompressor = nil
 	}
@@ -176,6 +178,7 @@ func (i *FilesItem) closeFilesAndRemove() {
 			if err := os.Remove(i.index.FilePath() + ".torrent"); err != nil {
 				log.Trace("remove after close", "err", err, "file", i.index.FileName())
 			}
+			checksum.Remove(i.index.FilePath())
 		}
 		i.index = nil
 	}
@@ -187,6 +190,7 @@ func (i *FilesItem) closeFilesAndRemove() {
 		if err := os.Remove(i.bindex.FilePath() + ".torrent"); err != nil {
 			log.Trace("remove after close", "err", err, "file", i.bindex.FileName())
 		}
+		checksum.Remove(i.bindex.FilePath())
 		i.bindex = nil
 	}
 	if i.existence != nil {
@@ -197,6 +201,7 @@ func (i *FilesItem) closeFilesAndRemove() {
 		if err := os.Remove(i.existence.FilePath + ".torrent"); err != nil {
 			log.Trace("remove after close", "err", err, "file", i.existence.FilePath)
 		}
+		checksum.Remove(i.existence.FilePath)
 		i.existence = nil
 	}
 }
@@ -32,6 +32,7 @@ import (
 	"github.com/erigontech/erigon-lib/commitment"
 	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/assert"
+	"github.com/erigontech/erigon-lib/crypto"
 	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/log/v3"
 )
@@ -81,6 +82,7 @@ type SharedDomains struct {
 	muMaps  sync.RWMutex
 	domains [kv.DomainLen]map[string]dataWithPrevStep
 	storage *btree2.Map[string, dataWithPrevStep]
+	codes   map[string][]byte // code hash -> code, written to kv.Code by Flush
 
 	domainWriters [kv.DomainLen]*DomainBufferedWriter
 	iiWriters     []*InvertedIndexBufferedWriter
@@ -97,6 +99,7 @@ func NewSharedDomains(tx kv.TemporalTx, logger log.Logger) (*SharedDomains, erro
 	sd := &SharedDomains{
 		logger:  logger,
 		storage: btree2.NewMap[string, dataWithPrevStep](128),
+		codes:   map[string][]byte{},
 		//trace:   true,
 	}
 	aggTx := AggTx(tx)
@@ -213,6 +216,7 @@ func (sd *SharedDomains) ClearRam(resetCommitment bool) {
 	}
 
 	sd.storage = btree2.NewMap[string, dataWithPrevStep](128)
+	sd.codes = map[string][]byte{}
 	sd.estSize = 0
 }
 
@@ -266,6 +270,31 @@ func (sd *SharedDomains) SizeEstimate() uint64 {
 const CodeSizeTableFake = "CodeSize"
 
 func (sd *SharedDomains) ReadsValid(readLists map[string]*KvList) bool {
+	valid := true
+	sd.scanStaleReads(readLists, func(table, key string) bool {
+		valid = false
+		return false
+	})
+	return valid
+}
+
+// StaleRead - key read by a parallel worker, whose value was changed since by an applied txn
+type StaleRead struct {
+	Table string // domain name or CodeSizeTableFake
+	Key   []byte
+}
+
+// StaleReads - like ReadsValid, but returns all the stale reads instead of stopping at the first one.
+// Used to explain conflicts of parallel execution.
+func (sd *SharedDomains) StaleReads(readLists map[string]*KvList) (stale []StaleRead) {
+	sd.scanStaleReads(readLists, func(table, key string) bool {
+		stale = append(stale, StaleRead{Table: table, Key: []byte(key)})
+		return true
+	})
+	return stale
+}
+
+func (sd *SharedDomains) scanStaleReads(readLists map[string]*KvList, f func(table, key string) bool) {
 	sd.muMaps.RLock()
 	defer sd.muMaps.RUnlock()
 
@@ -275,8 +304,8 @@ func (sd *SharedDomains) ReadsValid(readLists map[string]*KvList) bool {
 			m := sd.domains[kv.AccountsDomain]
 			for i, key := range list.Keys {
 				if val, ok := m[key]; ok {
-					if !bytes.Equal(list.Vals[i], val.data) {
-						return false
+					if !bytes.Equal(list.Vals[i], val.data) && !f(table, key) {
+						return
 					}
 				}
 			}
@@ -284,8 +313,8 @@ func (sd *SharedDomains) ReadsValid(readLists map[string]*KvList) bool {
 			m := sd.domains[kv.CodeDomain]
 			for i, key := range list.Keys {
 				if val, ok := m[key]; ok {
-					if !bytes.Equal(list.Vals[i], val.data) {
-						return false
+					if !bytes.Equal(list.Vals[i], val.data) && !f(table, key) {
+						return
 					}
 				}
 			}
@@ -293,8 +322,8 @@ func (sd *SharedDomains) ReadsValid(readLists map[string]*KvList) bool {
 			m := sd.storage
 			for i, key := range list.Keys {
 				if val, ok := m.Get(key); ok {
-					if !bytes.Equal(list.Vals[i], val.data) {
-						return false
+					if !bytes.Equal(list.Vals[i], val.data) && !f(table, key) {
+						return
 					}
 				}
 			}
@@ -302,8 +331,8 @@ func (sd *SharedDomains) ReadsValid(readLists map[string]*KvList) bool {
 			m := sd.domains[kv.CodeDomain]
 			for i, key := range list.Keys {
 				if val, ok := m[key]; ok {
-					if binary.BigEndian.Uint64(list.Vals[i]) != uint64(len(val.data)) {
-						return false
+					if binary.BigEndian.Uint64(list.Vals[i]) != uint64(len(val.data)) && !f(table, key) {
+						return
 					}
 				}
 			}
@@ -311,8 +340,6 @@ func (sd *SharedDomains) ReadsValid(readLists map[string]*KvList) bool {
 			panic(table)
 		}
 	}
-
-	return true
 }
 
 func (sd *SharedDomains) updateAccountCode(addrS string, code []byte, txNum uint64, prevCode []byte, prevStep uint64) error {
@@ -321,6 +348,9 @@ func (sd *SharedDomains) updateAccountCode(addrS string, code []byte, txNum uint
 	if len(code) == 0 {
 		return sd.domainWriters[kv.CodeDomain].DeleteWithPrev(addr, txNum, prevCode, prevStep)
 	}
+	sd.muMaps.Lock()
+	sd.codes[string(crypto.Keccak256(code))] = code
+	sd.muMaps.Unlock()
 	return sd.domainWriters[kv.CodeDomain].PutWithPrev(addr, code, txNum, prevCode, prevStep)
 }
 
@@ -449,6 +479,22 @@ func (sd *SharedDomains) flushDiffSet(ctx context.Context, tx kv.RwTx) error {
 	}
 	return nil
 }
+
+// flushCodes writes the codes by their hash to kv.Code, which the code domain
+// keyed by address doesn't allow to look up. Codes are never removed from it,
+// so unwinds leave the codes of the dropped blocks.
+func (sd *SharedDomains) flushCodes(tx kv.RwTx) error {
+	sd.muMaps.Lock()
+	defer sd.muMaps.Unlock()
+	for hash, code := range sd.codes {
+		if err := tx.Put(kv.Code, toBytesZeroCopy(hash), code); err != nil {
+			return err
+		}
+	}
+	sd.codes = map[string][]byte{}
+	return nil
+}
+
 func (sd *SharedDomains) flushWriters(ctx context.Context, tx kv.RwTx) error {
 	aggTx := AggTx(tx)
 	for di, w := range sd.domainWriters {
@@ -479,6 +525,9 @@ func (sd *SharedDomains) FlushWithoutCommitment(ctx context.Context, tx kv.RwTx)
 		return err
 	}
 	sd.pastChangesAccumulator = make(map[string]*StateChangeSet)
+	if err := sd.flushCodes(tx); err != nil {
+		return err
+	}
 	if err := sd.flushWriters(ctx, tx); err != nil {
 		return err
 	}
@@ -491,6 +540,9 @@ func (sd *SharedDomains) Flush(ctx context.Context, tx kv.RwTx) error {
 		return err
 	}
 	sd.pastChangesAccumulator = make(map[string]*StateChangeSet)
+	if err := sd.flushCodes(tx); err != nil {
+		return err
+	}
 	//_, err := sd.ComputeCommitment(ctx, true, sd.BlockNum(), sd.txNum, "flush-commitment")
 	//if err != nil {
 	//	return err
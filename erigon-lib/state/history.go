@@ -299,6 +299,14 @@ func (h *History) buildVI(ctx context.Context, historyIdxPath string, hist, efHi
 	}
 
 	histReader := h.dataReader(hist)
+	var firstPage uint64 // after header of file with dictionary of pages
+	if h.historyValuesOnCompressedPage > 1 {
+		header, err := seg.PagesHeaderOf(histReader)
+		if err != nil {
+			return err
+		}
+		firstPage = header.FirstPage()
+	}
 
 	_, fName := filepath.Split(historyIdxPath)
 	p := ps.AddNew(fName, uint64(efHist.Count())/2)
@@ -323,10 +331,10 @@ func (h *History) buildVI(ctx context.Context, historyIdxPath string, hist, efHi
 
 	i := 0
 	for {
-		histReader.Reset(0)
+		histReader.Reset(firstPage)
 		iiReader.Reset(0)
 
-		valOffset = 0
+		valOffset = firstPage
 		for iiReader.HasNext() {
 			keyBuf, _ = iiReader.Next(keyBuf[:0])
 			valBuf, _ = iiReader.Next(valBuf[:0])
@@ -576,6 +584,7 @@ func (h *History) collate(ctx context.Context, step, txFrom, txTo uint64, roTx k
 	collector := etl.NewCollectorWithAllocator(h.filenameBase+".collate.hist", h.dirs.Tmp, etl.SmallSortableBuffers, h.logger).LogLvl(log.LvlTrace)
 	defer collector.Close()
 	collector.SortAndFlushInBackground(true)
+	keep := h.retention.keepFunc(txTo)
 
 	for txnmb, k, err := keysCursor.Seek(txKey[:]); txnmb != nil; txnmb, k, err = keysCursor.Next() {
 		if err != nil {
@@ -585,6 +594,9 @@ func (h *History) collate(ctx context.Context, step, txFrom, txTo uint64, roTx k
 		if txNum >= txTo { // [txFrom; txTo)
 			break
 		}
+		if keep != nil && !keep(k) {
+			continue
+		}
 		if err := collector.Collect(k, txnmb); err != nil {
 			return HistoryCollation{}, fmt.Errorf("collect %s history key [%x]=>txn %d [%x]: %w", h.filenameBase, k, txNum, txnmb, err)
 		}
@@ -885,7 +897,11 @@ func (h *History) dataWriter(f *seg.Compressor) *seg.PagedWriter {
 	if !strings.Contains(f.FileName(), ".v") {
 		panic("assert: miss-use " + f.FileName())
 	}
-	return seg.NewPagedWriter(seg.NewWriter(f, h.Compression), h.historyValuesOnCompressedPage, true)
+	w := seg.NewPagedWriter(seg.NewWriter(f, h.Compression), h.historyValuesOnCompressedPage, true)
+	if h.Compression.Has(seg.CompressZstd) {
+		w.TrainDict()
+	}
+	return w
 }
 func (ht *HistoryRoTx) dataReader(f *seg.Decompressor) *seg.Reader     { return ht.h.dataReader(f) }
 func (ht *HistoryRoTx) datarWriter(f *seg.Compressor) *seg.PagedWriter { return ht.h.dataWriter(f) }
@@ -1165,7 +1181,11 @@ func (ht *HistoryRoTx) historySeekInFiles(key []byte, txNum uint64) ([]byte, boo
 	}
 
 	if ht.h.historyValuesOnCompressedPage > 1 {
-		v, ht.snappyReadBuffer = seg.GetFromPage(historyKey, v, ht.snappyReadBuffer, true)
+		header, err := seg.PagesHeaderOf(g)
+		if err != nil {
+			return nil, false, err
+		}
+		v, ht.snappyReadBuffer = header.GetFromPage(historyKey, v, ht.snappyReadBuffer, true)
 	}
 	return v, true, nil
 }
@@ -0,0 +1,75 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"sync/atomic"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/length"
+)
+
+// historyRetention - key-scoped retention policy of History/InvertedIndex: history of txNums before horizon
+// is kept only for keys accepted by `keep` (for example: keys of watched accounts). Newer history is kept fully.
+//
+// Applied when files are collated and merged: not-kept keys don't get into files which end before horizon.
+// Prune doesn't need to know about it - it removes from DB only what is already in files.
+// Existing files are not re-written: they lose not-kept keys when merged into bigger ones.
+type historyRetention struct {
+	policy atomic.Pointer[retentionPolicy]
+}
+
+type retentionPolicy struct {
+	keep    func(k []byte) bool
+	horizon uint64 // txNum
+}
+
+func (r *historyRetention) set(keep func(k []byte) bool, horizon uint64) {
+	if keep == nil {
+		r.policy.Store(nil)
+		return
+	}
+	r.policy.Store(&retentionPolicy{keep: keep, horizon: horizon})
+}
+
+// keepFunc - filter of keys for files which end at `toTxNum`. nil - all keys must be kept.
+// Caller must use one filter for all files built together (.ef and .v), because horizon can move in-between.
+func (r *historyRetention) keepFunc(toTxNum uint64) func(k []byte) bool {
+	if r == nil {
+		return nil
+	}
+	p := r.policy.Load()
+	if p == nil || toTxNum > p.horizon {
+		return nil
+	}
+	return p.keep
+}
+
+// KeepAddresses - `keep` func for SetHistoryRetention: accepts keys which start with one of given addresses
+func KeepAddresses(addrs []common.Address) func(k []byte) bool {
+	set := make(map[common.Address]struct{}, len(addrs))
+	for _, addr := range addrs {
+		set[addr] = struct{}{}
+	}
+	return func(k []byte) bool {
+		if len(k) < length.Addr {
+			return false
+		}
+		_, ok := set[common.Address(k[:length.Addr])]
+		return ok
+	}
+}
@@ -956,6 +956,82 @@ func TestHistoryMergeFiles(t *testing.T) {
 		checkHistoryHistory(t, h, txs)
 	}
 
+	t.Run("large_values", func(t *testing.T) {
+		db, h, txs := filledHistory(t, true, logger)
+		test(t, h, db, txs)
+	})
+	t.Run("small_values", func(t *testing.T) {
+		db, h, txs := filledHistory(t, false, logger)
+		test(t, h, db, txs)
+	})
+	t.Run("pages_zstd_dict", func(t *testing.T) {
+		db, h, txs := filledHistory(t, false, logger)
+		h.historyValuesOnCompressedPage = 16
+		h.Compression = seg.CompressNone | seg.CompressZstd
+		test(t, h, db, txs)
+
+		hc := h.BeginFilesRo()
+		defer hc.Close()
+		header, err := seg.PagesHeaderOf(hc.statelessGetter(0))
+		require.NoError(t, err)
+		require.NotNil(t, header)
+	})
+}
+
+func TestHistoryRetention(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	t.Parallel()
+
+	logger := log.New()
+	test := func(t *testing.T, h *History, db kv.RwDB, txs uint64) {
+		t.Helper()
+		const horizon = 500
+		keep := func(k []byte) bool { return k[len(k)-1]%2 == 0 }
+		h.retention = &historyRetention{}
+		h.retention.set(keep, horizon)
+		collateAndMergeHistory(t, db, h, txs, true)
+
+		tx, err := db.BeginRo(context.Background())
+		require.NoError(t, err)
+		defer tx.Rollback()
+		hc := h.BeginFilesRo()
+		defer hc.Close()
+		for keyNum := uint64(1); keyNum <= uint64(31); keyNum++ {
+			var k [8]byte
+			binary.BigEndian.PutUint64(k[:], keyNum)
+			k[0] = 1
+			label := fmt.Sprintf("keyNum=%d", keyNum)
+
+			it, err := hc.IdxRange(k[:], 0, horizon-int(h.aggregationStep), order.Asc, -1, tx)
+			require.NoError(t, err)
+			old, err := stream.ToArrayU64(it)
+			require.NoError(t, err)
+			it, err = hc.IdxRange(k[:], 2*int(h.aggregationStep)+horizon, int(txs), order.Asc, -1, tx)
+			require.NoError(t, err)
+			recent, err := stream.ToArrayU64(it)
+			require.NoError(t, err)
+			require.NotEmpty(t, recent, label)
+			if !keep(k[:]) {
+				require.Empty(t, old, label)
+				continue
+			}
+			require.NotEmpty(t, old, label)
+			for txNum := uint64(0); txNum <= txs; txNum++ {
+				var v [8]byte
+				binary.BigEndian.PutUint64(v[:], txNum/keyNum)
+				v[0] = 0xff
+				val, ok, err := hc.historySeekInFiles(k[:], txNum+1)
+				require.NoError(t, err)
+				if ok && txNum >= keyNum {
+					require.Equal(t, v[:], val, label)
+				}
+			}
+		}
+	}
+
 	t.Run("large_values", func(t *testing.T) {
 		db, h, txs := filledHistory(t, true, logger)
 		test(t, h, db, txs)
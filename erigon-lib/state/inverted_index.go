@@ -74,7 +74,8 @@ type InvertedIndex struct {
 	_visible *iiVisible
 	logger   log.Logger
 
-	checker *DependencyIntegrityChecker
+	checker   *DependencyIntegrityChecker
+	retention *historyRetention // nil - keep all keys
 }
 
 type iiCfg struct {
@@ -1005,6 +1006,7 @@ func (ii *InvertedIndex) collate(ctx context.Context, step uint64, roTx kv.Tx) (
 
 	var txKey [8]byte
 	binary.BigEndian.PutUint64(txKey[:], txFrom)
+	keep := ii.retention.keepFunc(txTo)
 
 	for k, v, err := keysCursor.Seek(txKey[:]); k != nil; k, v, err = keysCursor.Next() {
 		if err != nil {
@@ -1014,6 +1016,9 @@ func (ii *InvertedIndex) collate(ctx context.Context, step uint64, roTx kv.Tx) (
 		if txNum >= txTo { // [txFrom; txTo)
 			break
 		}
+		if keep != nil && !keep(v) {
+			continue
+		}
 		if err := collector.Collect(v, k); err != nil {
 			return InvertedIndexCollation{}, fmt.Errorf("collect %s history key [%x]=>txn %d [%x]: %w", ii.filenameBase, k, txNum, k, err)
 		}
@@ -598,6 +598,57 @@ func TestInvIndexMerge(t *testing.T) {
 	checkRanges(t, db, ii, txs)
 }
 
+func TestInvIndexRetention(t *testing.T) {
+	logger := log.New()
+	db, ii, _ := filledInvIndex(t, logger)
+	ctx := context.Background()
+	tx, err := db.BeginRo(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	keep := func(k []byte) bool { return k[len(k)-1]%2 == 0 }
+	keysOf := func(item *FilesItem) (keys []uint64) {
+		g := ii.dataReader(item.decompressor)
+		for g.Reset(0); g.HasNext(); {
+			k, _ := g.Next(nil)
+			g.Skip()
+			keys = append(keys, binary.BigEndian.Uint64(k))
+		}
+		return keys
+	}
+	build := func(step uint64) {
+		coll, err := ii.collate(ctx, step, tx)
+		require.NoError(t, err)
+		sf, err := ii.buildFiles(ctx, step, coll, background.NewProgressSet())
+		require.NoError(t, err)
+		ii.integrateDirtyFiles(sf, step*ii.aggregationStep, (step+1)*ii.aggregationStep)
+		ii.reCalcVisibleFiles(ii.dirtyFilesEndTxNumMinimax())
+	}
+
+	ii.retention = &historyRetention{}
+	ii.retention.set(keep, 2*ii.aggregationStep)
+	for step := uint64(0); step < 4; step++ {
+		build(step)
+	}
+	ic := ii.BeginFilesRo()
+	defer ic.Close()
+	even := []uint64{2, 4, 6, 8, 10, 12, 14, 16, 18, 20, 22, 24, 26, 28, 30}
+	require.Equal(t, even, keysOf(ic.files[1].src), "collated behind horizon")
+	require.Contains(t, keysOf(ic.files[2].src), uint64(3), "collated after horizon")
+
+	ii.retention.set(keep, 4*ii.aggregationStep)
+	in, err := ic.mergeFiles(ctx, ic.staticFilesInRange(0, 4*ii.aggregationStep), 0, 4*ii.aggregationStep, background.NewProgressSet())
+	require.NoError(t, err)
+	defer in.closeFilesAndRemove()
+	require.Equal(t, even, keysOf(in), "merged behind horizon")
+
+	ii.retention.set(nil, 0)
+	in2, err := ic.mergeFiles(ctx, ic.staticFilesInRange(2*ii.aggregationStep, 4*ii.aggregationStep), 2*ii.aggregationStep, 4*ii.aggregationStep, background.NewProgressSet())
+	require.NoError(t, err)
+	defer in2.closeFilesAndRemove()
+	require.Contains(t, keysOf(in2), uint64(3))
+}
+
 func TestInvIndexScanFiles(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -584,6 +584,11 @@ func (dt *DomainRoTx) mergeFiles(ctx context.Context, domainFiles, indexFiles, h
 }
 
 func (iit *InvertedIndexRoTx) mergeFiles(ctx context.Context, files []*FilesItem, startTxNum, endTxNum uint64, ps *background.ProgressSet) (*FilesItem, error) {
+	return iit.mergeKeptFiles(ctx, files, startTxNum, endTxNum, iit.ii.retention.keepFunc(endTxNum), ps)
+}
+
+// mergeKeptFiles - merges files, leaving only keys accepted by `keep` (nil - all keys)
+func (iit *InvertedIndexRoTx) mergeKeptFiles(ctx context.Context, files []*FilesItem, startTxNum, endTxNum uint64, keep func(k []byte) bool, ps *background.ProgressSet) (*FilesItem, error) {
 	if startTxNum == endTxNum {
 		panic(fmt.Sprintf("assert: startTxNum(%d) == endTxNum(%d)", startTxNum, endTxNum))
 	}
@@ -689,7 +694,7 @@ func (iit *InvertedIndexRoTx) mergeFiles(ctx context.Context, files []*FilesItem
 				heap.Push(&cp, ci1)
 			}
 		}
-		if keyBuf != nil {
+		if keyBuf != nil && (keep == nil || keep(keyBuf)) {
 			// fmt.Printf("pput %x->%x\n", keyBuf, valBuf)
 			if _, err = write.Write(keyBuf); err != nil {
 				return nil, err
@@ -704,7 +709,7 @@ func (iit *InvertedIndexRoTx) mergeFiles(ctx context.Context, files []*FilesItem
 		}
 		valBuf = append(valBuf[:0], lastVal...)
 	}
-	if keyBuf != nil {
+	if keyBuf != nil && (keep == nil || keep(keyBuf)) {
 		// fmt.Printf("put %x->%x\n", keyBuf, valBuf)
 		if _, err = write.Write(keyBuf); err != nil {
 			return nil, err
@@ -749,7 +754,9 @@ func (ht *HistoryRoTx) mergeFiles(ctx context.Context, indexFiles, historyFiles
 		}
 	}()
 
-	if indexIn, err = ht.iit.mergeFiles(ctx, indexFiles, r.index.from, r.index.to, ps); err != nil {
+	// .v file must have same keys as merged .ef file - see buildVI
+	keep := ht.h.retention.keepFunc(r.index.to)
+	if indexIn, err = ht.iit.mergeKeptFiles(ctx, indexFiles, r.index.from, r.index.to, keep, ps); err != nil {
 		return nil, nil, err
 	}
 	if r.history.needMerge {
@@ -829,6 +836,7 @@ func (ht *HistoryRoTx) mergeFiles(ctx context.Context, indexFiles, historyFiles
 		var keyCount int
 		for cp.Len() > 0 {
 			lastKey = append(lastKey[:0], cp[0].key...)
+			kept := keep == nil || keep(lastKey)
 			// Advance all the items that have this key (including the top)
 			for cp.Len() > 0 && bytes.Equal(cp[0].key, lastKey) {
 				ci1 := heap.Pop(&cp).(*CursorItem)
@@ -840,13 +848,18 @@ func (ht *HistoryRoTx) mergeFiles(ctx context.Context, indexFiles, historyFiles
 
 					var k, v []byte
 					k, v, valBuf, _ = ci1.hist.Next2(valBuf[:0])
+					if !kept {
+						continue
+					}
 					if err = pagedWr.Add(k, v); err != nil {
 						return nil, nil, err
 					}
 				}
 
 				// fmt.Printf("fput '%x'->%x\n", lastKey, ci1.val)
-				keyCount += int(count)
+				if kept {
+					keyCount += int(count)
+				}
 				if ci1.idx.HasNext() {
 					ci1.key, _ = ci1.idx.Next(ci1.key[:0])
 					ci1.val, _ = ci1.idx.Next(ci1.val[:0])
@@ -32,7 +32,7 @@ func InitSchemas() {
 
 	Schema.CommitmentDomain.version.DataKV = version.V1_0_standart
 	Schema.CommitmentDomain.version.AccessorKVI = version.V2_0_standart
-	Schema.CommitmentDomain.hist.version.DataV = version.V1_0_standart
+	Schema.CommitmentDomain.hist.version.DataV = version.V1_1_standart // v1.1: pages with zstd dictionary
 	Schema.CommitmentDomain.hist.version.AccessorVI = version.V1_0_standart
 	Schema.CommitmentDomain.hist.iiCfg.version.DataEF = version.V2_0_standart
 	Schema.CommitmentDomain.hist.iiCfg.version.AccessorEFI = version.V2_0_standart
@@ -47,7 +47,7 @@ func InitSchemas() {
 
 	Schema.RCacheDomain.version.DataKV = version.V2_0_standart
 	Schema.RCacheDomain.version.AccessorKVI = version.V2_0_standart
-	Schema.RCacheDomain.hist.version.DataV = version.V2_0_standart
+	Schema.RCacheDomain.hist.version.DataV = version.V2_1_standart // v2.1: pages with zstd dictionary
 	Schema.RCacheDomain.hist.version.AccessorVI = version.V1_0_standart
 	Schema.RCacheDomain.hist.iiCfg.version.DataEF = version.V2_0_standart
 	Schema.RCacheDomain.hist.iiCfg.version.AccessorEFI = version.V2_0_standart
@@ -96,6 +96,14 @@ type receiptRLP struct {
 	Logs              []*Log
 }
 
+// receipt69RLP is the eth/69 network encoding of a receipt.
+type receipt69RLP struct {
+	Type              uint8
+	PostStateOrStatus []byte
+	CumulativeGasUsed uint64
+	Logs              []*Log
+}
+
 // storedReceiptRLP is the storage encoding of a receipt.
 type storedReceiptRLP struct {
 	Type              uint8
@@ -411,6 +419,40 @@ func (r *ReceiptForStorage) DecodeRLP(s *rlp.Stream) error {
 	return nil
 }
 
+// ReceiptForNetwork69 is a wrapper around a Receipt with the eth/69 network
+// serialization: the transaction type is a field of the list instead of an
+// envelope and the Bloom field is omitted, deserialization re-computes it.
+type ReceiptForNetwork69 Receipt
+
+// EncodeRLP implements rlp.Encoder.
+func (r *ReceiptForNetwork69) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, &receipt69RLP{
+		Type:              r.Type,
+		PostStateOrStatus: (*Receipt)(r).statusEncoding(),
+		CumulativeGasUsed: r.CumulativeGasUsed,
+		Logs:              r.Logs,
+	})
+}
+
+// DecodeRLP implements rlp.Decoder.
+func (r *ReceiptForNetwork69) DecodeRLP(s *rlp.Stream) error {
+	var data receipt69RLP
+	if err := s.Decode(&data); err != nil {
+		return err
+	}
+	switch data.Type {
+	case LegacyTxType, AccessListTxType, DynamicFeeTxType, BlobTxType, SetCodeTxType:
+	default:
+		return ErrTxTypeNotSupported
+	}
+	if err := (*Receipt)(r).setStatus(data.PostStateOrStatus); err != nil {
+		return err
+	}
+	r.Type, r.CumulativeGasUsed, r.Logs = data.Type, data.CumulativeGasUsed, data.Logs
+	r.Bloom = LogsBloom(r.Logs)
+	return nil
+}
+
 // Receipts implements DerivableList for receipts.
 type Receipts []*Receipt
 
@@ -115,6 +115,7 @@ import (
 	"github.com/erigontech/erigon/p2p"
 	"github.com/erigontech/erigon/p2p/enode"
 	"github.com/erigontech/erigon/p2p/protocols/eth"
+	"github.com/erigontech/erigon/p2p/protocols/snap"
 	"github.com/erigontech/erigon/p2p/sentry"
 	"github.com/erigontech/erigon/p2p/sentry/sentry_multi_client"
 	"github.com/erigontech/erigon/polygon/bor"
@@ -132,6 +133,7 @@ import (
 	"github.com/erigontech/erigon/turbo/services"
 	"github.com/erigontech/erigon/turbo/shards"
 	"github.com/erigontech/erigon/turbo/silkworm"
+	"github.com/erigontech/erigon/turbo/snapshotsync"
 	"github.com/erigontech/erigon/turbo/snapshotsync/freezeblocks"
 	"github.com/erigontech/erigon/txnprovider"
 	"github.com/erigontech/erigon/txnprovider/shutter"
@@ -215,6 +217,7 @@ type Ethereum struct {
 	blockWriter    *blockio.BlockWriter
 	kvRPC          *remotedbserver.KvServer
 	logger         log.Logger
+	tracer         *tracers.Tracer
 
 	sentinel rpcsentinel.SentinelClient
 
@@ -352,6 +355,7 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 		minedBlocks:               make(chan *types.Block, 1),
 		minedBlockObservers:       event.NewObservers[*types.Block](),
 		logger:                    logger,
+		tracer:                    tracer,
 		stopNode: func() error {
 			return stack.Close()
 		},
@@ -502,6 +506,12 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 			return nil, err
 		}
 
+		snapServer := snap.NewServer(backend.chainDB, blockReader, logger)
+		go func() {
+			if err := snap.IndexCodes(backend.sentryCtx, backend.chainDB, logger); err != nil && !errors.Is(err, context.Canceled) {
+				logger.Warn("[snap] failed to index codes", "err", err)
+			}
+		}()
 		var pi int // points to next port to be picked from refCfg.AllowedPorts
 		for _, protocol := range p2pConfig.ProtocolVersion {
 			cfg := p2pConfig
@@ -534,6 +544,7 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 
 			cfg.ListenAddr = fmt.Sprintf("%s:%d", listenHost, listenPort)
 			server := sentry.NewGrpcServer(backend.sentryCtx, nil, readNodeInfo, &cfg, protocol, logger)
+			server.RegisterSnap(snapServer)
 			backend.sentryServers = append(backend.sentryServers, server)
 			sentries = append(sentries, direct.NewSentryClientDirect(protocol, server))
 		}
@@ -678,6 +689,7 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 
 	statusDataProvider := sentry.NewStatusDataProvider(
 		backend.chainDB,
+		backend.blockReader,
 		chainConfig,
 		genesis,
 		backend.config.NetworkID,
@@ -1171,6 +1183,37 @@ func (s *Ethereum) Init(stack *node.Node, config *ethconfig.Config, chainConfig
 	}
 
 	s.apiList = jsonrpc.APIList(chainKv, s.ethRpcClient, s.txPoolRpcClient, s.miningRpcClient, s.rpcFilters, s.rpcDaemonStateCache, blockReader, &httpRpcCfg, s.engine, s.logger, s.polygonBridge, s.heimdallService)
+	if s.txPool != nil && slices.Contains(httpRpcCfg.API, "txpool") {
+		// txpool_dump and txpool_explain need the pool itself, they aren't available in a standalone rpcdaemon
+		s.apiList = append(s.apiList, rpc.API{
+			Namespace: "txpool",
+			Public:    true,
+			Service:   txpool.NewAPI(s.txPool),
+			Version:   "1.0",
+		})
+	}
+	if s.txPool != nil && slices.Contains(httpRpcCfg.API, "eth") {
+		// private and conditional transactions go straight into the pool, bypassing the txpool grpc api
+		var storageRoot txpool.StorageRootReader
+		for _, api := range s.apiList {
+			if ethApi, ok := api.Service.(jsonrpc.EthAPI); ok && api.Namespace == "eth" {
+				storageRoot = func(ctx context.Context, addr common.Address) (common.Hash, error) {
+					proof, err := ethApi.GetProof(ctx, addr, nil, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+					if err != nil {
+						return common.Hash{}, err
+					}
+					return proof.StorageHash, nil
+				}
+				break
+			}
+		}
+		s.apiList = append(s.apiList, rpc.API{
+			Namespace: "eth",
+			Public:    true,
+			Service:   txpool.NewEthAPI(s.txPool, storageRoot),
+			Version:   "1.0",
+		})
+	}
 
 	if config.SilkwormRpcDaemon && httpRpcCfg.Enabled {
 		interface_log_settings := silkworm.RpcInterfaceLogSettings{
@@ -1714,6 +1757,21 @@ func (s *Ethereum) Start() error {
 		})
 	}
 
+	if s.config.Snapshot.ScrubInterval > 0 {
+		snapCfg, _ := snapcfg.KnownCfg(s.chainConfig.ChainName)
+		scrubber := snapshotsync.NewScrubber(s.config.Dirs, s.downloaderClient, snapCfg.Preverified.Items,
+			s.config.Snapshot.ScrubInterval, s.config.Snapshot.ScrubRate.Bytes(), s.blockSnapshots.DownloadReady, s.logger)
+		go scrubber.Run(s.sentryCtx)
+	}
+
+	if addr := s.config.Snapshot.ServeAddr; addr != "" {
+		go func() {
+			if err := downloader.ServeWebSeed(s.sentryCtx, addr, s.config.Dirs.Snap, s.logger); err != nil {
+				s.logger.Error("[snapshots] webseed server stopped", "err", err)
+			}
+		}()
+	}
+
 	if s.shutterPool != nil {
 		s.bgComponentsEg.Go(func() error {
 			defer s.logger.Info("[shutter] pool goroutine terminated")
@@ -1783,6 +1841,10 @@ func (s *Ethereum) Stop() error {
 	if err := s.bgComponentsEg.Wait(); err != nil && !errors.Is(err, context.Canceled) {
 		s.logger.Error("background component error", "err", err)
 	}
+	// the stage loop is stopped by now, so no more events reach the live tracer
+	if s.tracer != nil && s.tracer.Hooks != nil && s.tracer.Hooks.OnClose != nil {
+		s.tracer.Hooks.OnClose()
+	}
 
 	return nil
 }
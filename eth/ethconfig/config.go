@@ -153,6 +153,11 @@ type BlocksFreezing struct {
 	DisableDownloadE3 bool // disable download state snapshots
 	DownloaderAddr    string
 	ChainName         string
+
+	ScrubInterval time.Duration     // check files against their checksums or torrents. 0 - disabled
+	ScrubRate     datasize.ByteSize // read speed of checks, per second. 0 - unlimited
+
+	ServeAddr string // serve files over HTTP as webseed. empty - disabled
 }
 
 func (s BlocksFreezing) String() string {
@@ -281,6 +286,8 @@ type Sync struct {
 	BreakAfterStage            string
 	LoopBlockLimit             uint
 	ParallelStateFlushing      bool
+	ParallelExec               bool   // execute txns of a block in parallel during initial sync
+	ParallelExecVerifyEvery    uint64 // cross-check parallel execution against serial every N blocks, 0 - never
 
 	UploadLocation   string
 	UploadFrom       rpc.BlockNumber
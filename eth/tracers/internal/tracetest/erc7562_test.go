@@ -0,0 +1,179 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package tracetest
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/crypto"
+	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon/core"
+	"github.com/erigontech/erigon/core/vm"
+	"github.com/erigontech/erigon/core/vm/evmtypes"
+	"github.com/erigontech/erigon/eth/tracers"
+	"github.com/erigontech/erigon/execution/chainspec"
+	"github.com/erigontech/erigon/execution/consensus"
+	"github.com/erigontech/erigon/execution/stages/mock"
+	"github.com/erigontech/erigon/tests"
+)
+
+// erc7562Trace is the part of erc7562Tracer result checked by the tests.
+type erc7562Trace struct {
+	Error         string `json:"error"`
+	AccessedSlots struct {
+		Reads  map[string][]string `json:"reads"`
+		Writes map[string]uint64   `json:"writes"`
+	} `json:"accessedSlots"`
+	ExtCodeAccessInfo []common.Address     `json:"extCodeAccessInfo"`
+	UsedOpcodes       map[vm.OpCode]uint64 `json:"usedOpcodes"`
+	ContractSize      map[common.Address]struct {
+		ContractSize int       `json:"contractSize"`
+		Opcode       vm.OpCode `json:"opcode"`
+	} `json:"contractSize"`
+	OutOfGas bool            `json:"outOfGas"`
+	Keccak   []hexutil.Bytes `json:"keccak"`
+	Calls    []erc7562Trace  `json:"calls"`
+}
+
+func TestErc7562Tracer(t *testing.T) {
+	var (
+		to     = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+		callee = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+		empty  = common.HexToAddress("0x00000000000000000000000000000000000000cc")
+		other  = common.HexToAddress("0x00000000000000000000000000000000000000dd")
+	)
+	code := []byte{
+		byte(vm.TIMESTAMP), byte(vm.POP), // banned opcode
+		byte(vm.GAS), byte(vm.POP), // [OP-012] GAS not followed by call
+		byte(vm.PUSH1), 0x2a, byte(vm.PUSH1), 0, byte(vm.MSTORE),
+		byte(vm.PUSH1), 32, byte(vm.PUSH1), 0, byte(vm.KECCAK256), byte(vm.POP), // keccak(uint256(42))
+		byte(vm.PUSH1), 1, byte(vm.PUSH1), 5, byte(vm.SSTORE), // slot 5 = 1
+		byte(vm.PUSH1), 6, byte(vm.SLOAD), byte(vm.POP), // read slot 6
+	}
+	code = append(append(append(code, byte(vm.PUSH20)), empty.Bytes()...), byte(vm.EXTCODESIZE), byte(vm.POP))
+	// [OP-051] EXTCODESIZE ISZERO is allowed
+	code = append(append(append(code, byte(vm.PUSH20)), other.Bytes()...), byte(vm.EXTCODESIZE), byte(vm.ISZERO), byte(vm.POP))
+	for i := 0; i < 17; i++ {
+		code = append(code, byte(vm.PUSH1), byte(i))
+	}
+	code = append(code, byte(vm.SWAP16))
+	// call of infinite loop with 1000 gas
+	code = append(code, byte(vm.PUSH1), 0, byte(vm.DUP1), byte(vm.DUP1), byte(vm.DUP1), byte(vm.DUP1), byte(vm.PUSH20))
+	code = append(append(code, callee.Bytes()...), byte(vm.PUSH2), 0x03, 0xe8, byte(vm.CALL), byte(vm.POP), byte(vm.STOP))
+	calleeCode := []byte{byte(vm.JUMPDEST), byte(vm.PUSH1), 0, byte(vm.JUMP)}
+
+	res := traceErc7562(t, to, types.GenesisAlloc{
+		to:     {Nonce: 1, Code: code},
+		callee: {Nonce: 1, Code: calleeCode},
+	})
+
+	require.Equal(t, map[vm.OpCode]uint64{
+		vm.TIMESTAMP:   1,
+		vm.GAS:         1,
+		vm.MSTORE:      1,
+		vm.KECCAK256:   1,
+		vm.SSTORE:      1,
+		vm.SLOAD:       1,
+		vm.EXTCODESIZE: 2,
+		vm.CALL:        1,
+		vm.STOP:        1,
+	}, res.UsedOpcodes) // PUSHx, DUPx, SWAPx (including SWAP16), POP and ISZERO are ignored by default
+
+	preimage := uint256.NewInt(0x2a).Bytes32()
+	require.Equal(t, []hexutil.Bytes{preimage[:]}, res.Keccak)
+
+	slot := func(n byte) string { return common.BytesToHash([]byte{n}).Hex() }
+	require.Equal(t, map[string]uint64{slot(5): 1}, res.AccessedSlots.Writes)
+	require.Equal(t, map[string][]string{slot(6): {common.Hash{}.Hex()}}, res.AccessedSlots.Reads)
+
+	require.Equal(t, []common.Address{empty}, res.ExtCodeAccessInfo)
+	require.Len(t, res.ContractSize, 3)
+	require.Equal(t, 0, res.ContractSize[empty].ContractSize)
+	require.Equal(t, vm.EXTCODESIZE, res.ContractSize[empty].Opcode)
+	require.Equal(t, 0, res.ContractSize[other].ContractSize)
+	require.Equal(t, vm.EXTCODESIZE, res.ContractSize[other].Opcode)
+	require.Equal(t, len(calleeCode), res.ContractSize[callee].ContractSize)
+	require.Equal(t, vm.CALL, res.ContractSize[callee].Opcode)
+
+	require.False(t, res.OutOfGas)
+	require.Len(t, res.Calls, 1)
+	require.True(t, res.Calls[0].OutOfGas)
+	require.Equal(t, vm.ErrOutOfGas.Error(), res.Calls[0].Error)
+	require.Len(t, res.Calls[0].UsedOpcodes, 2)
+	require.Positive(t, res.Calls[0].UsedOpcodes[vm.JUMP])
+	require.Equal(t, res.Calls[0].UsedOpcodes[vm.JUMP], res.Calls[0].UsedOpcodes[vm.JUMPDEST])
+}
+
+func traceErc7562(t *testing.T, to common.Address, alloc types.GenesisAlloc) erc7562Trace {
+	privkey, err := crypto.HexToECDSA("0000000000000000deadbeef00000000000000000000000000000000deadbeef")
+	require.NoError(t, err)
+	signer := types.LatestSigner(chainspec.MainnetChainConfig)
+	tx, err := types.SignNewTx(privkey, *signer, &types.LegacyTx{
+		GasPrice: uint256.NewInt(0),
+		CommonTx: types.CommonTx{
+			GasLimit: 200000,
+			To:       &to,
+		},
+	})
+	require.NoError(t, err)
+	origin, _ := signer.Sender(tx)
+	alloc[origin] = types.GenesisAccount{Balance: big.NewInt(500000000000000)}
+	txContext := evmtypes.TxContext{
+		Origin:   origin,
+		GasPrice: uint256.NewInt(1),
+	}
+	context := evmtypes.BlockContext{
+		CanTransfer: core.CanTransfer,
+		Transfer:    consensus.Transfer,
+		BlockNumber: 8000000,
+		Time:        5,
+		Difficulty:  big.NewInt(0x30000),
+		GasLimit:    uint64(6000000),
+	}
+	rules := chainspec.MainnetChainConfig.Rules(context.BlockNumber, context.Time)
+	m := mock.Mock(t)
+	dbTx, err := m.DB.BeginTemporalRw(m.Ctx)
+	require.NoError(t, err)
+	defer dbTx.Rollback()
+
+	statedb, err := tests.MakePreState(rules, dbTx, alloc, context.BlockNumber)
+	require.NoError(t, err)
+	tracer, err := tracers.New("erc7562Tracer", nil, nil)
+	require.NoError(t, err)
+	statedb.SetHooks(tracer.Hooks)
+	evm := vm.NewEVM(context, txContext, statedb, chainspec.MainnetChainConfig, vm.Config{Tracer: tracer.Hooks})
+	msg, err := tx.AsMessage(*signer, nil, rules)
+	require.NoError(t, err)
+	tracer.OnTxStart(evm.GetVMContext(), tx, msg.From())
+	st := core.NewStateTransition(evm, msg, new(core.GasPool).AddGas(tx.GetGasLimit()).AddBlobGas(tx.GetBlobGas()))
+	vmRet, err := st.TransitionDb(true /* refunds */, false /* gasBailout */)
+	require.NoError(t, err)
+	tracer.OnTxEnd(&types.Receipt{GasUsed: vmRet.GasUsed}, err)
+	blob, err := tracer.GetResult()
+	require.NoError(t, err)
+
+	var res erc7562Trace
+	require.NoError(t, json.Unmarshal(blob, &res))
+	return res
+}
@@ -0,0 +1,227 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package live
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/holiman/uint256"
+
+	"github.com/erigontech/erigon-lib/chain"
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon/core/tracing"
+	"github.com/erigontech/erigon/eth/tracers"
+	"github.com/erigontech/erigon/execution/consensus/misc"
+)
+
+func init() {
+	register("balanceDeltaTracer", newBalanceDeltaTracer)
+}
+
+type balanceDeltaBlock struct {
+	Type       string                          `json:"type"`
+	Number     uint64                          `json:"number"`
+	Hash       common.Hash                     `json:"hash"`
+	ParentHash common.Hash                     `json:"parentHash"`
+	Deltas     map[common.Address]*hexutil.Big `json:"deltas"` // Net balance change of every account changed by the block
+	Supply     supplyDelta                     `json:"supply"`
+}
+
+// supplyDelta breaks down the change of the ether supply caused by a block.
+type supplyDelta struct {
+	Genesis           *hexutil.Big `json:"genesis,omitempty"` // Allocated in the genesis block
+	Reward            *hexutil.Big `json:"reward"`            // Block and uncle rewards
+	Withdrawals       *hexutil.Big `json:"withdrawals"`       // Withdrawn from the beacon chain
+	BurntBaseFee      *hexutil.Big `json:"burntBaseFee"`      // Burnt base fee (EIP-1559)
+	BurntBlobFee      *hexutil.Big `json:"burntBlobFee"`      // Burnt blob fee (EIP-4844)
+	BurntSelfdestruct *hexutil.Big `json:"burntSelfdestruct"` // Sent to an already self-destructed account
+	Delta             *hexutil.Big `json:"delta"`             // Total change
+}
+
+// balanceDeltaTracer writes, one line per block to `balancedeltas.jsonl`, the
+// net balance change of every account touched by a block and the change of
+// the ether supply it caused. Like jsonlCallTracer it writes reorg markers on
+// unwinds and expects blocks to be executed one after another.
+type balanceDeltaTracer struct {
+	out         *jsonlWriter
+	chainConfig *chain.Config
+	header      *types.Header
+	deltas      map[common.Address]*big.Int
+	reward      *big.Int
+	withdrawals *big.Int
+	burnt       *big.Int
+}
+
+func newBalanceDeltaTracer(ctx *tracers.Context, cfg json.RawMessage) (*tracers.Tracer, error) {
+	var config writerConfig
+	if cfg != nil {
+		if err := json.Unmarshal(cfg, &config); err != nil {
+			return nil, err
+		}
+	}
+	out, err := newJSONLWriter(config, "balancedeltas.jsonl")
+	if err != nil {
+		return nil, err
+	}
+	t := &balanceDeltaTracer{out: out}
+	return &tracers.Tracer{
+		Hooks: &tracing.Hooks{
+			OnBlockchainInit: t.OnBlockchainInit,
+			OnGenesisBlock:   t.OnGenesisBlock,
+			OnBlockStart:     t.OnBlockStart,
+			OnBlockEnd:       t.OnBlockEnd,
+			OnBlockUnwind:    t.OnBlockUnwind,
+			OnClose:          t.OnClose,
+			OnBalanceChange:  t.OnBalanceChange,
+		},
+		GetResult: func() (json.RawMessage, error) { return json.RawMessage{}, nil },
+		Stop:      func(err error) {},
+	}, nil
+}
+
+func (t *balanceDeltaTracer) OnBlockchainInit(chainConfig *chain.Config) {
+	t.chainConfig = chainConfig
+}
+
+func (t *balanceDeltaTracer) OnGenesisBlock(b *types.Block, alloc types.GenesisAlloc) {
+	t.reset(b.HeaderNoCopy())
+	genesis := new(big.Int)
+	for addr, account := range alloc {
+		if account.Balance == nil || account.Balance.Sign() == 0 {
+			continue
+		}
+		t.delta(addr).Add(t.delta(addr), account.Balance)
+		genesis.Add(genesis, account.Balance)
+	}
+	record := t.record()
+	record.Supply.Genesis = (*hexutil.Big)(genesis)
+	record.Supply.Delta.ToInt().Add(record.Supply.Delta.ToInt(), genesis)
+	t.header = nil
+	if err := t.out.writeBlock(0, record); err != nil {
+		log.Warn("[balanceDeltaTracer] failed to write block", "block", 0, "err", err)
+	}
+}
+
+func (t *balanceDeltaTracer) OnBlockStart(ev tracing.BlockEvent) {
+	t.reset(ev.Block.HeaderNoCopy())
+}
+
+func (t *balanceDeltaTracer) OnBlockEnd(err error) {
+	if t.header == nil || err != nil {
+		// the block is going to be executed again, don't persist partial results
+		t.header = nil
+		return
+	}
+	record := t.record()
+	t.header = nil
+	if err := t.out.writeBlock(record.Number, record); err != nil {
+		log.Warn("[balanceDeltaTracer] failed to write block", "block", record.Number, "err", err)
+	}
+}
+
+func (t *balanceDeltaTracer) OnBlockUnwind(from, unwindPoint uint64) {
+	if err := t.out.writeReorg(from, unwindPoint); err != nil {
+		log.Warn("[balanceDeltaTracer] failed to write reorg marker", "unwindTo", unwindPoint, "err", err)
+	}
+}
+
+func (t *balanceDeltaTracer) OnClose() {
+	if err := t.out.close(); err != nil {
+		log.Warn("[balanceDeltaTracer] failed to close output", "err", err)
+	}
+}
+
+func (t *balanceDeltaTracer) OnBalanceChange(addr common.Address, prev, new uint256.Int, reason tracing.BalanceChangeReason) {
+	if t.header == nil {
+		return
+	}
+	diff := new.ToBig()
+	diff.Sub(diff, prev.ToBig())
+	t.delta(addr).Add(t.delta(addr), diff)
+
+	switch reason {
+	case tracing.BalanceIncreaseRewardMineBlock, tracing.BalanceIncreaseRewardMineUncle:
+		t.reward.Add(t.reward, diff)
+	case tracing.BalanceIncreaseWithdrawal:
+		t.withdrawals.Add(t.withdrawals, diff)
+	case tracing.BalanceDecreaseSelfdestructBurn:
+		t.burnt.Sub(t.burnt, diff)
+	}
+}
+
+func (t *balanceDeltaTracer) reset(header *types.Header) {
+	t.header = header
+	t.deltas = make(map[common.Address]*big.Int)
+	t.reward, t.withdrawals, t.burnt = new(big.Int), new(big.Int), new(big.Int)
+}
+
+func (t *balanceDeltaTracer) delta(addr common.Address) *big.Int {
+	d, ok := t.deltas[addr]
+	if !ok {
+		d = new(big.Int)
+		t.deltas[addr] = d
+	}
+	return d
+}
+
+func (t *balanceDeltaTracer) record() *balanceDeltaBlock {
+	header := t.header
+	record := &balanceDeltaBlock{
+		Type:       recordTypeBlock,
+		Number:     header.Number.Uint64(),
+		Hash:       header.Hash(),
+		ParentHash: header.ParentHash,
+		Deltas:     make(map[common.Address]*hexutil.Big, len(t.deltas)),
+	}
+	for addr, d := range t.deltas {
+		if d.Sign() != 0 {
+			record.Deltas[addr] = (*hexutil.Big)(d)
+		}
+	}
+
+	baseFee := new(big.Int)
+	if header.BaseFee != nil {
+		baseFee.Mul(header.BaseFee, new(big.Int).SetUint64(header.GasUsed))
+	}
+	blobFee := new(big.Int)
+	if header.BlobGasUsed != nil && header.ExcessBlobGas != nil && t.chainConfig != nil {
+		price, err := misc.GetBlobGasPrice(t.chainConfig, *header.ExcessBlobGas, header.Time)
+		if err != nil {
+			log.Warn("[balanceDeltaTracer] failed to compute blob gas price", "block", record.Number, "err", err)
+		} else {
+			blobFee.Mul(price.ToBig(), new(big.Int).SetUint64(*header.BlobGasUsed))
+		}
+	}
+
+	delta := new(big.Int).Add(t.reward, t.withdrawals)
+	delta.Sub(delta, baseFee)
+	delta.Sub(delta, blobFee)
+	delta.Sub(delta, t.burnt)
+	record.Supply = supplyDelta{
+		Reward:            (*hexutil.Big)(t.reward),
+		Withdrawals:       (*hexutil.Big)(t.withdrawals),
+		BurntBaseFee:      (*hexutil.Big)(baseFee),
+		BurntBlobFee:      (*hexutil.Big)(blobFee),
+		BurntSelfdestruct: (*hexutil.Big)(t.burnt),
+		Delta:             (*hexutil.Big)(delta),
+	}
+	return record
+}
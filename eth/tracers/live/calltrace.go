@@ -0,0 +1,182 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package live
+
+import (
+	"encoding/json"
+
+	"github.com/holiman/uint256"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon/core/tracing"
+	"github.com/erigontech/erigon/eth/tracers"
+	_ "github.com/erigontech/erigon/eth/tracers/native" // the per-txn traces are produced by `callTracer`
+)
+
+func init() {
+	register("jsonlCallTracer", newJSONLCallTracer)
+}
+
+type jsonlCallTracerConfig struct {
+	writerConfig
+	TracerConfig json.RawMessage `json:"tracerConfig"` // Config of the `callTracer` run for every txn
+}
+
+type callTraceBlock struct {
+	Type         string        `json:"type"`
+	Number       uint64        `json:"number"`
+	Hash         common.Hash   `json:"hash"`
+	ParentHash   common.Hash   `json:"parentHash"`
+	Transactions []callTraceTx `json:"transactions"`
+}
+
+type callTraceTx struct {
+	TxHash  common.Hash     `json:"txHash"`
+	TxIndex int             `json:"txIndex"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// jsonlCallTracer writes the `callTracer` result of every txn executed during
+// sync, one line per block, to `calltraces.jsonl`. When blocks get unwound it
+// writes a reorg marker, so that the output can be consumed as an append-only
+// log. Blocks are expected to be executed one after another, which is the case
+// for the serial executor.
+type jsonlCallTracer struct {
+	out    *jsonlWriter
+	config jsonlCallTracerConfig
+	block  *callTraceBlock
+	tx     *tracers.Tracer // callTracer of the txn being executed
+	txHash common.Hash
+}
+
+func newJSONLCallTracer(ctx *tracers.Context, cfg json.RawMessage) (*tracers.Tracer, error) {
+	var config jsonlCallTracerConfig
+	if cfg != nil {
+		if err := json.Unmarshal(cfg, &config); err != nil {
+			return nil, err
+		}
+	}
+	out, err := newJSONLWriter(config.writerConfig, "calltraces.jsonl")
+	if err != nil {
+		return nil, err
+	}
+	t := &jsonlCallTracer{out: out, config: config}
+	return &tracers.Tracer{
+		Hooks: &tracing.Hooks{
+			OnBlockStart:  t.OnBlockStart,
+			OnBlockEnd:    t.OnBlockEnd,
+			OnBlockUnwind: t.OnBlockUnwind,
+			OnClose:       t.OnClose,
+			OnTxStart:     t.OnTxStart,
+			OnTxEnd:       t.OnTxEnd,
+			OnEnter:       t.OnEnter,
+			OnExit:        t.OnExit,
+			OnLog:         t.OnLog,
+		},
+		GetResult: func() (json.RawMessage, error) { return json.RawMessage{}, nil },
+		Stop:      func(err error) {},
+	}, nil
+}
+
+func (t *jsonlCallTracer) OnBlockStart(ev tracing.BlockEvent) {
+	t.block = &callTraceBlock{
+		Type:         recordTypeBlock,
+		Number:       ev.Block.NumberU64(),
+		Hash:         ev.Block.Hash(),
+		ParentHash:   ev.Block.ParentHash(),
+		Transactions: make([]callTraceTx, 0, len(ev.Block.Transactions())),
+	}
+}
+
+func (t *jsonlCallTracer) OnBlockEnd(err error) {
+	block := t.block
+	t.block, t.tx = nil, nil
+	if block == nil || err != nil {
+		// the block is going to be executed again, don't persist partial results
+		return
+	}
+	if err := t.out.writeBlock(block.Number, block); err != nil {
+		log.Warn("[jsonlCallTracer] failed to write block", "block", block.Number, "err", err)
+	}
+}
+
+func (t *jsonlCallTracer) OnBlockUnwind(from, unwindPoint uint64) {
+	if err := t.out.writeReorg(from, unwindPoint); err != nil {
+		log.Warn("[jsonlCallTracer] failed to write reorg marker", "unwindTo", unwindPoint, "err", err)
+	}
+}
+
+func (t *jsonlCallTracer) OnClose() {
+	if err := t.out.close(); err != nil {
+		log.Warn("[jsonlCallTracer] failed to close output", "err", err)
+	}
+}
+
+func (t *jsonlCallTracer) OnTxStart(env *tracing.VMContext, tx types.Transaction, from common.Address) {
+	if t.block == nil {
+		return
+	}
+	txCtx := &tracers.Context{BlockHash: t.block.Hash, TxIndex: len(t.block.Transactions), TxHash: tx.Hash()}
+	inner, err := tracers.New("callTracer", txCtx, t.config.TracerConfig)
+	if err != nil {
+		log.Warn("[jsonlCallTracer] failed to create callTracer", "err", err)
+		return
+	}
+	t.tx, t.txHash = inner, txCtx.TxHash
+	if inner.OnTxStart != nil {
+		inner.OnTxStart(env, tx, from)
+	}
+}
+
+func (t *jsonlCallTracer) OnTxEnd(receipt *types.Receipt, err error) {
+	inner := t.tx
+	t.tx = nil
+	if inner == nil || t.block == nil {
+		return
+	}
+	if inner.OnTxEnd != nil {
+		inner.OnTxEnd(receipt, err)
+	}
+	res := callTraceTx{TxHash: t.txHash, TxIndex: len(t.block.Transactions)}
+	if err != nil {
+		res.Error = err.Error()
+	} else if res.Result, err = inner.GetResult(); err != nil {
+		res.Error = err.Error()
+	}
+	t.block.Transactions = append(t.block.Transactions, res)
+}
+
+func (t *jsonlCallTracer) OnEnter(depth int, typ byte, from common.Address, to common.Address, precompile bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	if t.tx != nil && t.tx.OnEnter != nil {
+		t.tx.OnEnter(depth, typ, from, to, precompile, input, gas, value, code)
+	}
+}
+
+func (t *jsonlCallTracer) OnExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if t.tx != nil && t.tx.OnExit != nil {
+		t.tx.OnExit(depth, output, gasUsed, err, reverted)
+	}
+}
+
+func (t *jsonlCallTracer) OnLog(l *types.Log) {
+	if t.tx != nil && t.tx.OnLog != nil {
+		t.tx.OnLog(l)
+	}
+}
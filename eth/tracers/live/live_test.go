@@ -0,0 +1,158 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package live
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon/core/tracing"
+	"github.com/erigontech/erigon/core/vm"
+	"github.com/erigontech/erigon/eth/tracers"
+)
+
+func readRecords(t *testing.T, path string) []map[string]interface{} {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var records []map[string]interface{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	require.NoError(t, scanner.Err())
+	return records
+}
+
+func testBlock(number int64, txs ...types.Transaction) *types.Block {
+	header := &types.Header{Number: big.NewInt(number), BaseFee: big.NewInt(1), GasUsed: 21000}
+	return types.NewBlock(header, txs, nil, nil, nil)
+}
+
+func TestJSONLCallTracer(t *testing.T) {
+	dir := t.TempDir()
+	tracer, err := tracers.New("jsonlCallTracer", &tracers.Context{}, json.RawMessage(fmt.Sprintf(`{"path":%q}`, dir)))
+	require.NoError(t, err)
+
+	from, to := common.HexToAddress("0x1"), common.HexToAddress("0x2")
+	txn := types.NewTransaction(0, to, uint256.NewInt(7), 21000, uint256.NewInt(1), nil)
+	execBlock := func(number int64, blockErr error) {
+		tracer.OnBlockStart(tracing.BlockEvent{Block: testBlock(number, txn)})
+		tracer.OnTxStart(&tracing.VMContext{}, txn, from)
+		tracer.OnEnter(0, byte(vm.CALL), from, to, false, nil, 21000, uint256.NewInt(7), nil)
+		tracer.OnExit(0, nil, 0, nil, false)
+		tracer.OnTxEnd(&types.Receipt{GasUsed: 21000}, nil)
+		tracer.OnBlockEnd(blockErr)
+	}
+
+	execBlock(1, nil)
+	execBlock(2, errors.New("bad block")) // not persisted
+	execBlock(2, nil)
+	tracer.OnBlockUnwind(2, 1)
+	tracer.OnBlockUnwind(1, 1) // nothing written above the unwind point, no marker
+	execBlock(2, nil)
+	execBlock(2, nil) // re-executed without an unwind, e.g. after a restart
+	tracer.OnClose()
+
+	records := readRecords(t, filepath.Join(dir, "calltraces.jsonl"))
+	require.Len(t, records, 6)
+	expected := []struct {
+		typ    string
+		number float64
+	}{{"block", 1}, {"block", 2}, {"reorg", 1}, {"block", 2}, {"reorg", 1}, {"block", 2}}
+	for i, e := range expected {
+		require.Equal(t, e.typ, records[i]["type"], "record %d", i)
+		if e.typ == recordTypeBlock {
+			require.Equal(t, e.number, records[i]["number"], "record %d", i)
+		} else {
+			require.Equal(t, e.number, records[i]["unwindTo"], "record %d", i)
+		}
+	}
+
+	txs := records[0]["transactions"].([]interface{})
+	require.Len(t, txs, 1)
+	tx := txs[0].(map[string]interface{})
+	require.Equal(t, txn.Hash().Hex(), tx["txHash"])
+	result := tx["result"].(map[string]interface{})
+	require.Equal(t, "CALL", result["type"])
+	require.Equal(t, "0x7", result["value"])
+
+	// after a restart, the blocks which weren't committed are executed again,
+	// the record cut short by a crash is dropped
+	path := filepath.Join(dir, "calltraces.jsonl")
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"type":"block","number":3,"transa`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	tracer, err = tracers.New("jsonlCallTracer", &tracers.Context{}, json.RawMessage(fmt.Sprintf(`{"path":%q}`, dir)))
+	require.NoError(t, err)
+	execBlock(2, nil)
+	tracer.OnClose()
+	records = readRecords(t, path)
+	require.Len(t, records, 8)
+	require.Equal(t, recordTypeReorg, records[6]["type"])
+	require.Equal(t, float64(1), records[6]["unwindTo"])
+	require.Equal(t, float64(2), records[7]["number"])
+}
+
+func TestBalanceDeltaTracer(t *testing.T) {
+	dir := t.TempDir()
+	tracer, err := tracers.New("balanceDeltaTracer", &tracers.Context{}, json.RawMessage(fmt.Sprintf(`{"path":%q}`, dir)))
+	require.NoError(t, err)
+
+	miner, sender, receiver := common.HexToAddress("0x1"), common.HexToAddress("0x2"), common.HexToAddress("0x3")
+	genesis := types.NewBlock(&types.Header{Number: big.NewInt(0)}, nil, nil, nil, nil)
+	tracer.OnGenesisBlock(genesis, types.GenesisAlloc{sender: {Balance: big.NewInt(100_000)}})
+	tracer.OnBlockStart(tracing.BlockEvent{Block: testBlock(1)})
+	tracer.OnBalanceChange(sender, *uint256.NewInt(100_000), *uint256.NewInt(79_000), tracing.BalanceDecreaseGasBuy)
+	tracer.OnBalanceChange(sender, *uint256.NewInt(79_000), *uint256.NewInt(78_000), tracing.BalanceChangeTransfer)
+	tracer.OnBalanceChange(receiver, *uint256.NewInt(0), *uint256.NewInt(1_000), tracing.BalanceChangeTransfer)
+	tracer.OnBalanceChange(miner, *uint256.NewInt(0), *uint256.NewInt(2_000), tracing.BalanceIncreaseRewardMineBlock)
+	tracer.OnBlockEnd(nil)
+	tracer.OnClose()
+
+	records := readRecords(t, filepath.Join(dir, "balancedeltas.jsonl"))
+	require.Len(t, records, 2)
+	allocated := records[0]["supply"].(map[string]interface{})
+	require.Equal(t, "0x186a0", allocated["genesis"])
+	require.Equal(t, "0x186a0", allocated["delta"])
+
+	deltas := records[1]["deltas"].(map[string]interface{})
+	require.Equal(t, "-0x55f0", deltas[sender.Hex()])
+	require.Equal(t, "0x3e8", deltas[receiver.Hex()])
+	require.Equal(t, "0x7d0", deltas[miner.Hex()])
+	supply := records[1]["supply"].(map[string]interface{})
+	require.Equal(t, "0x7d0", supply["reward"])
+	require.Equal(t, "0x5208", supply["burntBaseFee"])
+	require.Equal(t, "-0x4a38", supply["delta"]) // 2000 issued, 21000 burnt
+}
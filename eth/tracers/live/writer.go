@@ -0,0 +1,202 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package live
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	recordTypeBlock = "block"
+	recordTypeReorg = "reorg"
+)
+
+// tailChunkSize is how much of the end of an output file is read at once to
+// find its last record.
+const tailChunkSize = 64 * 1024
+
+// writerConfig is the part of a live tracer config telling where its records go.
+type writerConfig struct {
+	Path string `json:"path"` // Directory the output file is appended to, records go to stdout if empty
+	Sync bool   `json:"sync"` // If true, the output file is fsync'ed after every block
+}
+
+// reorgRecord marks that every block above UnwindTo written before it is no
+// longer canonical. Consumers are expected to drop those blocks, they are
+// written again once the new chain is executed.
+//
+// Records are written as blocks are executed and unwound, before the sync cycle
+// commits them. Blocks written but not committed before a restart are executed
+// again, after a marker. A marker written but not committed isn't undone: the
+// blocks it dropped stay canonical and the following block doesn't continue
+// the chain of the consumer, which can tell from its parentHash.
+type reorgRecord struct {
+	Type     string `json:"type"`
+	From     uint64 `json:"from"`
+	UnwindTo uint64 `json:"unwindTo"`
+}
+
+// jsonlWriter appends one json record per line to a file (or stdout).
+type jsonlWriter struct {
+	mu        sync.Mutex
+	file      *os.File // nil when writing to stdout
+	w         *bufio.Writer
+	sync      bool
+	lastBlock uint64
+	hasBlock  bool
+}
+
+func newJSONLWriter(cfg writerConfig, fileName string) (*jsonlWriter, error) {
+	if cfg.Path == "" {
+		return &jsonlWriter{w: bufio.NewWriter(os.Stdout)}, nil
+	}
+	if err := os.MkdirAll(cfg.Path, 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(cfg.Path, fileName), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	w := &jsonlWriter{file: f, w: bufio.NewWriter(f), sync: cfg.Sync}
+	// blocks written before a restart are re-executed if they weren't committed
+	if w.lastBlock, w.hasBlock, err = lastRecordBlock(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("read live trace %s: %w", fileName, err)
+	}
+	return w, nil
+}
+
+// lastRecordBlock returns the block of the last record of a file: the number of
+// a block, or the block a reorg unwound to. A partial record left by a crash is
+// cut off the file.
+func lastRecordBlock(f *os.File) (number uint64, ok bool, err error) {
+	stat, err := f.Stat()
+	if err != nil {
+		return 0, false, err
+	}
+	// read chunks from the end until the last complete line is found
+	var tail []byte
+	pos := stat.Size()
+	for pos > 0 && bytes.Count(tail, []byte{'\n'}) < 2 {
+		n := min(tailChunkSize, pos)
+		pos -= n
+		chunk := make([]byte, n, int(n)+len(tail))
+		if _, err := f.ReadAt(chunk, pos); err != nil {
+			return 0, false, err
+		}
+		tail = append(chunk, tail...)
+	}
+	end := bytes.LastIndexByte(tail, '\n')
+	if complete := pos + int64(end) + 1; complete < stat.Size() {
+		if err := f.Truncate(complete); err != nil {
+			return 0, false, err
+		}
+	}
+	if end < 0 {
+		return 0, false, nil
+	}
+	line := tail[bytes.LastIndexByte(tail[:end], '\n')+1 : end]
+	var record struct {
+		Type     string `json:"type"`
+		Number   uint64 `json:"number"`
+		UnwindTo uint64 `json:"unwindTo"`
+	}
+	if err := json.Unmarshal(line, &record); err != nil {
+		return 0, false, err
+	}
+	switch record.Type {
+	case recordTypeBlock:
+		return record.Number, true, nil
+	case recordTypeReorg:
+		return record.UnwindTo, true, nil
+	}
+	return 0, false, nil
+}
+
+// writeBlock appends the record of a block and flushes it. Blocks are expected
+// in ascending order, a block at or below the last written one means the chain
+// was re-executed without an unwind being reported (e.g. after a restart), in
+// which case a reorg marker is written first.
+func (w *jsonlWriter) writeBlock(number uint64, record interface{}) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.hasBlock && number <= w.lastBlock && number > 0 {
+		if err := w.write(reorgRecord{Type: recordTypeReorg, From: w.lastBlock, UnwindTo: number - 1}); err != nil {
+			return err
+		}
+	}
+	if err := w.write(record); err != nil {
+		return err
+	}
+	w.lastBlock, w.hasBlock = number, true
+	return w.flush()
+}
+
+// writeReorg appends a reorg marker and flushes it.
+func (w *jsonlWriter) writeReorg(from, unwindTo uint64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.hasBlock && w.lastBlock <= unwindTo {
+		// nothing written above the unwind point
+		return nil
+	}
+	if err := w.write(reorgRecord{Type: recordTypeReorg, From: from, UnwindTo: unwindTo}); err != nil {
+		return err
+	}
+	w.lastBlock, w.hasBlock = unwindTo, true
+	return w.flush()
+}
+
+func (w *jsonlWriter) close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.flush(); err != nil {
+		return err
+	}
+	if w.file == nil {
+		return nil
+	}
+	return w.file.Close()
+}
+
+func (w *jsonlWriter) write(record interface{}) error {
+	buf, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	buf = append(buf, '\n')
+	if _, err := w.w.Write(buf); err != nil {
+		return fmt.Errorf("write live trace: %w", err)
+	}
+	return nil
+}
+
+func (w *jsonlWriter) flush() error {
+	if err := w.w.Flush(); err != nil {
+		return err
+	}
+	if w.sync && w.file != nil {
+		return w.file.Sync()
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 The go-ethereum Authors
+// (original work)
+// Copyright 2025 The Erigon Authors
+// (modifications)
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package native
+
+import (
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+
+	"github.com/holiman/uint256"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon/core/tracing"
+	"github.com/erigontech/erigon/core/vm"
+	"github.com/erigontech/erigon/eth/tracers"
+	"github.com/erigontech/erigon/eth/tracers/parity"
+)
+
+func init() {
+	register("flatCallTracer", newFlatCallTracer)
+}
+
+// flatCallTracer reports call frame information of a txn in the flat format
+// of trace_transaction, as opposed to the nested format of `callTracer`. The
+// frames are built by the same parity.CallFrames as the ones of trace_transaction.
+type flatCallTracer struct {
+	ctx         *tracers.Context
+	config      flatCallTracerConfig
+	blockNumber uint64
+	txHash      common.Hash
+	frames      parity.CallFrames
+	interrupt   atomic.Bool // Atomic flag to signal execution interruption
+	reason      error       // Textual reason for the interruption
+}
+
+type flatCallTracerConfig struct {
+	IncludePrecompiles bool `json:"includePrecompiles"` // If true, calls to precompiles are traced, like trace_transaction they are left out by default
+}
+
+// newFlatCallTracer returns a native go tracer which reports the call frames
+// of a txn in the same format as trace_transaction.
+func newFlatCallTracer(ctx *tracers.Context, cfg json.RawMessage) (*tracers.Tracer, error) {
+	var config flatCallTracerConfig
+	if cfg != nil {
+		if err := json.Unmarshal(cfg, &config); err != nil {
+			return nil, err
+		}
+	}
+	t := &flatCallTracer{ctx: ctx, config: config}
+	return &tracers.Tracer{
+		Hooks: &tracing.Hooks{
+			OnTxStart: t.OnTxStart,
+			OnEnter:   t.OnEnter,
+			OnExit:    t.OnExit,
+		},
+		GetResult: t.GetResult,
+		Stop:      t.Stop,
+	}, nil
+}
+
+func (t *flatCallTracer) OnTxStart(env *tracing.VMContext, tx types.Transaction, from common.Address) {
+	t.blockNumber = env.BlockNumber
+	t.txHash = env.TxHash
+	if t.ctx != nil && t.ctx.TxHash != (common.Hash{}) {
+		t.txHash = t.ctx.TxHash
+	}
+	if t.txHash == (common.Hash{}) && tx != nil {
+		t.txHash = tx.Hash()
+	}
+	t.frames = parity.CallFrames{IncludePrecompiles: t.config.IncludePrecompiles}
+}
+
+// OnEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *flatCallTracer) OnEnter(depth int, typ byte, from common.Address, to common.Address, precompile bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	if t.interrupt.Load() {
+		return
+	}
+	isCreate := vm.OpCode(typ) == vm.CREATE || vm.OpCode(typ) == vm.CREATE2
+	t.frames.Enter(depth != 0 /* deep */, vm.OpCode(typ), from, to, precompile, isCreate, input, gas, value)
+}
+
+// OnExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *flatCallTracer) OnExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if t.interrupt.Load() {
+		return
+	}
+	t.frames.Exit(depth != 0 /* deep */, output, gasUsed, err)
+}
+
+// GetResult returns the json-encoded flat list of call traces, and any
+// error arising from the encoding or forceful termination (via `Stop`).
+func (t *flatCallTracer) GetResult() (json.RawMessage, error) {
+	if len(t.frames.Traces) == 0 {
+		return nil, errors.New("invalid number of calls")
+	}
+	for _, trace := range t.frames.Traces {
+		if t.ctx != nil && t.ctx.BlockHash != (common.Hash{}) {
+			blockHash, blockNumber, txIndex := t.ctx.BlockHash, t.blockNumber, uint64(t.ctx.TxIndex)
+			trace.BlockHash = &blockHash
+			trace.BlockNumber = &blockNumber
+			trace.TransactionPosition = &txIndex
+		}
+		if t.txHash != (common.Hash{}) {
+			txHash := t.txHash
+			trace.TransactionHash = &txHash
+		}
+	}
+	res, err := json.Marshal(t.frames.Traces)
+	if err != nil {
+		return nil, err
+	}
+	return res, t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *flatCallTracer) Stop(err error) {
+	t.reason = err
+	t.interrupt.Store(true)
+}
@@ -0,0 +1,527 @@
+// Copyright 2025 The go-ethereum Authors
+// (original work)
+// Copyright 2025 The Erigon Authors
+// (modifications)
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package native
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"slices"
+	"sync/atomic"
+
+	"github.com/holiman/uint256"
+
+	"github.com/erigontech/erigon-lib/abi"
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon/core/tracing"
+	"github.com/erigontech/erigon/core/vm"
+	"github.com/erigontech/erigon/eth/tracers"
+)
+
+//go:generate gencodec -type callFrameWithOpcodes -field-override callFrameWithOpcodesMarshaling -out gen_callframewithopcodes_json.go
+
+func init() {
+	register("erc7562Tracer", newErc7562Tracer)
+}
+
+type contractSizeWithOpcode struct {
+	ContractSize int       `json:"contractSize"`
+	Opcode       vm.OpCode `json:"opcode"`
+}
+
+// callFrameWithOpcodes is a call frame annotated with what ERC-7562 validation
+// rules need to know about it: the opcodes it used, the storage it accessed and
+// the contracts it inspected.
+type callFrameWithOpcodes struct {
+	Type         vm.OpCode       `json:"-"`
+	From         common.Address  `json:"from"`
+	Gas          uint64          `json:"gas"`
+	GasUsed      uint64          `json:"gasUsed"`
+	To           *common.Address `json:"to,omitempty" rlp:"optional"`
+	Input        []byte          `json:"input" rlp:"optional"`
+	Output       []byte          `json:"output,omitempty" rlp:"optional"`
+	Error        string          `json:"error,omitempty" rlp:"optional"`
+	RevertReason string          `json:"revertReason,omitempty"`
+	Logs         []callLog       `json:"logs,omitempty" rlp:"optional"`
+	// Placed at end on purpose. The RLP will be decoded to 0 instead of
+	// nil if there are non-empty elements after in the struct.
+	Value *big.Int `json:"value,omitempty" rlp:"optional"`
+
+	AccessedSlots     accessedSlots                              `json:"accessedSlots"`
+	ExtCodeAccessInfo []common.Address                           `json:"extCodeAccessInfo"`
+	UsedOpcodes       map[vm.OpCode]uint64                       `json:"usedOpcodes"`
+	ContractSize      map[common.Address]*contractSizeWithOpcode `json:"contractSize"`
+	OutOfGas          bool                                       `json:"outOfGas"`
+	// Keccak preimages for the whole transaction are stored in the
+	// root call frame.
+	KeccakPreimages [][]byte               `json:"keccak,omitempty"`
+	Calls           []callFrameWithOpcodes `json:"calls,omitempty" rlp:"optional"`
+}
+
+func (f callFrameWithOpcodes) TypeString() string {
+	return f.Type.String()
+}
+
+func (f callFrameWithOpcodes) failed() bool {
+	return len(f.Error) > 0
+}
+
+func (f *callFrameWithOpcodes) processOutput(output []byte, err error) {
+	output = common.CopyBytes(output)
+	if err == nil {
+		f.Output = output
+		return
+	}
+	f.Error = err.Error()
+	if f.Type == vm.CREATE || f.Type == vm.CREATE2 {
+		f.To = nil
+	}
+	if !errors.Is(err, vm.ErrExecutionReverted) || len(output) == 0 {
+		return
+	}
+	f.Output = output
+	if len(output) < 4 {
+		return
+	}
+	if unpacked, err := abi.UnpackRevert(output); err == nil {
+		f.RevertReason = unpacked
+	}
+}
+
+type callFrameWithOpcodesMarshaling struct {
+	TypeString      string `json:"type"`
+	Gas             hexutil.Uint64
+	GasUsed         hexutil.Uint64
+	Value           *hexutil.Big
+	Input           hexutil.Bytes
+	Output          hexutil.Bytes
+	KeccakPreimages []hexutil.Bytes
+}
+
+type accessedSlots struct {
+	Reads           map[string][]string `json:"reads"`
+	Writes          map[string]uint64   `json:"writes"`
+	TransientReads  map[string]uint64   `json:"transientReads"`
+	TransientWrites map[string]uint64   `json:"transientWrites"`
+}
+
+type opcodeWithPartialStack struct {
+	Opcode        vm.OpCode
+	StackTopItems []uint256.Int
+}
+
+type erc7562Tracer struct {
+	config    erc7562TracerConfig
+	gasLimit  uint64
+	interrupt atomic.Bool // Atomic flag to signal execution interruption
+	reason    error       // Textual reason for the interruption
+	env       *tracing.VMContext
+
+	ignoredOpcodes       map[vm.OpCode]struct{}
+	callstackWithOpcodes []callFrameWithOpcodes
+	lastOpWithStack      *opcodeWithPartialStack
+	keccakPreimages      map[string]struct{}
+}
+
+type erc7562TracerConfig struct {
+	StackTopItemsSize int                         `json:"stackTopItemsSize"`
+	IgnoredOpcodes    map[hexutil.Uint64]struct{} `json:"ignoredOpcodes"` // Opcodes to ignore during OnOpcode hook execution
+	WithLog           bool                        `json:"withLog"`        // If true, erc7562 tracer will collect event logs
+}
+
+// withDefaults fills the settings left out of the given config.
+func (c erc7562TracerConfig) withDefaults() erc7562TracerConfig {
+	if c.IgnoredOpcodes == nil {
+		c.IgnoredOpcodes = defaultIgnoredOpcodes()
+	}
+	if c.StackTopItemsSize <= 0 {
+		c.StackTopItemsSize = 3
+	}
+	return c
+}
+
+// defaultIgnoredOpcodes are the opcodes which are not relevant for the validation
+// rules, they're left out of the used opcodes report.
+func defaultIgnoredOpcodes() map[hexutil.Uint64]struct{} {
+	ignored := make(map[hexutil.Uint64]struct{})
+
+	// Allow all PUSHx, DUPx and SWAPx opcodes as they have sequential codes
+	for op := vm.PUSH0; op <= vm.SWAP16; op++ {
+		ignored[hexutil.Uint64(op)] = struct{}{}
+	}
+
+	for _, op := range []vm.OpCode{
+		vm.POP, vm.ADD, vm.SUB, vm.MUL,
+		vm.DIV, vm.EQ, vm.LT, vm.GT,
+		vm.SLT, vm.SGT, vm.SHL, vm.SHR,
+		vm.AND, vm.OR, vm.NOT, vm.ISZERO,
+	} {
+		ignored[hexutil.Uint64(op)] = struct{}{}
+	}
+
+	return ignored
+}
+
+// newErc7562Tracer returns a native go tracer which collects the information
+// account-abstraction bundlers need to check a UserOperation against the
+// ERC-7562 validation rules: banned opcodes, storage access and code access.
+func newErc7562Tracer(ctx *tracers.Context, cfg json.RawMessage) (*tracers.Tracer, error) {
+	var config erc7562TracerConfig
+	if cfg != nil {
+		if err := json.Unmarshal(cfg, &config); err != nil {
+			return nil, err
+		}
+	}
+	config = config.withDefaults()
+
+	ignoredOpcodes := make(map[vm.OpCode]struct{}, len(config.IgnoredOpcodes))
+	for op := range config.IgnoredOpcodes {
+		ignoredOpcodes[vm.OpCode(op)] = struct{}{}
+	}
+
+	t := &erc7562Tracer{
+		config:               config,
+		ignoredOpcodes:       ignoredOpcodes,
+		callstackWithOpcodes: make([]callFrameWithOpcodes, 0, 1),
+		keccakPreimages:      make(map[string]struct{}),
+	}
+	return &tracers.Tracer{
+		Hooks: &tracing.Hooks{
+			OnTxStart: t.OnTxStart,
+			OnOpcode:  t.OnOpcode,
+			OnTxEnd:   t.OnTxEnd,
+			OnEnter:   t.OnEnter,
+			OnExit:    t.OnExit,
+			OnLog:     t.OnLog,
+		},
+		GetResult: t.GetResult,
+		Stop:      t.Stop,
+	}, nil
+}
+
+func (t *erc7562Tracer) OnTxStart(env *tracing.VMContext, tx types.Transaction, from common.Address) {
+	t.env = env
+	t.gasLimit = tx.GetGasLimit()
+}
+
+// OnEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *erc7562Tracer) OnEnter(depth int, typ byte, from common.Address, to common.Address, precompile bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	// Skip if tracing was interrupted
+	if t.interrupt.Load() {
+		return
+	}
+
+	toCopy := to
+	call := callFrameWithOpcodes{
+		Type:  vm.OpCode(typ),
+		From:  from,
+		To:    &toCopy,
+		Input: common.CopyBytes(input),
+		Gas:   gas,
+		AccessedSlots: accessedSlots{
+			Reads:           map[string][]string{},
+			Writes:          map[string]uint64{},
+			TransientReads:  map[string]uint64{},
+			TransientWrites: map[string]uint64{},
+		},
+		UsedOpcodes:       map[vm.OpCode]uint64{},
+		ExtCodeAccessInfo: make([]common.Address, 0),
+		ContractSize:      map[common.Address]*contractSizeWithOpcode{},
+	}
+	if value != nil {
+		call.Value = value.ToBig()
+	}
+	if depth == 0 {
+		call.Gas = t.gasLimit
+	}
+	t.callstackWithOpcodes = append(t.callstackWithOpcodes, call)
+}
+
+// OnExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *erc7562Tracer) OnExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if t.interrupt.Load() {
+		return
+	}
+	if depth == 0 {
+		if len(t.callstackWithOpcodes) == 1 {
+			t.callstackWithOpcodes[0].processOutput(output, err)
+		}
+		return
+	}
+
+	size := len(t.callstackWithOpcodes)
+	if size <= 1 {
+		return
+	}
+	// Pop call.
+	call := t.callstackWithOpcodes[size-1]
+	t.callstackWithOpcodes = t.callstackWithOpcodes[:size-1]
+	size -= 1
+
+	if errors.Is(err, vm.ErrCodeStoreOutOfGas) || errors.Is(err, vm.ErrOutOfGas) {
+		call.OutOfGas = true
+	}
+	call.GasUsed = gasUsed
+	call.processOutput(output, err)
+	// Nest call into parent.
+	t.callstackWithOpcodes[size-1].Calls = append(t.callstackWithOpcodes[size-1].Calls, call)
+}
+
+func (t *erc7562Tracer) OnTxEnd(receipt *types.Receipt, err error) {
+	if t.interrupt.Load() {
+		return
+	}
+	// Error happened during txn validation.
+	if err != nil || len(t.callstackWithOpcodes) == 0 {
+		return
+	}
+	t.callstackWithOpcodes[0].GasUsed = receipt.GasUsed
+	if t.config.WithLog {
+		// Logs are not emitted when the call fails
+		t.clearFailedLogs(&t.callstackWithOpcodes[0], false)
+	}
+}
+
+func (t *erc7562Tracer) OnLog(log *types.Log) {
+	// Only logs need to be captured via opcode processing
+	if !t.config.WithLog {
+		return
+	}
+	// Skip if tracing was interrupted
+	if t.interrupt.Load() {
+		return
+	}
+	current := &t.callstackWithOpcodes[len(t.callstackWithOpcodes)-1]
+	current.Logs = append(current.Logs, callLog{
+		Address:  log.Address,
+		Topics:   log.Topics,
+		Data:     log.Data,
+		Position: hexutil.Uint(len(current.Calls)),
+	})
+}
+
+// GetResult returns the json-encoded nested list of call traces, and any
+// error arising from the encoding or forceful termination (via `Stop`).
+func (t *erc7562Tracer) GetResult() (json.RawMessage, error) {
+	if t.interrupt.Load() {
+		return nil, t.reason
+	}
+	if len(t.callstackWithOpcodes) != 1 {
+		return nil, errors.New("incorrect number of top-level calls")
+	}
+
+	keccak := make([][]byte, 0, len(t.keccakPreimages))
+	for k := range t.keccakPreimages {
+		keccak = append(keccak, []byte(k))
+	}
+	slices.SortFunc(keccak, bytes.Compare)
+	t.callstackWithOpcodes[0].KeccakPreimages = keccak
+
+	res, err := json.Marshal(t.callstackWithOpcodes[0])
+	if err != nil {
+		return nil, err
+	}
+	return res, t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *erc7562Tracer) Stop(err error) {
+	t.reason = err
+	t.interrupt.Store(true)
+}
+
+// clearFailedLogs clears the logs of a callframe and all its children
+// in case of execution failure.
+func (t *erc7562Tracer) clearFailedLogs(cf *callFrameWithOpcodes, parentFailed bool) {
+	failed := cf.failed() || parentFailed
+	// Clear own logs
+	if failed {
+		cf.Logs = nil
+	}
+	for i := range cf.Calls {
+		t.clearFailedLogs(&cf.Calls[i], failed)
+	}
+}
+
+// OnOpcode implements the EVMLogger interface to trace a single step of VM execution.
+func (t *erc7562Tracer) OnOpcode(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+	if t.interrupt.Load() || len(t.callstackWithOpcodes) == 0 {
+		return
+	}
+	var (
+		opcode        = vm.OpCode(op)
+		stackData     = scope.StackData()
+		stackTopItems []uint256.Int
+	)
+	for i := 0; i < t.config.StackTopItemsSize && i < len(stackData); i++ {
+		stackTopItems = append(stackTopItems, *peepStack(stackData, i))
+	}
+	opcodeWithStack := &opcodeWithPartialStack{
+		Opcode:        opcode,
+		StackTopItems: stackTopItems,
+	}
+	t.handleReturnRevert(opcode)
+	currentCallFrame := &t.callstackWithOpcodes[len(t.callstackWithOpcodes)-1]
+	if t.lastOpWithStack != nil {
+		t.handleExtOpcodes(opcode, currentCallFrame)
+	}
+	t.handleAccessedContractSize(opcode, stackData, currentCallFrame)
+	if t.lastOpWithStack != nil {
+		t.handleGasObserved(opcode, currentCallFrame)
+	}
+	t.storeUsedOpcode(opcode, currentCallFrame)
+	t.handleStorageAccess(opcode, scope, currentCallFrame)
+	t.storeKeccak(opcode, scope)
+	t.lastOpWithStack = opcodeWithStack
+}
+
+func (t *erc7562Tracer) handleReturnRevert(opcode vm.OpCode) {
+	if opcode == vm.REVERT || opcode == vm.RETURN {
+		t.lastOpWithStack = nil
+	}
+}
+
+func (t *erc7562Tracer) handleGasObserved(opcode vm.OpCode, currentCallFrame *callFrameWithOpcodes) {
+	// [OP-012] GAS is allowed only when immediately followed by a call
+	pendingGasObserved := t.lastOpWithStack.Opcode == vm.GAS && !isCall(opcode)
+	if pendingGasObserved {
+		currentCallFrame.UsedOpcodes[vm.GAS]++
+	}
+}
+
+func (t *erc7562Tracer) storeUsedOpcode(opcode vm.OpCode, currentCallFrame *callFrameWithOpcodes) {
+	// ignore "unimportant" opcodes:
+	if _, ignored := t.ignoredOpcodes[opcode]; opcode != vm.GAS && !ignored {
+		currentCallFrame.UsedOpcodes[opcode]++
+	}
+}
+
+func (t *erc7562Tracer) handleStorageAccess(opcode vm.OpCode, scope tracing.OpContext, currentCallFrame *callFrameWithOpcodes) {
+	if opcode != vm.SLOAD && opcode != vm.SSTORE && opcode != vm.TLOAD && opcode != vm.TSTORE {
+		return
+	}
+	slot := common.Hash(peepStack(scope.StackData(), 0).Bytes32())
+	slotHex := slot.Hex()
+
+	switch opcode {
+	case vm.SLOAD:
+		// read slot values before this UserOp was created
+		// (so saving it if it was written before the first read)
+		_, rOk := currentCallFrame.AccessedSlots.Reads[slotHex]
+		_, wOk := currentCallFrame.AccessedSlots.Writes[slotHex]
+		if !rOk && !wOk {
+			var value uint256.Int
+			if err := t.env.IntraBlockState.GetState(scope.Address(), slot, &value); err != nil {
+				log.Warn("erc7562Tracer: failed to read storage", "address", scope.Address(), "slot", slot, "err", err)
+			}
+			currentCallFrame.AccessedSlots.Reads[slotHex] = append(currentCallFrame.AccessedSlots.Reads[slotHex], common.Hash(value.Bytes32()).Hex())
+		}
+	case vm.SSTORE:
+		currentCallFrame.AccessedSlots.Writes[slotHex]++
+	case vm.TLOAD:
+		currentCallFrame.AccessedSlots.TransientReads[slotHex]++
+	default:
+		currentCallFrame.AccessedSlots.TransientWrites[slotHex]++
+	}
+}
+
+func (t *erc7562Tracer) storeKeccak(opcode vm.OpCode, scope tracing.OpContext) {
+	if opcode != vm.KECCAK256 {
+		return
+	}
+	dataOffset := peepStack(scope.StackData(), 0).Uint64()
+	dataLength := peepStack(scope.StackData(), 1).Uint64()
+	preimage, err := tracers.GetMemoryCopyPadded(scope.MemoryData(), int64(dataOffset), int64(dataLength))
+	if err != nil {
+		log.Warn("erc7562Tracer: failed to copy keccak preimage from memory", "err", err)
+		return
+	}
+	t.keccakPreimages[string(preimage)] = struct{}{}
+}
+
+func (t *erc7562Tracer) handleExtOpcodes(opcode vm.OpCode, currentCallFrame *callFrameWithOpcodes) {
+	if !isEXT(t.lastOpWithStack.Opcode) || len(t.lastOpWithStack.StackTopItems) == 0 {
+		return
+	}
+	addr := common.BytesToAddress(t.lastOpWithStack.StackTopItems[0].Bytes())
+
+	// [OP-051] EXTCODESIZE ISZERO is allowed, it's used to check whether an address is a contract
+	if !(t.lastOpWithStack.Opcode == vm.EXTCODESIZE && opcode == vm.ISZERO) {
+		currentCallFrame.ExtCodeAccessInfo = append(currentCallFrame.ExtCodeAccessInfo, addr)
+	}
+}
+
+func (t *erc7562Tracer) handleAccessedContractSize(opcode vm.OpCode, stackData []uint256.Int, currentCallFrame *callFrameWithOpcodes) {
+	// [OP-041] Access to an address without deployed code is forbidden for EXTCODE* and *CALL opcodes
+	if !isEXTorCALL(opcode) {
+		return
+	}
+	n := 0
+	if !isEXT(opcode) {
+		n = 1
+	}
+	if n >= len(stackData) {
+		return
+	}
+	addr := common.BytesToAddress(peepStack(stackData, n).Bytes())
+	if _, ok := currentCallFrame.ContractSize[addr]; !ok && !isAllowedPrecompile(addr) {
+		code, err := t.env.IntraBlockState.GetCode(addr)
+		if err != nil {
+			log.Warn("erc7562Tracer: failed to read code", "address", addr, "err", err)
+		}
+		currentCallFrame.ContractSize[addr] = &contractSizeWithOpcode{
+			ContractSize: len(code),
+			Opcode:       opcode,
+		}
+	}
+}
+
+func peepStack(stackData []uint256.Int, n int) *uint256.Int {
+	return &stackData[len(stackData)-n-1]
+}
+
+func isEXTorCALL(opcode vm.OpCode) bool {
+	return isEXT(opcode) || isCall(opcode)
+}
+
+func isEXT(opcode vm.OpCode) bool {
+	return opcode == vm.EXTCODEHASH ||
+		opcode == vm.EXTCODESIZE ||
+		opcode == vm.EXTCODECOPY
+}
+
+func isCall(opcode vm.OpCode) bool {
+	return opcode == vm.CALL ||
+		opcode == vm.CALLCODE ||
+		opcode == vm.DELEGATECALL ||
+		opcode == vm.STATICCALL
+}
+
+// isAllowedPrecompile reports whether addr is one of the precompiles 0x01-0x09,
+// which are allowed to be called without having code.
+func isAllowedPrecompile(addr common.Address) bool {
+	addrInt := new(big.Int).SetBytes(addr[:])
+	return addrInt.Sign() == 1 && addrInt.Cmp(big.NewInt(10)) == -1
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package parity
+
+import (
+	"errors"
+
+	"github.com/holiman/uint256"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon/core/vm"
+)
+
+// CallFrames builds the traces of the call frames of a txn, in the order they
+// are entered, from the OnEnter and OnExit hooks of a tracer.
+type CallFrames struct {
+	Traces             []*Trace
+	IncludePrecompiles bool // by default Parity/OpenEthereum format does not include precompiles
+	Compat             bool // Bug for bug compatibility mode
+
+	traceAddr  []int
+	traceStack []*Trace
+	precompile bool // Whether the last Enter was called with `precompile = true`
+}
+
+// Enter adds the trace of a call frame, deep is whether it's called by another one.
+func (cf *CallFrames) Enter(deep bool, typ vm.OpCode, from common.Address, to common.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int) {
+	if precompile && deep && (value == nil || value.IsZero()) {
+		cf.precompile = true
+		if !cf.IncludePrecompiles {
+			return
+		}
+	}
+	if gas > 500000000 {
+		gas = 500000001 - (0x8000000000000000 - gas)
+	}
+	trace := &Trace{}
+	if create {
+		trResult := &CreateTraceResult{}
+		trace.Type = CREATE
+		trResult.Address = new(common.Address)
+		copy(trResult.Address[:], to.Bytes())
+		trace.Result = trResult
+	} else {
+		trace.Result = &TraceResult{}
+		trace.Type = CALL
+	}
+	if deep {
+		topTrace := cf.traceStack[len(cf.traceStack)-1]
+		traceIdx := topTrace.Subtraces
+		cf.traceAddr = append(cf.traceAddr, traceIdx)
+		topTrace.Subtraces++
+		if typ == vm.DELEGATECALL {
+			switch action := topTrace.Action.(type) {
+			case *CreateTraceAction:
+				value, _ = uint256.FromBig(action.Value.ToInt())
+			case *CallTraceAction:
+				value, _ = uint256.FromBig(action.Value.ToInt())
+			}
+		}
+		if typ == vm.STATICCALL {
+			value = uint256.NewInt(0)
+		}
+	}
+	trace.TraceAddress = make([]int, len(cf.traceAddr))
+	copy(trace.TraceAddress, cf.traceAddr)
+	if create {
+		action := CreateTraceAction{}
+		action.From = from
+		action.Gas.ToInt().SetUint64(gas)
+		action.Init = common.CopyBytes(input)
+		action.Value.ToInt().Set(value.ToBig())
+		trace.Action = &action
+	} else if typ == vm.SELFDESTRUCT {
+		trace.Type = SUICIDE
+		trace.Result = nil
+		action := &SuicideTraceAction{}
+		action.Address = from
+		action.RefundAddress = to
+		action.Balance.ToInt().Set(value.ToBig())
+		trace.Action = action
+	} else {
+		action := CallTraceAction{}
+		switch typ {
+		case vm.CALL:
+			action.CallType = CALL
+		case vm.CALLCODE:
+			action.CallType = CALLCODE
+		case vm.DELEGATECALL:
+			action.CallType = DELEGATECALL
+		case vm.STATICCALL:
+			action.CallType = STATICCALL
+		}
+		action.From = from
+		action.To = to
+		action.Gas.ToInt().SetUint64(gas)
+		action.Input = common.CopyBytes(input)
+		action.Value.ToInt().Set(value.ToBig())
+		trace.Action = &action
+	}
+	cf.Traces = append(cf.Traces, trace)
+	cf.traceStack = append(cf.traceStack, trace)
+}
+
+// Exit sets the result of the trace of the call frame entered last.
+func (cf *CallFrames) Exit(deep bool, output []byte, gasUsed uint64, err error) {
+	if cf.precompile {
+		cf.precompile = false
+		if !cf.IncludePrecompiles {
+			return
+		}
+	}
+	ignoreError := false
+	topTrace := cf.traceStack[len(cf.traceStack)-1]
+	if cf.Compat {
+		ignoreError = !deep && topTrace.Type == CREATE
+	}
+	if err != nil && !ignoreError {
+		if errors.Is(err, vm.ErrExecutionReverted) {
+			topTrace.Error = "Reverted"
+			switch topTrace.Type {
+			case CALL:
+				topTrace.Result.(*TraceResult).GasUsed = new(hexutil.Big)
+				topTrace.Result.(*TraceResult).GasUsed.ToInt().SetUint64(gasUsed)
+				topTrace.Result.(*TraceResult).Output = common.CopyBytes(output)
+			case CREATE:
+				topTrace.Result.(*CreateTraceResult).GasUsed = new(hexutil.Big)
+				topTrace.Result.(*CreateTraceResult).GasUsed.ToInt().SetUint64(gasUsed)
+				topTrace.Result.(*CreateTraceResult).Code = common.CopyBytes(output)
+			}
+		} else {
+			topTrace.Result = nil
+			topTrace.Error = err.Error()
+		}
+	} else {
+		if len(output) > 0 {
+			switch topTrace.Type {
+			case CALL:
+				topTrace.Result.(*TraceResult).Output = common.CopyBytes(output)
+			case CREATE:
+				topTrace.Result.(*CreateTraceResult).Code = common.CopyBytes(output)
+			}
+		}
+		switch topTrace.Type {
+		case CALL:
+			topTrace.Result.(*TraceResult).GasUsed = new(hexutil.Big)
+			topTrace.Result.(*TraceResult).GasUsed.ToInt().SetUint64(gasUsed)
+		case CREATE:
+			topTrace.Result.(*CreateTraceResult).GasUsed = new(hexutil.Big)
+			topTrace.Result.(*CreateTraceResult).GasUsed.ToInt().SetUint64(gasUsed)
+		}
+	}
+	cf.traceStack = cf.traceStack[:len(cf.traceStack)-1]
+	if deep {
+		cf.traceAddr = cf.traceAddr[:len(cf.traceAddr)-1]
+	}
+}
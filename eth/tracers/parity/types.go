@@ -0,0 +1,115 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+// Package parity builds the call traces of transactions in the Parity/OpenEthereum
+// format, the one of the trace_* methods and of the flatCallTracer.
+// See: https://openethereum.github.io/JSONRPC-trace-module
+package parity
+
+import (
+	"fmt"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+)
+
+// Types of traces and call types of CallTraceAction
+const (
+	CALL         = "call"
+	CALLCODE     = "callcode"
+	DELEGATECALL = "delegatecall"
+	STATICCALL   = "staticcall"
+	CREATE       = "create"
+	SUICIDE      = "suicide"
+)
+
+// Trace A trace in the desired format (Parity/OpenEthereum)
+type Trace struct {
+	// Do not change the ordering of these fields -- allows for easier comparison with other clients
+	Action              interface{}  `json:"action"` // Can be either CallTraceAction or CreateTraceAction
+	BlockHash           *common.Hash `json:"blockHash,omitempty"`
+	BlockNumber         *uint64      `json:"blockNumber,omitempty"`
+	Error               string       `json:"error,omitempty"`
+	Result              interface{}  `json:"result"`
+	Subtraces           int          `json:"subtraces"`
+	TraceAddress        []int        `json:"traceAddress"`
+	TransactionHash     *common.Hash `json:"transactionHash,omitempty"`
+	TransactionPosition *uint64      `json:"transactionPosition,omitempty"`
+	Type                string       `json:"type"`
+}
+
+type CallTraceAction struct {
+	From     common.Address `json:"from"`
+	CallType string         `json:"callType"`
+	Gas      hexutil.Big    `json:"gas"`
+	Input    hexutil.Bytes  `json:"input"`
+	To       common.Address `json:"to"`
+	Value    hexutil.Big    `json:"value"`
+}
+
+type CreateTraceAction struct {
+	From  common.Address `json:"from"`
+	Gas   hexutil.Big    `json:"gas"`
+	Init  hexutil.Bytes  `json:"init"`
+	Value hexutil.Big    `json:"value"`
+}
+
+type SuicideTraceAction struct {
+	Address       common.Address `json:"address"`
+	RefundAddress common.Address `json:"refundAddress"`
+	Balance       hexutil.Big    `json:"balance"`
+}
+
+type CreateTraceResult struct {
+	// Do not change the ordering of these fields -- allows for easier comparison with other clients
+	Address *common.Address `json:"address,omitempty"`
+	Code    hexutil.Bytes   `json:"code"`
+	GasUsed *hexutil.Big    `json:"gasUsed"`
+}
+
+// TraceResult A parity formatted trace result
+type TraceResult struct {
+	// Do not change the ordering of these fields -- allows for easier comparison with other clients
+	GasUsed *hexutil.Big  `json:"gasUsed"`
+	Output  hexutil.Bytes `json:"output"`
+}
+
+// Allows for easy printing of a parity trace for debugging
+func (t Trace) String() string {
+	var ret string
+	//ret += fmt.Sprintf("Action.SelfDestructed: %s\n", t.Action.SelfDestructed)
+	//ret += fmt.Sprintf("Action.Balance: %s\n", t.Action.Balance)
+	//ret += fmt.Sprintf("Action.CallType: %s\n", t.Action.CallType)
+	//ret += fmt.Sprintf("Action.From: %s\n", t.Action.From)
+	//ret += fmt.Sprintf("Action.Gas: %d\n", t.Action.Gas.ToInt())
+	//ret += fmt.Sprintf("Action.Init: %s\n", t.Action.Init)
+	//ret += fmt.Sprintf("Action.Input: %s\n", t.Action.Input)
+	//ret += fmt.Sprintf("Action.RefundAddress: %s\n", t.Action.RefundAddress)
+	//ret += fmt.Sprintf("Action.To: %s\n", t.Action.To)
+	//ret += fmt.Sprintf("Action.Value: %s\n", t.Action.Value)
+	ret += fmt.Sprintf("BlockHash: %v\n", t.BlockHash)
+	ret += fmt.Sprintf("BlockNumber: %d\n", t.BlockNumber)
+	//ret += fmt.Sprintf("Result.Address: %s\n", t.Result.Address)
+	//ret += fmt.Sprintf("Result.Code: %s\n", t.Result.Code)
+	//ret += fmt.Sprintf("Result.GasUsed: %s\n", t.Result.GasUsed)
+	//ret += fmt.Sprintf("Result.Output: %s\n", t.Result.Output)
+	ret += fmt.Sprintf("Subtraces: %d\n", t.Subtraces)
+	ret += fmt.Sprintf("TraceAddress: %v\n", t.TraceAddress)
+	ret += fmt.Sprintf("TransactionHash: %v\n", t.TransactionHash)
+	ret += fmt.Sprintf("TransactionPosition: %d\n", t.TransactionPosition)
+	ret += fmt.Sprintf("Type: %s\n", t.Type)
+	return ret
+}
@@ -30,7 +30,9 @@ import (
 	"github.com/erigontech/erigon-lib/common/dbg"
 	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/log/v3"
+	libstate "github.com/erigontech/erigon-lib/state"
 	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon-lib/types/accounts"
 	"github.com/erigontech/erigon/core"
 	"github.com/erigontech/erigon/core/state"
 	"github.com/erigontech/erigon/core/tracing"
@@ -46,19 +48,30 @@ import (
 
 var noop = state.NewNoopWriter()
 
+// stateWriter - state.Writer applies writes to the shared state right away, state.StateWriterBufferedV3 collects
+// them for the conflict resolution of parallel execution
+type stateWriter interface {
+	state.StateWriter
+	SetTxNum(txNum uint64)
+	ResetWriteSet()
+	WriteSet() map[string]*libstate.KvList
+	PrevAndDels() (map[string][]byte, map[string]*accounts.Account, map[string][]byte, map[string]uint64)
+}
+
 type Worker struct {
-	lock        sync.Locker
-	logger      log.Logger
-	chainDb     kv.RoDB
-	chainTx     kv.TemporalTx
-	background  bool // if true - worker does manage RoTx (begin/rollback) in .ResetTx()
-	blockReader services.FullBlockReader
-	in          *state.QueueWithRetry
-	rs          *state.ParallelExecutionState
-	stateWriter *state.Writer
-	stateReader state.ResettableStateReader
-	historyMode bool // if true - stateReader is HistoryReaderV3, otherwise it's state reader
-	chainConfig *chain.Config
+	lock         sync.Locker
+	logger       log.Logger
+	chainDb      kv.RoDB
+	chainTx      kv.TemporalTx
+	background   bool // if true - worker does manage RoTx (begin/rollback) in .ResetTx()
+	blockReader  services.FullBlockReader
+	in           *state.QueueWithRetry
+	rs           *state.ParallelExecutionState
+	stateWriter  stateWriter
+	bufferWrites bool // if true - writes are only collected into txTask.WriteLists, see BufferWrites
+	stateReader  state.ResettableStateReader
+	historyMode  bool // if true - stateReader is HistoryReaderV3, otherwise it's state reader
+	chainConfig  *chain.Config
 
 	ctx      context.Context
 	engine   consensus.Engine
@@ -120,7 +133,19 @@ func (rw *Worker) ResetState(rs *state.ParallelExecutionState, accumulator *shar
 	} else {
 		rw.SetReader(state.NewReaderV3(rs.TemporalGetter()))
 	}
-	rw.stateWriter = state.NewWriter(rs.TemporalPutDel(), accumulator, 0)
+	if rw.background || rw.bufferWrites {
+		// parallel workers must not write to the shared state: their writes are applied only if their reads are still valid
+		rw.stateWriter = state.NewStateWriterBufferedV3(rs, accumulator)
+	} else {
+		rw.stateWriter = state.NewWriter(rs.TemporalPutDel(), accumulator, 0)
+	}
+}
+
+// BufferWrites - makes a foreground worker collect its writes into txTask.WriteLists like the background ones,
+// instead of applying them. Used to re-execute txns serially to cross-check results of parallel execution.
+func (rw *Worker) BufferWrites(rs *state.ParallelExecutionState) {
+	rw.bufferWrites = true
+	rw.ResetState(rs, nil)
 }
 
 func (rw *Worker) SetGaspool(gp *core.GasPool) {
@@ -208,7 +233,10 @@ func (rw *Worker) RunTxTaskNoLock(txTask *state.TxTask, isMining, skipPostEvalua
 
 	rw.stateReader.SetTxNum(txTask.TxNum)
 	rw.stateWriter.SetTxNum(txTask.TxNum)
-	rw.rs.Domains().SetTxNum(txTask.TxNum)
+	if !rw.background {
+		// txNum of the shared domains is set by the apply loop in parallel execution
+		rw.rs.Domains().SetTxNum(txTask.TxNum)
+	}
 	rw.stateReader.ResetReadSet()
 	rw.stateWriter.ResetWriteSet()
 
@@ -293,6 +321,10 @@ func (rw *Worker) RunTxTaskNoLock(txTask *state.TxTask, isMining, skipPostEvalua
 
 		msg := txTask.TxAsMessage
 		rw.evm.ResetBetweenBlocks(txTask.EvmBlockContext, core.NewEVMTxContext(msg), ibs, rw.vmCfg, rules)
+		if rw.background || rw.bufferWrites {
+			// txns are executed out of order: each one may use the whole block gas, block total is checked after apply
+			rw.taskGasPool.Reset(txTask.Header.GasLimit, cc.GetMaxBlobGasPerBlock(txTask.Header.Time))
+		}
 
 		if hooks != nil && hooks.OnTxStart != nil {
 			hooks.OnTxStart(rw.evm.GetVMContext(), txn, msg.From())
@@ -314,7 +346,10 @@ func (rw *Worker) RunTxTaskNoLock(txTask *state.TxTask, isMining, skipPostEvalua
 			txTask.TraceFroms = rw.callTracer.Froms()
 			txTask.TraceTos = rw.callTracer.Tos()
 
-			txTask.CreateReceipt(rw.Tx())
+			if !rw.background {
+				// receipts of txns executed out of order are created by the apply loop: they depend on the previous receipt
+				txTask.CreateReceipt(rw.Tx())
+			}
 			if hooks != nil && hooks.OnTxEnd != nil {
 				hooks.OnTxEnd(txTask.BlockReceipts[txTask.TxIndex], nil)
 			}
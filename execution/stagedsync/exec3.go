@@ -36,6 +36,7 @@ import (
 	"github.com/erigontech/erigon-lib/config3"
 	"github.com/erigontech/erigon-lib/estimate"
 	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/prune"
 	"github.com/erigontech/erigon-lib/kv/rawdbv3"
 	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon-lib/metrics"
@@ -208,8 +209,6 @@ func ExecV3(ctx context.Context,
 	isMining bool,
 ) (execErr error) {
 	inMemExec := txc.Doms != nil
-	// TODO: e35 doesn't support parallel-exec yet
-	parallel = false //nolint
 
 	blockReader := cfg.blockReader
 	chainConfig := cfg.chainConfig
@@ -224,16 +223,14 @@ func ExecV3(ctx context.Context,
 	applyTx := txc.Tx
 	useExternalTx := applyTx != nil
 	if !useExternalTx {
-		if !parallel {
-			var err error
-			applyTx, err = cfg.db.BeginRw(ctx) //nolint
-			if err != nil {
-				return err
-			}
-			defer func() { // need callback - because tx may be committed
-				applyTx.Rollback()
-			}()
+		var err error
+		applyTx, err = cfg.db.BeginRw(ctx) //nolint
+		if err != nil {
+			return err
 		}
+		defer func() { // need callback - because tx may be committed
+			applyTx.Rollback()
+		}()
 	}
 
 	chainReader := NewChainReaderImpl(cfg.chainConfig, applyTx, blockReader, logger)
@@ -249,6 +246,18 @@ func ExecV3(ctx context.Context,
 			agg.SetCompressWorkers(1)
 		}
 	}
+	if len(cfg.prune.Watched) > 0 && !inMemExec && !isMining {
+		// history of blocks behind prune distance goes to files only for watched accounts
+		horizon, err := blockReader.TxnumReader(ctx).Min(applyTx, cfg.prune.History.PruneTo(maxBlockNum))
+		if err != nil {
+			return err
+		}
+		agg.SetHistoryRetention(state2.KeepAddresses(cfg.prune.Watched), horizon)
+		// readers of history before horizon must not fall back to latest state of not-watched accounts
+		if err := prune.SetWatchedHorizon(applyTx, horizon); err != nil {
+			return err
+		}
+	}
 
 	var err error
 	var doms *state2.SharedDomains
@@ -358,64 +367,36 @@ func ExecV3(ctx context.Context,
 	var stepsInDB float64
 	var executor executor
 
-	if parallel {
-		pe := &parallelExecutor{
-			txExecutor: txExecutor{
-				cfg:            cfg,
-				execStage:      execStage,
-				rs:             rs,
-				doms:           doms,
-				agg:            agg,
-				accumulator:    accumulator,
-				isMining:       isMining,
-				inMemExec:      inMemExec,
-				applyTx:        applyTx,
-				applyWorker:    applyWorker,
-				outputTxNum:    &outputTxNum,
-				outputBlockNum: stages.SyncMetrics[stages.Execution],
-				logger:         logger,
-			},
-			shouldGenerateChangesets: shouldGenerateChangesets,
-			workerCount:              workerCount,
-			pruneEvery:               pruneEvery,
-			logEvery:                 logEvery,
-			progress:                 progress,
-		}
-
-		executorCancel := pe.run(ctx, maxTxNum, logger)
-		defer executorCancel()
-
-		defer func() {
-			progress.Log("Done", executor.readState(), nil, pe.rws, 0 /*txCount - TODO*/, logGas, inputBlockNum.Load(), outputBlockNum.GetValueUint64(), outputTxNum.Load(), mxExecRepeats.GetValueUint64(), stepsInDB, shouldGenerateChangesets, inMemExec)
-		}()
+	applyWorker.ResetTx(applyTx)
+
+	se := &serialExecutor{
+		txExecutor: txExecutor{
+			cfg:            cfg,
+			execStage:      execStage,
+			rs:             rs,
+			doms:           doms,
+			agg:            agg,
+			u:              u,
+			isMining:       isMining,
+			inMemExec:      inMemExec,
+			applyTx:        applyTx,
+			applyWorker:    applyWorker,
+			outputTxNum:    &outputTxNum,
+			outputBlockNum: stages.SyncMetrics[stages.Execution],
+			logger:         logger,
+		},
+	}
+	executor = se
 
-		executor = pe
-	} else {
-		applyWorker.ResetTx(applyTx)
-
-		se := &serialExecutor{
-			txExecutor: txExecutor{
-				cfg:            cfg,
-				execStage:      execStage,
-				rs:             rs,
-				doms:           doms,
-				agg:            agg,
-				u:              u,
-				isMining:       isMining,
-				inMemExec:      inMemExec,
-				applyTx:        applyTx,
-				applyWorker:    applyWorker,
-				outputTxNum:    &outputTxNum,
-				outputBlockNum: stages.SyncMetrics[stages.Execution],
-				logger:         logger,
-			},
-		}
-
-		defer func() {
-			progress.Log("Done", executor.readState(), nil, nil, se.txCount, logGas, inputBlockNum.Load(), outputBlockNum.GetValueUint64(), outputTxNum.Load(), mxExecRepeats.GetValueUint64(), stepsInDB, shouldGenerateChangesets || cfg.syncCfg.KeepExecutionProofs, inMemExec)
-		}()
+	defer func() {
+		progress.Log("Done", executor.readState(), nil, nil, se.txCount, logGas, inputBlockNum.Load(), outputBlockNum.GetValueUint64(), outputTxNum.Load(), mxExecRepeats.GetValueUint64(), stepsInDB, shouldGenerateChangesets || cfg.syncCfg.KeepExecutionProofs, inMemExec)
+	}()
 
-		executor = se
+	// parallel workers neither report state changes to txpool nor call tracing hooks
+	if parallel && !inMemExec && accumulator == nil && hooks == nil {
+		pe := newParallelExecutor(ctx, se, workerCount, logger)
+		defer pe.wait()
+		executor = pe
 	}
 
 	blockComplete.Store(true)
@@ -469,11 +450,9 @@ Loop:
 		if shouldGenerateChangesets && blockNum > 0 {
 			executor.domains().SetChangesetAccumulator(changeset)
 		}
-		if !parallel {
-			select {
-			case readAhead <- blockNum:
-			default:
-			}
+		select {
+		case readAhead <- blockNum:
+		default:
 		}
 		inputBlockNum.Store(blockNum)
 		executor.domains().SetBlockNum(blockNum)
@@ -518,14 +497,7 @@ Loop:
 		gp := new(core.GasPool).AddGas(header.GasLimit).AddBlobGas(chainConfig.GetMaxBlobGasPerBlock(b.Time()))
 
 		// print type of engine
-		if parallel {
-			if err := executor.status(ctx, commitThreshold); err != nil {
-				if b.NumberU64() > 0 && hooks != nil && hooks.OnBlockEnd != nil {
-					hooks.OnBlockEnd(err)
-				}
-				return err
-			}
-		} else if accumulator != nil {
+		if accumulator != nil {
 			txs, err := blockReader.RawTransactions(context.Background(), executor.tx(), b.NumberU64(), b.NumberU64())
 			if err != nil {
 				if b.NumberU64() > 0 && hooks != nil && hooks.OnBlockEnd != nil {
@@ -641,38 +613,24 @@ Loop:
 			isAASequence = true
 		}
 
-		if parallel {
-			_, err := executor.execute(ctx, txTasks, nil /*gasPool*/) // For now don't use block's gas pool for parallel
-			if b.NumberU64() > 0 && hooks != nil && hooks.OnBlockEnd != nil {
-				hooks.OnBlockEnd(err)
-			}
-			if err != nil {
-				return err
-			}
-
-			agg.BuildFilesInBackground(outputTxNum.Load())
-		} else {
-			se := executor.(*serialExecutor)
+		se.skipPostEvaluation = skipPostEvaluation
 
-			se.skipPostEvaluation = skipPostEvaluation
-
-			continueLoop, err := se.execute(ctx, txTasks, gp)
-			if b.NumberU64() > 0 && hooks != nil && hooks.OnBlockEnd != nil {
-				hooks.OnBlockEnd(err)
-			}
-			if err != nil {
-				return err
-			}
+		continueLoop, err := executor.execute(ctx, txTasks, gp)
+		if b.NumberU64() > 0 && hooks != nil && hooks.OnBlockEnd != nil {
+			hooks.OnBlockEnd(err)
+		}
+		if err != nil {
+			return err
+		}
 
-			count += uint64(len(txTasks))
-			logGas += se.gasUsed
+		count += uint64(len(txTasks))
+		logGas += se.gasUsed
 
-			se.gasUsed = 0
-			se.blobGasUsed = 0
+		se.gasUsed = 0
+		se.blobGasUsed = 0
 
-			if !continueLoop {
-				break Loop
-			}
+		if !continueLoop {
+			break Loop
 		}
 
 		mxExecBlocks.Add(1)
@@ -709,84 +667,82 @@ Loop:
 		}
 
 		// MA commitTx
-		if !parallel {
-			select {
-			case <-logEvery.C:
-				if inMemExec || isMining {
-					break
-				}
+		select {
+		case <-logEvery.C:
+			if inMemExec || isMining {
+				break
+			}
 
-				stepsInDB := rawdbhelpers.IdxStepsCountV3(executor.tx())
-				progress.Log("", executor.readState(), nil, nil, count, logGas, inputBlockNum.Load(), outputBlockNum.GetValueUint64(), outputTxNum.Load(), mxExecRepeats.GetValueUint64(), stepsInDB, shouldGenerateChangesets, inMemExec)
+			stepsInDB := rawdbhelpers.IdxStepsCountV3(executor.tx())
+			progress.Log("", executor.readState(), nil, nil, count, logGas, inputBlockNum.Load(), outputBlockNum.GetValueUint64(), outputTxNum.Load(), mxExecRepeats.GetValueUint64(), stepsInDB, shouldGenerateChangesets, inMemExec)
 
-				//TODO: https://github.com/erigontech/erigon/issues/10724
-				//if executor.tx().(state2.HasAggTx).AggTx().(*state2.AggregatorRoTx).CanPrune(executor.tx(), outputTxNum.Load()) {
-				//	//small prune cause MDBX_TXN_FULL
-				//	if _, err := executor.tx().(state2.HasAggTx).AggTx().(*state2.AggregatorRoTx).PruneSmallBatches(ctx, 10*time.Hour, executor.tx()); err != nil {
-				//		return err
-				//	}
-				//}
+			//TODO: https://github.com/erigontech/erigon/issues/10724
+			//if executor.tx().(state2.HasAggTx).AggTx().(*state2.AggregatorRoTx).CanPrune(executor.tx(), outputTxNum.Load()) {
+			//	//small prune cause MDBX_TXN_FULL
+			//	if _, err := executor.tx().(state2.HasAggTx).AggTx().(*state2.AggregatorRoTx).PruneSmallBatches(ctx, 10*time.Hour, executor.tx()); err != nil {
+			//		return err
+			//	}
+			//}
 
-				aggregatorRo := state2.AggTx(executor.tx())
+			aggregatorRo := state2.AggTx(executor.tx())
 
-				isBatchFull := executor.readState().SizeEstimate() >= commitThreshold
+			isBatchFull := executor.readState().SizeEstimate() >= commitThreshold
 
-				needCalcRoot := isBatchFull ||
-					skipPostEvaluation || // If we skip post evaluation, then we should compute root hash ASAP for fail-fast
-					aggregatorRo.CanPrune(executor.tx(), outputTxNum.Load()) // if have something to prune - better prune ASAP to keep chaindata smaller
-				if !needCalcRoot {
-					break
-				}
+			needCalcRoot := isBatchFull ||
+				skipPostEvaluation || // If we skip post evaluation, then we should compute root hash ASAP for fail-fast
+				aggregatorRo.CanPrune(executor.tx(), outputTxNum.Load()) // if have something to prune - better prune ASAP to keep chaindata smaller
+			if !needCalcRoot {
+				break
+			}
 
-				var (
-					commitStart = time.Now()
+			var (
+				commitStart = time.Now()
 
-					pruneDuration time.Duration
-				)
-				ok, times, err := flushAndCheckCommitmentV3(ctx, b.HeaderNoCopy(), executor.tx(), executor.domains(), cfg, execStage, stageProgress, parallel, logger, u, inMemExec)
-				if err != nil {
-					return err
-				} else if !ok {
-					break Loop
-				}
+				pruneDuration time.Duration
+			)
+			ok, times, err := flushAndCheckCommitmentV3(ctx, b.HeaderNoCopy(), executor.tx(), executor.domains(), cfg, execStage, stageProgress, logger, u, inMemExec)
+			if err != nil {
+				return err
+			} else if !ok {
+				break Loop
+			}
 
-				computeCommitmentDuration += times.ComputeCommitment
-				flushDuration := times.Flush
+			computeCommitmentDuration += times.ComputeCommitment
+			flushDuration := times.Flush
 
-				timeStart := time.Now()
+			timeStart := time.Now()
 
-				// allow greedy prune on non-chain-tip
-				pruneTimeout := 250 * time.Millisecond
-				if initialCycle {
-					pruneTimeout = 10 * time.Hour
+			// allow greedy prune on non-chain-tip
+			pruneTimeout := 250 * time.Millisecond
+			if initialCycle {
+				pruneTimeout = 10 * time.Hour
 
-					if err = executor.tx().(kv.TemporalRwTx).GreedyPruneHistory(ctx, kv.CommitmentDomain); err != nil {
-						return err
-					}
-				}
-
-				if _, err := aggregatorRo.PruneSmallBatches(ctx, pruneTimeout, executor.tx()); err != nil {
+				if err = executor.tx().(kv.TemporalRwTx).GreedyPruneHistory(ctx, kv.CommitmentDomain); err != nil {
 					return err
 				}
-				pruneDuration = time.Since(timeStart)
+			}
 
-				commitDuration, err := executor.(*serialExecutor).commit(ctx, inputTxNum, outputBlockNum.GetValueUint64(), useExternalTx)
-				if err != nil {
-					return err
-				}
+			if _, err := aggregatorRo.PruneSmallBatches(ctx, pruneTimeout, executor.tx()); err != nil {
+				return err
+			}
+			pruneDuration = time.Since(timeStart)
 
-				// on chain-tip: if batch is full then stop execution - to allow stages commit
-				if !initialCycle && isBatchFull {
-					maxBlockNum = min(maxBlockNum, blockNum+changesetSafeRange) // allow for some changesets to be generated.
-				}
-				if initialCycle {
-					logger.Info("Committed", "time", time.Since(commitStart),
-						"block", outputBlockNum.GetValueUint64(), "txNum", inputTxNum,
-						"step", fmt.Sprintf("%.1f", float64(inputTxNum)/float64(agg.StepSize())),
-						"flush", flushDuration, "compute commitment", computeCommitmentDuration, "tx.commit", commitDuration, "prune", pruneDuration)
-				}
-			default:
+			commitDuration, err := executor.commit(ctx, inputTxNum, outputBlockNum.GetValueUint64(), useExternalTx)
+			if err != nil {
+				return err
+			}
+
+			// on chain-tip: if batch is full then stop execution - to allow stages commit
+			if !initialCycle && isBatchFull {
+				maxBlockNum = min(maxBlockNum, blockNum+changesetSafeRange) // allow for some changesets to be generated.
+			}
+			if initialCycle {
+				logger.Info("Committed", "time", time.Since(commitStart),
+					"block", outputBlockNum.GetValueUint64(), "txNum", inputTxNum,
+					"step", fmt.Sprintf("%.1f", float64(inputTxNum)/float64(agg.StepSize())),
+					"flush", flushDuration, "compute commitment", computeCommitmentDuration, "tx.commit", commitDuration, "prune", pruneDuration)
 			}
+		default:
 		}
 
 		select {
@@ -803,7 +759,7 @@ Loop:
 
 	if u != nil && !u.HasUnwindPoint() {
 		if b != nil {
-			_, _, err = flushAndCheckCommitmentV3(ctx, b.HeaderNoCopy(), executor.tx(), executor.domains(), cfg, execStage, stageProgress, parallel, logger, u, inMemExec)
+			_, _, err = flushAndCheckCommitmentV3(ctx, b.HeaderNoCopy(), executor.tx(), executor.domains(), cfg, execStage, stageProgress, logger, u, inMemExec)
 			if err != nil {
 				return err
 			}
@@ -921,17 +877,15 @@ type FlushAndComputeCommitmentTimes struct {
 }
 
 // flushAndCheckCommitmentV3 - does write state to db and then check commitment
-func flushAndCheckCommitmentV3(ctx context.Context, header *types.Header, applyTx kv.RwTx, doms *state2.SharedDomains, cfg ExecuteBlockCfg, e *StageState, maxBlockNum uint64, parallel bool, logger log.Logger, u Unwinder, inMemExec bool) (ok bool, times FlushAndComputeCommitmentTimes, err error) {
+func flushAndCheckCommitmentV3(ctx context.Context, header *types.Header, applyTx kv.RwTx, doms *state2.SharedDomains, cfg ExecuteBlockCfg, e *StageState, maxBlockNum uint64, logger log.Logger, u Unwinder, inMemExec bool) (ok bool, times FlushAndComputeCommitmentTimes, err error) {
 	start := time.Now()
 	// E2 state root check was in another stage - means we did flush state even if state root will not match
 	// And Unwind expecting it
-	if !parallel {
-		if err := e.Update(applyTx, maxBlockNum); err != nil {
-			return false, times, err
-		}
-		if _, err := rawdb.IncrementStateVersion(applyTx); err != nil {
-			return false, times, fmt.Errorf("writing plain state version: %w", err)
-		}
+	if err := e.Update(applyTx, maxBlockNum); err != nil {
+		return false, times, err
+	}
+	if _, err := rawdb.IncrementStateVersion(applyTx); err != nil {
+		return false, times, fmt.Errorf("writing plain state version: %w", err)
 	}
 
 	if header == nil {
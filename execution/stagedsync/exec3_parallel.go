@@ -1,17 +1,15 @@
 package stagedsync
 
 import (
+	"bytes"
 	"context"
-	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
 
-	"golang.org/x/sync/errgroup"
-
-	"github.com/erigontech/erigon-db/rawdb"
-	"github.com/erigontech/erigon-db/rawdb/rawdbhelpers"
 	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/log/v3"
@@ -20,10 +18,7 @@ import (
 	"github.com/erigontech/erigon-lib/types"
 	"github.com/erigontech/erigon/core"
 	"github.com/erigontech/erigon/core/state"
-	"github.com/erigontech/erigon/core/vm"
-	"github.com/erigontech/erigon/execution/consensus"
 	"github.com/erigontech/erigon/execution/exec3"
-	chaos_monkey "github.com/erigontech/erigon/tests/chaos-monkey"
 	"github.com/erigontech/erigon/turbo/shards"
 )
 
@@ -43,34 +38,26 @@ Until conflict-resolution succeed - none of execution updates must pass to lower
 Object TxTask it's just set of small buffers (readset + writeset) for each transaction.
 Write to TxTask happens by code like `txTask.ReadLists = rw.stateReader.ReadSet()`.
 
-- TxTask - objects coming from parallel-workers to conflict-resolution goroutine (ApplyLoop and method ReadsValid).
-Flush of data to lower-level-of-abstraction is done by method `agg.ApplyState` (method agg.ApplyHistory exists
-only for performance - to reduce time of RwLock on state, but by meaning `ApplyState+ApplyHistory` it's 1 method to
-flush changes from TxTask to lower-level-of-abstraction).
+- TxTask - objects coming from parallel-workers to conflict-resolution (method ReadsValid).
+Flush of data to lower-level-of-abstraction is done by method `ParallelExecutionState.ApplyState`.
 
-- ParallelExecutionState - it's all updates which are stored in RAM - all parallel workers can see this updates.
+- SharedDomains - it's all updates which are stored in RAM - all parallel workers can see this updates.
 Execution of txs always done on Valid version of state (no partial-updates of state).
-Flush of updates to lower-level-of-abstractions done by method `ParallelExecutionState.Flush`.
-On this level-of-abstraction also exists ReaderV3.
-IntraBlockState does call ReaderV3, and ReaderV3 call ParallelExecutionState(in-mem-cache) or DB (RoTx).
-WAL - also on this level-of-abstraction - agg.ApplyHistory does write updates from TxTask to WAL.
-WAL it's like ParallelExecutionState just without reading api (can only write there). WAL flush to disk periodically (doesn't need much RAM).
-
-- RoTx - see everything what committed to DB. Commit is done by rwLoop goroutine.
-rwloop does:
-  - stop all Workers
-  - call ParallelExecutionState.Flush()
-  - commit
-  - open new RoTx
-  - set new RoTx to all Workers
-  - start Worker start workers
-
-When rwLoop has nothing to do - it does Prune, or flush of WAL to RwTx (agg.rotate+agg.Flush)
+Flush of updates to lower-level-of-abstractions done by method `SharedDomains.Flush`.
+On this level-of-abstraction also exists ReaderParallelV3.
+IntraBlockState does call ReaderParallelV3, and ReaderParallelV3 call SharedDomains(in-mem-cache) or DB (RoTx).
+
+- RoTx - see everything what committed to DB. Commit is done by ExecV3 exactly like in serial execution,
+between blocks - when workers are idle. After commit workers open new RoTx.
+
+Execution is deterministic: the txns of a block are executed speculatively by the workers, but results are
+applied strictly in txNum order by the same code as in serial execution. Txn whose reads are not valid anymore
+(conflict) is re-executed right away on top of all the previous txns - so result is always same as of serial execution.
 */
 
 type executor interface {
 	execute(ctx context.Context, tasks []*state.TxTask, gp *core.GasPool) (bool, error)
-	status(ctx context.Context, commitThreshold uint64) error
+	commit(ctx context.Context, txNum uint64, blockNum uint64, useExternalTx bool) (time.Duration, error)
 	wait() error
 	getHeader(ctx context.Context, hash common.Hash, number uint64) (*types.Header, error)
 
@@ -128,414 +115,237 @@ func (te *txExecutor) getHeader(ctx context.Context, hash common.Hash, number ui
 	return h, err
 }
 
+// parallelExecutor - executes txns of each block by background workers, and applies results by serialExecutor
 type parallelExecutor struct {
-	txExecutor
-	rwLoopErrCh              chan error
-	rwLoopG                  *errgroup.Group
-	applyLoopWg              sync.WaitGroup
-	execWorkers              []*exec3.Worker
-	stopWorkers              func()
-	waitWorkers              func()
-	lastBlockNum             atomic.Uint64
-	in                       *state.QueueWithRetry
-	rws                      *state.ResultsQueue
-	rwsConsumed              chan struct{}
-	shouldGenerateChangesets bool
-	workerCount              int
-	pruneEvery               *time.Ticker
-	logEvery                 *time.Ticker
-	slowDownLimit            *time.Ticker
-	progress                 *Progress
+	*serialExecutor
+	in          *state.QueueWithRetry
+	rws         *state.ResultsQueue
+	workers     []*exec3.Worker
+	stopWorkers func()
+	workersDone context.Context // canceled if workers exited: no more results will come
+
+	conflicts    *conflictTracker
+	verifyEvery  uint64
+	verifyWorker *exec3.Worker // serial re-execution of sampled blocks, see ethconfig.Sync.ParallelExecVerifyEvery
 }
 
-func (pe *parallelExecutor) applyLoop(ctx context.Context, maxTxNum uint64, blockComplete *atomic.Bool, errCh chan error) {
-	defer pe.applyLoopWg.Done()
-	defer func() {
-		if rec := recover(); rec != nil {
-			pe.logger.Warn("[dbg] apply loop panic", "rec", rec)
-		}
-		pe.logger.Warn("[dbg] apply loop exit")
+func newParallelExecutor(ctx context.Context, se *serialExecutor, workerCount int, logger log.Logger) *parallelExecutor {
+	pe := &parallelExecutor{
+		serialExecutor: se,
+		in:             state.NewQueueWithRetry(100_000),
+		conflicts:      se.cfg.parallelConflicts,
+		verifyEvery:    se.cfg.syncCfg.ParallelExecVerifyEvery,
+	}
+	var waitWorkers func()
+	pe.workers, _, pe.rws, pe.stopWorkers, waitWorkers = exec3.NewWorkersPool(
+		pe.RWMutex.RLocker(), nil, logger, nil, ctx, true, se.cfg.db, se.rs, pe.in,
+		se.cfg.blockReader, se.cfg.chainConfig, se.cfg.genesis, se.cfg.engine, workerCount, se.cfg.dirs, se.isMining)
+	workersDone, cancel := context.WithCancel(ctx)
+	pe.workersDone = workersDone
+	go func() {
+		defer cancel()
+		waitWorkers()
 	}()
-	//fmt.Println("applyLoop started")
-	//defer fmt.Println("applyLoop done")
 
-	applyLoopInner := func(ctx context.Context) error {
-		tx, err := pe.cfg.db.BeginRo(ctx)
-		if err != nil {
-			return err
-		}
-		defer tx.Rollback()
+	if pe.verifyEvery > 0 {
+		pe.verifyWorker = exec3.NewWorker(nil, logger, nil, ctx, false, se.cfg.db, nil, se.cfg.blockReader, se.cfg.chainConfig, se.cfg.genesis, nil, se.cfg.engine, se.cfg.dirs, se.isMining)
+		pe.verifyWorker.BufferWrites(se.rs)
+		pe.verifyWorker.ResetTx(se.applyTx)
+	}
+	return pe
+}
 
-		pe.applyWorker.ResetTx(tx)
+func (pe *parallelExecutor) execute(ctx context.Context, tasks []*state.TxTask, gp *core.GasPool) (cont bool, err error) {
+	if len(tasks) == 0 {
+		return true, nil
+	}
+	if gp != nil {
+		pe.applyWorker.SetGaspool(gp)
+	}
 
-		for pe.outputTxNum.Load() <= maxTxNum {
-			if err := pe.rws.Drain(ctx); err != nil {
-				return err
-			}
+	report := &blockConflicts{blockNum: tasks[0].BlockNum}
+	serial := pe.schedule(tasks, report)
+	for i, txTask := range tasks {
+		if !serial[i] {
+			pe.in.Add(ctx, txTask)
+		}
+	}
+	verify := pe.verifyEvery > 0 && report.blockNum%pe.verifyEvery == 0
 
-			processedTxNum, conflicts, triggers, _ /*processedBlockNum*/, stoppedAtBlockEnd, err :=
-				pe.processResultQueue(ctx, pe.outputTxNum.Load(), pe.rwsConsumed, true, false)
-			if err != nil {
-				return err
+	for i, txTask := range tasks {
+		if !serial[i] {
+			if err := pe.waitResult(ctx, txTask.TxNum); err != nil {
+				return false, err
 			}
-			//fmt.Println("QR", processedTxNum, conflicts, triggers, processedBlockNum, stoppedAtBlockEnd, err)
-			mxExecRepeats.AddInt(conflicts)
-			mxExecTriggers.AddInt(triggers)
-
-			if processedTxNum > 0 {
-				pe.outputTxNum.Store(processedTxNum)
-				blockComplete.Store(stoppedAtBlockEnd)
-				// TODO update logGas here
-			}
-
 		}
-		return nil
+		if cont, err := pe.apply(ctx, txTask, serial[i], report, verify); !cont || err != nil {
+			return cont, err
+		}
 	}
 
-	if err := applyLoopInner(ctx); err != nil {
-		if !errors.Is(err, context.Canceled) {
-			errCh <- err
+	pe.conflicts.blockDone(report, verify, pe.execStage.LogPrefix(), pe.logger)
+	return true, nil
+}
+
+// apply - executes the txn if it's serial or has conflicts, and applies its result to the shared state.
+// Holds the write lock: workers hold the read lock while executing, because they read the shared state.
+func (pe *parallelExecutor) apply(ctx context.Context, txTask *state.TxTask, serial bool, report *blockConflicts, verify bool) (cont bool, err error) {
+	pe.Lock()
+	defer pe.Unlock()
+
+	pe.doms.SetTxNum(txTask.TxNum)
+	switch {
+	case serial:
+		pe.applyWorker.RunTxTaskNoLock(txTask, pe.isMining, pe.skipPostEvaluation)
+	case txTask.Error != nil || !pe.rs.ReadsValid(txTask.ReadLists):
+		pe.conflicts.conflict(report, txTask, pe.rs.StaleReads(txTask.ReadLists))
+		// all the previous txns are applied: re-execution gives the same result as serial execution
+		pe.applyWorker.RunTxTaskNoLock(txTask.Reset(), pe.isMining, pe.skipPostEvaluation)
+	default:
+		txTask.CreateReceipt(pe.applyTx.(kv.TemporalTx))
+		if verify {
+			if err := pe.verify(txTask); err != nil {
+				return false, err
+			}
+			report.verified++
 		}
 	}
+	return pe.applyResult(ctx, txTask)
 }
 
-////TODO: owner of `resultCh` is main goroutine, but owner of `retryQueue` is applyLoop.
-// Now rwLoop closing both (because applyLoop we completely restart)
-// Maybe need split channels? Maybe don't exit from ApplyLoop? Maybe current way is also ok?
-
-func (pe *parallelExecutor) rwLoop(ctx context.Context, maxTxNum uint64, logger log.Logger) error {
-	//fmt.Println("rwLoop started", maxTxNum)
-	//defer fmt.Println("rwLoop done")
-
-	tx := pe.applyTx
-	if tx == nil {
-		tx, err := pe.cfg.db.BeginRw(ctx)
-		if err != nil {
-			return err
+// schedule - returns txns which must be executed serially: block initialisation and finalisation, history execution,
+// account abstraction txns, and txns which depend on a previous txn of the block - by sender, or by recipient which
+// is known to cause conflicts
+func (pe *parallelExecutor) schedule(tasks []*state.TxTask, report *blockConflicts) []bool {
+	serial := make([]bool, len(tasks))
+	seen := make(map[common.Address]struct{}, len(tasks))
+	for i, txTask := range tasks {
+		if txTask.TxIndex < 0 || txTask.Final || txTask.HistoryExecution || txTask.Tx.Type() == types.AccountAbstractionTxType {
+			serial[i] = true
+			continue
+		}
+		report.txns++
+		keys := []common.Address{*txTask.Sender()}
+		if to := txTask.Tx.GetTo(); to != nil && pe.conflicts.hinted(*to) {
+			keys = append(keys, *to)
+		}
+		for _, key := range keys {
+			if _, ok := seen[key]; ok && !serial[i] {
+				serial[i] = true
+				report.serial++
+			}
+			seen[key] = struct{}{}
 		}
-		defer tx.Rollback()
 	}
+	return serial
+}
 
-	defer pe.applyLoopWg.Wait()
-	applyCtx, cancelApplyCtx := context.WithCancel(ctx)
-	defer cancelApplyCtx()
-	pe.applyLoopWg.Add(1)
-
-	blockComplete := atomic.Bool{}
-	blockComplete.Store(true)
-
-	go pe.applyLoop(applyCtx, maxTxNum, &blockComplete, pe.rwLoopErrCh)
+// waitResult - waits until the txn is executed by a worker
+func (pe *parallelExecutor) waitResult(ctx context.Context, txNum uint64) error {
+	for {
+		rwsIt := pe.rws.Iter()
+		found := rwsIt.HasNext(txNum)
+		if found {
+			rwsIt.PopNext()
+		}
+		rwsIt.Close()
+		if found {
+			return nil
+		}
 
-	for pe.outputTxNum.Load() <= maxTxNum {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-
-		case <-pe.logEvery.C:
-			stepsInDB := rawdbhelpers.IdxStepsCountV3(tx)
-			pe.progress.Log("", pe.rs, pe.in, pe.rws, pe.rs.DoneCount(), 0 /* TODO logGas*/, pe.lastBlockNum.Load(), pe.outputBlockNum.GetValueUint64(), pe.outputTxNum.Load(), mxExecRepeats.GetValueUint64(), stepsInDB, pe.shouldGenerateChangesets || pe.cfg.syncCfg.KeepExecutionProofs, pe.inMemExec)
-			if pe.agg.HasBackgroundFilesBuild() {
-				logger.Info(fmt.Sprintf("[%s] Background files build", pe.execStage.LogPrefix()), "progress", pe.agg.BackgroundProgress())
-			}
-		case <-pe.pruneEvery.C:
-			if pe.rs.SizeEstimate() < pe.cfg.batchSize.Bytes() {
-				if pe.doms.BlockNum() != pe.outputBlockNum.GetValueUint64() {
-					panic(fmt.Errorf("%d != %d", pe.doms.BlockNum(), pe.outputBlockNum.GetValueUint64()))
-				}
-				_, err := pe.doms.ComputeCommitment(ctx, true, pe.outputBlockNum.GetValueUint64(), pe.outputTxNum.Load(), pe.execStage.LogPrefix())
-				if err != nil {
-					return err
-				}
-				if _, err := tx.(kv.TemporalRwTx).PruneSmallBatches(ctx, 10*time.Hour); err != nil {
-					return err
-				}
-				if !pe.inMemExec {
-					if err = pe.doms.Flush(ctx, tx); err != nil {
-						return err
-					}
-				}
-				break
-			}
-			if pe.inMemExec {
-				break
-			}
-
-			cancelApplyCtx()
-			pe.applyLoopWg.Wait()
-
-			var t0, t1, t2, t3, t4 time.Duration
-			commitStart := time.Now()
-			logger.Info("Committing (parallel)...", "blockComplete.Load()", blockComplete.Load())
-			if err := func() error {
-				//Drain results (and process) channel because read sets do not carry over
-				for !blockComplete.Load() {
-					pe.rws.DrainNonBlocking(ctx)
-					pe.applyWorker.ResetTx(tx)
-
-					processedTxNum, conflicts, triggers, processedBlockNum, stoppedAtBlockEnd, err :=
-						pe.processResultQueue(ctx, pe.outputTxNum.Load(), nil, false, true)
-					if err != nil {
-						return err
-					}
-
-					mxExecRepeats.AddInt(conflicts)
-					mxExecTriggers.AddInt(triggers)
-					if processedBlockNum > 0 {
-						pe.outputBlockNum.SetUint64(processedBlockNum)
-					}
-					if processedTxNum > 0 {
-						pe.outputTxNum.Store(processedTxNum)
-						blockComplete.Store(stoppedAtBlockEnd)
-					}
-				}
-				t0 = time.Since(commitStart)
-				pe.Lock() // This is to prevent workers from starting work on any new txTask
-				defer pe.Unlock()
-
-				select {
-				case pe.rwsConsumed <- struct{}{}:
-				default:
-				}
-
-				// Drain results channel because read sets do not carry over
-				pe.rws.DropResults(ctx, func(txTask *state.TxTask) {
-					pe.rs.ReTry(txTask, pe.in)
-				})
-
-				//lastTxNumInDb, _ := txNumsReader.Max(tx, outputBlockNum.Get())
-				//if lastTxNumInDb != outputTxNum.Load()-1 {
-				//	panic(fmt.Sprintf("assert: %d != %d", lastTxNumInDb, outputTxNum.Load()))
-				//}
-
-				t1 = time.Since(commitStart)
-				tt := time.Now()
-				t2 = time.Since(tt)
-				tt = time.Now()
-				_, err := pe.doms.ComputeCommitment(ctx, true, pe.doms.BlockNum(), pe.doms.TxNum(), "flush-commitment")
-				if err != nil {
-					return err
-				}
-
-				if err := pe.doms.Flush(ctx, tx); err != nil {
-					return err
-				}
-				pe.doms.ClearRam(true)
-				t3 = time.Since(tt)
-
-				if err := pe.execStage.Update(tx, pe.outputBlockNum.GetValueUint64()); err != nil {
-					return err
-				}
-				if _, err := rawdb.IncrementStateVersion(tx); err != nil {
-					return fmt.Errorf("writing plain state version: %w", err)
-				}
-
-				tx.CollectMetrics()
-				tt = time.Now()
-				if err := tx.Commit(); err != nil {
-					return err
-				}
-				t4 = time.Since(tt)
-				for i := 0; i < len(pe.execWorkers); i++ {
-					pe.execWorkers[i].ResetTx(nil)
-				}
-
-				return nil
-			}(); err != nil {
-				return err
-			}
-			var err error
-			if tx, err = pe.cfg.db.BeginRw(ctx); err != nil {
-				return err
-			}
-			defer tx.Rollback()
-
-			applyCtx, cancelApplyCtx = context.WithCancel(ctx) //nolint:fatcontext
-			defer cancelApplyCtx()
-			pe.applyLoopWg.Add(1)
-			go pe.applyLoop(applyCtx, maxTxNum, &blockComplete, pe.rwLoopErrCh)
-
-			logger.Info("Committed", "time", time.Since(commitStart), "drain", t0, "drain_and_lock", t1, "rs.flush", t2, "agg.flush", t3, "tx.commit", t4)
+		case <-pe.workersDone.Done():
+			return fmt.Errorf("exec3 workers stopped before txNum %d is executed", txNum)
+		default:
 		}
+		_ = pe.rws.Drain(pe.workersDone) // cancellation is checked above
 	}
-	if err := pe.doms.Flush(ctx, tx); err != nil {
-		return err
-	}
-	if err := pe.execStage.Update(tx, pe.outputBlockNum.GetValueUint64()); err != nil {
-		return err
+}
+
+// verify - re-executes the txn serially, and checks that the result is the same as of parallel execution
+func (pe *parallelExecutor) verify(txTask *state.TxTask) error {
+	check := *txTask
+	check.BalanceIncreaseSet, check.ReadLists, check.WriteLists = nil, nil, nil
+	check.Logs, check.TraceFroms, check.TraceTos = nil, nil, nil
+	check.BlockReceipts = slices.Clone(txTask.BlockReceipts)
+	pe.verifyWorker.RunTxTaskNoLock(&check, pe.isMining, pe.skipPostEvaluation)
+
+	var diff string
+	switch {
+	case check.Error != nil:
+		diff = fmt.Sprintf("serial execution failed: %v", check.Error)
+	case check.Failed != txTask.Failed:
+		diff = fmt.Sprintf("failed %t, serial %t", txTask.Failed, check.Failed)
+	case check.GasUsed != txTask.GasUsed:
+		diff = fmt.Sprintf("gas used %d, serial %d", txTask.GasUsed, check.GasUsed)
+	case len(check.Logs) != len(txTask.Logs):
+		diff = fmt.Sprintf("logs %d, serial %d", len(txTask.Logs), len(check.Logs))
+	case !maps.Equal(check.BalanceIncreaseSet, txTask.BalanceIncreaseSet):
+		diff = "balance increases"
+	default:
+		diff = writeListsDiff(txTask.WriteLists, check.WriteLists)
 	}
-	if err := tx.Commit(); err != nil {
-		return err
+	mxExecVerifiedTxns.Inc()
+	if diff == "" {
+		return nil
 	}
-	return nil
+	return fmt.Errorf("parallel execution of block %d txn %d (txNum %d) differs from serial: %s", txTask.BlockNum, txTask.TxIndex, txTask.TxNum, diff)
 }
 
-func (pe *parallelExecutor) processResultQueue(ctx context.Context, inputTxNum uint64, backPressure chan<- struct{}, canRetry, forceStopAtBlockEnd bool) (outputTxNum uint64, conflicts, triggers int, processedBlockNum uint64, stopedAtBlockEnd bool, err error) {
-	rwsIt := pe.rws.Iter()
-	defer rwsIt.Close()
-	//defer fmt.Println("PRQ", "Done")
-
-	var i int
-	outputTxNum = inputTxNum
-	for rwsIt.HasNext(outputTxNum) {
-		txTask := rwsIt.PopNext()
-		//fmt.Println("PRQ", txTask.BlockNum, txTask.TxIndex, txTask.TxNum)
-		if txTask.Error != nil || !pe.rs.ReadsValid(txTask.ReadLists) {
-			conflicts++
-			//fmt.Println(txTask.TxNum, txTask.Error)
-			if errors.Is(txTask.Error, vm.ErrIntraBlockStateFailed) ||
-				errors.Is(txTask.Error, core.ErrStateTransitionFailed) {
-				return outputTxNum, conflicts, triggers, processedBlockNum, false, fmt.Errorf("%w: %v", consensus.ErrInvalidBlock, txTask.Error)
-			}
-			if i > 0 && canRetry {
-				//send to re-exex
-				pe.rs.ReTry(txTask, pe.in)
-				continue
-			}
-
-			// resolve first conflict right here: it's faster and conflict-free
-			pe.applyWorker.RunTxTaskNoLock(txTask.Reset(), pe.isMining, false)
-			if txTask.Error != nil {
-				//fmt.Println("RETRY", txTask.TxNum, txTask.Error)
-				return outputTxNum, conflicts, triggers, processedBlockNum, false, fmt.Errorf("%w: %v", consensus.ErrInvalidBlock, txTask.Error)
-			}
-			if pe.cfg.syncCfg.ChaosMonkey {
-				chaosErr := chaos_monkey.ThrowRandomConsensusError(pe.execStage.CurrentSyncCycle.IsInitialCycle, txTask.TxIndex, pe.cfg.badBlockHalt, txTask.Error)
-				if chaosErr != nil {
-					log.Warn("Monkey in a consensus")
-					return outputTxNum, conflicts, triggers, processedBlockNum, false, chaosErr
-				}
-			}
-			// TODO: post-validation of gasUsed and blobGasUsed
-			i++
+// writeListsDiff - describes the first difference of the write lists, or returns empty string if they are same.
+// Later writes of same key override the previous ones.
+func writeListsDiff(a, b map[string]*state2.KvList) string {
+	latest := func(lists map[string]*state2.KvList, table string) map[string][]byte {
+		list, ok := lists[table]
+		if !ok {
+			return nil
 		}
-
-		if txTask.Final {
-			pe.rs.SetTxNum(txTask.TxNum, txTask.BlockNum)
-			err := pe.rs.ApplyState(ctx, txTask)
-			if err != nil {
-				return outputTxNum, conflicts, triggers, processedBlockNum, false, fmt.Errorf("ParallelExecutionState.Apply: %w", err)
-			}
-
-			if processedBlockNum > pe.lastBlockNum.Load() {
-				pe.outputBlockNum.SetUint64(processedBlockNum)
-				pe.lastBlockNum.Store(processedBlockNum)
-			}
-			//if !bytes.Equal(rh, txTask.BlockRoot[:]) {
-			//	log.Error("block hash mismatch", "rh", hex.EncodeToString(rh), "blockRoot", hex.EncodeToString(txTask.BlockRoot[:]), "bn", txTask.BlockNum, "txn", txTask.TxNum)
-			//	return outputTxNum, conflicts, triggers, processedBlockNum, false, fmt.Errorf("block hashk mismatch: %x != %x bn =%d, txn= %d", rh, txTask.BlockRoot[:], txTask.BlockNum, txTask.TxNum)
-			//}
+		m := make(map[string][]byte, list.Len())
+		for i, key := range list.Keys {
+			m[key] = list.Vals[i]
 		}
-		triggers += pe.rs.CommitTxNum(txTask.Sender(), txTask.TxNum, pe.in)
-		outputTxNum++
-		if backPressure != nil {
-			select {
-			case backPressure <- struct{}{}:
-			default:
+		return m
+	}
+	for _, domain := range []kv.Domain{kv.AccountsDomain, kv.CodeDomain, kv.StorageDomain} {
+		table := domain.String()
+		va, vb := latest(a, table), latest(b, table)
+		for key, v := range va {
+			if w, ok := vb[key]; !ok || !bytes.Equal(v, w) {
+				return fmt.Sprintf("%s %x: %x, serial %x", table, key, v, w)
 			}
 		}
-		if err := pe.rs.ApplyLogsAndTraces(txTask, pe.rs.Domains()); err != nil {
-			return outputTxNum, conflicts, triggers, processedBlockNum, false, fmt.Errorf("ParallelExecutionState.Apply: %w", err)
-		}
-		processedBlockNum = txTask.BlockNum
-		if !stopedAtBlockEnd {
-			stopedAtBlockEnd = txTask.Final
-		}
-		if forceStopAtBlockEnd && txTask.Final {
-			break
+		for key, w := range vb {
+			if _, ok := va[key]; !ok {
+				return fmt.Sprintf("%s %x: not written, serial %x", table, key, w)
+			}
 		}
 	}
-	return
+	return ""
 }
 
-func (pe *parallelExecutor) run(ctx context.Context, maxTxNum uint64, logger log.Logger) context.CancelFunc {
-	pe.slowDownLimit = time.NewTicker(time.Second)
-	pe.rwsConsumed = make(chan struct{}, 1)
-	pe.rwLoopErrCh = make(chan error)
-	pe.in = state.NewQueueWithRetry(100_000)
-
-	pe.execWorkers, _, pe.rws, pe.stopWorkers, pe.waitWorkers = exec3.NewWorkersPool(
-		pe.RWMutex.RLocker(), pe.accumulator, logger, nil, ctx, true, pe.cfg.db, pe.rs, pe.in,
-		pe.cfg.blockReader, pe.cfg.chainConfig, pe.cfg.genesis, pe.cfg.engine, pe.workerCount+1, pe.cfg.dirs, pe.isMining)
-
-	rwLoopCtx, rwLoopCtxCancel := context.WithCancel(ctx)
-	pe.rwLoopG, rwLoopCtx = errgroup.WithContext(rwLoopCtx)
-	pe.rwLoopG.Go(func() error {
-		defer pe.rws.Close()
-		defer pe.in.Close()
-		defer pe.applyLoopWg.Wait()
-		defer func() {
-			logger.Warn("[dbg] rwloop exit")
-		}()
-		return pe.rwLoop(rwLoopCtx, maxTxNum, logger)
-	})
-
-	return func() {
-		rwLoopCtxCancel()
-		pe.slowDownLimit.Stop()
-		pe.wait()
-		pe.stopWorkers()
-		close(pe.rwsConsumed)
-		pe.in.Close()
+func (pe *parallelExecutor) commit(ctx context.Context, txNum uint64, blockNum uint64, useExternalTx bool) (time.Duration, error) {
+	t, err := pe.serialExecutor.commit(ctx, txNum, blockNum, useExternalTx)
+	if err != nil {
+		return t, err
 	}
-}
-
-func (pe *parallelExecutor) status(ctx context.Context, commitThreshold uint64) error {
-	select {
-	case err := <-pe.rwLoopErrCh:
-		if err != nil {
-			return err
-		}
-	case <-ctx.Done():
-		return ctx.Err()
-	default:
+	// state and tx are re-created by commit
+	pe.Lock()
+	defer pe.Unlock()
+	for _, w := range pe.workers {
+		w.ResetTx(nil)
+		w.ResetState(pe.rs, nil)
 	}
-
-	for pe.rws.Len() > pe.rws.Limit() || pe.rs.SizeEstimate() >= commitThreshold {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case _, ok := <-pe.rwsConsumed:
-			if !ok {
-				return nil
-			}
-		case <-pe.slowDownLimit.C:
-			//logger.Warn("skip", "rws.Len()", rws.Len(), "rws.Limit()", rws.Limit(), "rws.ResultChLen()", rws.ResultChLen())
-			//if tt := rws.Dbg(); tt != nil {
-			//	log.Warn("fst", "n", tt.TxNum, "in.len()", in.Len(), "out", outputTxNum.Load(), "in.NewTasksLen", in.NewTasksLen())
-			//}
-			return nil
-		}
+	if pe.verifyWorker != nil {
+		pe.verifyWorker.ResetTx(pe.applyTx)
+		pe.verifyWorker.BufferWrites(pe.rs)
 	}
-
-	return nil
+	return t, nil
 }
 
 func (pe *parallelExecutor) wait() error {
-	pe.applyLoopWg.Wait()
-
-	if pe.rwLoopG != nil {
-		err := pe.rwLoopG.Wait()
-
-		if err != nil {
-			return err
-		}
-
-		pe.waitWorkers()
-	}
-
+	pe.stopWorkers()
+	pe.in.Close()
 	return nil
 }
-
-func (pe *parallelExecutor) execute(ctx context.Context, tasks []*state.TxTask, gp *core.GasPool) (bool, error) {
-	for _, txTask := range tasks {
-		if txTask.Sender() != nil {
-			if ok := pe.rs.RegisterSender(txTask); ok {
-				pe.rs.AddWork(ctx, txTask, pe.in)
-			}
-		} else {
-			pe.rs.AddWork(ctx, txTask, pe.in)
-		}
-	}
-
-	return false, nil
-}
@@ -0,0 +1,149 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package stagedsync
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/length"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/metrics"
+	state2 "github.com/erigontech/erigon-lib/state"
+	"github.com/erigontech/erigon/core/state"
+)
+
+var (
+	mxExecConflictBlocks = metrics.NewCounter(`exec_conflict_blocks`)
+	mxExecVerifiedTxns   = metrics.NewCounter(`exec_verified_txns`)
+)
+
+const (
+	conflictHintsLimit = 4096 // accounts remembered by conflictTracker
+	conflictHintAfter  = 2    // conflicts after which txns sent to the account are scheduled serially
+	conflictReportKeys = 5    // most conflicting keys in the block report
+)
+
+// conflictKey - state key, whose value read by a txn executed in parallel was changed by a previous txn of the block
+type conflictKey struct {
+	table string // domain name or state2.CodeSizeTableFake
+	key   string
+}
+
+func (k conflictKey) address() common.Address {
+	return common.BytesToAddress([]byte(k.key[:min(len(k.key), length.Addr)]))
+}
+
+func (k conflictKey) String() string {
+	switch k.table {
+	case kv.AccountsDomain.String():
+		return fmt.Sprintf("%x", k.key)
+	case kv.StorageDomain.String():
+		return fmt.Sprintf("%x/%x", k.key[:length.Addr], k.key[length.Addr:])
+	default:
+		return fmt.Sprintf("%x/code", k.key)
+	}
+}
+
+// blockConflicts - report of parallel execution of one block
+type blockConflicts struct {
+	blockNum   uint64
+	txns       int // txns which can be executed in parallel
+	serial     int // txns scheduled serially because of dependencies
+	reExecuted int // txns executed in parallel, then again - because of conflict
+	verified   int // txns executed in parallel, then serially - to verify the result
+	keys       map[conflictKey]int
+}
+
+// conflictTracker - reports conflicts of parallel execution, and remembers accounts which cause them:
+// txns sent to such accounts are scheduled serially. Shared by all ExecV3 runs.
+type conflictTracker struct {
+	hints  *lru.Cache[common.Address, int]
+	blocks int            // blocks executed in parallel
+	totals blockConflicts // sums of reports of all blocks, without keys
+}
+
+func newConflictTracker() *conflictTracker {
+	hints, err := lru.New[common.Address, int](conflictHintsLimit)
+	if err != nil {
+		panic(err)
+	}
+	return &conflictTracker{hints: hints}
+}
+
+func (ct *conflictTracker) hinted(addr common.Address) bool {
+	n, ok := ct.hints.Peek(addr)
+	return ok && n >= conflictHintAfter
+}
+
+// conflict - records re-execution of the txn because of the stale reads
+func (ct *conflictTracker) conflict(report *blockConflicts, txTask *state.TxTask, stale []state2.StaleRead) {
+	report.reExecuted++
+	mxExecRepeats.Inc()
+	if report.keys == nil {
+		report.keys = map[conflictKey]int{}
+	}
+	accounts := map[common.Address]struct{}{}
+	for _, read := range stale {
+		key := conflictKey{table: read.Table, key: string(read.Key)}
+		report.keys[key]++
+		accounts[key.address()] = struct{}{}
+	}
+	for addr := range accounts {
+		n, _ := ct.hints.Peek(addr)
+		ct.hints.Add(addr, n+1)
+	}
+}
+
+// blockDone - logs the block report if the block had conflicts
+func (ct *conflictTracker) blockDone(report *blockConflicts, verified bool, logPrefix string, logger log.Logger) {
+	mxExecTriggers.AddInt(report.serial)
+	ct.blocks++
+	ct.totals.txns += report.txns
+	ct.totals.serial += report.serial
+	ct.totals.reExecuted += report.reExecuted
+	ct.totals.verified += report.verified
+	if report.reExecuted == 0 {
+		if verified {
+			logger.Debug(fmt.Sprintf("[%s] parallel execution verified", logPrefix), "block", report.blockNum, "txns", report.txns)
+		}
+		return
+	}
+	mxExecConflictBlocks.Inc()
+
+	keys := make([]conflictKey, 0, len(report.keys))
+	for key := range report.keys {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b conflictKey) int {
+		if c := cmp.Compare(report.keys[b], report.keys[a]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.key, b.key)
+	})
+	top := make([]string, 0, conflictReportKeys)
+	for _, key := range keys[:min(len(keys), conflictReportKeys)] {
+		top = append(top, fmt.Sprintf("%s=%d", key, report.keys[key]))
+	}
+	logger.Debug(fmt.Sprintf("[%s] parallel execution conflicts", logPrefix), "block", report.blockNum, "txns", report.txns,
+		"serial", report.serial, "reExecuted", report.reExecuted, "verified", verified, "keys", top)
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package stagedsync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/log/v3"
+	state2 "github.com/erigontech/erigon-lib/state"
+	"github.com/erigontech/erigon/core/state"
+)
+
+func TestConflictTracker(t *testing.T) {
+	ct := newConflictTracker()
+	token, other := common.Address{1}, common.Address{2}
+	slot := append(token.Bytes(), common.Hash{3}.Bytes()...)
+	stale := []state2.StaleRead{
+		{Table: kv.StorageDomain.String(), Key: slot},
+		{Table: kv.AccountsDomain.String(), Key: token.Bytes()},
+	}
+
+	report := &blockConflicts{blockNum: 1, txns: 3}
+	ct.conflict(report, &state.TxTask{}, stale)
+	require.False(t, ct.hinted(token), "one conflict per txn is counted once")
+	ct.conflict(report, &state.TxTask{}, stale[:1])
+	require.True(t, ct.hinted(token))
+	require.False(t, ct.hinted(other))
+
+	require.Equal(t, 2, report.reExecuted)
+	require.Equal(t, 2, report.keys[conflictKey{table: kv.StorageDomain.String(), key: string(slot)}])
+	require.Equal(t, "0100000000000000000000000000000000000000/0300000000000000000000000000000000000000000000000000000000000000",
+		conflictKey{table: kv.StorageDomain.String(), key: string(slot)}.String())
+	ct.blockDone(report, true, "test", log.New())
+	require.Equal(t, 1, ct.blocks)
+	require.Equal(t, 2, ct.totals.reExecuted)
+}
+
+func TestWriteListsDiff(t *testing.T) {
+	list := func(kvs ...string) map[string]*state2.KvList {
+		l := &state2.KvList{}
+		for i := 0; i < len(kvs); i += 2 {
+			l.Push(kvs[i], []byte(kvs[i+1]))
+		}
+		return map[string]*state2.KvList{kv.AccountsDomain.String(): l}
+	}
+	require.Empty(t, writeListsDiff(list("a", "1", "b", "2"), list("b", "2", "a", "1")))
+	require.Empty(t, writeListsDiff(list("a", "1", "a", "2"), list("a", "2")), "only latest write matters")
+	require.NotEmpty(t, writeListsDiff(list("a", "1"), list("a", "2")))
+	require.NotEmpty(t, writeListsDiff(list("a", "1"), list("a", "1", "b", "2")))
+	require.NotEmpty(t, writeListsDiff(list("a", "1", "b", "2"), list("a", "1")))
+}
@@ -0,0 +1,191 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package stagedsync_test
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-db/rawdb"
+	"github.com/erigontech/erigon-lib/chain"
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/u256"
+	"github.com/erigontech/erigon-lib/crypto"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/prune"
+	"github.com/erigontech/erigon-lib/log/v3"
+	state2 "github.com/erigontech/erigon-lib/state"
+	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon-lib/wrap"
+	"github.com/erigontech/erigon/core"
+	"github.com/erigontech/erigon/core/vm"
+	"github.com/erigontech/erigon/execution/stagedsync"
+	"github.com/erigontech/erigon/execution/stagedsync/stages"
+	"github.com/erigontech/erigon/execution/stages/mock"
+)
+
+// TestExecV3ParallelMatchesSerial - executes a chain of conflicting txns (same sender, same recipient, coinbase,
+// self-destruct) by parallel and by serial execution, and compares the results
+func TestExecV3ParallelMatchesSerial(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	keys := make([]*ecdsa.PrivateKey, 4)
+	senders := make([]common.Address, len(keys))
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		senders[i] = crypto.PubkeyToAddress(keys[i].PublicKey)
+	}
+	var (
+		counter    = common.HexToAddress("0x000000000000000000000000000000000000cccc")
+		destructed = common.HexToAddress("0x000000000000000000000000000000000000dddd")
+		recipient  = common.HexToAddress("0x000000000000000000000000000000000000eeee")
+		coinbase   = common.HexToAddress("0x000000000000000000000000000000000000cbcb")
+	)
+	alloc := types.GenesisAlloc{
+		// increments slot 0 and logs its new value
+		counter: {
+			Code: []byte{
+				byte(vm.PUSH1), 0, byte(vm.SLOAD), byte(vm.PUSH1), 1, byte(vm.ADD),
+				byte(vm.DUP1), byte(vm.PUSH1), 0, byte(vm.SSTORE),
+				byte(vm.PUSH1), 0, byte(vm.MSTORE), byte(vm.PUSH1), 32, byte(vm.PUSH1), 0, byte(vm.LOG0),
+			},
+			Balance: big.NewInt(0),
+		},
+		// self-destructs to recipient
+		destructed: {
+			Code:    append(append([]byte{byte(vm.PUSH20)}, recipient.Bytes()...), byte(vm.SELFDESTRUCT)),
+			Nonce:   1,
+			Balance: big.NewInt(1000),
+		},
+	}
+	for _, sender := range senders {
+		alloc[sender] = types.GenesisAccount{Balance: big.NewInt(common.Ether)}
+	}
+	gspec := &types.Genesis{Config: chain.TestChainConfig, Alloc: alloc}
+
+	rCacheDomain := state2.Schema.RCacheDomain
+	t.Cleanup(func() { state2.Schema.RCacheDomain = rCacheDomain })
+	state2.EnableHistoricalRCache() // to read receipts of each block, as with --persist.receipts
+
+	serial := mock.MockWithGenesis(t, gspec, keys[0], false)
+
+	signer := types.LatestSignerForChainID(chain.TestChainConfig.ChainID)
+	chainPack, err := core.GenerateChain(serial.ChainConfig, serial.Genesis, serial.Engine, serial.DB, 5, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(coinbase)
+		send := func(from int, to common.Address, value uint64) {
+			txn, err := types.SignTx(types.NewTransaction(b.TxNonce(senders[from]), to, uint256.NewInt(value), 100_000, u256.Num1, nil), *signer, keys[from])
+			require.NoError(t, err)
+			b.AddTx(txn)
+		}
+		send(0, recipient, 1)
+		send(0, coinbase, 1)
+		send(0, counter, 0)
+		send(1, counter, 0)
+		send(2, counter, 0)
+		send(3, destructed, 1000)
+		send(1, destructed, 0) // self-destruct in the first block, plain transfer in the next ones
+		send(2, recipient, 1)
+		send(3, counter, 0)
+		send(1, coinbase, 1)
+	})
+	require.NoError(t, err)
+	require.NoError(t, serial.InsertChain(chainPack))
+	serialTx, err := serial.DB.BeginTemporalRo(serial.Ctx)
+	require.NoError(t, err)
+	defer serialTx.Rollback()
+
+	t.Run("parallel", func(t *testing.T) {
+		blocks, txns, _, _, verified := testExecV3Parallel(t, gspec, keys[0], chainPack, serial, serialTx, counter, 0)
+		require.Equal(t, len(chainPack.Blocks), blocks)
+		require.NotZero(t, txns)
+		require.Zero(t, verified)
+	})
+	t.Run("verify every block", func(t *testing.T) {
+		blocks, txns, serialTxns, reExecuted, verified := testExecV3Parallel(t, gspec, keys[0], chainPack, serial, serialTx, counter, 1)
+		require.Equal(t, len(chainPack.Blocks), blocks)
+		require.NotZero(t, verified)
+		// all txns executed in parallel without conflicts are re-executed serially
+		require.Equal(t, txns-serialTxns-reExecuted, verified)
+	})
+}
+
+// testExecV3Parallel - executes the chain by parallel execution, compares the results with serial execution,
+// and returns sums of parallel execution reports
+func testExecV3Parallel(t *testing.T, gspec *types.Genesis, key *ecdsa.PrivateKey, chainPack *core.ChainPack, serial *mock.MockSentry,
+	serialTx kv.TemporalTx, counter common.Address, verifyEvery uint64) (blocks, txns, serialTxns, reExecuted, verified int) {
+	parallel := mock.MockWithGenesis(t, gspec, key, false)
+
+	// like in the initial cycle: by several workers and without notifications to txpool, see ExecV3
+	syncCfg := parallel.Cfg().Sync
+	syncCfg.ParallelExec = true
+	syncCfg.ParallelExecVerifyEvery = verifyEvery
+	syncCfg.ExecWorkerCount = 4
+	execCfg := stagedsync.StageExecuteBlocksCfg(parallel.DB, prune.MockMode, parallel.Cfg().BatchSize, parallel.ChainConfig, parallel.Engine,
+		&vm.Config{}, nil, false, true /* badBlockHalt */, parallel.Dirs, parallel.BlockReader, nil, gspec, syncCfg, nil)
+	parallel.Sync.MockExecFunc(stages.Execution, func(badBlockUnwind bool, s *stagedsync.StageState, u stagedsync.Unwinder, txc wrap.TxContainer, logger log.Logger) error {
+		s.CurrentSyncCycle.IsInitialCycle = true
+		return stagedsync.SpawnExecuteBlocksStage(s, u, txc, 0, parallel.Ctx, execCfg, logger)
+	})
+
+	// state root of every block is checked against its header by both executions
+	require.NoError(t, parallel.InsertChain(chainPack))
+
+	parallelTx, err := parallel.DB.BeginTemporalRo(parallel.Ctx)
+	require.NoError(t, err)
+	defer parallelTx.Rollback()
+
+	stateRoot := func(tx kv.TemporalTx) []byte {
+		doms, err := state2.NewSharedDomains(tx, log.New())
+		require.NoError(t, err)
+		defer doms.Close()
+		root, err := doms.ComputeCommitment(serial.Ctx, false, doms.BlockNum(), doms.TxNum(), "")
+		require.NoError(t, err)
+		return root
+	}
+	require.Equal(t, chainPack.TopBlock.Root().Bytes(), stateRoot(serialTx))
+	require.Equal(t, chainPack.TopBlock.Root().Bytes(), stateRoot(parallelTx))
+
+	var logs int
+	for _, block := range chainPack.Blocks {
+		serialReceipts, err := rawdb.ReadReceiptsCacheV2(serialTx, block, serial.BlockReader.TxnumReader(serial.Ctx))
+		require.NoError(t, err)
+		parallelReceipts, err := rawdb.ReadReceiptsCacheV2(parallelTx, block, parallel.BlockReader.TxnumReader(parallel.Ctx))
+		require.NoError(t, err)
+		require.Len(t, parallelReceipts, len(block.Transactions()))
+		require.Equal(t, serialReceipts, parallelReceipts, "block %d", block.NumberU64())
+		for _, receipt := range parallelReceipts {
+			require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+			logs += len(receipt.Logs)
+		}
+	}
+	require.Equal(t, 4*len(chainPack.Blocks), logs)
+
+	// every counter call saw all the previous ones
+	serialCounter, _, err := serial.NewStateReader(serialTx).ReadAccountStorage(counter, common.Hash{})
+	require.NoError(t, err)
+	parallelCounter, _, err := parallel.NewStateReader(parallelTx).ReadAccountStorage(counter, common.Hash{})
+	require.NoError(t, err)
+	require.Equal(t, serialCounter, parallelCounter)
+	require.Equal(t, *uint256.NewInt(uint64(4 * len(chainPack.Blocks))), parallelCounter)
+	return stagedsync.ParallelExecTotals(execCfg)
+}
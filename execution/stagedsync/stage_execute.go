@@ -88,6 +88,7 @@ type ExecuteBlockCfg struct {
 	blockProduction bool
 
 	applyWorker, applyWorkerMining *exec3.Worker
+	parallelConflicts              *conflictTracker
 }
 
 func StageExecuteBlocksCfg(
@@ -131,6 +132,7 @@ func StageExecuteBlocksCfg(
 		silkworm:          silkworm,
 		applyWorker:       exec3.NewWorker(nil, log.Root(), vmConfig.Tracer, context.Background(), false, db, nil, blockReader, chainConfig, genesis, nil, engine, dirs, false),
 		applyWorkerMining: exec3.NewWorker(nil, log.Root(), vmConfig.Tracer, context.Background(), false, db, nil, blockReader, chainConfig, genesis, nil, engine, dirs, true),
+		parallelConflicts: newConflictTracker(),
 	}
 }
 
@@ -155,7 +157,7 @@ func ExecBlockV3(s *StageState, u Unwinder, txc wrap.TxContainer, toBlock uint64
 		return nil
 	}
 
-	parallel := txc.Tx == nil
+	parallel := cfg.syncCfg.ParallelExec && workersCount > 1 && !isMining
 	if err := ExecV3(ctx, s, u, workersCount, cfg, txc, parallel, to, logger, cfg.vmConfig.Tracer, initialCycle, isMining); err != nil {
 		return err
 	}
@@ -383,6 +385,11 @@ func UnwindExecutionStage(u *UnwindState, s *StageState, txc wrap.TxContainer, c
 			return err
 		}
 	}
+	// with an external tx the unwind isn't committed yet, like the blocks reported
+	// by OnBlockEnd, see tracing.BlockUnwindHook
+	if hooks := cfg.vmConfig.Tracer; hooks != nil && hooks.OnBlockUnwind != nil {
+		hooks.OnBlockUnwind(s.BlockNumber, u.UnwindPoint)
+	}
 	return nil
 }
 
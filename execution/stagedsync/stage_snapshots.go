@@ -615,7 +615,7 @@ type snapshotUploader struct {
 	files           map[string]*uploadState
 	uploadFs        string
 	rclone          *downloader.RCloneClient
-	uploadSession   *downloader.RCloneSession
+	uploadSession   downloader.UploadSession
 	uploadScheduled atomic.Bool
 	uploading       atomic.Bool
 	manifestMutex   sync.Mutex
@@ -1009,14 +1009,13 @@ func isLocalFs(ctx context.Context, rclient *downloader.RCloneClient, fs string)
 	return true
 }
 
-func (u *snapshotUploader) start(ctx context.Context, logger log.Logger) {
+func (u *snapshotUploader) newRCloneSession(ctx context.Context, logger log.Logger) (*downloader.RCloneSession, error) {
 	var err error
 
 	u.rclone, err = downloader.NewRCloneClient(logger)
 
 	if err != nil {
-		logger.Warn("[uploader] Uploading disabled: rclone start failed", "err", err)
-		return
+		return nil, fmt.Errorf("rclone start failed: %w", err)
 	}
 
 	uploadFs := u.uploadFs
@@ -1027,24 +1026,56 @@ func (u *snapshotUploader) start(ctx context.Context, logger log.Logger) {
 		uploadFs, err = filepath.Abs(uploadFs)
 
 		if err != nil {
-			logger.Warn("[uploader] Uploading disabled: invalid upload fs", "err", err, "fs", u.uploadFs)
-			return
+			return nil, fmt.Errorf("invalid upload fs: %s: %w", u.uploadFs, err)
 		}
 
 		if err := os.MkdirAll(uploadFs, 0755); err != nil {
-			logger.Warn("[uploader] Uploading disabled: can't create upload fs", "err", err, "fs", u.uploadFs)
-			return
+			return nil, fmt.Errorf("can't create upload fs: %s: %w", u.uploadFs, err)
 		}
 	}
 
-	u.uploadSession, err = u.rclone.NewSession(ctx, u.cfg.dirs.Snap, uploadFs, nil)
+	session, err := u.rclone.NewSession(ctx, u.cfg.dirs.Snap, uploadFs, nil)
 
 	if err != nil {
-		logger.Warn("[uploader] Uploading disabled: rclone session failed", "err", err)
-		return
+		return nil, fmt.Errorf("rclone session failed: %w", err)
+	}
+
+	return session, nil
+}
+
+// newS3UploadSession - uploads to S3-compatible storage without rclone, see downloader.ParseS3Location
+func newS3UploadSession(location string, snapDir string, logger log.Logger) (*downloader.S3Session, error) {
+	cfg, err := downloader.ParseS3Location(location)
+	if err != nil {
+		return nil, err
+	}
+	client, err := downloader.NewS3Client(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.NewSession(snapDir), nil
+}
+
+func (u *snapshotUploader) start(ctx context.Context, logger log.Logger) {
+	if downloader.IsS3Location(u.uploadFs) {
+		session, err := newS3UploadSession(u.uploadFs, u.cfg.dirs.Snap, logger)
+		if err != nil {
+			logger.Warn("[uploader] Uploading disabled: s3 session failed", "err", err)
+			return
+		}
+		u.uploadSession = session
+	} else {
+		session, err := u.newRCloneSession(ctx, logger)
+		if err != nil {
+			logger.Warn("[uploader] Uploading disabled", "err", err)
+			return
+		}
+		u.uploadSession = session
 	}
 
 	go func() {
+		var err error
 
 		remoteFiles, _ := u.downloadManifest(ctx)
 		refreshFromRemote := false
@@ -1305,7 +1336,7 @@ func (u *snapshotUploader) upload(ctx context.Context, logger log.Logger) {
 			err := func() error {
 				state.Lock()
 				defer state.Unlock()
-				if !state.remote && state.torrent != nil && len(state.uploads) == 0 && u.rclone != nil {
+				if !state.remote && state.torrent != nil && len(state.uploads) == 0 && u.uploadSession != nil {
 					state.uploads = []string{state.file, state.file + ".torrent"}
 					uploadList = append(uploadList, state)
 				}
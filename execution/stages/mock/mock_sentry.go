@@ -412,6 +412,7 @@ func MockWithEverything(tb testing.TB, gspec *types.Genesis, key *ecdsa.PrivateK
 
 	statusDataProvider := sentry.NewStatusDataProvider(
 		db,
+		mock.BlockReader,
 		mock.ChainConfig,
 		mock.Genesis,
 		mock.ChainConfig.ChainID.Uint64(),
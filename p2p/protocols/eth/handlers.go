@@ -27,6 +27,7 @@ import (
 	"github.com/erigontech/erigon-lib/chain"
 	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/empty"
+	"github.com/erigontech/erigon-lib/direct"
 	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon-lib/rlp"
@@ -178,7 +179,20 @@ type cachedReceipts struct {
 	PendingIndex    int // index of the first not-found receipt in the query
 }
 
-func AnswerGetReceiptsQueryCacheOnly(ctx context.Context, receiptsGetter ReceiptsGetter, query GetReceiptsPacket) (*cachedReceipts, bool, error) {
+// encodeReceipts encodes the receipts of a block for the given protocol
+// version, eth/69 drops the bloom.
+func encodeReceipts(receipts types.Receipts, protocol uint) ([]byte, error) {
+	if protocol < direct.ETH69 {
+		return rlp.EncodeToBytes(receipts)
+	}
+	receipts69 := make([]*types.ReceiptForNetwork69, len(receipts))
+	for i, receipt := range receipts {
+		receipts69[i] = (*types.ReceiptForNetwork69)(receipt)
+	}
+	return rlp.EncodeToBytes(receipts69)
+}
+
+func AnswerGetReceiptsQueryCacheOnly(ctx context.Context, receiptsGetter ReceiptsGetter, query GetReceiptsPacket, protocol uint) (*cachedReceipts, bool, error) {
 	var (
 		bytes        int
 		receiptsList []rlp.RawValue
@@ -193,7 +207,7 @@ func AnswerGetReceiptsQueryCacheOnly(ctx context.Context, receiptsGetter Receipt
 			break
 		}
 		if receipts, ok := receiptsGetter.GetCachedReceipts(ctx, hash); ok {
-			if encoded, err := rlp.EncodeToBytes(receipts); err != nil {
+			if encoded, err := encodeReceipts(receipts, protocol); err != nil {
 				return nil, needMore, fmt.Errorf("failed to encode receipt: %w", err)
 			} else {
 				receiptsList = append(receiptsList, encoded)
@@ -214,7 +228,7 @@ func AnswerGetReceiptsQueryCacheOnly(ctx context.Context, receiptsGetter Receipt
 	}, needMore, nil
 }
 
-func AnswerGetReceiptsQuery(ctx context.Context, cfg *chain.Config, receiptsGetter ReceiptsGetter, br services.HeaderAndBodyReader, db kv.TemporalTx, query GetReceiptsPacket, cachedReceipts *cachedReceipts) ([]rlp.RawValue, error) { //nolint:unparam
+func AnswerGetReceiptsQuery(ctx context.Context, cfg *chain.Config, receiptsGetter ReceiptsGetter, br services.HeaderAndBodyReader, db kv.TemporalTx, query GetReceiptsPacket, protocol uint, cachedReceipts *cachedReceipts) ([]rlp.RawValue, error) { //nolint:unparam
 	// Gather state data until the fetch or network limits is reached
 	var (
 		bytes        int
@@ -268,7 +282,7 @@ func AnswerGetReceiptsQuery(ctx context.Context, cfg *chain.Config, receiptsGett
 		//}
 
 		// If known, encode and queue for response packet
-		if encoded, err := rlp.EncodeToBytes(results); err != nil {
+		if encoded, err := encodeReceipts(results, protocol); err != nil {
 			return nil, fmt.Errorf("failed to encode receipt: %w", err)
 		} else {
 			receipts = append(receipts, encoded)
@@ -20,6 +20,7 @@
 package eth
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -35,6 +36,7 @@ import (
 var ProtocolToString = map[uint]string{
 	direct.ETH67: "eth67",
 	direct.ETH68: "eth68",
+	direct.ETH69: "eth69",
 }
 
 // ProtocolName is the official short name of the `eth` protocol used during
@@ -45,6 +47,10 @@ const ProtocolName = "eth"
 const maxMessageSize = 10 * 1024 * 1024
 const ProtocolMaxMsgSize = maxMessageSize
 
+// ProtocolLengths are the number of implemented messages corresponding to
+// different protocol versions.
+var ProtocolLengths = map[uint]uint64{direct.ETH67: 17, direct.ETH68: 17, direct.ETH69: 18}
+
 const (
 	// Protocol messages in eth/64
 	StatusMsg          = 0x00
@@ -62,6 +68,9 @@ const (
 	NewPooledTransactionHashesMsg = 0x08
 	GetPooledTransactionsMsg      = 0x09
 	PooledTransactionsMsg         = 0x0a
+
+	// Protocol messages introduced in eth/69
+	BlockRangeUpdateMsg = 0x11
 )
 
 var ToProto = map[uint]map[uint64]proto_sentry.MessageId{
@@ -93,6 +102,19 @@ var ToProto = map[uint]map[uint64]proto_sentry.MessageId{
 		GetPooledTransactionsMsg:      proto_sentry.MessageId_GET_POOLED_TRANSACTIONS_66,
 		PooledTransactionsMsg:         proto_sentry.MessageId_POOLED_TRANSACTIONS_66,
 	},
+	direct.ETH69: {
+		GetBlockHeadersMsg:            proto_sentry.MessageId_GET_BLOCK_HEADERS_66,
+		BlockHeadersMsg:               proto_sentry.MessageId_BLOCK_HEADERS_66,
+		GetBlockBodiesMsg:             proto_sentry.MessageId_GET_BLOCK_BODIES_66,
+		BlockBodiesMsg:                proto_sentry.MessageId_BLOCK_BODIES_66,
+		GetReceiptsMsg:                proto_sentry.MessageId_GET_RECEIPTS_69, // Modified in eth/69
+		ReceiptsMsg:                   proto_sentry.MessageId_RECEIPTS_69,     // Modified in eth/69
+		TransactionsMsg:               proto_sentry.MessageId_TRANSACTIONS_66,
+		NewPooledTransactionHashesMsg: proto_sentry.MessageId_NEW_POOLED_TRANSACTION_HASHES_68,
+		GetPooledTransactionsMsg:      proto_sentry.MessageId_GET_POOLED_TRANSACTIONS_66,
+		PooledTransactionsMsg:         proto_sentry.MessageId_POOLED_TRANSACTIONS_66,
+		BlockRangeUpdateMsg:           proto_sentry.MessageId_BLOCK_RANGE_UPDATE_69,
+	},
 }
 
 var FromProto = map[uint]map[proto_sentry.MessageId]uint64{
@@ -124,6 +146,19 @@ var FromProto = map[uint]map[proto_sentry.MessageId]uint64{
 		proto_sentry.MessageId_GET_POOLED_TRANSACTIONS_66:       GetPooledTransactionsMsg,
 		proto_sentry.MessageId_POOLED_TRANSACTIONS_66:           PooledTransactionsMsg,
 	},
+	direct.ETH69: {
+		proto_sentry.MessageId_GET_BLOCK_HEADERS_66:             GetBlockHeadersMsg,
+		proto_sentry.MessageId_BLOCK_HEADERS_66:                 BlockHeadersMsg,
+		proto_sentry.MessageId_GET_BLOCK_BODIES_66:              GetBlockBodiesMsg,
+		proto_sentry.MessageId_BLOCK_BODIES_66:                  BlockBodiesMsg,
+		proto_sentry.MessageId_GET_RECEIPTS_69:                  GetReceiptsMsg,
+		proto_sentry.MessageId_RECEIPTS_69:                      ReceiptsMsg,
+		proto_sentry.MessageId_TRANSACTIONS_66:                  TransactionsMsg,
+		proto_sentry.MessageId_NEW_POOLED_TRANSACTION_HASHES_68: NewPooledTransactionHashesMsg,
+		proto_sentry.MessageId_GET_POOLED_TRANSACTIONS_66:       GetPooledTransactionsMsg,
+		proto_sentry.MessageId_POOLED_TRANSACTIONS_66:           PooledTransactionsMsg,
+		proto_sentry.MessageId_BLOCK_RANGE_UPDATE_69:            BlockRangeUpdateMsg,
+	},
 }
 
 // Packet represents a p2p message in the `eth` protocol.
@@ -142,6 +177,38 @@ type StatusPacket struct {
 	ForkID          forkid.ID
 }
 
+// StatusPacket69 is the network packet for the status message for eth/69. It
+// drops the total difficulty and announces the range of blocks the peer serves
+// instead.
+type StatusPacket69 struct {
+	ProtocolVersion uint32
+	NetworkID       uint64
+	Genesis         common.Hash
+	ForkID          forkid.ID
+	EarliestBlock   uint64
+	LatestBlock     uint64
+	LatestBlockHash common.Hash
+}
+
+// BlockRangeUpdatePacket announces the range of blocks a peer serves, it's
+// sent by eth/69 peers as their chain advances.
+type BlockRangeUpdatePacket struct {
+	EarliestBlock   uint64
+	LatestBlock     uint64
+	LatestBlockHash common.Hash
+}
+
+// Validate checks that the announced range is consistent.
+func (p *BlockRangeUpdatePacket) Validate() error {
+	if p.EarliestBlock > p.LatestBlock {
+		return fmt.Errorf("invalid block range: earliest %d > latest %d", p.EarliestBlock, p.LatestBlock)
+	}
+	if p.LatestBlockHash == (common.Hash{}) {
+		return errors.New("invalid block range: zero latest block hash")
+	}
+	return nil
+}
+
 // NewBlockHashesPacket is the network packet for the block announcements.
 type NewBlockHashesPacket []struct {
 	Hash   common.Hash // Hash of one particular block being announced
@@ -354,6 +421,13 @@ type ReceiptsPacket66 struct {
 	ReceiptsPacket
 }
 
+// ReceiptsPacket69 is the network packet for block receipts distribution over
+// eth/69, where receipts don't include the bloom.
+type ReceiptsPacket69 struct {
+	RequestId uint64
+	Receipts  [][]*types.ReceiptForNetwork69
+}
+
 // ReceiptsRLPPacket is used for receipts, when we already have it encoded
 type ReceiptsRLPPacket []rlp.RawValue
 
@@ -366,6 +440,12 @@ type ReceiptsRLPPacket66 struct {
 func (*StatusPacket) Name() string { return "Status" }
 func (*StatusPacket) Kind() byte   { return StatusMsg }
 
+func (*StatusPacket69) Name() string { return "Status" }
+func (*StatusPacket69) Kind() byte   { return StatusMsg }
+
+func (*BlockRangeUpdatePacket) Name() string { return "BlockRangeUpdate" }
+func (*BlockRangeUpdatePacket) Kind() byte   { return BlockRangeUpdateMsg }
+
 func (*NewBlockHashesPacket) Name() string { return "NewBlockHashes" }
 func (*NewBlockHashesPacket) Kind() byte   { return NewBlockHashesMsg }
 
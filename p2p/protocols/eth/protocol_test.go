@@ -25,6 +25,7 @@ import (
 	"testing"
 
 	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/direct"
 	"github.com/erigontech/erigon-lib/rlp"
 	"github.com/erigontech/erigon-lib/types"
 )
@@ -224,9 +225,83 @@ func TestEth66Messages(t *testing.T) {
 			ReceiptsRLPPacket66{1111, ReceiptsRLPPacket([]rlp.RawValue{receiptsRlp})},
 			common.FromHex("f90172820457f9016cf90169f901668001b9010000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000f85ff85d940000000000000000000000000000000000000011f842a0000000000000000000000000000000000000000000000000000000000000deada0000000000000000000000000000000000000000000000000000000000000beef830100ff"),
 		},
+		{
+			ReceiptsPacket69{1111, [][]*types.ReceiptForNetwork69{{(*types.ReceiptForNetwork69)(receipts[0])}}},
+			common.FromHex("f86d820457f868f866f864808001f85ff85d940000000000000000000000000000000000000011f842a0000000000000000000000000000000000000000000000000000000000000deada0000000000000000000000000000000000000000000000000000000000000beef830100ff"),
+		},
+		{
+			BlockRangeUpdatePacket{1, 2, hashes[0]},
+			common.FromHex("e30102a000000000000000000000000000000000000000000000000000000000deadc0de"),
+		},
 	} {
 		if have, _ := rlp.EncodeToBytes(tc.message); !bytes.Equal(have, tc.want) {
 			t.Errorf("test %d, type %T, have\n\t%x\nwant\n\t%x", i, tc.message, have, tc.want)
 		}
 	}
 }
+
+func TestReceipts69EncodeDecode(t *testing.T) {
+	receipts := types.Receipts{
+		{
+			Type:              types.LegacyTxType,
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: 21000,
+		},
+		{
+			Type:              types.DynamicFeeTxType,
+			Status:            types.ReceiptStatusFailed,
+			CumulativeGasUsed: 42000,
+			Logs: []*types.Log{{
+				Address: common.BytesToAddress([]byte{0x11}),
+				Topics:  []common.Hash{common.HexToHash("dead")},
+				Data:    []byte{0x01},
+			}},
+		},
+	}
+	encoded, err := encodeReceipts(receipts, direct.ETH69)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []*types.ReceiptForNetwork69
+	if err := rlp.DecodeBytes(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != len(receipts) {
+		t.Fatalf("have %d receipts, want %d", len(decoded), len(receipts))
+	}
+	for i, r := range decoded {
+		want := receipts[i]
+		if r.Type != want.Type || r.Status != want.Status || r.CumulativeGasUsed != want.CumulativeGasUsed || len(r.Logs) != len(want.Logs) {
+			t.Errorf("receipt %d: have %+v, want %+v", i, r, want)
+		}
+		if r.Bloom != types.LogsBloom(want.Logs) {
+			t.Errorf("receipt %d: bloom wasn't recomputed", i)
+		}
+	}
+
+	// eth/68 and older keep the consensus encoding
+	encoded, err = encodeReceipts(receipts, direct.ETH68)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded68 types.Receipts
+	if err := rlp.DecodeBytes(encoded, &decoded68); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded68) != len(receipts) {
+		t.Fatalf("have %d receipts, want %d", len(decoded68), len(receipts))
+	}
+}
+
+func TestBlockRangeUpdateValidate(t *testing.T) {
+	hash := common.HexToHash("deadc0de")
+	if err := (&BlockRangeUpdatePacket{EarliestBlock: 1, LatestBlock: 2, LatestBlockHash: hash}).Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := (&BlockRangeUpdatePacket{EarliestBlock: 3, LatestBlock: 2, LatestBlockHash: hash}).Validate(); err == nil {
+		t.Fatal("expected an error for an inverted range")
+	}
+	if err := (&BlockRangeUpdatePacket{EarliestBlock: 1, LatestBlock: 2}).Validate(); err == nil {
+		t.Fatal("expected an error for a zero hash")
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package snap
+
+import (
+	"context"
+	"time"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/length"
+	"github.com/erigontech/erigon-lib/crypto"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/execution/stagedsync/stages"
+)
+
+const (
+	// codesIndexBatch is the number of accounts whose code is indexed per
+	// transaction by IndexCodes.
+	codesIndexBatch = 10_000
+
+	// codesIndexWait is how often IndexCodes checks whether the state was
+	// executed, before it starts.
+	codesIndexWait = time.Minute
+)
+
+// codesIndexedKey is the key of kv.Code under which IndexCodes keeps its
+// progress: the address to continue from, or codesIndexedDone. Code hashes are
+// longer, and a reset of the table resets the progress too.
+var (
+	codesIndexedKey  = []byte("indexed")
+	codesIndexedDone = []byte{1}
+)
+
+// IndexCodes adds the codes of the code domain to kv.Code, the index by code
+// hash bytecodes are served from. The state flushes the codes it executes to
+// kv.Code, so only the codes of the state files downloaded before the first
+// execution are missing from it. It waits for the first execution, is resumed
+// after restarts, and returns once all codes were indexed.
+func IndexCodes(ctx context.Context, db kv.TemporalRwDB, logger log.Logger) error {
+	for {
+		var progress uint64
+		if err := db.View(ctx, func(tx kv.Tx) (err error) {
+			progress, err = stages.GetStageProgress(tx, stages.Execution)
+			return err
+		}); err != nil {
+			return err
+		}
+		if progress > 0 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(codesIndexWait):
+		}
+	}
+
+	var indexed int
+	for {
+		var done bool
+		if err := db.UpdateTemporal(ctx, func(tx kv.TemporalRwTx) (err error) {
+			done, err = indexCodesBatch(tx, &indexed)
+			return err
+		}); err != nil {
+			return err
+		}
+		if done {
+			break
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	if indexed > 0 {
+		logger.Info("[snap] indexed codes by hash", "codes", indexed)
+	}
+	return nil
+}
+
+// indexCodesBatch indexes the codes of up to codesIndexBatch accounts from the
+// progress of IndexCodes and reports whether all were indexed.
+func indexCodesBatch(tx kv.TemporalRwTx, indexed *int) (done bool, err error) {
+	from, err := tx.GetOne(kv.Code, codesIndexedKey)
+	if err != nil {
+		return false, err
+	}
+	if len(from) > 0 && len(from) != length.Addr {
+		return true, nil
+	}
+	from = common.Copy(from)
+
+	it, err := tx.Debug().RangeLatest(kv.CodeDomain, from, nil, codesIndexBatch+1)
+	if err != nil {
+		return false, err
+	}
+	defer it.Close()
+	var n int
+	for it.HasNext() {
+		addr, code, err := it.Next()
+		if err != nil {
+			return false, err
+		}
+		if n == codesIndexBatch {
+			return false, tx.Put(kv.Code, codesIndexedKey, common.Copy(addr))
+		}
+		n++
+		if len(code) == 0 {
+			continue
+		}
+		if err := tx.Put(kv.Code, crypto.Keccak256(code), code); err != nil {
+			return false, err
+		}
+		*indexed++
+	}
+	return true, tx.Put(kv.Code, codesIndexedKey, codesIndexedDone)
+}
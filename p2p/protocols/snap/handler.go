@@ -0,0 +1,172 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package snap
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"golang.org/x/sync/semaphore"
+	"golang.org/x/time/rate"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/p2p"
+	"github.com/erigontech/erigon/turbo/services"
+)
+
+const (
+	// softResponseLimit is the target maximum size of replies to data retrievals.
+	softResponseLimit = 2 * 1024 * 1024
+
+	// maxCodeLookups is the maximum number of bytecodes to serve. This number is
+	// there to limit the number of disk lookups.
+	maxCodeLookups = 1024
+
+	// maxTrieNodeLookups is the maximum number of state trie nodes to serve. This
+	// number is there to limit the number of disk lookups.
+	maxTrieNodeLookups = 1024
+
+	// maxRecentStates is the number of blocks before the latest one whose states
+	// are served, when the commitment history is kept.
+	maxRecentStates = 128
+
+	// maxStateReaders is the maximum number of state requests served at once,
+	// across the peers. Each of them opens the state and builds a witness.
+	maxStateReaders = 4
+
+	// stateRequestsPerSecond and stateRequestsBurst throttle the state requests
+	// of a peer, see NewPeerLimiter.
+	stateRequestsPerSecond = 10
+	stateRequestsBurst     = 20
+)
+
+// Server serves the snap/1 requests of peers from the latest state, and from the
+// states of the recent blocks when the commitment history is kept: the account
+// and storage ranges are walked in the order of their hashed keys along the
+// branches of the commitment domain, which also proves their boundaries. Other
+// state roots aren't served, the responses to their requests are empty.
+type Server struct {
+	db           kv.TemporalRoDB
+	blockReader  services.FullBlockReader
+	stateReaders *semaphore.Weighted
+
+	recentLock  sync.Mutex
+	recentHead  common.Hash            // hash of the latest block when recentRoots were read
+	recentRoots map[common.Hash]uint64 // state root -> block number, see recentBlock
+
+	logger log.Logger
+}
+
+func NewServer(db kv.TemporalRoDB, blockReader services.FullBlockReader, logger log.Logger) *Server {
+	return &Server{
+		db:           db,
+		blockReader:  blockReader,
+		stateReaders: semaphore.NewWeighted(maxStateReaders),
+		logger:       logger,
+	}
+}
+
+// NewPeerLimiter returns the limiter of the state requests of a peer, to pass to
+// HandleMessage for all its messages.
+func NewPeerLimiter() *rate.Limiter {
+	return rate.NewLimiter(stateRequestsPerSecond, stateRequestsBurst)
+}
+
+// viewState runs f for a state request of a peer once its limiter allows it and
+// less than maxStateReaders requests are being served.
+func (s *Server) viewState(ctx context.Context, limiter *rate.Limiter, f func(tx kv.TemporalTx) error) error {
+	if err := limiter.Wait(ctx); err != nil {
+		return err
+	}
+	if err := s.stateReaders.Acquire(ctx, 1); err != nil {
+		return err
+	}
+	defer s.stateReaders.Release(1)
+	return s.db.ViewTemporal(ctx, f)
+}
+
+// HandleMessage answers a request of a peer, whose state requests are throttled
+// by limiter. Responses are discarded as the node doesn't sync with snap. The
+// returned errors are the ones of the peer, failures to serve a request are
+// logged and answered with an empty response.
+func (s *Server) HandleMessage(ctx context.Context, msg p2p.Msg, w p2p.MsgWriter, limiter *rate.Limiter) error {
+	switch msg.Code {
+	case GetAccountRangeMsg:
+		var query GetAccountRangePacket
+		if err := msg.Decode(&query); err != nil {
+			return err
+		}
+		res := &AccountRangePacket{ID: query.ID}
+		if err := s.viewState(ctx, limiter, func(tx kv.TemporalTx) (err error) {
+			res, err = s.AnswerGetAccountRangeQuery(ctx, tx, &query)
+			return err
+		}); err != nil {
+			s.logger.Debug("[snap] failed to serve account range", "root", query.Root, "origin", query.Origin, "err", err)
+			res = &AccountRangePacket{ID: query.ID}
+		}
+		return p2p.Send(w, AccountRangeMsg, res)
+	case GetStorageRangesMsg:
+		var query GetStorageRangesPacket
+		if err := msg.Decode(&query); err != nil {
+			return err
+		}
+		res := &StorageRangesPacket{ID: query.ID}
+		if err := s.viewState(ctx, limiter, func(tx kv.TemporalTx) (err error) {
+			res, err = s.AnswerGetStorageRangesQuery(ctx, tx, &query)
+			return err
+		}); err != nil {
+			s.logger.Debug("[snap] failed to serve storage ranges", "root", query.Root, "accounts", len(query.Accounts), "err", err)
+			res = &StorageRangesPacket{ID: query.ID}
+		}
+		return p2p.Send(w, StorageRangesMsg, res)
+	case GetByteCodesMsg:
+		var query GetByteCodesPacket
+		if err := msg.Decode(&query); err != nil {
+			return err
+		}
+		res := &ByteCodesPacket{ID: query.ID}
+		if err := s.viewState(ctx, limiter, func(tx kv.TemporalTx) (err error) {
+			res, err = s.AnswerGetByteCodesQuery(tx, &query)
+			return err
+		}); err != nil {
+			s.logger.Debug("[snap] failed to serve bytecodes", "hashes", len(query.Hashes), "err", err)
+			res = &ByteCodesPacket{ID: query.ID}
+		}
+		return p2p.Send(w, ByteCodesMsg, res)
+	case GetTrieNodesMsg:
+		var query GetTrieNodesPacket
+		if err := msg.Decode(&query); err != nil {
+			return err
+		}
+		res := &TrieNodesPacket{ID: query.ID}
+		if err := s.viewState(ctx, limiter, func(tx kv.TemporalTx) (err error) {
+			res, err = s.AnswerGetTrieNodesQuery(ctx, tx, &query)
+			return err
+		}); err != nil {
+			s.logger.Debug("[snap] failed to serve trie nodes", "root", query.Root, "paths", len(query.Paths), "err", err)
+			res = &TrieNodesPacket{ID: query.ID}
+		}
+		return p2p.Send(w, TrieNodesMsg, res)
+	case AccountRangeMsg, StorageRangesMsg, ByteCodesMsg, TrieNodesMsg:
+		return nil
+	default:
+		return fmt.Errorf("invalid snap message code %d", msg.Code)
+	}
+}
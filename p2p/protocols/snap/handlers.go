@@ -0,0 +1,705 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package snap
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/holiman/uint256"
+
+	"github.com/erigontech/erigon-db/rawdb"
+	"github.com/erigontech/erigon-lib/commitment"
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/empty"
+	"github.com/erigontech/erigon-lib/common/length"
+	"github.com/erigontech/erigon-lib/crypto"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/order"
+	"github.com/erigontech/erigon-lib/rlp"
+	libstate "github.com/erigontech/erigon-lib/state"
+	"github.com/erigontech/erigon-lib/trie"
+	"github.com/erigontech/erigon-lib/types/accounts"
+)
+
+const (
+	// estAccountSize is the approximate size of an account in a response, used
+	// to stop the walk of an account range before the accounts are read.
+	estAccountSize = length.Hash + 100
+
+	// stateLookupSlack is the ratio by which a storage range response may exceed
+	// the requested size, so that small storage tries are served whole.
+	stateLookupSlack = 0.1
+)
+
+var maxHash = common.HexToHash("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+
+// AnswerGetAccountRangeQuery returns the accounts from the origin of the query
+// up to the first one at or after its limit, with the proofs of the origin and
+// of the last account.
+func (s *Server) AnswerGetAccountRangeQuery(ctx context.Context, tx kv.TemporalTx, query *GetAccountRangePacket) (*AccountRangePacket, error) {
+	res := &AccountRangePacket{ID: query.ID}
+	domains, err := s.openState(ctx, tx, query.Root)
+	if err != nil || domains == nil {
+		return res, err
+	}
+	defer domains.Close()
+	sdCtx := domains.GetCommitmentContext()
+
+	limitBytes := min(query.Bytes, softResponseLimit)
+	origin, limit := keyNibbles(query.Origin[:]), keyNibbles(query.Limit[:])
+	var (
+		addrs  [][]byte
+		hashes []common.Hash
+		size   uint64
+	)
+	err = sdCtx.WalkLeaves(nil, origin, func(plainKey, hashedKey []byte) (bool, error) {
+		addrs = append(addrs, plainKey)
+		hashes = append(hashes, nibblesToHash(hashedKey))
+		size += estAccountSize
+		return bytes.Compare(hashedKey, limit) < 0 && size < limitBytes, nil
+	})
+	if errors.Is(err, commitment.ErrNoBranch) {
+		return res, nil // state of a single account, not worth syncing with snap
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// the predecessor of the origin and the first account after it expand the
+	// path to the origin in the witness
+	prev, err := sdCtx.PrevLeaf(nil, origin)
+	if err != nil {
+		return nil, err
+	}
+	for _, addr := range append(addrs, prev) {
+		if addr != nil {
+			sdCtx.TouchKey(kv.AccountsDomain, string(addr), nil)
+		}
+	}
+	proofTrie, _, err := sdCtx.Witness(ctx, nil, query.Root[:], "snap")
+	if err != nil {
+		return nil, err
+	}
+
+	for i, hash := range hashes {
+		acc, _ := proofTrie.GetAccount(hash[:])
+		if acc == nil {
+			return nil, fmt.Errorf("account %x is missing in the witness", addrs[i])
+		}
+		body, err := slimAccountRLP(acc)
+		if err != nil {
+			return nil, err
+		}
+		res.Accounts = append(res.Accounts, &AccountData{Hash: hash, Body: body})
+	}
+
+	var proof proofSet
+	if err := proof.add(proofTrie.Prove(query.Origin[:], 0, false)); err != nil {
+		return nil, err
+	}
+	if len(hashes) > 0 {
+		if err := proof.add(proofTrie.Prove(hashes[len(hashes)-1][:], 0, false)); err != nil {
+			return nil, err
+		}
+	}
+	res.Proof = proof.nodes
+	return res, nil
+}
+
+// AnswerGetStorageRangesQuery returns the storage slots of the accounts of the
+// query. The origin and the limit of the query only apply to the first account,
+// when it doesn't start from the first slot or its range is capped the proofs of
+// its boundaries are added and no more accounts are served.
+func (s *Server) AnswerGetStorageRangesQuery(ctx context.Context, tx kv.TemporalTx, query *GetStorageRangesPacket) (*StorageRangesPacket, error) {
+	res := &StorageRangesPacket{ID: query.ID}
+	domains, err := s.openState(ctx, tx, query.Root)
+	if err != nil || domains == nil {
+		return res, err
+	}
+	defer domains.Close()
+	sdCtx := domains.GetCommitmentContext()
+
+	limitBytes := min(query.Bytes, softResponseLimit)
+	hardLimit := uint64(float64(limitBytes) * (1 + stateLookupSlack))
+	var size uint64
+	for _, accountHash := range query.Accounts {
+		// once the size is exceeded, don't open a range which would need proofs
+		if size >= limitBytes {
+			break
+		}
+		var origin common.Hash
+		if len(query.Origin) > 0 {
+			origin, query.Origin = common.BytesToHash(query.Origin), nil
+		}
+		limit := maxHash
+		if len(query.Limit) > 0 {
+			limit, query.Limit = common.BytesToHash(query.Limit), nil
+		}
+
+		addr, err := accountByHash(sdCtx, accountHash)
+		if err != nil {
+			return nil, err
+		}
+		if addr == nil {
+			continue
+		}
+		var (
+			slots    []*StorageData
+			keys     [][]byte
+			abort    bool
+			readErr  error
+			lastSlot common.Hash
+		)
+		err = walkStorage(tx, domains, addr, accountHash, origin, func(plainKey []byte, slotHash common.Hash) bool {
+			if size >= hardLimit {
+				abort = true
+				return false
+			}
+			v, err := domains.storage(tx, plainKey)
+			if err != nil {
+				readErr = err
+				return false
+			}
+			body, err := rlp.EncodeToBytes(v)
+			if err != nil {
+				readErr = err
+				return false
+			}
+			slots = append(slots, &StorageData{Hash: slotHash, Body: body})
+			keys = append(keys, plainKey)
+			lastSlot = slotHash
+			size += uint64(length.Hash + len(body))
+			return bytes.Compare(slotHash[:], limit[:]) < 0
+		})
+		if err == nil {
+			err = readErr
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(slots) > 0 {
+			res.Slots = append(res.Slots, slots)
+		}
+		if origin == (common.Hash{}) && (!abort || len(slots) == 0) {
+			continue // the whole storage trie is in the response
+		}
+
+		prev, err := prevStorage(tx, domains, addr, accountHash, origin)
+		if err != nil {
+			return nil, err
+		}
+		sdCtx.TouchKey(kv.AccountsDomain, string(addr), nil)
+		for _, key := range [][]byte{prev, firstOf(keys), lastOf(keys)} {
+			if key != nil {
+				sdCtx.TouchKey(kv.StorageDomain, string(key), nil)
+			}
+		}
+		proofTrie, _, err := sdCtx.Witness(ctx, nil, query.Root[:], "snap")
+		if err != nil {
+			return nil, err
+		}
+		accountProof, err := proofTrie.Prove(accountHash[:], 0, false)
+		if err != nil {
+			return nil, err
+		}
+		var proof proofSet
+		if err := proof.add(proofTrie.Prove(append(accountHash[:], origin[:]...), len(accountProof), true)); err != nil {
+			return nil, err
+		}
+		if len(slots) > 0 {
+			if err := proof.add(proofTrie.Prove(append(accountHash[:], lastSlot[:]...), len(accountProof), true)); err != nil {
+				return nil, err
+			}
+		}
+		res.Proof = proof.nodes
+		// the proofs terminate the response
+		break
+	}
+	return res, nil
+}
+
+// AnswerGetByteCodesQuery returns the bytecodes of the query which are known,
+// from the index of codes by hash in kv.Code, see IndexCodes.
+func (s *Server) AnswerGetByteCodesQuery(tx kv.TemporalTx, query *GetByteCodesPacket) (*ByteCodesPacket, error) {
+	res := &ByteCodesPacket{ID: query.ID}
+	limitBytes := min(query.Bytes, softResponseLimit)
+	var size uint64
+	for i, hash := range query.Hashes {
+		if i >= maxCodeLookups || size >= limitBytes {
+			break
+		}
+		if hash == empty.CodeHash {
+			res.Codes = append(res.Codes, []byte{})
+			continue
+		}
+		code, err := tx.GetOne(kv.Code, hash[:])
+		if err != nil {
+			return nil, err
+		}
+		if code == nil {
+			continue
+		}
+		res.Codes = append(res.Codes, code)
+		size += uint64(len(code))
+	}
+	return res, nil
+}
+
+// nodeRequest is a trie node to serve, found by proving a leaf below its path.
+type nodeRequest struct {
+	accountHash common.Hash
+	slotHash    *common.Hash // nil for account trie nodes
+	depth       int
+	found       bool
+}
+
+// AnswerGetTrieNodesQuery returns the trie nodes at the paths of the query. A
+// node is served by proving a leaf below it, the nodes which aren't found are
+// empty in the response.
+func (s *Server) AnswerGetTrieNodesQuery(ctx context.Context, tx kv.TemporalTx, query *GetTrieNodesPacket) (*TrieNodesPacket, error) {
+	res := &TrieNodesPacket{ID: query.ID}
+	domains, err := s.openState(ctx, tx, query.Root)
+	if err != nil || domains == nil {
+		return res, err
+	}
+	defer domains.Close()
+	sdCtx := domains.GetCommitmentContext()
+
+	var (
+		requests []nodeRequest
+		touched  bool
+	)
+	firstLeaf := func(prefix, path []byte) (plainKey, hashedKey []byte, err error) {
+		err = sdCtx.WalkLeaves(prefix, append(common.CopyBytes(prefix), path...), func(k, h []byte) (bool, error) {
+			if bytes.HasPrefix(h[len(prefix):], path) {
+				plainKey, hashedKey = k, h
+			}
+			return false, nil
+		})
+		if errors.Is(err, commitment.ErrNoBranch) {
+			err = nil
+		}
+		return plainKey, hashedKey, err
+	}
+	for _, pathset := range query.Paths {
+		if len(pathset) == 0 || len(requests) >= maxTrieNodeLookups {
+			break
+		}
+		if len(pathset) == 1 {
+			path := compactToNibbles(pathset[0])
+			addr, hashedKey, err := firstLeaf(nil, path)
+			if err != nil {
+				return nil, err
+			}
+			req := nodeRequest{depth: len(path)}
+			if addr != nil {
+				sdCtx.TouchKey(kv.AccountsDomain, string(addr), nil)
+				req.accountHash, req.found, touched = nibblesToHash(hashedKey), true, true
+			}
+			requests = append(requests, req)
+			continue
+		}
+
+		accountHash := common.BytesToHash(pathset[0])
+		addr, err := accountByHash(sdCtx, accountHash)
+		if err != nil {
+			return nil, err
+		}
+		if addr != nil {
+			sdCtx.TouchKey(kv.AccountsDomain, string(addr), nil)
+			touched = true
+		}
+		for _, compactPath := range pathset[1:] {
+			if len(requests) >= maxTrieNodeLookups {
+				break
+			}
+			path := compactToNibbles(compactPath)
+			req := nodeRequest{accountHash: accountHash, slotHash: &common.Hash{}, depth: len(path)}
+			if addr != nil {
+				var from common.Hash
+				copy(from[:], nibblesToBytes(path))
+				err := walkStorage(tx, domains, addr, accountHash, from, func(plainKey []byte, slotHash common.Hash) bool {
+					if bytes.HasPrefix(keyNibbles(slotHash[:]), path) {
+						sdCtx.TouchKey(kv.StorageDomain, string(plainKey), nil)
+						*req.slotHash, req.found = slotHash, true
+					}
+					return false
+				})
+				if err != nil {
+					return nil, err
+				}
+			}
+			requests = append(requests, req)
+		}
+	}
+	if !touched {
+		res.Nodes = make([][]byte, len(requests))
+		return res, nil
+	}
+
+	proofTrie, _, err := sdCtx.Witness(ctx, nil, query.Root[:], "snap")
+	if err != nil {
+		return nil, err
+	}
+	limitBytes := min(query.Bytes, softResponseLimit)
+	var size uint64
+	for _, req := range requests {
+		if size >= limitBytes {
+			break
+		}
+		var node []byte
+		if req.found {
+			accountProof, err := proofTrie.Prove(req.accountHash[:], 0, false)
+			if err != nil {
+				return nil, err
+			}
+			proof := accountProof
+			if req.slotHash != nil {
+				if proof, err = proofTrie.Prove(append(req.accountHash[:], req.slotHash[:]...), len(accountProof), true); err != nil {
+					return nil, err
+				}
+			}
+			node = proofNodeAt(proof, req.depth)
+		}
+		res.Nodes = append(res.Nodes, node)
+		size += uint64(len(node))
+	}
+	return res, nil
+}
+
+// blockState is the state of a block served to the peers, see Server.openState.
+type blockState struct {
+	*libstate.SharedDomains
+	asOf uint64 // first txNum after the block, 0 for the latest state
+}
+
+// storage reads the value of a storage slot in the state.
+func (st *blockState) storage(tx kv.TemporalTx, key []byte) ([]byte, error) {
+	if st.asOf > 0 {
+		v, _, err := tx.GetAsOf(kv.StorageDomain, key, st.asOf)
+		return v, err
+	}
+	v, _, err := st.GetLatest(kv.StorageDomain, tx, key)
+	return v, err
+}
+
+// openState opens the state with the given root: the latest one or, when the
+// commitment history is kept, the one of a block among the maxRecentStates
+// before it. It returns nil when root isn't one of them.
+func (s *Server) openState(ctx context.Context, tx kv.TemporalTx, root common.Hash) (*blockState, error) {
+	domains, err := libstate.NewSharedDomains(tx, s.logger)
+	if err != nil {
+		return nil, err
+	}
+	st, err := s.seekState(ctx, tx, domains, root)
+	if err != nil || st == nil {
+		domains.Close()
+		return nil, err
+	}
+	return st, nil
+}
+
+func (s *Server) seekState(ctx context.Context, tx kv.TemporalTx, domains *libstate.SharedDomains, root common.Hash) (*blockState, error) {
+	latest := domains.BlockNum()
+	history, _, err := rawdb.ReadDBCommitmentHistoryEnabled(tx)
+	if err != nil {
+		return nil, err
+	}
+	blockNum, ok, err := s.recentBlock(ctx, tx, root, latest, history)
+	if err != nil || !ok {
+		return nil, err
+	}
+	if blockNum == latest {
+		return &blockState{SharedDomains: domains}, nil
+	}
+
+	// like eth_getProof, the branches, accounts and storage are read as of the block
+	asOf, err := s.blockReader.TxnumReader(ctx).Min(tx, blockNum+1)
+	if err != nil {
+		return nil, err
+	}
+	if asOf < tx.Debug().HistoryStartFrom(kv.CommitmentDomain) {
+		return nil, nil
+	}
+	domains.GetCommitmentContext().SetLimitReadAsOfTxNum(asOf, false)
+	if err := domains.SeekCommitment(ctx, tx); err != nil {
+		return nil, err
+	}
+	return &blockState{SharedDomains: domains, asOf: asOf}, nil
+}
+
+// recentBlock returns the number of the block, among the latest one and, with
+// history, the maxRecentStates before it, whose state root is root. The roots of
+// these blocks are read once per latest block.
+func (s *Server) recentBlock(ctx context.Context, tx kv.TemporalTx, root common.Hash, latest uint64, history bool) (uint64, bool, error) {
+	head, err := s.blockReader.HeaderByNumber(ctx, tx, latest)
+	if err != nil || head == nil {
+		return 0, false, err
+	}
+	if !history {
+		return latest, head.Root == root, nil
+	}
+
+	s.recentLock.Lock()
+	defer s.recentLock.Unlock()
+	if s.recentHead != head.Hash() {
+		roots := make(map[common.Hash]uint64, maxRecentStates+1)
+		roots[head.Root] = latest
+		for blockNum := latest; blockNum > 0 && latest-blockNum < maxRecentStates; blockNum-- {
+			header, err := s.blockReader.HeaderByNumber(ctx, tx, blockNum-1)
+			if err != nil {
+				return 0, false, err
+			}
+			if header == nil {
+				break
+			}
+			if _, ok := roots[header.Root]; !ok { // the latest block with a root wins
+				roots[header.Root] = blockNum - 1
+			}
+		}
+		s.recentHead, s.recentRoots = head.Hash(), roots
+	}
+	blockNum, ok := s.recentRoots[root]
+	return blockNum, ok, nil
+}
+
+// accountByHash returns the address of the account with the given hash, or nil
+// if there is no such account.
+func accountByHash(sdCtx *libstate.SharedDomainsCommitmentContext, hash common.Hash) ([]byte, error) {
+	target := keyNibbles(hash[:])
+	var addr []byte
+	err := sdCtx.WalkLeaves(nil, target, func(plainKey, hashedKey []byte) (bool, error) {
+		if bytes.Equal(hashedKey, target) {
+			addr = plainKey
+		}
+		return false, nil
+	})
+	if errors.Is(err, commitment.ErrNoBranch) {
+		return nil, nil
+	}
+	return addr, err
+}
+
+// walkStorage calls fn with the storage slots of addr from origin in the order of
+// their hashes until it returns false. The storage tries of less than two slots
+// under their root aren't stored in branches, they are sorted from the domain.
+func walkStorage(tx kv.TemporalTx, domains *blockState, addr []byte, accountHash, origin common.Hash, fn func(plainKey []byte, slotHash common.Hash) bool) error {
+	accountNibbles := keyNibbles(accountHash[:])
+	err := domains.GetCommitmentContext().WalkLeaves(accountNibbles, append(accountNibbles, keyNibbles(origin[:])...), func(plainKey, hashedKey []byte) (bool, error) {
+		return fn(plainKey, nibblesToHash(hashedKey[64:])), nil
+	})
+	if !errors.Is(err, commitment.ErrNoBranch) {
+		return err
+	}
+
+	slots, err := sortedSlots(tx, domains, addr)
+	if err != nil {
+		return err
+	}
+	for _, slot := range slots {
+		if bytes.Compare(slot.hash[:], origin[:]) >= 0 && !fn(slot.key, slot.hash) {
+			break
+		}
+	}
+	return nil
+}
+
+// prevStorage returns the plain key of the last storage slot of addr before
+// origin, or nil if there is no such slot.
+func prevStorage(tx kv.TemporalTx, domains *blockState, addr []byte, accountHash, origin common.Hash) ([]byte, error) {
+	accountNibbles := keyNibbles(accountHash[:])
+	prev, err := domains.GetCommitmentContext().PrevLeaf(accountNibbles, append(accountNibbles, keyNibbles(origin[:])...))
+	if !errors.Is(err, commitment.ErrNoBranch) {
+		return prev, err
+	}
+
+	slots, err := sortedSlots(tx, domains, addr)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(slots) - 1; i >= 0; i-- {
+		if bytes.Compare(slots[i].hash[:], origin[:]) < 0 {
+			return slots[i].key, nil
+		}
+	}
+	return nil, nil
+}
+
+type storageSlot struct {
+	key  []byte
+	hash common.Hash
+}
+
+// sortedSlots reads the storage slots of addr from the domain and sorts them by
+// their hashes.
+func sortedSlots(tx kv.TemporalTx, domains *blockState, addr []byte) ([]storageSlot, error) {
+	var slots []storageSlot
+	add := func(k, v []byte) {
+		if len(v) > 0 {
+			slots = append(slots, storageSlot{key: common.CopyBytes(k), hash: crypto.Keccak256Hash(k[len(addr):])})
+		}
+	}
+	if domains.asOf > 0 {
+		to, _ := kv.NextSubtree(addr)
+		it, err := tx.RangeAsOf(kv.StorageDomain, addr, to, domains.asOf, order.Asc, kv.Unlim)
+		if err != nil {
+			return nil, err
+		}
+		defer it.Close()
+		for it.HasNext() {
+			k, v, err := it.Next()
+			if err != nil {
+				return nil, err
+			}
+			add(k, v)
+		}
+	} else if err := domains.IteratePrefix(kv.StorageDomain, addr, tx, func(k, v []byte, _ uint64) (bool, error) {
+		add(k, v)
+		return true, nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Slice(slots, func(i, j int) bool { return bytes.Compare(slots[i].hash[:], slots[j].hash[:]) < 0 })
+	return slots, nil
+}
+
+// slimAccountRLP encodes an account in the slim format of snap, where the empty
+// storage root and code hash are omitted.
+func slimAccountRLP(acc *accounts.Account) ([]byte, error) {
+	slim := struct {
+		Nonce    uint64
+		Balance  *uint256.Int
+		Root     []byte
+		CodeHash []byte
+	}{Nonce: acc.Nonce, Balance: &acc.Balance}
+	if !acc.IsEmptyRoot() {
+		slim.Root = acc.Root[:]
+	}
+	if !acc.IsEmptyCodeHash() {
+		slim.CodeHash = acc.CodeHash[:]
+	}
+	return rlp.EncodeToBytes(&slim)
+}
+
+// proofSet collects the distinct nodes of proofs.
+type proofSet struct {
+	nodes [][]byte
+	seen  map[string]struct{}
+}
+
+func (p *proofSet) add(proof [][]byte, err error) error {
+	if err != nil {
+		return err
+	}
+	if p.seen == nil {
+		p.seen = make(map[string]struct{})
+	}
+	for _, node := range proof {
+		if _, ok := p.seen[string(node)]; !ok {
+			p.seen[string(node)] = struct{}{}
+			p.nodes = append(p.nodes, node)
+		}
+	}
+	return nil
+}
+
+// proofNodeAt returns the node of proof at the nibble depth, or nil if no node
+// of proof starts there.
+func proofNodeAt(proof [][]byte, depth int) []byte {
+	var d int
+	for _, node := range proof {
+		if d == depth {
+			return node
+		}
+		elems, _, err := rlp.SplitList(node)
+		if err != nil {
+			return nil
+		}
+		n, err := rlp.CountValues(elems)
+		if err != nil {
+			return nil
+		}
+		switch n {
+		case 17:
+			d++
+		case 2:
+			key, _, err := rlp.SplitString(elems)
+			if err != nil || len(key) == 0 {
+				return nil
+			}
+			keybytes := trie.CompactToKeybytes(key)
+			if keybytes.Terminating {
+				return nil
+			}
+			d += keybytes.Nibbles()
+		default:
+			return nil
+		}
+		if d > depth {
+			return nil
+		}
+	}
+	return nil
+}
+
+func keyNibbles(key []byte) []byte {
+	nibbles := make([]byte, len(key)*2)
+	for i, b := range key {
+		nibbles[i*2], nibbles[i*2+1] = b>>4, b&0x0f
+	}
+	return nibbles
+}
+
+// nibblesToBytes packs nibbles, an odd last nibble is the high half of the last byte.
+func nibblesToBytes(nibbles []byte) []byte {
+	key := make([]byte, (len(nibbles)+1)/2)
+	for i, n := range nibbles {
+		key[i/2] |= n << (4 * (1 - i%2))
+	}
+	return key
+}
+
+func nibblesToHash(nibbles []byte) (hash common.Hash) {
+	copy(hash[:], nibblesToBytes(nibbles))
+	return hash
+}
+
+func compactToNibbles(compact []byte) []byte {
+	if len(compact) == 0 {
+		return nil
+	}
+	keybytes := trie.CompactToKeybytes(compact)
+	return keyNibbles(keybytes.Data)[:keybytes.Nibbles()]
+}
+
+func firstOf(keys [][]byte) []byte {
+	if len(keys) == 0 {
+		return nil
+	}
+	return keys[0]
+}
+
+func lastOf(keys [][]byte) []byte {
+	if len(keys) == 0 {
+		return nil
+	}
+	return keys[len(keys)-1]
+}
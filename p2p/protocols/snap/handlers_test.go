@@ -0,0 +1,295 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package snap_test
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-db/rawdb"
+	"github.com/erigontech/erigon-lib/chain"
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/u256"
+	"github.com/erigontech/erigon-lib/crypto"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/rlp"
+	libstate "github.com/erigontech/erigon-lib/state"
+	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon/core"
+	"github.com/erigontech/erigon/core/vm"
+	"github.com/erigontech/erigon/execution/stagedsync/stages"
+	"github.com/erigontech/erigon/execution/stages/mock"
+	"github.com/erigontech/erigon/p2p"
+	"github.com/erigontech/erigon/p2p/protocols/snap"
+)
+
+type slimAccount struct {
+	Nonce    uint64
+	Balance  *uint256.Int
+	Root     []byte
+	CodeHash []byte
+}
+
+func TestAnswerSnapQueries(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	contract := common.HexToAddress("0xc0de")
+	code := []byte{0x60, 0x00, 0x60, 0x00, 0xf3}
+	storage := map[common.Hash]common.Hash{}
+	for i := 1; i <= 100; i++ {
+		storage[common.BigToHash(big.NewInt(int64(i)))] = common.BigToHash(big.NewInt(int64(i * 1000)))
+	}
+	alloc := types.GenesisAlloc{
+		crypto.PubkeyToAddress(key.PublicKey): {Balance: big.NewInt(1e18)},
+		contract:                              {Balance: big.NewInt(1), Code: code, Storage: storage},
+	}
+	for i := 1; i <= 300; i++ {
+		alloc[common.BigToAddress(big.NewInt(int64(i)))] = types.GenesisAccount{Balance: big.NewInt(int64(i))}
+	}
+	m := mock.MockWithGenesis(t, &types.Genesis{Config: chain.TestChainConfig, Alloc: alloc}, key, false)
+	server := snap.NewServer(m.DB, m.BlockReader, log.New())
+	ctx := context.Background()
+
+	tx, err := m.DB.BeginTemporalRo(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	root := m.Genesis.Root()
+	maxHash := common.HexToHash("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	contractHash := crypto.Keccak256Hash(contract[:])
+
+	var expected []common.Hash
+	for addr := range alloc {
+		expected = append(expected, crypto.Keccak256Hash(addr[:]))
+	}
+	sort.Slice(expected, func(i, j int) bool { return bytes.Compare(expected[i][:], expected[j][:]) < 0 })
+	hashes := func(accounts []*snap.AccountData) (res []common.Hash) {
+		for _, acc := range accounts {
+			res = append(res, acc.Hash)
+		}
+		return res
+	}
+
+	t.Run("AccountRange", func(t *testing.T) {
+		res, err := server.AnswerGetAccountRangeQuery(ctx, tx, &snap.GetAccountRangePacket{ID: 1, Root: root, Limit: maxHash, Bytes: 1 << 20})
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), res.ID)
+		require.Equal(t, expected, hashes(res.Accounts))
+		require.Equal(t, root, crypto.Keccak256Hash(res.Proof[0]))
+		for _, acc := range res.Accounts {
+			var slim slimAccount
+			require.NoError(t, rlp.DecodeBytes(acc.Body, &slim))
+			if acc.Hash == contractHash {
+				require.Equal(t, crypto.Keccak256(code), slim.CodeHash)
+				require.Len(t, slim.Root, 32)
+			} else {
+				require.Empty(t, slim.CodeHash)
+				require.Empty(t, slim.Root)
+			}
+		}
+
+		// pages from an origin which isn't an account, up to the first account past the limit
+		origin := expected[100]
+		origin[31]++
+		res, err = server.AnswerGetAccountRangeQuery(ctx, tx, &snap.GetAccountRangePacket{Root: root, Origin: origin, Limit: expected[150], Bytes: 1 << 20})
+		require.NoError(t, err)
+		require.Equal(t, expected[101:151], hashes(res.Accounts))
+		require.NotEmpty(t, res.Proof)
+
+		res, err = server.AnswerGetAccountRangeQuery(ctx, tx, &snap.GetAccountRangePacket{Root: root, Limit: maxHash, Bytes: 1000})
+		require.NoError(t, err)
+		require.NotEmpty(t, res.Accounts)
+		require.Less(t, len(res.Accounts), len(expected))
+
+		// only the recent states are served
+		res, err = server.AnswerGetAccountRangeQuery(ctx, tx, &snap.GetAccountRangePacket{Root: common.Hash{1}, Limit: maxHash, Bytes: 1 << 20})
+		require.NoError(t, err)
+		require.Empty(t, res.Accounts)
+		require.Empty(t, res.Proof)
+	})
+
+	t.Run("StorageRanges", func(t *testing.T) {
+		var expectedSlots []common.Hash
+		for slot := range storage {
+			expectedSlots = append(expectedSlots, crypto.Keccak256Hash(slot[:]))
+		}
+		sort.Slice(expectedSlots, func(i, j int) bool { return bytes.Compare(expectedSlots[i][:], expectedSlots[j][:]) < 0 })
+		slotHashes := func(slots []*snap.StorageData) (res []common.Hash) {
+			for _, slot := range slots {
+				res = append(res, slot.Hash)
+			}
+			return res
+		}
+
+		bankHash := crypto.Keccak256Hash(crypto.PubkeyToAddress(key.PublicKey).Bytes())
+		res, err := server.AnswerGetStorageRangesQuery(ctx, tx, &snap.GetStorageRangesPacket{Root: root, Accounts: []common.Hash{contractHash, bankHash}, Bytes: 1 << 20})
+		require.NoError(t, err)
+		require.Len(t, res.Slots, 1)
+		require.Equal(t, expectedSlots, slotHashes(res.Slots[0]))
+		require.Empty(t, res.Proof)
+		var value []byte
+		require.NoError(t, rlp.DecodeBytes(res.Slots[0][0].Body, &value))
+		require.NotEmpty(t, value)
+
+		// a capped range is proven
+		res, err = server.AnswerGetStorageRangesQuery(ctx, tx, &snap.GetStorageRangesPacket{Root: root, Accounts: []common.Hash{contractHash}, Bytes: 500})
+		require.NoError(t, err)
+		require.Len(t, res.Slots, 1)
+		require.Less(t, len(res.Slots[0]), len(expectedSlots))
+		require.NotEmpty(t, res.Proof)
+
+		res, err = server.AnswerGetStorageRangesQuery(ctx, tx, &snap.GetStorageRangesPacket{Root: root, Accounts: []common.Hash{contractHash}, Origin: expectedSlots[10][:], Limit: expectedSlots[20][:], Bytes: 1 << 20})
+		require.NoError(t, err)
+		require.Equal(t, expectedSlots[10:21], slotHashes(res.Slots[0]))
+		require.NotEmpty(t, res.Proof)
+	})
+
+	t.Run("ByteCodes", func(t *testing.T) {
+		query := &snap.GetByteCodesPacket{Hashes: []common.Hash{crypto.Keccak256Hash(code), {1}}, Bytes: 1 << 20}
+		res, err := server.AnswerGetByteCodesQuery(tx, query)
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{code}, res.Codes)
+
+		// codes of state which wasn't executed, e.g. downloaded, are indexed by IndexCodes
+		require.NoError(t, m.DB.Update(ctx, func(tx kv.RwTx) error {
+			if err := tx.ClearTable(kv.Code); err != nil {
+				return err
+			}
+			return stages.SaveStageProgress(tx, stages.Execution, 1)
+		}))
+		require.NoError(t, snap.IndexCodes(ctx, m.DB, log.New()))
+		require.NoError(t, snap.IndexCodes(ctx, m.DB, log.New())) // done already
+		require.NoError(t, m.DB.ViewTemporal(ctx, func(tx kv.TemporalTx) error {
+			res, err = server.AnswerGetByteCodesQuery(tx, query)
+			return err
+		}))
+		require.Equal(t, [][]byte{code}, res.Codes)
+	})
+
+	t.Run("TrieNodes", func(t *testing.T) {
+		res, err := server.AnswerGetTrieNodesQuery(ctx, tx, &snap.GetTrieNodesPacket{
+			Root: root,
+			Paths: []snap.TrieNodePathSet{
+				{{0x00}},                      // account trie root
+				{contractHash[:], {0x00}},     // storage trie root
+				{{0x10 | contractHash[0]>>4}}, // account trie node with odd path
+			},
+			Bytes: 1 << 20,
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Nodes, 3)
+		require.Equal(t, root, crypto.Keccak256Hash(res.Nodes[0]))
+		require.NotEmpty(t, res.Nodes[1])
+		require.NotEmpty(t, res.Nodes[2])
+
+		accounts, err := server.AnswerGetAccountRangeQuery(ctx, tx, &snap.GetAccountRangePacket{Root: root, Origin: contractHash, Limit: contractHash, Bytes: 1 << 20})
+		require.NoError(t, err)
+		var slim slimAccount
+		require.NoError(t, rlp.DecodeBytes(accounts.Accounts[0].Body, &slim))
+		require.Equal(t, common.BytesToHash(slim.Root), crypto.Keccak256Hash(res.Nodes[1]))
+	})
+
+	t.Run("HandleMessage", func(t *testing.T) {
+		local, remote := p2p.MsgPipe()
+		defer local.Close()
+		defer remote.Close()
+		go func() {
+			require.NoError(t, p2p.Send(remote, snap.GetAccountRangeMsg, &snap.GetAccountRangePacket{ID: 7, Root: root, Limit: expected[9], Bytes: 1 << 20}))
+		}()
+		msg, err := local.ReadMsg()
+		require.NoError(t, err)
+		errc := make(chan error, 1)
+		go func() { errc <- server.HandleMessage(ctx, msg, local, snap.NewPeerLimiter()) }()
+		var res snap.AccountRangePacket
+		msg, err = remote.ReadMsg()
+		require.NoError(t, err)
+		require.Equal(t, uint64(snap.AccountRangeMsg), msg.Code)
+		require.NoError(t, msg.Decode(&res))
+		require.NoError(t, <-errc)
+		require.Equal(t, uint64(7), res.ID)
+		require.Equal(t, expected[:10], hashes(res.Accounts))
+	})
+}
+
+func TestAnswerSnapQueriesRecentStates(t *testing.T) {
+	// the recent states are read from the commitment history
+	commitmentSchema := libstate.Schema.CommitmentDomain
+	libstate.EnableHistoricalCommitment()
+	t.Cleanup(func() { libstate.Schema.CommitmentDomain = commitmentSchema })
+
+	key, _ := crypto.GenerateKey()
+	bank := crypto.PubkeyToAddress(key.PublicKey)
+	recipient := common.HexToAddress("0xeeee")
+	counter := common.HexToAddress("0xc0de")
+	alloc := types.GenesisAlloc{
+		bank: {Balance: big.NewInt(1e18)},
+		// increments slot 0
+		counter: {
+			Code:    []byte{byte(vm.PUSH1), 0, byte(vm.SLOAD), byte(vm.PUSH1), 1, byte(vm.ADD), byte(vm.PUSH1), 0, byte(vm.SSTORE)},
+			Balance: big.NewInt(0),
+		},
+	}
+	m := mock.MockWithGenesis(t, &types.Genesis{Config: chain.TestChainConfig, Alloc: alloc}, key, false)
+	require.NoError(t, m.DB.Update(context.Background(), func(tx kv.RwTx) error {
+		return rawdb.WriteDBCommitmentHistoryEnabled(tx, true)
+	}))
+	signer := types.LatestSignerForChainID(nil)
+	chainPack, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, 3, func(i int, b *core.BlockGen) {
+		for _, to := range []common.Address{recipient, counter} {
+			txn, err := types.SignTx(types.NewTransaction(b.TxNonce(bank), to, u256.Num1, 100_000, u256.Num1, nil), *signer, key)
+			require.NoError(t, err)
+			b.AddTx(txn)
+		}
+	})
+	require.NoError(t, err)
+	require.NoError(t, m.InsertChain(chainPack))
+
+	server := snap.NewServer(m.DB, m.BlockReader, log.New())
+	ctx := context.Background()
+	tx, err := m.DB.BeginTemporalRo(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	recipientHash, counterHash := crypto.Keccak256Hash(recipient[:]), crypto.Keccak256Hash(counter[:])
+
+	for i, block := range chainPack.Blocks {
+		root := block.Root()
+		res, err := server.AnswerGetAccountRangeQuery(ctx, tx, &snap.GetAccountRangePacket{Root: root, Origin: recipientHash, Limit: recipientHash, Bytes: 1 << 20})
+		require.NoError(t, err)
+		require.NotEmpty(t, res.Accounts, "block %d", block.NumberU64())
+		require.Equal(t, recipientHash, res.Accounts[0].Hash)
+		require.Equal(t, root, crypto.Keccak256Hash(res.Proof[0]))
+		var slim slimAccount
+		require.NoError(t, rlp.DecodeBytes(res.Accounts[0].Body, &slim))
+		require.Equal(t, uint256.NewInt(uint64(i+1)), slim.Balance)
+
+		slots, err := server.AnswerGetStorageRangesQuery(ctx, tx, &snap.GetStorageRangesPacket{Root: root, Accounts: []common.Hash{counterHash}, Bytes: 1 << 20})
+		require.NoError(t, err)
+		require.Len(t, slots.Slots, 1)
+		var value []byte
+		require.NoError(t, rlp.DecodeBytes(slots.Slots[0][0].Body, &value))
+		require.Equal(t, []byte{byte(i + 1)}, value)
+
+		nodes, err := server.AnswerGetTrieNodesQuery(ctx, tx, &snap.GetTrieNodesPacket{Root: root, Paths: []snap.TrieNodePathSet{{{0x00}}}, Bytes: 1 << 20})
+		require.NoError(t, err)
+		require.Equal(t, root, crypto.Keccak256Hash(nodes.Nodes[0]))
+	}
+}
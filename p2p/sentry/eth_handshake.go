@@ -19,6 +19,7 @@ package sentry
 import (
 	"fmt"
 
+	"github.com/erigontech/erigon-lib/direct"
 	"github.com/erigontech/erigon-lib/gointerfaces"
 	proto_sentry "github.com/erigontech/erigon-lib/gointerfaces/sentryproto"
 	"github.com/erigontech/erigon/p2p"
@@ -31,41 +32,66 @@ func readAndValidatePeerStatusMessage(
 	status *proto_sentry.StatusData,
 	version uint,
 	minVersion uint,
-) (*eth.StatusPacket, *p2p.PeerError) {
+) (*eth.StatusPacket, *eth.BlockRangeUpdatePacket, *p2p.PeerError) {
 	msg, err := rw.ReadMsg()
 	if err != nil {
-		return nil, p2p.NewPeerError(p2p.PeerErrorStatusReceive, p2p.DiscNetworkError, err, "readAndValidatePeerStatusMessage rw.ReadMsg error")
+		return nil, nil, p2p.NewPeerError(p2p.PeerErrorStatusReceive, p2p.DiscNetworkError, err, "readAndValidatePeerStatusMessage rw.ReadMsg error")
 	}
 
-	reply, err := tryDecodeStatusMessage(&msg)
+	reply, blockRange, err := tryDecodeStatusMessage(&msg, version)
 	msg.Discard()
 	if err != nil {
-		return nil, p2p.NewPeerError(p2p.PeerErrorStatusDecode, p2p.DiscProtocolError, err, "readAndValidatePeerStatusMessage tryDecodeStatusMessage error")
+		return nil, nil, p2p.NewPeerError(p2p.PeerErrorStatusDecode, p2p.DiscProtocolError, err, "readAndValidatePeerStatusMessage tryDecodeStatusMessage error")
 	}
 
 	err = checkPeerStatusCompatibility(reply, status, version, minVersion)
 	if err != nil {
-		return nil, p2p.NewPeerError(p2p.PeerErrorStatusIncompatible, p2p.DiscUselessPeer, err, "readAndValidatePeerStatusMessage checkPeerStatusCompatibility error")
+		return nil, nil, p2p.NewPeerError(p2p.PeerErrorStatusIncompatible, p2p.DiscUselessPeer, err, "readAndValidatePeerStatusMessage checkPeerStatusCompatibility error")
 	}
 
-	return reply, nil
+	return reply, blockRange, nil
 }
 
-func tryDecodeStatusMessage(msg *p2p.Msg) (*eth.StatusPacket, error) {
+// tryDecodeStatusMessage decodes the status of a peer. The eth/69 status is
+// returned as the status of the previous versions, with the latest block hash as
+// the head and without the total difficulty, along with the range of blocks the
+// peer serves.
+func tryDecodeStatusMessage(msg *p2p.Msg, version uint) (*eth.StatusPacket, *eth.BlockRangeUpdatePacket, error) {
 	if msg.Code != eth.StatusMsg {
-		return nil, fmt.Errorf("first msg has code %x (!= %x)", msg.Code, eth.StatusMsg)
+		return nil, nil, fmt.Errorf("first msg has code %x (!= %x)", msg.Code, eth.StatusMsg)
 	}
 
 	if msg.Size > eth.ProtocolMaxMsgSize {
-		return nil, fmt.Errorf("message is too large %d, limit %d", msg.Size, eth.ProtocolMaxMsgSize)
+		return nil, nil, fmt.Errorf("message is too large %d, limit %d", msg.Size, eth.ProtocolMaxMsgSize)
 	}
 
-	var reply eth.StatusPacket
-	if err := msg.Decode(&reply); err != nil {
-		return nil, fmt.Errorf("decode message %v: %w", msg, err)
+	if version < direct.ETH69 {
+		var reply eth.StatusPacket
+		if err := msg.Decode(&reply); err != nil {
+			return nil, nil, fmt.Errorf("decode message %v: %w", msg, err)
+		}
+		return &reply, nil, nil
 	}
 
-	return &reply, nil
+	var reply eth.StatusPacket69
+	if err := msg.Decode(&reply); err != nil {
+		return nil, nil, fmt.Errorf("decode message %v: %w", msg, err)
+	}
+	blockRange := &eth.BlockRangeUpdatePacket{
+		EarliestBlock:   reply.EarliestBlock,
+		LatestBlock:     reply.LatestBlock,
+		LatestBlockHash: reply.LatestBlockHash,
+	}
+	if err := blockRange.Validate(); err != nil {
+		return nil, nil, err
+	}
+	return &eth.StatusPacket{
+		ProtocolVersion: reply.ProtocolVersion,
+		NetworkID:       reply.NetworkID,
+		Head:            reply.LatestBlockHash,
+		Genesis:         reply.Genesis,
+		ForkID:          reply.ForkID,
+	}, blockRange, nil
 }
 
 func checkPeerStatusCompatibility(
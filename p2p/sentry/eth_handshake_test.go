@@ -17,17 +17,21 @@
 package sentry
 
 import (
+	"bytes"
 	"math/big"
 	"testing"
 
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/direct"
 	"github.com/erigontech/erigon-lib/gointerfaces"
 	proto_sentry "github.com/erigontech/erigon-lib/gointerfaces/sentryproto"
+	"github.com/erigontech/erigon-lib/rlp"
 	"github.com/erigontech/erigon/execution/chainspec"
+	"github.com/erigontech/erigon/p2p"
 	"github.com/erigontech/erigon/p2p/forkid"
 	"github.com/erigontech/erigon/p2p/protocols/eth"
 )
@@ -96,3 +100,42 @@ func TestCheckPeerStatusCompatibility(t *testing.T) {
 		assert.ErrorIs(t, err, forkid.ErrLocalIncompatibleOrStale)
 	})
 }
+
+func TestTryDecodeStatusMessage69(t *testing.T) {
+	networkID := chainspec.MainnetChainConfig.ChainID.Uint64()
+	heightForks, timeForks := forkid.GatherForks(chainspec.MainnetChainConfig, 0 /* genesisTime */)
+	goodStatus := eth.StatusPacket69{
+		ProtocolVersion: direct.ETH69,
+		NetworkID:       networkID,
+		Genesis:         chainspec.MainnetGenesisHash,
+		ForkID:          forkid.NewIDFromForks(heightForks, timeForks, chainspec.MainnetGenesisHash, 0, 0),
+		EarliestBlock:   10,
+		LatestBlock:     20,
+		LatestBlockHash: common.HexToHash("deadc0de"),
+	}
+	msg := func(status eth.StatusPacket69) *p2p.Msg {
+		data, err := rlp.EncodeToBytes(&status)
+		require.NoError(t, err)
+		return &p2p.Msg{Code: eth.StatusMsg, Size: uint32(len(data)), Payload: bytes.NewReader(data)}
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		reply, blockRange, err := tryDecodeStatusMessage(msg(goodStatus), direct.ETH69)
+		require.NoError(t, err)
+		assert.Equal(t, goodStatus.LatestBlockHash, reply.Head)
+		assert.Equal(t, goodStatus.ForkID, reply.ForkID)
+		assert.Equal(t, &eth.BlockRangeUpdatePacket{EarliestBlock: 10, LatestBlock: 20, LatestBlockHash: goodStatus.LatestBlockHash}, blockRange)
+	})
+	t.Run("invalid block range", func(t *testing.T) {
+		status := goodStatus
+		status.EarliestBlock = 21
+		_, _, err := tryDecodeStatusMessage(msg(status), direct.ETH69)
+		assert.ErrorContains(t, err, "invalid block range")
+	})
+	t.Run("eth68 status", func(t *testing.T) {
+		data, err := rlp.EncodeToBytes(&eth.StatusPacket{ProtocolVersion: direct.ETH68, NetworkID: networkID, TD: big.NewInt(1)})
+		require.NoError(t, err)
+		_, _, err = tryDecodeStatusMessage(&p2p.Msg{Code: eth.StatusMsg, Size: uint32(len(data)), Payload: bytes.NewReader(data)}, direct.ETH69)
+		assert.Error(t, err)
+	})
+}
@@ -58,6 +58,7 @@ import (
 	"github.com/erigontech/erigon/p2p/enode"
 	"github.com/erigontech/erigon/p2p/forkid"
 	"github.com/erigontech/erigon/p2p/protocols/eth"
+	"github.com/erigontech/erigon/p2p/protocols/snap"
 
 	_ "github.com/erigontech/erigon/polygon/chain" // Register Polygon chains
 )
@@ -67,6 +68,10 @@ const (
 	// complete before dropping the connection.= as malicious.
 	handshakeTimeout  = 5 * time.Second
 	maxPermitsPerPeer = 4 // How many outstanding requests per peer we may have
+
+	// blockRangeUpdateInterval is how many blocks the head advances before the
+	// range of blocks we serve is announced again to eth/69 peers.
+	blockRangeUpdateInterval = 32
 )
 
 // PeerInfo collects various extra bits of information about the peer,
@@ -77,6 +82,7 @@ type PeerInfo struct {
 	deadlines     []time.Time // Request deadlines
 	latestDealine time.Time
 	height        uint64
+	earliestBlock uint64 // The earliest block served by eth/69 peers
 	rw            p2p.MsgReadWriter
 	protocol      uint
 
@@ -197,6 +203,18 @@ func (pi *PeerInfo) SetIncreasedHeight(newHeight uint64) {
 	}
 }
 
+// EarliestBlock returns the earliest block the peer serves, it's only known for
+// eth/69 peers and zero otherwise.
+func (pi *PeerInfo) EarliestBlock() uint64 {
+	return atomic.LoadUint64(&pi.earliestBlock)
+}
+
+// SetBlockRange records the range of blocks an eth/69 peer serves.
+func (pi *PeerInfo) SetBlockRange(blockRange *eth.BlockRangeUpdatePacket) {
+	atomic.StoreUint64(&pi.earliestBlock, blockRange.EarliestBlock)
+	pi.SetIncreasedHeight(blockRange.LatestBlock)
+}
+
 // ClearDeadlines goes through the deadlines of
 // given peers and removes the ones that have passed
 // Optionally, it also clears one extra deadline - this is used when response is received
@@ -298,26 +316,40 @@ func handShake(
 	rw p2p.MsgReadWriter,
 	version uint,
 	minVersion uint,
-) (*common.Hash, *p2p.PeerError) {
+) (*common.Hash, *eth.BlockRangeUpdatePacket, *p2p.PeerError) {
 	// Send out own handshake in a new thread
 	errChan := make(chan *p2p.PeerError, 2)
 	resultChan := make(chan *eth.StatusPacket, 1)
+	blockRangeChan := make(chan *eth.BlockRangeUpdatePacket, 1)
 
 	ourTD := gointerfaces.ConvertH256ToUint256Int(status.TotalDifficulty)
 	// Convert proto status data into the one required by devp2p
 	genesisHash := gointerfaces.ConvertH256ToHash(status.ForkData.Genesis)
+	forkID := forkid.NewIDFromForks(status.ForkData.HeightForks, status.ForkData.TimeForks, genesisHash, status.MaxBlockHeight, status.MaxBlockTime)
 
 	go func() {
 		defer debug.LogPanic()
-		status := &eth.StatusPacket{
-			ProtocolVersion: uint32(version),
-			NetworkID:       status.NetworkId,
-			TD:              ourTD.ToBig(),
-			Head:            gointerfaces.ConvertH256ToHash(status.BestHash),
-			Genesis:         genesisHash,
-			ForkID:          forkid.NewIDFromForks(status.ForkData.HeightForks, status.ForkData.TimeForks, genesisHash, status.MaxBlockHeight, status.MaxBlockTime),
+		var err error
+		if version < direct.ETH69 {
+			err = p2p.Send(rw, eth.StatusMsg, &eth.StatusPacket{
+				ProtocolVersion: uint32(version),
+				NetworkID:       status.NetworkId,
+				TD:              ourTD.ToBig(),
+				Head:            gointerfaces.ConvertH256ToHash(status.BestHash),
+				Genesis:         genesisHash,
+				ForkID:          forkID,
+			})
+		} else {
+			err = p2p.Send(rw, eth.StatusMsg, &eth.StatusPacket69{
+				ProtocolVersion: uint32(version),
+				NetworkID:       status.NetworkId,
+				Genesis:         genesisHash,
+				ForkID:          forkID,
+				EarliestBlock:   status.MinimumBlockHeight,
+				LatestBlock:     status.MaxBlockHeight,
+				LatestBlockHash: gointerfaces.ConvertH256ToHash(status.BestHash),
+			})
 		}
-		err := p2p.Send(rw, eth.StatusMsg, status)
 
 		if err == nil {
 			errChan <- nil
@@ -328,10 +360,11 @@ func handShake(
 
 	go func() {
 		defer debug.LogPanic()
-		status, err := readAndValidatePeerStatusMessage(rw, status, version, minVersion)
+		status, blockRange, err := readAndValidatePeerStatusMessage(rw, status, version, minVersion)
 
 		if err == nil {
 			resultChan <- status
+			blockRangeChan <- blockRange
 			errChan <- nil
 		} else {
 			errChan <- err
@@ -344,17 +377,17 @@ func handShake(
 		select {
 		case err := <-errChan:
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 		case <-timeout.C:
-			return nil, p2p.NewPeerError(p2p.PeerErrorStatusHandshakeTimeout, p2p.DiscReadTimeout, nil, "sentry.handShake timeout")
+			return nil, nil, p2p.NewPeerError(p2p.PeerErrorStatusHandshakeTimeout, p2p.DiscReadTimeout, nil, "sentry.handShake timeout")
 		case <-ctx.Done():
-			return nil, p2p.NewPeerError(p2p.PeerErrorDiscReason, p2p.DiscQuitting, ctx.Err(), "sentry.handShake ctx.Done")
+			return nil, nil, p2p.NewPeerError(p2p.PeerErrorDiscReason, p2p.DiscQuitting, ctx.Err(), "sentry.handShake ctx.Done")
 		}
 	}
 
 	peerStatus := <-resultChan
-	return &peerStatus.Head, nil
+	return &peerStatus.Head, <-blockRangeChan, nil
 }
 
 func runPeer(
@@ -530,6 +563,19 @@ func runPeer(
 				logger.Error(fmt.Sprintf("%s: reading msg into bytes: %v", hex.EncodeToString(peerID[:]), err))
 			}
 			send(eth.ToProto[protocol][msg.Code], peerID, b)
+		case eth.BlockRangeUpdateMsg:
+			if protocol < direct.ETH69 {
+				logger.Error(fmt.Sprintf("[p2p] Unknown message code: %d, peerID=%v", msg.Code, hex.EncodeToString(peerID[:])))
+				break
+			}
+			var blockRange eth.BlockRangeUpdatePacket
+			if err := msg.Decode(&blockRange); err != nil {
+				return p2p.NewPeerError(p2p.PeerErrorInvalidMessage, p2p.DiscProtocolError, err, "sentry.runPeer: BlockRangeUpdate decode error")
+			}
+			if err := blockRange.Validate(); err != nil {
+				return p2p.NewPeerError(p2p.PeerErrorInvalidMessage, p2p.DiscProtocolError, err, "sentry.runPeer: invalid BlockRangeUpdate")
+			}
+			peerInfo.SetBlockRange(&blockRange)
 		case 11:
 			// Ignore
 			// TODO: Investigate why BSC peers for eth/67 send these messages
@@ -614,7 +660,7 @@ func NewGrpcServer(ctx context.Context, dialCandidates func() enode.Iterator, re
 	ss.Protocols = append(ss.Protocols, p2p.Protocol{
 		Name:           eth.ProtocolName,
 		Version:        protocol,
-		Length:         17,
+		Length:         eth.ProtocolLengths[protocol],
 		DialCandidates: disc,
 		Run: func(peer *p2p.Peer, rw p2p.MsgReadWriter) *p2p.PeerError {
 			peerID := peer.Pubkey()
@@ -636,10 +682,13 @@ func NewGrpcServer(ctx context.Context, dialCandidates func() enode.Iterator, re
 				return p2p.NewPeerError(p2p.PeerErrorLocalStatusNeeded, p2p.DiscProtocolError, nil, "could not get status message from core")
 			}
 
-			peerBestHash, err := handShake(ctx, status, rw, protocol, protocol)
+			peerBestHash, peerBlockRange, err := handShake(ctx, status, rw, protocol, protocol)
 			if err != nil {
 				return err
 			}
+			if peerBlockRange != nil {
+				peerInfo.SetBlockRange(peerBlockRange)
+			}
 
 			// handshake is successful
 			logger.Trace("[p2p] Received status message OK", "peerId", printablePeerID, "name", peer.Name())
@@ -678,6 +727,52 @@ func NewGrpcServer(ctx context.Context, dialCandidates func() enode.Iterator, re
 	return ss
 }
 
+// RegisterSnap serves the snap/1 protocol to the peers from server. It requires
+// the state of the node, so it's only available to sentries running in the
+// node, and must be called before the p2p server is started.
+func (ss *GrpcServer) RegisterSnap(server *snap.Server) {
+	ss.Protocols = append(ss.Protocols, p2p.Protocol{
+		Name:    snap.ProtocolName,
+		Version: snap.SNAP1,
+		Length:  snap.ProtocolLength,
+		Run: func(peer *p2p.Peer, rw p2p.MsgReadWriter) *p2p.PeerError {
+			return runSnapPeer(ss.ctx, rw, server)
+		},
+		NodeInfo: func() interface{} {
+			return nil
+		},
+		PeerInfo: func(peerID [64]byte) interface{} {
+			return nil
+		},
+	})
+}
+
+func runSnapPeer(ctx context.Context, rw p2p.MsgReadWriter, server *snap.Server) *p2p.PeerError {
+	limiter := snap.NewPeerLimiter()
+	for {
+		if err := common.Stopped(ctx.Done()); err != nil {
+			return p2p.NewPeerError(p2p.PeerErrorDiscReason, p2p.DiscQuitting, ctx.Err(), "sentry.runSnapPeer: context stopped")
+		}
+		msg, err := rw.ReadMsg()
+		if err != nil {
+			return p2p.NewPeerError(p2p.PeerErrorMessageReceive, p2p.DiscNetworkError, err, "sentry.runSnapPeer: ReadMsg error")
+		}
+		if msg.Size > snap.ProtocolMaxMsgSize {
+			msg.Discard()
+			return p2p.NewPeerError(p2p.PeerErrorMessageSizeLimit, p2p.DiscSubprotocolError, nil, fmt.Sprintf("sentry.runSnapPeer: message is too large %d, limit %d", msg.Size, snap.ProtocolMaxMsgSize))
+		}
+		err = server.HandleMessage(ctx, msg, rw, limiter)
+		msg.Discard()
+		if err != nil {
+			var peerErr *p2p.PeerError
+			if errors.As(err, &peerErr) {
+				return peerErr
+			}
+			return p2p.NewPeerError(p2p.PeerErrorMessageSend, p2p.DiscSubprotocolError, err, "sentry.runSnapPeer: failed to answer")
+		}
+	}
+}
+
 // Sentry creates and runs standalone sentry
 func Sentry(ctx context.Context, dirs datadir.Dirs, sentryAddr string, discoveryDNS []string, cfg *p2p.Config, protocolVersion uint, healthCheck bool, logger log.Logger) error {
 	dir.MustExist(dirs.DataDir)
@@ -712,6 +807,7 @@ type GrpcServer struct {
 	p2pServer            *p2p.Server
 	p2pServerLock        sync.RWMutex
 	statusData           *proto_sentry.StatusData
+	announcedBlockRange  *eth.BlockRangeUpdatePacket // The last range of blocks announced to eth/69 peers, guarded by statusDataLock
 	statusDataLock       sync.RWMutex
 	messageStreams       map[proto_sentry.MessageId]map[uint64]chan *proto_sentry.InboundMessage
 	messagesSubscriberID uint64
@@ -996,6 +1092,8 @@ func (ss *GrpcServer) HandShake(context.Context, *emptypb.Empty) (*proto_sentry.
 		reply.Protocol = proto_sentry.Protocol_ETH67
 	case direct.ETH68:
 		reply.Protocol = proto_sentry.Protocol_ETH68
+	case direct.ETH69:
+		reply.Protocol = proto_sentry.Protocol_ETH69
 	}
 	return reply, nil
 }
@@ -1058,10 +1156,45 @@ func (ss *GrpcServer) SetStatus(ctx context.Context, statusData *proto_sentry.St
 	if ss.statusData == nil || statusData.MaxBlockHeight != 0 {
 		// Not overwrite statusData if the message contains zero MaxBlock (comes from standalone transaction pool)
 		ss.statusData = statusData
+		ss.announceBlockRange(statusData)
 	}
 	return reply, nil
 }
 
+// announceBlockRange sends the range of blocks we serve to the eth/69 peers
+// when the earliest block changes or the head advanced by
+// blockRangeUpdateInterval blocks since the last announcement.
+func (ss *GrpcServer) announceBlockRange(statusData *proto_sentry.StatusData) {
+	if ss.Protocols[0].Version < direct.ETH69 {
+		return
+	}
+	blockRange := &eth.BlockRangeUpdatePacket{
+		EarliestBlock:   statusData.MinimumBlockHeight,
+		LatestBlock:     statusData.MaxBlockHeight,
+		LatestBlockHash: gointerfaces.ConvertH256ToHash(statusData.BestHash),
+	}
+	if blockRange.Validate() != nil {
+		return
+	}
+	if last := ss.announcedBlockRange; last != nil && last.EarliestBlock == blockRange.EarliestBlock &&
+		blockRange.LatestBlock >= last.LatestBlock && blockRange.LatestBlock < last.LatestBlock+blockRangeUpdateInterval {
+		return
+	}
+	ss.announcedBlockRange = blockRange
+
+	data, err := rlp.EncodeToBytes(blockRange)
+	if err != nil {
+		ss.logger.Error("[sentry] announceBlockRange encode failed", "err", err)
+		return
+	}
+	ss.rangePeers(func(peerInfo *PeerInfo) bool {
+		if peerInfo.protocol >= direct.ETH69 {
+			ss.writePeer("[sentry] announceBlockRange", peerInfo, eth.BlockRangeUpdateMsg, data, 0)
+		}
+		return true
+	})
+}
+
 func (ss *GrpcServer) Peers(_ context.Context, _ *emptypb.Empty) (*proto_sentry.PeersReply, error) {
 	p2pServer := ss.getP2PServer()
 	if p2pServer == nil {
@@ -79,7 +79,7 @@ func startHandshake(
 	errChan chan *p2p.PeerError,
 ) {
 	go func() {
-		_, err := handShake(ctx, status, pipe, protocolVersion, protocolVersion)
+		_, _, err := handShake(ctx, status, pipe, protocolVersion, protocolVersion)
 		errChan <- err
 	}()
 }
@@ -87,6 +87,7 @@ func startHandshake(
 // Tests that peers are correctly accepted (or rejected) based on the advertised
 // fork IDs in the protocol handshake.
 func TestForkIDSplit67(t *testing.T) { testForkIDSplit(t, direct.ETH67) }
+func TestForkIDSplit69(t *testing.T) { testForkIDSplit(t, direct.ETH69) }
 
 func testForkIDSplit(t *testing.T, protocol uint) {
 	var (
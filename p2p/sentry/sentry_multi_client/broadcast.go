@@ -25,8 +25,10 @@ import (
 
 	"google.golang.org/grpc"
 
+	"github.com/erigontech/erigon-lib/direct"
 	proto_sentry "github.com/erigontech/erigon-lib/gointerfaces/sentryproto"
 	"github.com/erigontech/erigon-lib/log/v3"
+	libsentry "github.com/erigontech/erigon-lib/p2p/sentry"
 	"github.com/erigontech/erigon-lib/rlp"
 	"github.com/erigontech/erigon-lib/types"
 	"github.com/erigontech/erigon/execution/stages/headerdownload"
@@ -56,6 +58,9 @@ func (cs *MultiClient) PropagateNewBlockHashes(ctx context.Context, announces []
 		if ready, ok := sentry.(interface{ Ready() bool }); ok && !ready.Ready() {
 			continue
 		}
+		if !carriesMessage(sentry, req66.Id) {
+			continue
+		}
 
 		_, err = sentry.SendMessageToAll(ctx, &req66, &grpc.EmptyCallOption{})
 		if err != nil {
@@ -93,6 +98,9 @@ func (cs *MultiClient) BroadcastNewBlock(ctx context.Context, header *types.Head
 		if ready, ok := sentry.(interface{ Ready() bool }); ok && !ready.Ready() {
 			continue
 		}
+		if !carriesMessage(sentry, req66.Data.Id) {
+			continue
+		}
 
 		_, err = sentry.SendMessageToRandomPeers(ctx, &req66, &grpc.EmptyCallOption{})
 		if err != nil {
@@ -105,6 +113,17 @@ func (cs *MultiClient) BroadcastNewBlock(ctx context.Context, header *types.Head
 	}
 }
 
+// carriesMessage reports whether the protocol of the sentry has the message,
+// e.g. eth/69 dropped the block announcements.
+func carriesMessage(sentry proto_sentry.SentryClient, id proto_sentry.MessageId) bool {
+	directSentry, ok := sentry.(direct.SentryClient)
+	if !ok {
+		return true
+	}
+	_, ok = libsentry.ProtoIds[proto_sentry.Protocol(directSentry.Protocol()-direct.ETH65)][id]
+	return ok
+}
+
 func networkTemporaryErr(err error) bool {
 	return errors.Is(err, syscall.EPIPE) || errors.Is(err, p2p.ErrShuttingDown)
 }
@@ -33,6 +33,7 @@ import (
 	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon-lib/types"
 	"github.com/erigontech/erigon/p2p/forkid"
+	"github.com/erigontech/erigon/turbo/services"
 )
 
 var ErrNoHead = errors.New("ReadChainHead: ReadCurrentHeader error")
@@ -45,7 +46,8 @@ type ChainHead struct {
 }
 
 type StatusDataProvider struct {
-	db kv.RoDB
+	db          kv.RoDB
+	blockReader services.FullBlockReader
 
 	networkId   uint64
 	genesisHash common.Hash
@@ -58,6 +60,7 @@ type StatusDataProvider struct {
 
 func NewStatusDataProvider(
 	db kv.RoDB,
+	blockReader services.FullBlockReader,
 	chainConfig *chain.Config,
 	genesis *types.Block,
 	networkId uint64,
@@ -65,6 +68,7 @@ func NewStatusDataProvider(
 ) *StatusDataProvider {
 	s := &StatusDataProvider{
 		db:          db,
+		blockReader: blockReader,
 		networkId:   networkId,
 		genesisHash: genesis.Hash(),
 		genesisHead: makeGenesisChainHead(genesis),
@@ -104,11 +108,12 @@ func makeGenesisChainHead(genesis *types.Block) ChainHead {
 
 func (s *StatusDataProvider) makeStatusData(head ChainHead) *proto_sentry.StatusData {
 	return &proto_sentry.StatusData{
-		NetworkId:       s.networkId,
-		TotalDifficulty: gointerfaces.ConvertUint256IntToH256(head.HeadTd),
-		BestHash:        gointerfaces.ConvertHashToH256(head.HeadHash),
-		MaxBlockHeight:  head.HeadHeight,
-		MaxBlockTime:    head.HeadTime,
+		NetworkId:          s.networkId,
+		TotalDifficulty:    gointerfaces.ConvertUint256IntToH256(head.HeadTd),
+		BestHash:           gointerfaces.ConvertHashToH256(head.HeadHash),
+		MaxBlockHeight:     head.HeadHeight,
+		MaxBlockTime:       head.HeadTime,
+		MinimumBlockHeight: s.earliestBlock(),
 		ForkData: &proto_sentry.Forks{
 			Genesis:     gointerfaces.ConvertHashToH256(s.genesisHash),
 			HeightForks: s.heightForks,
@@ -117,6 +122,20 @@ func (s *StatusDataProvider) makeStatusData(head ChainHead) *proto_sentry.Status
 	}
 }
 
+// earliestBlock returns the earliest block we serve to peers: the first block of
+// the block snapshots, which don't start at genesis when older blocks were
+// skipped (see --prune.mode=minimal). Blocks past the snapshots are in the db.
+func (s *StatusDataProvider) earliestBlock() uint64 {
+	if s.blockReader == nil {
+		return 0
+	}
+	ranges := s.blockReader.Snapshots().Ranges()
+	if len(ranges) == 0 {
+		return 0
+	}
+	return ranges[0].From()
+}
+
 func (s *StatusDataProvider) GetStatusData(ctx context.Context) (*proto_sentry.StatusData, error) {
 	chainHead, err := ReadChainHead(ctx, s.db)
 	if err != nil {
@@ -81,6 +81,7 @@ type Client struct {
 	isHTTP          bool
 	services        *serviceRegistry
 	methodAllowList AllowList
+	rateLimiter     *RateLimiter
 	batchLimit      int // batch size limit
 
 	idCounter uint32
@@ -119,6 +120,7 @@ func (c *Client) newClientConn(conn ServerCodec) *clientConn {
 	ctx := context.WithValue(context.Background(), clientContextKey{}, c)
 	ctx = context.WithValue(ctx, peerInfoContextKey{}, conn.peerInfo())
 	handler := newHandler(ctx, conn, c.idgen, c.services, c.methodAllowList, 50, false /* traceRequests */, c.logger, 0)
+	handler.rateLimiter = c.rateLimiter
 	return &clientConn{conn, handler}
 }
 
@@ -203,18 +205,19 @@ func newClient(initctx context.Context, connect reconnectFunc, logger log.Logger
 	if err != nil {
 		return nil, err
 	}
-	c := initClient(conn, randomIDGenerator(), &serviceRegistry{logger: logger}, 0, logger)
+	c := initClient(conn, randomIDGenerator(), &serviceRegistry{logger: logger}, 0, nil, logger)
 	c.reconnectFunc = connect
 	return c, nil
 }
 
-func initClient(conn ServerCodec, idgen func() ID, services *serviceRegistry, batchLimit int, logger log.Logger) *Client {
+func initClient(conn ServerCodec, idgen func() ID, services *serviceRegistry, batchLimit int, rateLimiter *RateLimiter, logger log.Logger) *Client {
 	_, isHTTP := conn.(*httpConn)
 	c := &Client{
 		idgen:       idgen,
 		isHTTP:      isHTTP,
 		services:    services,
 		batchLimit:  batchLimit,
+		rateLimiter: rateLimiter,
 		writeConn:   conn,
 		close:       make(chan struct{}),
 		closing:     make(chan struct{}),
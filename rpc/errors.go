@@ -19,7 +19,10 @@
 
 package rpc
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 var (
 	_ Error = new(methodNotFoundError)
@@ -29,6 +32,9 @@ var (
 	_ Error = new(invalidMessageError)
 	_ Error = new(InvalidParamsError)
 	_ Error = new(CustomError)
+	_ Error = new(RateLimitedError)
+
+	_ DataError = new(RateLimitedError)
 )
 
 const defaultErrorCode = -32000
@@ -92,3 +98,30 @@ type CustomError struct {
 func (e *CustomError) ErrorCode() int { return e.Code }
 
 func (e *CustomError) Error() string { return e.Message }
+
+// RateLimitedError is returned when a call doesn't fit the rate limit of the
+// client. RetryAfter tells the client how long to back off, it's zero when the
+// method costs more than the client can ever spend at once.
+type RateLimitedError struct {
+	Method     string
+	RetryAfter time.Duration
+	Cost       int
+	Burst      int
+}
+
+// ErrorCode is the "limit exceeded" code of EIP-1474.
+func (e *RateLimitedError) ErrorCode() int { return -32005 }
+
+func (e *RateLimitedError) Error() string {
+	if e.RetryAfter == 0 {
+		return fmt.Sprintf("rate limit exceeded: %s costs %d, more than the limit of %d", e.Method, e.Cost, e.Burst)
+	}
+	return fmt.Sprintf("rate limit exceeded, retry after %s", e.RetryAfter.Round(time.Millisecond))
+}
+
+func (e *RateLimitedError) ErrorData() interface{} {
+	if e.RetryAfter == 0 {
+		return nil
+	}
+	return map[string]interface{}{"retryAfter": e.RetryAfter.Seconds()}
+}
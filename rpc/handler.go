@@ -72,6 +72,7 @@ type handler struct {
 
 	allowList     AllowList // a list of explicitly allowed methods, if empty -- everything is allowed
 	forbiddenList ForbiddenList
+	rateLimiter   *RateLimiter // per-client limits, nil if calls aren't limited
 
 	subLock             sync.Mutex
 	serverSubs          map[ID]*Subscription
@@ -476,6 +477,11 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage, stream jsonstrea
 	if err != nil {
 		return msg.errorResponse(&InvalidParamsError{err.Error()})
 	}
+	if h.rateLimiter != nil && callb != h.unsubscribeCb {
+		if err := h.rateLimiter.allow(cp.ctx, msg.Method); err != nil {
+			return msg.errorResponse(err)
+		}
+	}
 	start := time.Now()
 	answer := h.runMethod(cp.ctx, msg, callb, args, stream)
 
@@ -516,6 +522,12 @@ func (h *handler) handleSubscribe(cp *callProc, msg *jsonrpcMessage, stream json
 	}
 	args = args[1:]
 
+	if h.rateLimiter != nil {
+		if err := h.rateLimiter.allow(cp.ctx, namespace+"_"+name); err != nil {
+			return msg.errorResponse(err)
+		}
+	}
+
 	// Install notifier in context so the subscription handler can find it.
 	n := &RemoteNotifier{h: h, namespace: namespace}
 	cp.notifiers = append(cp.notifiers, n)
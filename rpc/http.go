@@ -254,6 +254,7 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	connInfo.HTTP.Host = r.Host
 	connInfo.HTTP.Origin = r.Header.Get("Origin")
 	connInfo.HTTP.UserAgent = r.Header.Get("User-Agent")
+	connInfo.JWTSubject, _ = r.Context().Value(jwtSubjectContextKey{}).(string)
 	ctx := r.Context()
 	ctx = context.WithValue(ctx, peerInfoContextKey{}, connInfo)
 
@@ -316,7 +317,12 @@ func validateRequest(r *http.Request) (int, error) {
 	return http.StatusUnsupportedMediaType, err
 }
 
-func CheckJwtSecret(w http.ResponseWriter, r *http.Request, jwtSecret []byte) bool {
+type jwtSubjectContextKey struct{}
+
+// CheckJwtSecret checks the JWT of the request and responds with an error if it's not valid. Valid JWT gives the
+// context to pass the request on with (r.WithContext), which makes the subject of the JWT known to the handlers,
+// e.g. for rate limiting.
+func CheckJwtSecret(w http.ResponseWriter, r *http.Request, jwtSecret []byte) (context.Context, bool) {
 	var tokenStr string
 	// Check if JWT signature is correct
 	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
@@ -325,7 +331,7 @@ func CheckJwtSecret(w http.ResponseWriter, r *http.Request, jwtSecret []byte) bo
 
 	if len(tokenStr) == 0 {
 		http.Error(w, "missing token", http.StatusForbidden)
-		return false
+		return nil, false
 	}
 
 	keyFunc := func(token *jwt.Token) (interface{}, error) {
@@ -353,8 +359,11 @@ func CheckJwtSecret(w http.ResponseWriter, r *http.Request, jwtSecret []byte) bo
 	case time.Until(claims.IssuedAt.Time) > jwtTokenExpiry:
 		http.Error(w, "future token", http.StatusForbidden)
 	default:
-		return true
+		if claims.Subject != "" {
+			return context.WithValue(r.Context(), jwtSubjectContextKey{}, claims.Subject), true
+		}
+		return r.Context(), true
 	}
 
-	return false
+	return nil, false
 }
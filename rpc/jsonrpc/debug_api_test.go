@@ -40,6 +40,7 @@ import (
 	"github.com/erigontech/erigon-lib/kv/stream"
 	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon/cmd/rpcdaemon/cli/httpcfg"
 	"github.com/erigontech/erigon/cmd/rpcdaemon/rpcdaemontest"
 	"github.com/erigontech/erigon/eth/ethconfig"
 	tracersConfig "github.com/erigontech/erigon/eth/tracers/config"
@@ -78,7 +79,7 @@ func TestTraceBlockByNumber(t *testing.T) {
 	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
 	baseApi := NewBaseApi(nil, stateCache, m.BlockReader, false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs, nil)
 	ethApi := NewEthAPI(baseApi, m.DB, nil, nil, nil, 5000000, ethconfig.Defaults.RPCTxFeeCap, 100_000, false, 100_000, 128, log.New())
-	api := NewPrivateDebugAPI(baseApi, m.DB, 0)
+	api := NewPrivateDebugAPI(baseApi, m.DB, 0, 0)
 	for _, tt := range debugTraceTransactionTests {
 		var buf bytes.Buffer
 		s := jsonstream.New(jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096))
@@ -129,7 +130,7 @@ func TestTraceBlockByNumber(t *testing.T) {
 func TestTraceBlockByHash(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
 	ethApi := NewEthAPI(newBaseApiForTest(m), m.DB, nil, nil, nil, 5000000, ethconfig.Defaults.RPCTxFeeCap, 100_000, false, 100_000, 128, log.New())
-	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, 0)
 	for _, tt := range debugTraceTransactionTests {
 		var buf bytes.Buffer
 		s := jsonstream.New(jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096))
@@ -160,7 +161,7 @@ func TestTraceBlockByHash(t *testing.T) {
 
 func TestTraceTransaction(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
-	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, 0)
 	for _, tt := range debugTraceTransactionTests {
 		var buf bytes.Buffer
 		s := jsonstream.New(jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096))
@@ -189,7 +190,7 @@ func TestTraceTransaction(t *testing.T) {
 
 func TestTraceTransactionNoRefund(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
-	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, 0)
 	for _, tt := range debugTraceTransactionNoRefundTests {
 		var buf bytes.Buffer
 		s := jsonstream.New(jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096))
@@ -217,9 +218,30 @@ func TestTraceTransactionNoRefund(t *testing.T) {
 	}
 }
 
+func TestTraceTransactionFlatCallTracer(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, 0)
+	traceApi := NewTraceAPI(newBaseApiForTest(m), m.DB, &httpcfg.HttpCfg{})
+	tracer := "flatCallTracer"
+	for _, tt := range debugTraceTransactionTests {
+		var buf bytes.Buffer
+		s := jsonstream.New(jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096))
+		err := api.TraceTransaction(m.Ctx, common.HexToHash(tt.txHash), &tracersConfig.TraceConfig{Tracer: &tracer}, s)
+		require.NoError(t, err)
+		require.NoError(t, s.Flush())
+
+		// the output of flatCallTracer is the one of trace_transaction
+		traces, err := traceApi.Transaction(m.Ctx, common.HexToHash(tt.txHash), new(bool), &tracersConfig.TraceConfig{})
+		require.NoError(t, err)
+		expected, err := json.Marshal(traces)
+		require.NoError(t, err)
+		require.JSONEq(t, string(expected), buf.String())
+	}
+}
+
 func TestStorageRangeAt(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
-	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, 0)
 	t.Run("invalid addr", func(t *testing.T) {
 		var block4 *types.Block
 		var err error
@@ -313,7 +335,7 @@ func TestStorageRangeAt(t *testing.T) {
 
 func TestAccountRange(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
-	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, 0)
 
 	t.Run("valid account", func(t *testing.T) {
 		addr := common.HexToAddress("0x537e697c7ab75a26f9ecf0ce810e3154dfcaaf55")
@@ -372,7 +394,7 @@ func TestAccountRange(t *testing.T) {
 
 func TestGetModifiedAccountsByNumber(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
-	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, 0)
 
 	t.Run("correct input", func(t *testing.T) {
 		n, n2 := rpc.BlockNumber(1), rpc.BlockNumber(2)
@@ -471,7 +493,7 @@ func TestMapTxNum2BlockNum(t *testing.T) {
 
 func TestAccountAt(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
-	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, 0)
 
 	var blockHash0, blockHash1, blockHash3, blockHash10, blockHash12 common.Hash
 	_ = m.DB.View(m.Ctx, func(tx kv.Tx) error {
@@ -534,7 +556,7 @@ func TestAccountAt(t *testing.T) {
 
 func TestGetBadBlocks(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
-	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 5000000)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 5000000, 0)
 	ctx := context.Background()
 
 	require := require.New(t)
@@ -599,7 +621,7 @@ func TestGetBadBlocks(t *testing.T) {
 
 func TestGetRawTransaction(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
-	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 5000000)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 5000000, 0)
 	ctx := context.Background()
 
 	require := require.New(t)
@@ -636,3 +658,50 @@ func TestGetRawTransaction(t *testing.T) {
 	}
 	require.True(testedOnce, "Test flow didn't touch the target flow")
 }
+
+func TestTraceChain(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	baseApi := NewBaseApi(nil, stateCache, m.BlockReader, false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs, nil)
+	api := NewPrivateDebugAPI(baseApi, m.DB, 0, 0)
+
+	srv := rpc.NewServer(50, false, false, false, log.New(), 0)
+	defer srv.Stop()
+	require.NoError(t, srv.RegisterName("debug", api))
+	client := rpc.DialInProc(srv, log.New())
+	defer client.Close()
+
+	tx, err := m.DB.BeginRo(m.Ctx)
+	require.NoError(t, err)
+	latest := rawdb.ReadCurrentHeader(tx).Number.Uint64()
+	tx.Rollback()
+
+	results := make(chan blockTraceResult)
+	sub, err := client.Subscribe(m.Ctx, "debug", results, "traceChain", rpc.BlockNumber(1), rpc.LatestBlockNumber, &tracersConfig.TraceConfig{})
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	for number := uint64(1); number <= latest; number++ {
+		var res blockTraceResult
+		select {
+		case res = <-results:
+		case err := <-sub.Err():
+			t.Fatalf("subscription failed: %v", err)
+		}
+		require.Empty(t, res.Error)
+		require.Equal(t, number, uint64(res.Block))
+
+		var buf bytes.Buffer
+		s := jsonstream.New(jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096))
+		require.NoError(t, api.TraceBlockByNumber(m.Ctx, rpc.BlockNumber(number), &tracersConfig.TraceConfig{}, s))
+		require.NoError(t, s.Flush())
+		require.JSONEq(t, buf.String(), string(res.Traces))
+	}
+
+	_, err = client.Subscribe(m.Ctx, "debug", results, "traceChain", rpc.BlockNumber(2), rpc.BlockNumber(1), &tracersConfig.TraceConfig{})
+	require.ErrorContains(t, err, "invalid block range")
+
+	api.maxTraceChainBlocks = 2
+	_, err = client.Subscribe(m.Ctx, "debug", results, "traceChain", rpc.BlockNumber(1), rpc.BlockNumber(3), &tracersConfig.TraceConfig{})
+	require.ErrorContains(t, err, "block range too large")
+}
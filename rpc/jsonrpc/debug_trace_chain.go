@@ -0,0 +1,224 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/debug"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/jsonstream"
+	"github.com/erigontech/erigon-lib/log/v3"
+	tracersConfig "github.com/erigontech/erigon/eth/tracers/config"
+	"github.com/erigontech/erigon/rpc"
+	"github.com/erigontech/erigon/rpc/rpchelper"
+)
+
+// traceChainMaxWorkers caps the number of blocks traced concurrently by one
+// debug_traceChain subscription.
+const traceChainMaxWorkers = 8
+
+// blockTraceResult is a notification of the debug_traceChain subscription.
+type blockTraceResult struct {
+	Block  hexutil.Uint64  `json:"block"`
+	Hash   common.Hash     `json:"hash"`
+	Traces json.RawMessage `json:"traces,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// traceChainJob is a block to be traced by one of the workers, its result is
+// sent to the job's own channel so that it can be delivered in order.
+type traceChainJob struct {
+	number uint64
+	result chan *blockTraceResult
+}
+
+// TraceChain implements debug_subscribe("traceChain", start, end, config). It
+// traces all blocks in [start, end] with the configured tracer and notifies
+// the result of every block, in order, as soon as it is available. Blocks are
+// traced by a pool of workers, each with its own read transaction. The
+// subscription ends after the last block or after the first block that
+// fails to be traced. Ranges longer than --trace.chain.maxblocks are rejected.
+func (api *DebugAPIImpl) TraceChain(ctx context.Context, start, end rpc.BlockNumber, config *tracersConfig.TraceConfig) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	from, to, err := api.traceChainRange(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		config = &tracersConfig.TraceConfig{}
+	}
+	if config.BorTraceEnabled == nil {
+		var disabled bool
+		config.BorTraceEnabled = &disabled
+	}
+
+	workers := min(uint64(traceChainMaxWorkers), uint64(runtime.GOMAXPROCS(0)), to-from+1)
+
+	rpcSub := notifier.CreateSubscription()
+
+	traceCtx, cancel := context.WithCancel(context.Background())
+	jobs := make(chan traceChainJob)
+	// pending bounds how far ahead of the notified block the workers can get
+	pending := make(chan traceChainJob, 2*workers)
+
+	var wg sync.WaitGroup
+	for i := uint64(0); i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer debug.LogPanic()
+			defer wg.Done()
+			for job := range jobs {
+				job.result <- api.traceChainBlock(traceCtx, job.number, config)
+			}
+		}()
+	}
+
+	go func() {
+		defer debug.LogPanic()
+		defer close(jobs)
+		defer close(pending)
+		for number := from; number <= to; number++ {
+			job := traceChainJob{number: number, result: make(chan *blockTraceResult, 1)}
+			select {
+			case pending <- job:
+			case <-traceCtx.Done():
+				return
+			}
+			select {
+			case jobs <- job:
+			case <-traceCtx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		defer debug.LogPanic()
+		defer func() {
+			cancel()
+			wg.Wait()
+		}()
+		for job := range pending {
+			var res *blockTraceResult
+			select {
+			case res = <-job.result:
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+			if err := notifier.Notify(rpcSub.ID, res); err != nil {
+				log.Warn("[rpc] error while notifying subscription", "err", err)
+				return
+			}
+			if res.Error != "" {
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// traceChainRange resolves the block range of debug_traceChain.
+func (api *DebugAPIImpl) traceChainRange(ctx context.Context, start, end rpc.BlockNumber) (uint64, uint64, error) {
+	tx, err := api.db.BeginTemporalRo(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	from, _, _, err := rpchelper.GetBlockNumber(ctx, rpc.BlockNumberOrHashWithNumber(start), tx, api._blockReader, api.filters)
+	if err != nil {
+		return 0, 0, err
+	}
+	to, _, _, err := rpchelper.GetBlockNumber(ctx, rpc.BlockNumberOrHashWithNumber(end), tx, api._blockReader, api.filters)
+	if err != nil {
+		return 0, 0, err
+	}
+	if from > to {
+		return 0, 0, fmt.Errorf("invalid block range: start %d is greater than end %d", from, to)
+	}
+	if api.maxTraceChainBlocks > 0 && to-from+1 > api.maxTraceChainBlocks {
+		return 0, 0, fmt.Errorf("block range too large: %d blocks, max is %d", to-from+1, api.maxTraceChainBlocks)
+	}
+	if err := api.BaseAPI.checkPruneHistory(ctx, tx, from); err != nil {
+		return 0, 0, err
+	}
+	return from, to, nil
+}
+
+// traceChainBlock traces the canonical block with the given number in a read
+// transaction of its own.
+func (api *DebugAPIImpl) traceChainBlock(ctx context.Context, number uint64, config *tracersConfig.TraceConfig) *blockTraceResult {
+	res := &blockTraceResult{Block: hexutil.Uint64(number)}
+	if err := ctx.Err(); err != nil {
+		res.Error = err.Error()
+		return res
+	}
+
+	tx, err := api.db.BeginTemporalRo(ctx)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	defer tx.Rollback()
+
+	hash, ok, err := api._blockReader.CanonicalHash(ctx, tx, number)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	if !ok {
+		res.Error = fmt.Sprintf("block %d not found", number)
+		return res
+	}
+	res.Hash = hash
+	block, err := api.blockWithSenders(ctx, tx, hash, number)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	if block == nil {
+		res.Error = fmt.Sprintf("block %d not found", number)
+		return res
+	}
+
+	var buf bytes.Buffer
+	stream := jsonstream.New(&buf)
+	if err := api.traceBlockWithTx(ctx, tx, block, config, stream); err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	if err := stream.Flush(); err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.Traces = buf.Bytes()
+	return res
+}
@@ -99,10 +99,16 @@ func (api *APIImpl) GetCode(ctx context.Context, address common.Address, blockNr
 	}
 
 	acc, err := reader.ReadAccountData(address)
-	if acc == nil || err != nil || acc.IsEmptyCodeHash() {
+	if err != nil {
+		return nil, err
+	}
+	if acc == nil || acc.IsEmptyCodeHash() {
 		return hexutil.Bytes(""), nil
 	}
-	res, _ := reader.ReadAccountCode(address)
+	res, err := reader.ReadAccountCode(address)
+	if err != nil {
+		return nil, err
+	}
 	if res == nil {
 		return hexutil.Bytes(""), nil
 	}
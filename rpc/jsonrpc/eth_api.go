@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
 	"reflect"
 	"sync"
@@ -42,6 +43,7 @@ import (
 	"github.com/erigontech/erigon-lib/types"
 	"github.com/erigontech/erigon-lib/types/accounts"
 	"github.com/erigontech/erigon/core"
+	"github.com/erigontech/erigon/core/state"
 	"github.com/erigontech/erigon/eth/filters"
 	"github.com/erigontech/erigon/execution/consensus"
 	"github.com/erigontech/erigon/execution/consensus/misc"
@@ -114,6 +116,7 @@ type EthAPI interface {
 	SignTransaction(_ context.Context, txObject interface{}) (common.Hash, error)
 	GetProof(ctx context.Context, address common.Address, storageKeys []hexutil.Bytes, blockNr rpc.BlockNumberOrHash) (*accounts.AccProofResult, error)
 	CreateAccessList(ctx context.Context, args ethapi.CallArgs, blockNrOrHash *rpc.BlockNumberOrHash, optimizeGas *bool) (*accessListResult, error)
+	SimulateV1(ctx context.Context, req SimulationRequest, blockNrOrHash *rpc.BlockNumberOrHash) ([]map[string]interface{}, error)
 
 	// Mining related (see ./eth_mining.go)
 	Coinbase(ctx context.Context) (common.Address, error)
@@ -394,6 +397,36 @@ func (api *BaseAPI) checkPruneHistory(ctx context.Context, tx kv.Tx, block uint6
 	return nil
 }
 
+// checkPruneHistoryOf - checks that indices of given accounts are kept for the block: with --prune.history.watch
+// history before the horizon is kept only for watched accounts. No accounts - means query of all accounts.
+// State reads are checked by the history reader itself.
+func (api *BaseAPI) checkPruneHistoryOf(tx kv.Tx, block uint64, addrs ...common.Address) error {
+	horizon, err := prune.GetWatchedHorizon(tx)
+	if err != nil || horizon == 0 {
+		return err
+	}
+	fromTxNum, err := api._txNumReader.Min(tx, block)
+	if err != nil {
+		return err
+	}
+	if fromTxNum >= horizon {
+		return nil
+	}
+	if len(addrs) == 0 {
+		return fmt.Errorf("%w: history before txNum %d is kept only for --prune.history.watch accounts, query must be limited to them", state.PrunedError, horizon)
+	}
+	watched, err := prune.GetWatched(tx)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if !prune.IsWatched(watched, addr) {
+			return fmt.Errorf("%w: history before txNum %d is kept only for --prune.history.watch accounts, %s is not watched", state.PrunedError, horizon, addr)
+		}
+	}
+	return nil
+}
+
 func (api *BaseAPI) pruneMode(tx kv.Tx) (*prune.Mode, error) {
 	p := api._pruneMode.Load()
 	if p != nil {
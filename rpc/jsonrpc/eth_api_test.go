@@ -19,6 +19,8 @@ package jsonrpc
 import (
 	"context"
 	"fmt"
+	"math/big"
+	"slices"
 	"testing"
 	"time"
 
@@ -29,12 +31,16 @@ import (
 	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/datadir"
 	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/kv/kvcache"
+	"github.com/erigontech/erigon-lib/kv/prune"
 	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon-lib/types"
 	"github.com/erigontech/erigon/cmd/rpcdaemon/rpcdaemontest"
 	"github.com/erigontech/erigon/core"
+	"github.com/erigontech/erigon/core/state"
 	"github.com/erigontech/erigon/eth/ethconfig"
+	"github.com/erigontech/erigon/eth/filters"
 	"github.com/erigontech/erigon/execution/stages/mock"
 	"github.com/erigontech/erigon/rpc"
 	"github.com/erigontech/erigon/rpc/ethapi"
@@ -69,6 +75,56 @@ func TestGetBalanceChangesInBlock(t *testing.T) {
 	}
 }
 
+// setWatchedHistory - emulates --prune.history.watch: history before block `horizon` is kept only for watched accounts
+func setWatchedHistory(t *testing.T, m *mock.MockSentry, horizon uint64, watched ...common.Address) {
+	t.Helper()
+	tx, err := m.DB.BeginRw(m.Ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	horizonTxNum, err := m.BlockReader.TxnumReader(m.Ctx).Min(tx, horizon)
+	require.NoError(t, err)
+	slices.SortFunc(watched, func(a, b common.Address) int { return a.Cmp(b) })
+	var v []byte
+	for _, addr := range watched {
+		v = append(v, addr.Bytes()...)
+	}
+	require.NoError(t, tx.Put(kv.DatabaseInfo, kv.PruneWatched, v))
+	require.NoError(t, prune.SetWatchedHorizon(tx, horizonTxNum))
+	require.NoError(t, tx.Commit())
+}
+
+func TestGetBalance_WatchedHistory(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	watched, other := common.HexToAddress("0x71562b71999873db5b286df957af199ec94617f7"), common.HexToAddress("0x703c4b2bD70c169f5717101CaeE543299Fc946C7")
+	setWatchedHistory(t, m, 5, watched)
+	api := NewEthAPI(newBaseApiForTest(m), m.DB, nil, nil, nil, 5000000, ethconfig.Defaults.RPCTxFeeCap, 100_000, false, 100_000, 128, log.New())
+	ctx := context.Background()
+
+	_, err := api.GetBalance(ctx, watched, rpc.BlockNumberOrHashWithNumber(0))
+	require.NoError(t, err)
+	_, err = api.GetBalance(ctx, other, rpc.BlockNumberOrHashWithNumber(0))
+	require.ErrorIs(t, err, state.PrunedError)
+	_, err = api.GetTransactionCount(ctx, other, rpc.BlockNumberOrHashWithNumber(0))
+	require.ErrorIs(t, err, state.PrunedError)
+	_, err = api.GetCode(ctx, other, rpc.BlockNumberOrHashWithNumber(0))
+	require.ErrorIs(t, err, state.PrunedError)
+	_, err = api.GetStorageAt(ctx, other, "0x0", rpc.BlockNumberOrHashWithNumber(0))
+	require.ErrorIs(t, err, state.PrunedError)
+	_, err = api.GetBalance(ctx, other, rpc.BlockNumberOrHashWithNumber(5))
+	require.NoError(t, err)
+	_, err = api.GetBalance(ctx, other, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+	require.NoError(t, err)
+
+	_, err = api.GetLogs(ctx, filters.FilterCriteria{FromBlock: common.Big0})
+	require.ErrorIs(t, err, state.PrunedError, "logs of all accounts")
+	_, err = api.GetLogs(ctx, filters.FilterCriteria{FromBlock: common.Big0, Addresses: []common.Address{other}})
+	require.ErrorIs(t, err, state.PrunedError)
+	_, err = api.GetLogs(ctx, filters.FilterCriteria{FromBlock: common.Big0, Addresses: []common.Address{watched}})
+	require.NoError(t, err)
+	_, err = api.GetLogs(ctx, filters.FilterCriteria{FromBlock: big.NewInt(5)})
+	require.NoError(t, err)
+}
+
 func TestGetTransactionReceipt(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
 	db := m.DB
@@ -18,193 +18,100 @@ package jsonrpc
 
 import (
 	"context"
-	"fmt"
-	"math/big"
+	"errors"
 	"time"
 
 	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/hexutil"
-	"github.com/erigontech/erigon-lib/common/math"
-	"github.com/erigontech/erigon-lib/crypto"
 	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon-lib/types"
-	"github.com/erigontech/erigon/core"
-	"github.com/erigontech/erigon/core/state"
-	"github.com/erigontech/erigon/core/vm"
 	bortypes "github.com/erigontech/erigon/polygon/bor/types"
 	borrawdb "github.com/erigontech/erigon/polygon/rawdb"
 	"github.com/erigontech/erigon/rpc"
 	"github.com/erigontech/erigon/rpc/ethapi"
 	"github.com/erigontech/erigon/rpc/rpchelper"
-	"github.com/erigontech/erigon/turbo/transactions"
 )
 
-func (api *APIImpl) CallBundle(ctx context.Context, txHashes []common.Hash, stateBlockNumberOrHash rpc.BlockNumberOrHash, timeoutMilliSecondsPtr *int64) (map[string]interface{}, error) {
+// CallBundle implements eth_callBundle. It simulates either the mined transactions with the given hashes in a block
+// on top of stateBlockNumberOrHash, or a bundle of signed transactions as sent to Flashbots relays. The response to
+// hashes is {results: [{txHash, gasUsed, value or error}], bundleHash}, as before bundles were supported; the response
+// to a bundle is CallBundleResult.
+func (api *APIImpl) CallBundle(ctx context.Context, args CallBundleArgs, stateBlockNumberOrHash *rpc.BlockNumberOrHash, timeoutMilliSecondsPtr *int64) (interface{}, error) {
 	tx, err := api.db.BeginTemporalRo(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	chainConfig, err := api.chainConfig(ctx, tx)
-	if err != nil {
-		return nil, err
-	}
-	engine := api.engine()
-
-	if len(txHashes) == 0 {
-		return nil, nil
-	}
-
 	var txs types.Transactions
-
-	for _, txHash := range txHashes {
-		blockNum, _, ok, err := api.txnLookup(ctx, tx, txHash)
-		if err != nil {
-			return nil, err
-		}
-		if !ok {
-			return nil, nil
-		}
-		block, err := api.blockByNumberWithSenders(ctx, tx, blockNum)
-		if err != nil {
-			return nil, err
-		}
-		if block == nil {
+	var blockOverride *BlockOverrides
+	if args.TxHashes != nil {
+		if len(args.TxHashes) == 0 {
 			return nil, nil
 		}
-		var txn types.Transaction
-		for _, transaction := range block.Transactions() {
-			if transaction.Hash() == txHash {
-				txn = transaction
-				break
+		for _, txHash := range args.TxHashes {
+			blockNum, _, ok, err := api.txnLookup(ctx, tx, txHash)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return nil, nil
+			}
+			block, err := api.blockByNumberWithSenders(ctx, tx, blockNum)
+			if err != nil {
+				return nil, err
+			}
+			if block == nil {
+				return nil, nil
 			}
+			var txn types.Transaction
+			for _, transaction := range block.Transactions() {
+				if transaction.Hash() == txHash {
+					txn = transaction
+					break
+				}
+			}
+			if txn == nil {
+				return nil, nil // not error, see https://github.com/erigontech/erigon/issues/1645
+			}
+			txs = append(txs, txn)
 		}
-		if txn == nil {
-			return nil, nil // not error, see https://github.com/erigontech/erigon/issues/1645
+	} else {
+		if len(args.Txs) == 0 {
+			return nil, errors.New("bundle missing txs")
 		}
-		txs = append(txs, txn)
-	}
-	defer func(start time.Time) { log.Trace("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
-
-	stateBlockNumber, hash, latest, err := rpchelper.GetBlockNumber(ctx, stateBlockNumberOrHash, tx, api._blockReader, api.filters)
-	if err != nil {
-		return nil, err
-	}
-	var stateReader state.StateReader
-	if latest {
-		cacheView, err := api.stateCache.View(ctx, tx)
-		if err != nil {
-			return nil, err
+		for _, encodedTx := range args.Txs {
+			txn, err := types.DecodeWrappedTransaction(encodedTx)
+			if err != nil {
+				return nil, err
+			}
+			txs = append(txs, txn)
 		}
-		stateReader = rpchelper.CreateLatestCachedStateReader(cacheView, tx)
-	} else {
-		stateReader, err = rpchelper.CreateHistoryStateReader(tx, stateBlockNumber+1, 0, api._txNumReader)
-		if err != nil {
-			return nil, err
+		if args.StateBlockNumber != nil {
+			stateBlockNumberOrHash = args.StateBlockNumber
 		}
+		blockOverride = args.blockOverrides()
 	}
-	ibs := state.New(stateReader)
-
-	parent, _ := api.headerByRPCNumber(ctx, rpc.BlockNumber(stateBlockNumber), tx)
-	if parent == nil {
-		return nil, fmt.Errorf("block %d(%x) not found", stateBlockNumber, hash)
+	if stateBlockNumberOrHash == nil {
+		latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		stateBlockNumberOrHash = &latest
 	}
 
-	blockNumber := stateBlockNumber + 1
-
-	timestamp := parent.Time + chainConfig.SecondsPerSlot()
-
-	coinbase := parent.Coinbase
-	header := &types.Header{
-		ParentHash: parent.Hash(),
-		Number:     new(big.Int).SetUint64(blockNumber),
-		GasLimit:   parent.GasLimit,
-		Time:       timestamp,
-		Difficulty: parent.Difficulty,
-		Coinbase:   coinbase,
+	timeout := 5000 * time.Millisecond
+	if timeoutMilliSecondsPtr != nil {
+		timeout = time.Millisecond * time.Duration(*timeoutMilliSecondsPtr)
+	} else if args.Timeout != nil {
+		timeout = time.Second * time.Duration(*args.Timeout)
 	}
-
-	signer := types.MakeSigner(chainConfig, blockNumber, timestamp)
-	rules := chainConfig.Rules(blockNumber, timestamp)
-	firstMsg, err := txs[0].AsMessage(*signer, nil, rules)
+	res, err := api.callBundle(ctx, tx, txs, *stateBlockNumberOrHash, blockOverride, timeout)
 	if err != nil {
 		return nil, err
 	}
-
-	blockCtx := transactions.NewEVMBlockContext(engine, header, stateBlockNumberOrHash.RequireCanonical, tx, api._blockReader, chainConfig)
-	txCtx := core.NewEVMTxContext(firstMsg)
-	// Get a new instance of the EVM
-	evm := vm.NewEVM(blockCtx, txCtx, ibs, chainConfig, vm.Config{})
-
-	timeoutMilliSeconds := int64(5000)
-	if timeoutMilliSecondsPtr != nil {
-		timeoutMilliSeconds = *timeoutMilliSecondsPtr
-	}
-	timeout := time.Millisecond * time.Duration(timeoutMilliSeconds)
-	// Setup context so it may be cancelled the call has completed
-	// or, in case of unmetered gas, setup a context with a timeout.
-	var cancel context.CancelFunc
-	if timeout > 0 {
-		ctx, cancel = context.WithTimeout(ctx, timeout)
-	} else {
-		ctx, cancel = context.WithCancel(ctx)
-	}
-	// Make sure the context is cancelled when the call has completed
-	// this makes sure resources are cleaned up.
-	defer cancel()
-
-	// Wait for the context to be done and cancel the evm. Even if the
-	// EVM has finished, cancelling may be done (repeatedly)
-	go func() {
-		<-ctx.Done()
-		evm.Cancel()
-	}()
-
-	// Setup the gas pool (also for unmetered requests)
-	// and apply the message.
-	gp := new(core.GasPool).AddGas(math.MaxUint64).AddBlobGas(math.MaxUint64)
-
-	bundleHash := crypto.NewKeccakState()
-	defer crypto.ReturnToPool(bundleHash)
-
-	results := make([]map[string]interface{}, 0, len(txs))
-	for _, txn := range txs {
-		msg, err := txn.AsMessage(*signer, nil, rules)
-		msg.SetCheckNonce(false)
-		if err != nil {
-			return nil, err
-		}
-		// Execute the transaction message
-		result, err := core.ApplyMessage(evm, msg, gp, true /* refunds */, false /* gasBailout */, engine)
-		if err != nil {
-			return nil, err
-		}
-		// If the timer caused an abort, return an appropriate error message
-		if evm.Cancelled() {
-			return nil, fmt.Errorf("execution aborted (timeout = %v)", timeout)
-		}
-
-		txHash := txn.Hash().String()
-		jsonResult := map[string]interface{}{
-			"txHash":  txHash,
-			"gasUsed": result.GasUsed,
-		}
-		bundleHash.Write(txn.Hash().Bytes())
-		if result.Err != nil {
-			jsonResult["error"] = result.Err.Error()
-		} else {
-			jsonResult["value"] = common.BytesToHash(result.Return())
-		}
-
-		results = append(results, jsonResult)
+	if args.TxHashes != nil {
+		return res.txHashesResult(), nil
 	}
-
-	ret := map[string]interface{}{}
-	ret["results"] = results
-	ret["bundleHash"] = hexutil.Encode(bundleHash.Sum(nil))
-	return ret, nil
+	return res, nil
 }
 
 // GetBlockByNumber implements eth_getBlockByNumber. Returns information about a block given the block's number.
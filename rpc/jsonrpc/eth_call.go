@@ -27,6 +27,7 @@ import (
 	"github.com/holiman/uint256"
 	"google.golang.org/grpc"
 
+	"github.com/erigontech/erigon-db/rawdb"
 	"github.com/erigontech/erigon-lib/chain/params"
 	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/empty"
@@ -301,7 +302,8 @@ func (api *APIImpl) EstimateGas(ctx context.Context, argsOrNil *ethapi2.CallArgs
 	return hexutil.Uint64(hi), nil
 }
 
-// GetProof implements eth_getProof partially; Proofs are available only with the `latest` block tag.
+// GetProof implements eth_getProof. Proofs of blocks before the latest one are built from the commitment history, so
+// they are available only if it's kept, see --prune.experimental.include-commitment-history.
 func (api *APIImpl) GetProof(ctx context.Context, address common.Address, storageKeys []hexutil.Bytes, blockNrOrHash rpc.BlockNumberOrHash) (*accounts.AccProofResult, error) {
 	roTx, err := api.db.BeginTemporalRo(ctx)
 	if err != nil {
@@ -349,6 +351,15 @@ func (api *APIImpl) getProof(ctx context.Context, roTx kv.TemporalTx, address co
 		return nil, fmt.Errorf("block number is in the future latest=%d requested=%d", latestBlock, blockNrOrHash.BlockNumber.Uint64())
 	}
 	if blockNrOrHash.BlockNumber.Uint64() < latestBlock {
+		// The branches on the paths to the touched keys are read as of the block from the commitment history, and so
+		// are the accounts and the storage, so that a proof costs the same however old the block is.
+		commitmentHistory, _, err := rawdb.ReadDBCommitmentHistoryEnabled(tx)
+		if err != nil {
+			return nil, err
+		}
+		if !commitmentHistory {
+			return nil, fmt.Errorf("proofs of blocks before the latest one (%d) require commitment history, see --prune.experimental.include-commitment-history", latestBlock)
+		}
 		// Get first txnum of blockNumber+1 to ensure that correct state root will be restored as of blockNumber has been executed
 		lastTxnInBlock, err := api._txNumReader.Min(tx, blockNrOrHash.BlockNumber.Uint64()+1)
 		if err != nil {
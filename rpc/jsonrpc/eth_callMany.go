@@ -17,8 +17,10 @@
 package jsonrpc
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
@@ -26,18 +28,24 @@ import (
 
 	"github.com/holiman/uint256"
 
+	"github.com/erigontech/erigon-lib/abi"
 	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/hexutil"
 	"github.com/erigontech/erigon-lib/common/math"
+	"github.com/erigontech/erigon-lib/crypto"
+	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon-lib/types"
 	"github.com/erigontech/erigon/core"
 	"github.com/erigontech/erigon/core/state"
 	"github.com/erigontech/erigon/core/vm"
 	"github.com/erigontech/erigon/core/vm/evmtypes"
+	"github.com/erigontech/erigon/execution/consensus/misc"
 	"github.com/erigontech/erigon/rpc"
 	"github.com/erigontech/erigon/rpc/ethapi"
 	"github.com/erigontech/erigon/rpc/rpchelper"
+	"github.com/erigontech/erigon/turbo/shards"
+	"github.com/erigontech/erigon/turbo/transactions"
 )
 
 type BlockOverrides struct {
@@ -86,6 +94,26 @@ func blockHeaderOverride(blockCtx *evmtypes.BlockContext, blockOverride BlockOve
 	}
 }
 
+// cancelEvmOnTimeout - cancels evm after timeout (0 - no timeout), or when the returned function is called.
+func cancelEvmOnTimeout(ctx context.Context, evm *vm.EVM, timeout time.Duration) context.CancelFunc {
+	// Setup context so it may be cancelled the call has completed
+	// or, in case of unmetered gas, setup a context with a timeout.
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+
+	// Wait for the context to be done and cancel the evm. Even if the
+	// EVM has finished, cancelling may be done (repeatedly)
+	go func() {
+		<-ctx.Done()
+		evm.Cancel()
+	}()
+	return cancel
+}
+
 func (api *APIImpl) CallMany(ctx context.Context, bundles []Bundle, simulateContext StateContext, stateOverride *ethapi.StateOverrides, timeoutMilliSecondsPtr *int64) ([][]map[string]interface{}, error) {
 	var (
 		hash               common.Hash
@@ -185,25 +213,11 @@ func (api *APIImpl) CallMany(ctx context.Context, bundles []Bundle, simulateCont
 	}
 
 	timeout := time.Millisecond * time.Duration(timeoutMilliSeconds)
-	// Setup context so it may be cancelled the call has completed
-	// or, in case of unmetered gas, setup a context with a timeout.
-	var cancel context.CancelFunc
-	if timeout > 0 {
-		ctx, cancel = context.WithTimeout(ctx, timeout)
-	} else {
-		ctx, cancel = context.WithCancel(ctx)
-	}
 	// Make sure the context is cancelled when the call has completed
 	// this makes sure resources are cleaned up.
+	cancel := cancelEvmOnTimeout(ctx, evm, timeout)
 	defer cancel()
 
-	// Wait for the context to be done and cancel the evm. Even if the
-	// EVM has finished, cancelling may be done (repeatedly)
-	go func() {
-		<-ctx.Done()
-		evm.Cancel()
-	}()
-
 	// Setup the gas pool (also for unmetered requests)
 	// and apply the message.
 	gp := new(core.GasPool).AddGas(math.MaxUint64).AddBlobGas(math.MaxUint64)
@@ -242,29 +256,7 @@ func (api *APIImpl) CallMany(ctx context.Context, bundles []Bundle, simulateCont
 
 	for _, bundle := range bundles {
 		// first change blockContext
-		if bundle.BlockOverride.BlockNumber != nil {
-			blockCtx.BlockNumber = uint64(*bundle.BlockOverride.BlockNumber)
-		}
-		if bundle.BlockOverride.BaseFee != nil {
-			blockCtx.BaseFee = bundle.BlockOverride.BaseFee
-		}
-		if bundle.BlockOverride.Coinbase != nil {
-			blockCtx.Coinbase = *bundle.BlockOverride.Coinbase
-		}
-		if bundle.BlockOverride.Difficulty != nil {
-			blockCtx.Difficulty = new(big.Int).SetUint64(uint64(*bundle.BlockOverride.Difficulty))
-		}
-		if bundle.BlockOverride.Timestamp != nil {
-			blockCtx.Time = uint64(*bundle.BlockOverride.Timestamp)
-		}
-		if bundle.BlockOverride.GasLimit != nil {
-			blockCtx.GasLimit = uint64(*bundle.BlockOverride.GasLimit)
-		}
-		if bundle.BlockOverride.BlockHash != nil {
-			for blockNum, hash := range *bundle.BlockOverride.BlockHash {
-				overrideBlockHash[blockNum] = hash
-			}
-		}
+		blockHeaderOverride(&blockCtx, bundle.BlockOverride, overrideBlockHash)
 		results := []map[string]interface{}{}
 		for _, txn := range bundle.Transactions {
 			if txn.Gas == nil || *(txn.Gas) == 0 {
@@ -312,3 +304,274 @@ func (api *APIImpl) CallMany(ctx context.Context, bundles []Bundle, simulateCont
 
 	return ret, err
 }
+
+// CallBundleArgs is the first argument of eth_callBundle: either the hashes of mined transactions, or a bundle of
+// signed transactions with the block to simulate it in, as sent to Flashbots relays.
+type CallBundleArgs struct {
+	TxHashes         []common.Hash          `json:"-"`
+	Txs              []hexutil.Bytes        `json:"txs"`
+	BlockNumber      *hexutil.Uint64        `json:"blockNumber"`      // Block the bundle targets, the block after StateBlockNumber by default
+	StateBlockNumber *rpc.BlockNumberOrHash `json:"stateBlockNumber"` // Block whose state the bundle is simulated on
+	Timestamp        *uint64                `json:"timestamp"`        // Timestamp of the target block
+	Coinbase         *common.Address        `json:"coinbase"`
+	GasLimit         *uint64                `json:"gasLimit"`
+	BaseFee          *big.Int               `json:"baseFee"` // Computed from the state block by default
+	Timeout          *int64                 `json:"timeout"` // In seconds
+}
+
+func (args *CallBundleArgs) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		args.TxHashes = []common.Hash{}
+		return json.Unmarshal(trimmed, &args.TxHashes)
+	}
+	type bundleArgs CallBundleArgs
+	return json.Unmarshal(data, (*bundleArgs)(args))
+}
+
+func (args *CallBundleArgs) blockOverrides() *BlockOverrides {
+	overrides := &BlockOverrides{
+		BlockNumber: args.BlockNumber,
+		Coinbase:    args.Coinbase,
+		Timestamp:   (*hexutil.Uint64)(args.Timestamp),
+	}
+	if args.GasLimit != nil {
+		gasLimit := hexutil.Uint(*args.GasLimit)
+		overrides.GasLimit = &gasLimit
+	}
+	if args.BaseFee != nil {
+		overrides.BaseFee, _ = uint256.FromBig(args.BaseFee)
+	}
+	return overrides
+}
+
+// CallBundleResult is the eth_callBundle response, compatible with the one of Flashbots relays. Amounts of wei are
+// decimal strings.
+type CallBundleResult struct {
+	BundleGasPrice    string                `json:"bundleGasPrice"`
+	BundleHash        common.Hash           `json:"bundleHash"`
+	CoinbaseDiff      string                `json:"coinbaseDiff"`
+	EthSentToCoinbase string                `json:"ethSentToCoinbase"`
+	GasFees           string                `json:"gasFees"`
+	Results           []*CallBundleTxResult `json:"results"`
+	StateBlockNumber  uint64                `json:"stateBlockNumber"`
+	TotalGasUsed      uint64                `json:"totalGasUsed"`
+}
+
+// txHashesResult - the response to hashes of mined transactions: value is the returned data as 32-byte hash.
+func (r *CallBundleResult) txHashesResult() map[string]interface{} {
+	results := make([]map[string]interface{}, 0, len(r.Results))
+	for _, txResult := range r.Results {
+		jsonResult := map[string]interface{}{
+			"txHash":  txResult.TxHash.String(),
+			"gasUsed": txResult.GasUsed,
+		}
+		if txResult.Error != "" {
+			jsonResult["error"] = txResult.Error
+		} else {
+			jsonResult["value"] = common.BytesToHash(txResult.Value)
+		}
+		results = append(results, jsonResult)
+	}
+	return map[string]interface{}{
+		"results":    results,
+		"bundleHash": hexutil.Encode(r.BundleHash[:]),
+	}
+}
+
+// CallBundleTxResult is the outcome of a transaction of a bundle.
+type CallBundleTxResult struct {
+	TxHash            common.Hash                          `json:"txHash"`
+	FromAddress       common.Address                       `json:"fromAddress"`
+	ToAddress         *common.Address                      `json:"toAddress"`
+	GasUsed           uint64                               `json:"gasUsed"`
+	GasPrice          string                               `json:"gasPrice"`          // Paid to the coinbase per unit of gas, including direct transfers
+	EffectiveGasPrice *hexutil.Big                         `json:"effectiveGasPrice"` // Paid by the sender per unit of gas
+	GasFees           string                               `json:"gasFees"`           // Priority fees paid to the coinbase
+	CoinbaseDiff      string                               `json:"coinbaseDiff"`
+	EthSentToCoinbase string                               `json:"ethSentToCoinbase"` // Paid to the coinbase other than by fees
+	Value             hexutil.Bytes                        `json:"value,omitempty"`
+	Error             string                               `json:"error,omitempty"`
+	Revert            string                               `json:"revert,omitempty"` // Revert reason
+	StateDiff         map[common.Address]*StateDiffAccount `json:"stateDiff"`
+}
+
+// callBundle executes txs one after another in a block on top of the state of stateBlockNumberOrHash. The block is
+// the one after the state block, blockOverride sets what the block of a bundle of signed transactions differs in.
+// It's nil for mined transactions, which are executed regardless of their nonces and without a base fee.
+func (api *APIImpl) callBundle(ctx context.Context, tx kv.TemporalTx, txs types.Transactions, stateBlockNumberOrHash rpc.BlockNumberOrHash, blockOverride *BlockOverrides, timeout time.Duration) (*CallBundleResult, error) {
+	chainConfig, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	engine := api.engine()
+
+	defer func(start time.Time) { log.Trace("Executing EVM callBundle finished", "runtime", time.Since(start)) }(time.Now())
+
+	stateBlockNumber, hash, latest, err := rpchelper.GetBlockNumber(ctx, stateBlockNumberOrHash, tx, api._blockReader, api.filters)
+	if err != nil {
+		return nil, err
+	}
+	stateReader, err := rpchelper.CreateStateReaderFromBlockNumber(ctx, tx, stateBlockNumber, latest, 0, api.stateCache, api._txNumReader)
+	if err != nil {
+		return nil, err
+	}
+	parent, err := api.headerByRPCNumber(ctx, rpc.BlockNumber(stateBlockNumber), tx)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil {
+		return nil, fmt.Errorf("block %d(%x) not found", stateBlockNumber, hash)
+	}
+
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).SetUint64(stateBlockNumber + 1),
+		GasLimit:   parent.GasLimit,
+		Time:       parent.Time + chainConfig.SecondsPerSlot(),
+		Difficulty: parent.Difficulty,
+		Coinbase:   parent.Coinbase,
+	}
+	if blockOverride != nil {
+		// number and time of the block decide its rules and fees, the rest is overridden in the block context
+		if blockOverride.BlockNumber != nil {
+			header.Number.SetUint64(uint64(*blockOverride.BlockNumber))
+		}
+		if blockOverride.Timestamp != nil {
+			header.Time = uint64(*blockOverride.Timestamp)
+		}
+		if chainConfig.IsLondon(header.Number.Uint64()) {
+			header.BaseFee = misc.CalcBaseFee(chainConfig, parent)
+		}
+		if chainConfig.IsCancun(header.Time) {
+			excessBlobGas := misc.CalcExcessBlobGas(chainConfig, parent, header.Time)
+			header.ExcessBlobGas = &excessBlobGas
+		}
+	}
+	blockNumber := header.Number.Uint64()
+	signer := types.MakeSigner(chainConfig, blockNumber, header.Time)
+	rules := chainConfig.Rules(blockNumber, header.Time)
+
+	blockCtx := transactions.NewEVMBlockContext(engine, header, stateBlockNumberOrHash.RequireCanonical, tx, api._blockReader, chainConfig)
+	if blockOverride != nil {
+		blockHeaderOverride(&blockCtx, *blockOverride, nil /* no block hash overrides */)
+		header.BaseFee = blockCtx.BaseFee.ToBig()
+	}
+	var baseFee uint256.Int
+	if blockCtx.BaseFee != nil {
+		baseFee.Set(blockCtx.BaseFee)
+	}
+
+	// the writes of every transaction are kept in the cache for the following ones
+	stateCache := shards.NewStateCache(32, 0 /* no limit */)
+	cachedWriter := state.NewCachedWriter(state.NewNoopWriter(), stateCache)
+	ibs := state.New(state.NewCachedReader(stateReader, stateCache))
+	evm := vm.NewEVM(blockCtx, evmtypes.TxContext{}, ibs, chainConfig, vm.Config{})
+
+	// Make sure the context is cancelled when the call has completed
+	// this makes sure resources are cleaned up.
+	cancel := cancelEvmOnTimeout(ctx, evm, timeout)
+	defer cancel()
+
+	// Setup the gas pool (also for unmetered requests)
+	// and apply the message.
+	gp := new(core.GasPool).AddGas(math.MaxUint64).AddBlobGas(math.MaxUint64)
+
+	bundleHash := crypto.NewKeccakState()
+	defer crypto.ReturnToPool(bundleHash)
+
+	ret := &CallBundleResult{StateBlockNumber: stateBlockNumber, Results: make([]*CallBundleTxResult, 0, len(txs))}
+	coinbaseDiff, gasFees := new(big.Int), new(big.Int)
+	for txIndex, txn := range txs {
+		msg, err := txn.AsMessage(*signer, header.BaseFee, rules)
+		if err != nil {
+			return nil, err
+		}
+		if blockOverride == nil {
+			msg.SetCheckNonce(false)
+		}
+
+		// the state before the transaction, to diff against
+		initialIbs := state.New(state.NewCachedReader(stateReader, stateCache.Clone()))
+		ibs.Reset()
+		ibs.SetTxContext(blockNumber, txIndex)
+		coinbaseBefore, err := ibs.GetBalance(blockCtx.Coinbase)
+		if err != nil {
+			return nil, err
+		}
+
+		evm.Reset(core.NewEVMTxContext(msg), ibs)
+		result, err := core.ApplyMessage(evm, msg, gp, true /* refunds */, false /* gasBailout */, engine)
+		if err != nil {
+			return nil, fmt.Errorf("txn %x: %w", txn.Hash(), err)
+		}
+		// If the timer caused an abort, return an appropriate error message
+		if evm.Cancelled() {
+			return nil, fmt.Errorf("execution aborted (timeout = %v)", timeout)
+		}
+		coinbaseAfter, err := ibs.GetBalance(blockCtx.Coinbase)
+		if err != nil {
+			return nil, err
+		}
+
+		sd := &StateDiff{sdMap: make(map[common.Address]*StateDiffAccount)}
+		if err = ibs.FinalizeTx(rules, sd); err != nil {
+			return nil, err
+		}
+		if err = sd.CompareStates(initialIbs, ibs); err != nil {
+			return nil, err
+		}
+		if err = ibs.CommitBlock(rules, cachedWriter); err != nil {
+			return nil, err
+		}
+
+		// the sender pays min(feeCap, baseFee+tipCap) per gas, the coinbase gets what's above the base fee
+		effectiveGasPrice := new(uint256.Int).Add(&baseFee, msg.TipCap())
+		if effectiveGasPrice.Gt(msg.FeeCap()) {
+			effectiveGasPrice.Set(msg.FeeCap())
+		}
+		txGasFees := new(big.Int).Mul(new(big.Int).Sub(effectiveGasPrice.ToBig(), baseFee.ToBig()), new(big.Int).SetUint64(result.GasUsed))
+		txCoinbaseDiff := new(big.Int).Sub(coinbaseAfter.ToBig(), coinbaseBefore.ToBig())
+
+		txResult := &CallBundleTxResult{
+			TxHash:            txn.Hash(),
+			FromAddress:       msg.From(),
+			ToAddress:         msg.To(),
+			GasUsed:           result.GasUsed,
+			GasPrice:          perGas(txCoinbaseDiff, result.GasUsed).String(),
+			EffectiveGasPrice: (*hexutil.Big)(effectiveGasPrice.ToBig()),
+			GasFees:           txGasFees.String(),
+			CoinbaseDiff:      txCoinbaseDiff.String(),
+			EthSentToCoinbase: new(big.Int).Sub(txCoinbaseDiff, txGasFees).String(),
+			StateDiff:         sd.sdMap,
+		}
+		if result.Err != nil {
+			txResult.Error = result.Err.Error()
+			if reason, errUnpack := abi.UnpackRevert(result.Revert()); errUnpack == nil {
+				txResult.Revert = reason
+			}
+		} else {
+			txResult.Value = common.CopyBytes(result.Return())
+		}
+		ret.Results = append(ret.Results, txResult)
+
+		bundleHash.Write(txn.Hash().Bytes())
+		ret.TotalGasUsed += result.GasUsed
+		coinbaseDiff.Add(coinbaseDiff, txCoinbaseDiff)
+		gasFees.Add(gasFees, txGasFees)
+	}
+
+	ret.BundleHash = common.BytesToHash(bundleHash.Sum(nil))
+	ret.BundleGasPrice = perGas(coinbaseDiff, ret.TotalGasUsed).String()
+	ret.CoinbaseDiff = coinbaseDiff.String()
+	ret.EthSentToCoinbase = new(big.Int).Sub(coinbaseDiff, gasFees).String()
+	ret.GasFees = gasFees.String()
+	return ret, nil
+}
+
+// perGas divides an amount of wei by the gas it was paid for.
+func perGas(amount *big.Int, gas uint64) *big.Int {
+	if gas == 0 {
+		return new(big.Int)
+	}
+	return new(big.Int).Div(amount, new(big.Int).SetUint64(gas))
+}
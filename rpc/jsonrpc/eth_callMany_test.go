@@ -17,14 +17,20 @@
 package jsonrpc
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"strconv"
 	"testing"
 
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
 	"github.com/erigontech/erigon-lib/chain"
+	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/datadir"
 	"github.com/erigontech/erigon-lib/common/hexutil"
 	"github.com/erigontech/erigon-lib/crypto"
@@ -183,3 +189,107 @@ func TestCallMany(t *testing.T) {
 		t.Errorf("eth_callMany: %s", "balanceUnmatch")
 	}
 }
+
+func TestCallBundle(t *testing.T) {
+	var (
+		key, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		key1, _  = crypto.HexToECDSA("49a7b37aa6f6645917e7b807e9d1c00d4fa71f18343b0d4122a4d2df64dd6fee")
+		address  = crypto.PubkeyToAddress(key.PublicKey)
+		address1 = crypto.PubkeyToAddress(key1.PublicKey)
+		coinbase = common.HexToAddress("0xc014ba5e")
+		gspec    = &types.Genesis{
+			Config: chain.TestChainConfig,
+			Alloc: types.GenesisAlloc{
+				address:  {Balance: big.NewInt(9000000000000000000)},
+				address1: {Balance: big.NewInt(200000000000000000)},
+			},
+			GasLimit: 10000000,
+		}
+		chainID = big.NewInt(1337)
+		ctx     = context.Background()
+	)
+
+	transactOpts, _ := bind.NewKeyedTransactorWithChainID(key, chainID)
+	transactOpts1, _ := bind.NewKeyedTransactorWithChainID(key1, chainID)
+	contractBackend := backends.NewTestSimulatedBackendWithConfig(t, gspec.Alloc, gspec.Config, gspec.GasLimit)
+	defer contractBackend.Close()
+	tokenAddr, deployTxn, tokenContract, err := contracts.DeployToken(transactOpts, contractBackend, address1)
+	require.NoError(t, err)
+	mintTxn, err := tokenContract.Mint(transactOpts1, address1, big.NewInt(100))
+	require.NoError(t, err)
+	contractBackend.Commit()
+
+	api := NewEthAPI(NewBaseApi(nil, kvcache.New(kvcache.DefaultCoherentConfig), contractBackend.BlockReader(), false, rpccfg.DefaultEvmCallTimeout, contractBackend.Engine(), datadir.New(t.TempDir()), nil), contractBackend.DB(), nil, nil, nil, 5000000, ethconfig.Defaults.RPCTxFeeCap, 100_000, false, 100_000, 128, log.New())
+
+	// mined transactions by hash
+	var args CallBundleArgs
+	require.NoError(t, json.Unmarshal([]byte(fmt.Sprintf(`["%s", "%s"]`, deployTxn.Hash(), mintTxn.Hash())), &args))
+	genesis := rpc.BlockNumberOrHashWithNumber(0)
+	minedRes, err := api.CallBundle(ctx, args, &genesis, nil)
+	require.NoError(t, err)
+	// same response as before bundles of signed transactions were supported
+	minedJSON, err := json.Marshal(minedRes)
+	require.NoError(t, err)
+	var mined struct {
+		Results    []map[string]json.RawMessage `json:"results"`
+		BundleHash hexutil.Bytes                `json:"bundleHash"`
+	}
+	require.NoError(t, json.Unmarshal(minedJSON, &mined))
+	require.Len(t, mined.Results, 2)
+	require.Len(t, mined.BundleHash, 32)
+	for i, txHash := range []common.Hash{deployTxn.Hash(), mintTxn.Hash()} {
+		require.Len(t, mined.Results[i], 3)
+		require.JSONEq(t, strconv.Quote(txHash.String()), string(mined.Results[i]["txHash"]))
+		require.Contains(t, mined.Results[i], "gasUsed")
+		require.Contains(t, mined.Results[i], "value")
+	}
+	require.JSONEq(t, strconv.Quote(common.BytesToHash([]byte{1}).String()), string(mined.Results[1]["value"]))
+
+	// a bundle of signed transactions: a direct payment to the coinbase and a reverting token transfer
+	signer := types.LatestSignerForChainID(chainID)
+	gasPrice := uint256.NewInt(1e10)
+	payment, err := types.SignTx(types.NewTransaction(1, coinbase, uint256.NewInt(1e15), 21000, gasPrice, nil), *signer, key)
+	require.NoError(t, err)
+	transferData := append(hexutil.MustDecode("0xa9059cbb"), append(common.LeftPadBytes(address1.Bytes(), 32), common.LeftPadBytes([]byte{1}, 32)...)...)
+	transfer, err := types.SignTx(types.NewTransaction(2, tokenAddr, uint256.NewInt(0), 100000, gasPrice, transferData), *signer, key)
+	require.NoError(t, err)
+	var encoded []string
+	for _, txn := range []types.Transaction{payment, transfer} {
+		var buf bytes.Buffer
+		require.NoError(t, txn.MarshalBinary(&buf))
+		encoded = append(encoded, hexutil.Encode(buf.Bytes()))
+	}
+	bundle, err := json.Marshal(map[string]interface{}{
+		"txs":              encoded,
+		"blockNumber":      "0x2",
+		"stateBlockNumber": "latest",
+		"coinbase":         coinbase,
+		"timestamp":        1_000_000,
+	})
+	require.NoError(t, err)
+	args = CallBundleArgs{}
+	require.NoError(t, json.Unmarshal(bundle, &args))
+	bundleRes, err := api.CallBundle(ctx, args, nil, nil)
+	require.NoError(t, err)
+	res, ok := bundleRes.(*CallBundleResult)
+	require.True(t, ok)
+	require.Equal(t, uint64(1), res.StateBlockNumber)
+	require.Len(t, res.Results, 2)
+
+	paymentRes, transferRes := res.Results[0], res.Results[1]
+	require.Equal(t, address, paymentRes.FromAddress)
+	require.Equal(t, uint64(21000), paymentRes.GasUsed)
+	require.Equal(t, "1000000000000000", paymentRes.EthSentToCoinbase)
+	require.Contains(t, paymentRes.StateDiff, coinbase)
+	require.Contains(t, paymentRes.StateDiff, address)
+	require.Equal(t, "execution reverted", transferRes.Error)
+	require.Equal(t, "0", transferRes.EthSentToCoinbase)
+
+	fees, _ := new(big.Int).SetString(transferRes.GasFees, 10)
+	require.Positive(t, fees.Sign())
+	require.Equal(t, paymentRes.GasUsed+transferRes.GasUsed, res.TotalGasUsed)
+	require.Equal(t, "1000000000000000", res.EthSentToCoinbase)
+	coinbaseDiff, _ := new(big.Int).SetString(res.CoinbaseDiff, 10)
+	gasFees, _ := new(big.Int).SetString(res.GasFees, 10)
+	require.Equal(t, new(big.Int).Add(gasFees, big.NewInt(1e15)), coinbaseDiff)
+}
@@ -37,6 +37,7 @@ import (
 	"github.com/erigontech/erigon-lib/kv/kvcache"
 	"github.com/erigontech/erigon-lib/kv/rawdbv3"
 	"github.com/erigontech/erigon-lib/log/v3"
+	libstate "github.com/erigontech/erigon-lib/state"
 	"github.com/erigontech/erigon-lib/trie"
 	"github.com/erigontech/erigon-lib/types"
 	"github.com/erigontech/erigon/cmd/rpcdaemon/rpcdaemontest"
@@ -109,10 +110,38 @@ func TestEthCallToPrunedBlock(t *testing.T) {
 	}
 }
 
+func TestEthCallWatchedHistory(t *testing.T) {
+	m, bankAddress, contractAddress := chainWithDeployedContract(t)
+	api := NewEthAPI(newBaseApiForTest(m), m.DB, nil, nil, nil, 5000000, ethconfig.Defaults.RPCTxFeeCap, 100_000, false, 100_000, 128, log.New())
+	callData := hexutil.Bytes(hexutil.MustDecode("0x2e64cec1"))
+	call := func(from common.Address, block rpc.BlockNumber) error {
+		blockNumberOrHash := rpc.BlockNumberOrHashWithNumber(block)
+		_, err := api.Call(context.Background(), ethapi.CallArgs{From: &from, To: &contractAddress, Data: &callData}, &blockNumberOrHash, nil)
+		return err
+	}
+	require.NoError(t, call(bankAddress, 1))
+
+	// execution reads the sender, the contract and the coinbase: without their history it would run on latest state
+	coinbase := common.Address{}
+	setWatchedHistory(t, m, 3, bankAddress)
+	require.ErrorIs(t, call(bankAddress, 1), state.PrunedError)
+	setWatchedHistory(t, m, 3, bankAddress, contractAddress, coinbase)
+	require.NoError(t, call(bankAddress, 1))
+	require.NoError(t, call(bankAddress, 3))
+}
+
 func TestGetProof(t *testing.T) {
 	var maxGetProofRewindBlockCount = 1 // Note, this is unsafe for parallel tests, but, this test is the only consumer for now
 
+	// proofs of older blocks are built from the commitment history
+	commitmentSchema := libstate.Schema.CommitmentDomain
+	libstate.EnableHistoricalCommitment()
+	t.Cleanup(func() { libstate.Schema.CommitmentDomain = commitmentSchema })
 	m, bankAddr, contractAddr := chainWithDeployedContract(t)
+	err := m.DB.Update(context.Background(), func(tx kv.RwTx) error {
+		return rawdb.WriteDBCommitmentHistoryEnabled(tx, true)
+	})
+	require.NoError(t, err)
 	api := NewEthAPI(newBaseApiForTest(m), m.DB, nil, nil, nil, 5000000, ethconfig.Defaults.RPCTxFeeCap, 100_000, false, maxGetProofRewindBlockCount, 128, log.New())
 
 	key := func(b byte) hexutil.Bytes {
@@ -172,19 +201,30 @@ func TestGetProof(t *testing.T) {
 			blockNum:    3,
 			stateVal:    0,
 		},
-		// {
-		// 	name:        "olderBlockWithState",
-		// 	addr:        contractAddr,
-		// 	blockNum:    2,
-		// 	storageKeys: []common.Hash{key(1), key(5), key(9), key(13)},
-		// 	stateVal:    1,
-		// },
-		// {
-		// 	name:        "tooOldBlock",
-		// 	addr:        contractAddr,
-		// 	blockNum:    1,
-		// 	expectedErr: "requested block is too old, block must be within 1 blocks of the head block number (currently 3)",
-		// },
+		{
+			name:        "olderBlockWithState",
+			addr:        contractAddr,
+			blockNum:    2,
+			storageKeys: []hexutil.Bytes{key(1), key(5), key(9), key(13)},
+			stateVal:    1,
+		},
+		{
+			name:        "olderBlockWithMissingState",
+			addr:        contractAddr,
+			blockNum:    2,
+			storageKeys: []hexutil.Bytes{hexutil.FromHex("0xdeaddeaddeaddeaddeaddeaddeaddeaddeaddeaddeaddeaddeaddeaddeaddead")},
+			stateVal:    0,
+		},
+		{
+			name:     "olderBlockEOA",
+			addr:     bankAddr,
+			blockNum: 1,
+		},
+		{
+			name:     "olderBlockNoAccount",
+			addr:     common.HexToAddress("0xdeaddeaddeaddeaddeaddeaddeaddeaddeaddead0"),
+			blockNum: 1,
+		},
 	}
 
 	for _, tt := range tests {
@@ -234,6 +274,16 @@ func TestGetProof(t *testing.T) {
 	}
 }
 
+func TestGetProofWithoutCommitmentHistory(t *testing.T) {
+	m, _, contractAddr := chainWithDeployedContract(t)
+	api := NewEthAPI(newBaseApiForTest(m), m.DB, nil, nil, nil, 5000000, ethconfig.Defaults.RPCTxFeeCap, 100_000, false, 100_000, 128, log.New())
+
+	_, err := api.GetProof(context.Background(), contractAddr, nil, rpc.BlockNumberOrHashWithNumber(3))
+	require.NoError(t, err)
+	_, err = api.GetProof(context.Background(), contractAddr, nil, rpc.BlockNumberOrHashWithNumber(2))
+	require.ErrorContains(t, err, "require commitment history")
+}
+
 func TestGetBlockByTimestampLatestTime(t *testing.T) {
 	ctx := context.Background()
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
@@ -17,11 +17,18 @@
 package jsonrpc
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
+	"time"
 
+	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/debug"
+	remote "github.com/erigontech/erigon-lib/gointerfaces/remoteproto"
+	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon-lib/types"
 	"github.com/erigontech/erigon/eth/filters"
@@ -34,6 +41,14 @@ func (api *APIImpl) NewPendingTransactionFilter(_ context.Context) (string, erro
 	if api.filters == nil {
 		return "", rpc.ErrNotificationsUnsupported
 	}
+	if store := api.filters.Store(); store != nil {
+		f := &rpchelper.StoredFilter{ID: rpchelper.NewStoredFilterID(), Type: rpchelper.PendingTxsStoredFilter, LastPoll: time.Now()}
+		if err := store.Put(f); err != nil {
+			return "", err
+		}
+		api.filters.ReadStoredPendingTxs(f.ID)
+		return "0x" + f.ID, nil
+	}
 	txsCh, id := api.filters.SubscribePendingTxs(32)
 	go func() {
 		for txs := range txsCh {
@@ -44,10 +59,13 @@ func (api *APIImpl) NewPendingTransactionFilter(_ context.Context) (string, erro
 }
 
 // NewBlockFilter implements eth_newBlockFilter. Creates a filter in the node, to notify when a new block arrives.
-func (api *APIImpl) NewBlockFilter(_ context.Context) (string, error) {
+func (api *APIImpl) NewBlockFilter(ctx context.Context) (string, error) {
 	if api.filters == nil {
 		return "", rpc.ErrNotificationsUnsupported
 	}
+	if store := api.filters.Store(); store != nil {
+		return api.newStoredFilter(ctx, store, &rpchelper.StoredFilter{Type: rpchelper.BlocksStoredFilter})
+	}
 	ch, id := api.filters.SubscribeNewHeads(32)
 	go func() {
 		for block := range ch {
@@ -58,10 +76,13 @@ func (api *APIImpl) NewBlockFilter(_ context.Context) (string, error) {
 }
 
 // NewFilter implements eth_newFilter. Creates an arbitrary filter object, based on filter options, to notify when the state changes (logs).
-func (api *APIImpl) NewFilter(_ context.Context, crit filters.FilterCriteria) (string, error) {
+func (api *APIImpl) NewFilter(ctx context.Context, crit filters.FilterCriteria) (string, error) {
 	if api.filters == nil {
 		return "", rpc.ErrNotificationsUnsupported
 	}
+	if store := api.filters.Store(); store != nil {
+		return api.newStoredFilter(ctx, store, &rpchelper.StoredFilter{Type: rpchelper.LogsStoredFilter, Addresses: crit.Addresses, Topics: crit.Topics})
+	}
 	logs, id := api.filters.SubscribeLogs(256, crit)
 	go func() {
 		for lg := range logs {
@@ -78,6 +99,12 @@ func (api *APIImpl) UninstallFilter(_ context.Context, index string) (isDeleted
 	}
 	// remove 0x
 	cutIndex := strings.TrimPrefix(index, "0x")
+	if store := api.filters.Store(); store != nil {
+		api.filters.UnsubscribeStoredPendingTxs(cutIndex)
+		if isDeleted, err = store.Delete(cutIndex); err != nil || isDeleted {
+			return isDeleted, err
+		}
+	}
 	if ok := api.filters.UnsubscribeHeads(rpchelper.HeadsSubID(cutIndex)); ok {
 		isDeleted = true
 	}
@@ -93,13 +120,22 @@ func (api *APIImpl) UninstallFilter(_ context.Context, index string) (isDeleted
 // GetFilterChanges implements eth_getFilterChanges.
 // Polling method for a previously created filter
 // returns an array of logs, block headers, or pending transactions which have occurred since the last poll.
-func (api *APIImpl) GetFilterChanges(_ context.Context, index string) ([]any, error) {
+func (api *APIImpl) GetFilterChanges(ctx context.Context, index string) ([]any, error) {
 	if api.filters == nil {
 		return nil, rpc.ErrNotificationsUnsupported
 	}
 	stub := make([]any, 0)
 	// remove 0x
 	cutIndex := strings.TrimPrefix(index, "0x")
+	if store := api.filters.Store(); store != nil {
+		f, err := store.Get(cutIndex)
+		if err != nil {
+			return nil, err
+		}
+		if f != nil {
+			return api.storedFilterChanges(ctx, store, f)
+		}
+	}
 	if blocks, ok := api.filters.ReadPendingBlocks(rpchelper.HeadsSubID(cutIndex)); ok {
 		for _, v := range blocks {
 			stub = append(stub, v.Hash())
@@ -127,17 +163,191 @@ func (api *APIImpl) GetFilterChanges(_ context.Context, index string) ([]any, er
 // GetFilterLogs implements eth_getFilterLogs.
 // Polling method for a previously created filter
 // returns an array of logs which have occurred since the last poll.
-func (api *APIImpl) GetFilterLogs(_ context.Context, index string) ([]*types.Log, error) {
+func (api *APIImpl) GetFilterLogs(ctx context.Context, index string) ([]*types.Log, error) {
 	if api.filters == nil {
 		return nil, rpc.ErrNotificationsUnsupported
 	}
 	cutIndex := strings.TrimPrefix(index, "0x")
+	if store := api.filters.Store(); store != nil {
+		f, err := store.Get(cutIndex)
+		if err != nil {
+			return nil, err
+		}
+		if f != nil && f.Type == rpchelper.LogsStoredFilter {
+			changes, err := api.storedFilterChanges(ctx, store, f)
+			if err != nil {
+				return nil, err
+			}
+			logs := make([]*types.Log, len(changes))
+			for i, change := range changes {
+				logs[i] = change.(*types.Log)
+			}
+			return logs, nil
+		}
+	}
 	if logs, ok := api.filters.ReadLogs(rpchelper.LogsSubID(cutIndex)); ok {
 		return logs, nil
 	}
 	return nil, errors.New("filter not found")
 }
 
+// newStoredFilter persists a new log or block filter, its first poll returns
+// the changes of the blocks executed after the current one.
+func (api *APIImpl) newStoredFilter(ctx context.Context, store rpchelper.FilterStore, f *rpchelper.StoredFilter) (string, error) {
+	tx, err := api.db.BeginTemporalRo(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+	latest, latestHash, _, err := rpchelper.GetBlockNumber(ctx, rpc.BlockNumberOrHashWithNumber(rpc.LatestExecutedBlockNumber), tx, api._blockReader, nil)
+	if err != nil {
+		return "", err
+	}
+
+	f.ID, f.LastBlock, f.LastBlockHash, f.LastPoll = rpchelper.NewStoredFilterID(), latest, latestHash, time.Now()
+	if err := store.Put(f); err != nil {
+		return "", err
+	}
+	return "0x" + f.ID, nil
+}
+
+// storedFilterPollAttempts is how many times a poll of a stored filter is
+// computed again when concurrent polls of the filter won.
+const storedFilterPollAttempts = 8
+
+// storedFilterChanges returns the changes of a stored filter since its last
+// poll, from whichever rpcdaemon it was, and moves the filter past them. Log
+// and block filters get the changes of all blocks executed since, even the
+// ones executed while no rpcdaemon was running. If a reorg dropped blocks
+// whose changes were returned, log filters get their logs as removed, and both
+// get the changes of the new canonical blocks. If a concurrent poll moved the
+// filter first, the changes are computed again from where it left the filter.
+func (api *APIImpl) storedFilterChanges(ctx context.Context, store rpchelper.FilterStore, f *rpchelper.StoredFilter) ([]any, error) {
+	if f.Type == rpchelper.PendingTxsStoredFilter {
+		changes := make([]any, 0)
+		for _, txn := range api.filters.ReadStoredPendingTxs(f.ID) {
+			if txn != nil {
+				changes = append(changes, txn.Hash())
+			}
+		}
+		f.LastPoll = time.Now()
+		if err := store.Put(f); err != nil && !errors.Is(err, rpchelper.ErrStoredFilterChanged) {
+			return nil, err
+		}
+		// a concurrent poll kept the filter alive as well
+		return changes, nil
+	}
+
+	for attempt := 1; ; attempt++ {
+		changes, err := api.pollStoredFilter(ctx, store, f)
+		if !errors.Is(err, rpchelper.ErrStoredFilterChanged) || attempt == storedFilterPollAttempts {
+			return changes, err
+		}
+		if f, err = store.Get(f.ID); err != nil {
+			return nil, err
+		}
+		if f == nil {
+			return nil, errors.New("filter not found")
+		}
+	}
+}
+
+// pollStoredFilter computes the changes of a log or block filter since its
+// last poll and stores the filter moved past them.
+func (api *APIImpl) pollStoredFilter(ctx context.Context, store rpchelper.FilterStore, f *rpchelper.StoredFilter) ([]any, error) {
+	changes := make([]any, 0)
+
+	tx, err := api.db.BeginTemporalRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	latest, latestHash, _, err := rpchelper.GetBlockNumber(ctx, rpc.BlockNumberOrHashWithNumber(rpc.LatestExecutedBlockNumber), tx, api._blockReader, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	forkPoint, forkHash, err := api.storedFilterForkPoint(ctx, tx, f)
+	if err != nil {
+		return nil, err
+	}
+	if forkPoint < f.LastBlock {
+		for _, lg := range f.Rewind(forkPoint, forkHash) {
+			changes = append(changes, lg)
+		}
+	}
+
+	if latest > f.LastBlock {
+		var logs []*types.Log
+		switch f.Type {
+		case rpchelper.BlocksStoredFilter:
+			for number := f.LastBlock + 1; number <= latest; number++ {
+				hash, ok, err := api._blockReader.CanonicalHash(ctx, tx, number)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					return nil, fmt.Errorf("canonical hash not found %d", number)
+				}
+				changes = append(changes, hash)
+			}
+		case rpchelper.LogsStoredFilter:
+			rpcLogs, err := api.getLogsV3(ctx, tx, f.LastBlock+1, latest, f.Criteria())
+			if err != nil {
+				return nil, err
+			}
+			for _, lg := range rpcLogs {
+				logs = append(logs, &types.Log{
+					Address:     lg.Address,
+					Topics:      lg.Topics,
+					Data:        lg.Data,
+					BlockNumber: lg.BlockNumber,
+					TxHash:      lg.TxHash,
+					TxIndex:     lg.TxIndex,
+					BlockHash:   lg.BlockHash,
+					Index:       lg.Index,
+					Removed:     lg.Removed,
+				})
+			}
+			for _, lg := range logs {
+				changes = append(changes, lg)
+			}
+		default:
+			return nil, fmt.Errorf("unknown filter type %q", f.Type)
+		}
+		f.Advance(latest, latestHash, logs)
+	}
+	f.LastPoll = time.Now()
+	return changes, store.Put(f)
+}
+
+// storedFilterForkPoint returns the highest block of the chain last polled by
+// a stored filter which is still canonical. It walks down the dropped blocks
+// by their parent hashes, if they are pruned already it goes as deep as the
+// logs returned for them are kept.
+func (api *APIImpl) storedFilterForkPoint(ctx context.Context, tx kv.Tx, f *rpchelper.StoredFilter) (uint64, common.Hash, error) {
+	number, hash := f.LastBlock, f.LastBlockHash
+	for {
+		canonical, ok, err := api._blockReader.CanonicalHash(ctx, tx, number)
+		if err != nil {
+			return 0, common.Hash{}, err
+		}
+		if (ok && canonical == hash) || number == 0 {
+			return number, canonical, nil
+		}
+		header, err := api._blockReader.HeaderByHash(ctx, tx, hash)
+		if err != nil {
+			return 0, common.Hash{}, err
+		}
+		if header == nil {
+			number -= min(number, rpchelper.StoredFilterReorgDepth)
+			canonical, _, err := api._blockReader.CanonicalHash(ctx, tx, number)
+			return number, canonical, err
+		}
+		number, hash = number-1, header.ParentHash
+	}
+}
+
 // NewHeads send a notification each time a new (header) block is appended to the chain.
 func (api *APIImpl) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
 	if api.filters == nil {
@@ -176,6 +386,47 @@ func (api *APIImpl) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
 	return rpcSub, nil
 }
 
+// NewHeadsWithReorgs implements eth_subscribe("newHeadsWithReorgs"). Like
+// newHeads it sends a notification each time a block is appended to the
+// canonical chain, it also tells which previously notified headers are no
+// longer canonical after a reorg: {"removed": [headers], "added": [headers]}.
+func (api *APIImpl) NewHeadsWithReorgs(ctx context.Context) (*rpc.Subscription, error) {
+	if api.filters == nil {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		defer debug.LogPanic()
+		changes, id := api.filters.SubscribeHeadChanges(32)
+		defer api.filters.UnsubscribeHeadChanges(id)
+		for {
+			select {
+			case change, ok := <-changes:
+				if change != nil {
+					err := notifier.Notify(rpcSub.ID, change)
+					if err != nil {
+						log.Warn("[rpc] error while notifying subscription", "err", err)
+					}
+				}
+				if !ok {
+					log.Warn("[rpc] head changes channel was closed")
+					return
+				}
+			case <-rpcSub.Err():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 // NewPendingTransactions send a notification each time when a transaction had added into mempool.
 func (api *APIImpl) NewPendingTransactions(ctx context.Context, fullTx *bool) (*rpc.Subscription, error) {
 	if api.filters == nil {
@@ -302,3 +553,80 @@ func (api *APIImpl) Logs(ctx context.Context, crit filters.FilterCriteria) (*rpc
 
 	return rpcSub, nil
 }
+
+// SyncingAPI serves eth_subscribe("syncing"), it can't be served by APIImpl
+// which already has a Syncing method for eth_syncing.
+type SyncingAPI struct {
+	ethBackend rpchelper.ApiBackend
+	filters    *rpchelper.Filters
+}
+
+func NewSyncingAPI(ethBackend rpchelper.ApiBackend, filters *rpchelper.Filters) *SyncingAPI {
+	return &SyncingAPI{ethBackend: ethBackend, filters: filters}
+}
+
+// syncingResult is a notification of the syncing subscription, Status is the
+// same as returned by eth_syncing while the node is syncing.
+type syncingResult struct {
+	Syncing bool                   `json:"syncing"`
+	Status  map[string]interface{} `json:"status,omitempty"`
+}
+
+// Syncing implements eth_subscribe("syncing"). It sends the sync status
+// right away, then every time the node starts or stops syncing and, while it
+// is syncing, every time the progress of the stages changes.
+func (api *SyncingAPI) Syncing(ctx context.Context) (*rpc.Subscription, error) {
+	if api.filters == nil {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	current, err := api.ethBackend.Syncing(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		defer debug.LogPanic()
+		replies, id := api.filters.SubscribeSyncing(8)
+		defer api.filters.UnsubscribeSyncing(id)
+
+		var last []byte
+		notify := func(reply *remote.SyncingReply) {
+			res := syncingResult{Syncing: reply.Syncing}
+			if reply.Syncing {
+				res.Status = syncingStatus(reply)
+			}
+			encoded, err := json.Marshal(res)
+			if err != nil || bytes.Equal(encoded, last) {
+				return
+			}
+			last = encoded
+			if err := notifier.Notify(rpcSub.ID, json.RawMessage(encoded)); err != nil {
+				log.Warn("[rpc] error while notifying subscription", "err", err)
+			}
+		}
+
+		notify(current)
+		for {
+			select {
+			case reply, ok := <-replies:
+				if reply != nil {
+					notify(reply)
+				}
+				if !ok {
+					log.Warn("[rpc] syncing channel was closed")
+					return
+				}
+			case <-rpcSub.Err():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
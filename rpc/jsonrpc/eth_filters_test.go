@@ -17,18 +17,24 @@
 package jsonrpc
 
 import (
+	"errors"
+	"math/big"
 	"math/rand"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/erigontech/erigon-db/rawdb"
 	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/length"
 	txpool "github.com/erigontech/erigon-lib/gointerfaces/txpoolproto"
+	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/kv/kvcache"
 	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/types"
 	"github.com/erigontech/erigon/cmd/rpcdaemon/rpcdaemontest"
 	"github.com/erigontech/erigon/eth/ethconfig"
 	"github.com/erigontech/erigon/eth/filters"
@@ -114,3 +120,145 @@ func TestLogsSubscribeAndUnsubscribe_WithoutConcurrentMapIssue(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestStoredFilters(t *testing.T) {
+	require := require.New(t)
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	ctx, conn := rpcdaemontest.CreateTestGrpcConn(t, mock.Mock(t))
+	mining := txpool.NewMiningClient(conn)
+	cfg := rpchelper.DefaultFiltersConfig
+	cfg.RpcFiltersStoreDir = t.TempDir()
+	newAPI := func() *APIImpl {
+		ff := rpchelper.New(ctx, cfg, nil, nil, mining, func() {}, m.Log)
+		require.NotNil(ff.Store())
+		return NewEthAPI(NewBaseApi(ff, stateCache, m.BlockReader, false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs, nil), m.DB, nil, nil, nil, 5000000, ethconfig.Defaults.RPCTxFeeCap, 100_000, false, 100_000, 128, log.New())
+	}
+	api := newAPI()
+
+	bf, err := api.NewBlockFilter(ctx)
+	require.NoError(err)
+	nf, err := api.NewFilter(ctx, filters.FilterCriteria{})
+	require.NoError(err)
+	ptf, err := api.NewPendingTransactionFilter(ctx)
+	require.NoError(err)
+
+	// no new blocks since the filters were installed
+	changes, err := api.GetFilterChanges(ctx, bf)
+	require.NoError(err)
+	require.Empty(changes)
+
+	// pretend the filters were last polled at genesis, before a restart
+	store := api.filters.Store()
+	for _, id := range []string{bf, nf} {
+		f, err := store.Get(id[2:])
+		require.NoError(err)
+		f.LastBlock = 0
+		require.NoError(store.Put(f))
+	}
+	api = newAPI()
+
+	changes, err = api.GetFilterChanges(ctx, bf)
+	require.NoError(err)
+	tx, err := m.DB.BeginRo(ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+	head, err := rpchelper.GetLatestBlockNumber(tx)
+	require.NoError(err)
+	require.Len(changes, int(head))
+	hash, _, err := m.BlockReader.CanonicalHash(ctx, tx, 1)
+	require.NoError(err)
+	require.Equal(hash, changes[0])
+
+	changes, err = api.GetFilterChanges(ctx, nf)
+	require.NoError(err)
+	require.NotEmpty(changes)
+	require.IsType(&types.Log{}, changes[0])
+	var logs []*types.Log
+	for _, change := range changes {
+		logs = append(logs, change.(*types.Log))
+	}
+
+	// changes are returned once
+	changes, err = api.GetFilterChanges(ctx, nf)
+	require.NoError(err)
+	require.Empty(changes)
+
+	// pretend the logs of the block with the last ones were returned for a
+	// block which a reorg dropped since
+	reorged := logs[len(logs)-1].BlockNumber
+	var reorgedLogs []*types.Log
+	for _, lg := range logs {
+		if lg.BlockNumber == reorged {
+			reorgedLogs = append(reorgedLogs, lg)
+		}
+	}
+	parentHash, _, err := m.BlockReader.CanonicalHash(ctx, tx, reorged-1)
+	require.NoError(err)
+	dropped := &types.Header{Number: new(big.Int).SetUint64(reorged), ParentHash: parentHash, Extra: []byte("dropped")}
+	require.NoError(m.DB.Update(ctx, func(tx kv.RwTx) error { return rawdb.WriteHeader(tx, dropped) }))
+	for _, id := range []string{bf, nf} {
+		f, err := store.Get(id[2:])
+		require.NoError(err)
+		f.LastBlock, f.LastBlockHash = reorged, dropped.Hash()
+		require.NoError(store.Put(f))
+	}
+
+	changes, err = api.GetFilterChanges(ctx, nf)
+	require.NoError(err)
+	var removed, added []*types.Log
+	for _, change := range changes {
+		if lg := change.(*types.Log); lg.Removed {
+			removed = append(removed, lg)
+		} else {
+			added = append(added, lg)
+		}
+	}
+	require.Len(removed, len(reorgedLogs))
+	for i, lg := range removed {
+		require.Equal(reorgedLogs[i].TxHash, lg.TxHash)
+		require.Equal(reorgedLogs[i].Index, lg.Index)
+	}
+	require.Equal(reorgedLogs, added) // the block of the canonical chain has the same logs
+
+	changes, err = api.GetFilterChanges(ctx, bf)
+	require.NoError(err)
+	require.Len(changes, int(head-reorged)+1)
+	hash, _, err = m.BlockReader.CanonicalHash(ctx, tx, reorged)
+	require.NoError(err)
+	require.Equal(hash, changes[0])
+	changes, err = api.GetFilterChanges(ctx, ptf)
+	require.NoError(err)
+	require.Empty(changes)
+
+	// concurrent polls from two rpcdaemons return every change once
+	f, err := store.Get(bf[2:])
+	require.NoError(err)
+	f.LastBlock = 0
+	require.NoError(store.Put(f))
+	var wg sync.WaitGroup
+	polled, errs := make([][]any, 2), make([]error, 2)
+	for i, api := range []*APIImpl{api, newAPI()} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			polled[i], errs[i] = api.GetFilterChanges(ctx, bf)
+		}()
+	}
+	wg.Wait()
+	require.NoError(errors.Join(errs...))
+	seen := map[any]struct{}{}
+	for _, change := range append(polled[0], polled[1]...) {
+		require.NotContains(seen, change)
+		seen[change] = struct{}{}
+	}
+	require.Len(seen, int(head))
+
+	for _, id := range []string{bf, nf, ptf} {
+		ok, err := api.UninstallFilter(ctx, id)
+		require.NoError(err)
+		require.True(ok)
+	}
+	_, err = api.GetFilterChanges(ctx, bf)
+	require.Error(err)
+}
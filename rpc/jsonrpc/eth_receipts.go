@@ -140,6 +140,9 @@ func (api *APIImpl) GetLogs(ctx context.Context, crit filters.FilterCriteria) (t
 		}
 		end = latest
 	}
+	if err := api.checkPruneHistoryOf(tx, begin, crit.Addresses...); err != nil {
+		return nil, err
+	}
 
 	erigonLogs, err := api.getLogsV3(ctx, tx, begin, end, crit)
 	if err != nil {
@@ -0,0 +1,609 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/holiman/uint256"
+
+	"github.com/erigontech/erigon-lib/chain"
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/crypto"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon/core"
+	"github.com/erigontech/erigon/core/state"
+	"github.com/erigontech/erigon/core/tracing"
+	"github.com/erigontech/erigon/core/vm"
+	"github.com/erigontech/erigon/core/vm/evmtypes"
+	"github.com/erigontech/erigon/execution/consensus"
+	"github.com/erigontech/erigon/execution/consensus/misc"
+	"github.com/erigontech/erigon/rpc"
+	"github.com/erigontech/erigon/rpc/ethapi"
+	"github.com/erigontech/erigon/rpc/rpchelper"
+)
+
+const (
+	// maxSimulateBlocks is the maximum number of blocks (including the empty
+	// ones inserted to fill number gaps) a single eth_simulateV1 request may produce.
+	maxSimulateBlocks = 256
+	// simulateTimestampIncrement is the default time distance between two simulated blocks.
+	simulateTimestampIncrement = 12
+)
+
+// Error codes defined by the eth_simulateV1 specification.
+const (
+	simErrCodeNonceTooLow            = -38010
+	simErrCodeNonceTooHigh           = -38011
+	simErrCodeIntrinsicGas           = -38013
+	simErrCodeInsufficientFunds      = -38014
+	simErrCodeBlockGasLimitReached   = -38015
+	simErrCodeBlockNumberInvalid     = -38020
+	simErrCodeBlockTimestampInvalid  = -38021
+	simErrCodeSenderIsNotEOA         = -38024
+	simErrCodeMaxInitCodeSizeExceed  = -38025
+	simErrCodeClientLimitExceeded    = -38026
+	simErrCodeFeeCapTooLow           = -32005
+	simErrCodeInternalError          = -32603
+	simErrCodeReverted               = 3
+	simErrCodeVMError                = -32015
+	simErrCodeInvalidParams          = -32602
+	simErrCodeTimeout                = -32016
+	simErrCodeMaxFeePerBlobGasTooLow = -38012
+)
+
+// transferAddress is the pseudo-address ERC-7528 assigns to native ETH, used as the
+// emitter of the synthetic Transfer logs produced when traceTransfers is enabled.
+var transferAddress = common.HexToAddress("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE")
+
+// transferTopic is keccak256("Transfer(address,address,uint256)").
+var transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// SimulationRequest is the payload of eth_simulateV1.
+type SimulationRequest struct {
+	BlockStateCalls        []SimulatedBlock `json:"blockStateCalls"`
+	TraceTransfers         bool             `json:"traceTransfers"`
+	Validation             bool             `json:"validation"`
+	ReturnFullTransactions bool             `json:"returnFullTransactions"`
+}
+
+// SimulatedBlock is a single block of an eth_simulateV1 request: the overrides applied
+// before the block is executed and the calls it contains.
+type SimulatedBlock struct {
+	BlockOverrides *ethapi.BlockOverrides `json:"blockOverrides"`
+	StateOverrides *ethapi.StateOverrides `json:"stateOverrides"`
+	Calls          []ethapi.CallArgs      `json:"calls"`
+}
+
+// SimulatedCallResult is the outcome of one call of a simulated block.
+type SimulatedCallResult struct {
+	ReturnValue hexutil.Bytes       `json:"returnData"`
+	Logs        []*types.Log        `json:"logs"`
+	GasUsed     hexutil.Uint64      `json:"gasUsed"`
+	Status      hexutil.Uint64      `json:"status"`
+	Error       *SimulatedCallError `json:"error,omitempty"`
+}
+
+// SimulatedCallError describes why a simulated call failed without invalidating its block.
+type SimulatedCallError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+// SimulateV1 implements eth_simulateV1. Executes a sequence of calls grouped into blocks on top of the given
+// block, carrying state from one block to the next, and returns the resulting blocks with per-call results.
+func (api *APIImpl) SimulateV1(ctx context.Context, req SimulationRequest, blockNrOrHash *rpc.BlockNumberOrHash) ([]map[string]interface{}, error) {
+	if len(req.BlockStateCalls) == 0 {
+		return nil, &rpc.CustomError{Code: simErrCodeInvalidParams, Message: "empty input"}
+	}
+	if len(req.BlockStateCalls) > maxSimulateBlocks {
+		return nil, &rpc.CustomError{Code: simErrCodeClientLimitExceeded, Message: "too many blocks"}
+	}
+	if blockNrOrHash == nil {
+		blockNrOrHash = &latestNumOrHash
+	}
+
+	tx, err := api.db.BeginTemporalRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	chainConfig, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	header, _, err := headerByNumberOrHash(ctx, tx, *blockNrOrHash, api)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, errors.New("header not found")
+	}
+
+	stateReader, err := rpchelper.CreateStateReader(ctx, tx, api._blockReader, *blockNrOrHash, 0, api.filters, api.stateCache, api._txNumReader)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(start time.Time) { log.Trace("Executing EVM simulateV1 finished", "runtime", time.Since(start)) }(time.Now())
+
+	if api.evmCallTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, api.evmCallTimeout)
+		defer cancel()
+	}
+
+	sim := &simulator{
+		api:            api,
+		tx:             tx,
+		chainConfig:    chainConfig,
+		engine:         api.engine(),
+		base:           header,
+		ibs:            state.New(stateReader),
+		hashes:         map[uint64]common.Hash{},
+		traceTransfers: req.TraceTransfers,
+		validation:     req.Validation,
+		fullTx:         req.ReturnFullTransactions,
+	}
+	return sim.execute(ctx, req.BlockStateCalls)
+}
+
+// simulator carries the state of one eth_simulateV1 request from block to block.
+type simulator struct {
+	api         *APIImpl
+	tx          kv.Tx
+	chainConfig *chain.Config
+	engine      consensus.EngineReader
+	base        *types.Header
+
+	ibs *state.IntraBlockState
+	// hashes of the blocks simulated so far, consulted by BLOCKHASH before the canonical chain
+	hashes map[uint64]common.Hash
+	// txIndex is a running counter over all simulated transactions: the same IntraBlockState
+	// is shared by every block, so logs must be collected under request-unique indices
+	txIndex int
+
+	traceTransfers bool
+	validation     bool
+	fullTx         bool
+}
+
+func (s *simulator) execute(ctx context.Context, blocks []SimulatedBlock) ([]map[string]interface{}, error) {
+	blocks, err := s.sanitizeChain(blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	var tracer *transferTracer
+	if s.traceTransfers {
+		tracer = &transferTracer{}
+		s.ibs.SetHooks(tracer.Hooks())
+	}
+
+	results := make([]map[string]interface{}, 0, len(blocks))
+	parent := s.base
+	for i := range blocks {
+		fields, header, err := s.simulateBlock(ctx, &blocks[i], parent, tracer)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, fields)
+		parent = header
+	}
+	return results, nil
+}
+
+// sanitizeChain assigns numbers and timestamps to blocks that don't override them, validates the ones that do, and
+// inserts empty blocks to fill gaps between requested block numbers.
+func (s *simulator) sanitizeChain(blocks []SimulatedBlock) ([]SimulatedBlock, error) {
+	res := make([]SimulatedBlock, 0, len(blocks))
+	prevNumber := s.base.Number.Uint64()
+	prevTime := s.base.Time
+	for _, block := range blocks {
+		overrides := ethapi.BlockOverrides{}
+		if block.BlockOverrides != nil {
+			overrides = *block.BlockOverrides
+		}
+		block.BlockOverrides = &overrides
+
+		number := prevNumber + 1
+		if overrides.Number != nil {
+			n := overrides.Number.ToInt()
+			if !n.IsUint64() || n.Uint64() <= prevNumber {
+				return nil, &rpc.CustomError{Code: simErrCodeBlockNumberInvalid, Message: fmt.Sprintf("block numbers must be in order: %s <= %d", n, prevNumber)}
+			}
+			number = n.Uint64()
+		}
+		if number-s.base.Number.Uint64() > maxSimulateBlocks {
+			return nil, &rpc.CustomError{Code: simErrCodeClientLimitExceeded, Message: "too many blocks"}
+		}
+		for n := prevNumber + 1; n < number; n++ {
+			prevTime += simulateTimestampIncrement
+			t := prevTime
+			res = append(res, SimulatedBlock{BlockOverrides: &ethapi.BlockOverrides{
+				Number: (*hexutil.Big)(new(big.Int).SetUint64(n)),
+				Time:   (*hexutil.Uint64)(&t),
+			}})
+		}
+		overrides.Number = (*hexutil.Big)(new(big.Int).SetUint64(number))
+		prevNumber = number
+
+		if overrides.Time == nil {
+			t := prevTime + simulateTimestampIncrement
+			overrides.Time = (*hexutil.Uint64)(&t)
+		} else if uint64(*overrides.Time) <= prevTime {
+			return nil, &rpc.CustomError{Code: simErrCodeBlockTimestampInvalid, Message: fmt.Sprintf("block timestamps must be in order: %d <= %d", uint64(*overrides.Time), prevTime)}
+		}
+		prevTime = uint64(*overrides.Time)
+
+		res = append(res, block)
+	}
+	return res, nil
+}
+
+// makeHeader derives the header of a simulated block from its parent and the block overrides.
+func (s *simulator) makeHeader(overrides *ethapi.BlockOverrides, parent *types.Header) *types.Header {
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Coinbase:   parent.Coinbase,
+		Difficulty: new(big.Int).Set(parent.Difficulty),
+		Number:     overrides.Number.ToInt(),
+		GasLimit:   parent.GasLimit,
+		Time:       uint64(*overrides.Time),
+	}
+	if overrides.FeeRecipient != nil {
+		header.Coinbase = *overrides.FeeRecipient
+	}
+	if overrides.GasLimit != nil {
+		header.GasLimit = uint64(*overrides.GasLimit)
+	}
+	if overrides.PrevRanDao != nil {
+		header.MixDigest = *overrides.PrevRanDao
+	}
+	if s.chainConfig.IsLondon(header.Number.Uint64()) {
+		switch {
+		case overrides.BaseFeePerGas != nil:
+			header.BaseFee = new(big.Int).Set(overrides.BaseFeePerGas.ToInt())
+		case s.validation:
+			header.BaseFee = misc.CalcBaseFee(s.chainConfig, parent)
+		default:
+			// Without validation calls are allowed to have zero gas price, so the base fee is zero as well.
+			header.BaseFee = new(big.Int)
+		}
+	}
+	if s.chainConfig.IsCancun(header.Time) {
+		excessBlobGas := misc.CalcExcessBlobGas(s.chainConfig, parent, header.Time)
+		header.ExcessBlobGas = &excessBlobGas
+		header.BlobGasUsed = new(uint64)
+		header.ParentBeaconBlockRoot = new(common.Hash)
+	}
+	if s.chainConfig.IsPrague(header.Time) {
+		header.RequestsHash = types.FlatRequests{}.Hash()
+	}
+	return header
+}
+
+func (s *simulator) getHash(ctx context.Context) func(n uint64) (common.Hash, error) {
+	return func(n uint64) (common.Hash, error) {
+		if hash, ok := s.hashes[n]; ok {
+			return hash, nil
+		}
+		hash, ok, err := s.api._blockReader.CanonicalHash(ctx, s.tx, n)
+		if err != nil || !ok {
+			log.Debug("Can't get block hash by number", "number", n, "only-canonical", true, "err", err, "ok", ok)
+		}
+		return hash, err
+	}
+}
+
+// simulateBlock executes the calls of a single simulated block and returns its RPC representation.
+// System contracts (EIP-4788, EIP-2935) are not invoked and the state root is left empty: the simulated
+// state only lives in the IntraBlockState and is never committed.
+func (s *simulator) simulateBlock(ctx context.Context, block *SimulatedBlock, parent *types.Header, tracer *transferTracer) (map[string]interface{}, *types.Header, error) {
+	header := s.makeHeader(block.BlockOverrides, parent)
+	blockNum := header.Number.Uint64()
+
+	if block.StateOverrides != nil {
+		if err := block.StateOverrides.Override(s.ibs); err != nil {
+			return nil, nil, &rpc.CustomError{Code: simErrCodeInvalidParams, Message: err.Error()}
+		}
+	}
+
+	blockCtx := core.NewEVMBlockContext(header, s.getHash(ctx), s.engine, &header.Coinbase, s.chainConfig)
+	if block.BlockOverrides.BlobBaseFee != nil {
+		blobBaseFee, overflow := uint256.FromBig(block.BlockOverrides.BlobBaseFee.ToInt())
+		if overflow {
+			return nil, nil, &rpc.CustomError{Code: simErrCodeInvalidParams, Message: "BlockOverrides.BlobBaseFee uint256 overflow"}
+		}
+		blockCtx.BlobBaseFee = blobBaseFee
+	} else if !s.validation && blockCtx.BlobBaseFee != nil {
+		blockCtx.BlobBaseFee = new(uint256.Int)
+	}
+	rules := s.chainConfig.Rules(blockNum, header.Time)
+
+	vmConfig := vm.Config{NoBaseFee: !s.validation}
+	if tracer != nil {
+		vmConfig.Tracer = tracer.Hooks()
+	}
+
+	gp := new(core.GasPool).AddGas(header.GasLimit).AddBlobGas(s.chainConfig.GetMaxBlobGasPerBlock(header.Time))
+	var (
+		txns        = make([]types.Transaction, 0, len(block.Calls))
+		senders     = make([]common.Address, 0, len(block.Calls))
+		receipts    = make(types.Receipts, 0, len(block.Calls))
+		callResults = make([]SimulatedCallResult, 0, len(block.Calls))
+		cumGasUsed  uint64
+		blobGasUsed uint64
+	)
+	for i := range block.Calls {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, &rpc.CustomError{Code: simErrCodeTimeout, Message: fmt.Sprintf("execution aborted (timeout = %v)", s.api.evmCallTimeout)}
+		}
+		call := block.Calls[i]
+		if err := s.sanitizeCall(&call, gp); err != nil {
+			return nil, nil, err
+		}
+		baseFee := blockCtx.BaseFee
+		msg, err := call.ToMessage(s.api.GasCap, baseFee)
+		if err != nil {
+			return nil, nil, &rpc.CustomError{Code: simErrCodeInvalidParams, Message: err.Error()}
+		}
+		msg.SetCheckNonce(s.validation)
+		txn, err := call.ToTransaction(s.api.GasCap, baseFee)
+		if err != nil {
+			return nil, nil, &rpc.CustomError{Code: simErrCodeInvalidParams, Message: err.Error()}
+		}
+
+		s.ibs.SetTxContext(blockNum, s.txIndex)
+		if tracer != nil {
+			tracer.reset()
+		}
+		evm := vm.NewEVM(blockCtx, core.NewEVMTxContext(msg), s.ibs, s.chainConfig, vmConfig)
+		stop := context.AfterFunc(ctx, evm.Cancel)
+		result, err := core.ApplyMessage(evm, msg, gp, true /* refunds */, false /* gasBailout */, s.engine)
+		stop()
+		if err != nil {
+			return nil, nil, simTxValidationError(err)
+		}
+		if evm.Cancelled() {
+			return nil, nil, &rpc.CustomError{Code: simErrCodeTimeout, Message: fmt.Sprintf("execution aborted (timeout = %v)", s.api.evmCallTimeout)}
+		}
+		if err = s.ibs.FinalizeTx(rules, state.NewNoopWriter()); err != nil {
+			return nil, nil, err
+		}
+
+		var logs types.Logs
+		if tracer != nil {
+			logs = tracer.logs()
+		} else {
+			logs = s.ibs.GetRawLogs(s.txIndex)
+		}
+		s.txIndex++
+
+		cumGasUsed += result.GasUsed
+		blobGasUsed += msg.BlobGas()
+		receipt := &types.Receipt{
+			Type:              txn.Type(),
+			CumulativeGasUsed: cumGasUsed,
+			TxHash:            txn.Hash(),
+			GasUsed:           result.GasUsed,
+			Logs:              logs,
+			BlockNumber:       header.Number,
+			TransactionIndex:  uint(i),
+		}
+		if result.Failed() {
+			receipt.Status = types.ReceiptStatusFailed
+		} else {
+			receipt.Status = types.ReceiptStatusSuccessful
+		}
+		if msg.To() == nil {
+			receipt.ContractAddress = crypto.CreateAddress(msg.From(), msg.Nonce())
+		}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+
+		callResult := SimulatedCallResult{
+			ReturnValue: common.CopyBytes(result.Return()),
+			GasUsed:     hexutil.Uint64(result.GasUsed),
+			Status:      hexutil.Uint64(receipt.Status),
+		}
+		if result.Err != nil {
+			callResult.Error = simCallError(result)
+		}
+
+		txns = append(txns, txn)
+		senders = append(senders, msg.From())
+		receipts = append(receipts, receipt)
+		callResults = append(callResults, callResult)
+	}
+
+	var withdrawals types.Withdrawals
+	if s.chainConfig.IsShanghai(header.Time) {
+		withdrawals = block.BlockOverrides.Withdrawals
+		if withdrawals == nil {
+			withdrawals = types.Withdrawals{}
+		}
+	}
+	for _, w := range withdrawals {
+		amount := new(uint256.Int).Mul(new(uint256.Int).SetUint64(w.Amount), uint256.NewInt(common.GWei))
+		if err := s.ibs.AddBalance(w.Address, *amount, tracing.BalanceIncreaseWithdrawal); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	header.GasUsed = cumGasUsed
+	if header.BlobGasUsed != nil {
+		*header.BlobGasUsed = blobGasUsed
+	}
+	b := types.NewBlock(header, txns, nil, receipts, withdrawals)
+	blockHash := b.Hash()
+	s.hashes[blockNum] = blockHash
+
+	var logIndex uint
+	for i, receipt := range receipts {
+		receipt.BlockHash = blockHash
+		for _, l := range receipt.Logs {
+			l.BlockHash = blockHash
+			l.BlockNumber = blockNum
+			l.TxHash = receipt.TxHash
+			l.TxIndex = uint(i)
+			l.Index = logIndex
+			logIndex++
+		}
+		callResults[i].Logs = receipt.Logs
+		if callResults[i].Logs == nil {
+			callResults[i].Logs = []*types.Log{}
+		}
+	}
+
+	fields, err := ethapi.RPCMarshalBlock(b, true, false, map[string]interface{}{"calls": callResults})
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.fullTx {
+		transactions := make([]interface{}, len(txns))
+		for i, txn := range txns {
+			rpcTxn := ethapi.NewRPCTransaction(txn, blockHash, blockNum, uint64(i), header.BaseFee)
+			// simulated transactions are not signed, the sender comes from the call itself
+			rpcTxn.From = senders[i]
+			transactions[i] = rpcTxn
+		}
+		fields["transactions"] = transactions
+	}
+	return fields, b.HeaderNoCopy(), nil
+}
+
+// sanitizeCall fills in the call fields eth_simulateV1 defaults from the current state and block.
+func (s *simulator) sanitizeCall(call *ethapi.CallArgs, gp *core.GasPool) error {
+	if call.From == nil {
+		call.From = new(common.Address)
+	}
+	if call.Nonce == nil {
+		nonce, err := s.ibs.GetNonce(*call.From)
+		if err != nil {
+			return err
+		}
+		call.Nonce = (*hexutil.Uint64)(&nonce)
+	}
+	if call.Gas == nil {
+		remaining := gp.Gas()
+		call.Gas = (*hexutil.Uint64)(&remaining)
+	}
+	if gp.Gas() < uint64(*call.Gas) {
+		return &rpc.CustomError{Code: simErrCodeBlockGasLimitReached, Message: fmt.Sprintf("block gas limit reached: %d >= %d", uint64(*call.Gas), gp.Gas())}
+	}
+	if call.ChainID == nil {
+		call.ChainID = (*hexutil.Big)(s.chainConfig.ChainID)
+	}
+	return nil
+}
+
+// simCallError converts a failed (but included) call into its eth_simulateV1 error object.
+func simCallError(result *evmtypes.ExecutionResult) *SimulatedCallError {
+	if errors.Is(result.Err, vm.ErrExecutionReverted) {
+		revertErr := ethapi.NewRevertError(result)
+		return &SimulatedCallError{Code: simErrCodeReverted, Message: revertErr.Error(), Data: revertErr.ErrorData().(string)}
+	}
+	return &SimulatedCallError{Code: simErrCodeVMError, Message: result.Err.Error()}
+}
+
+// simTxValidationError maps a consensus error, which invalidates the whole simulated chain, to its spec error code.
+func simTxValidationError(err error) error {
+	code := simErrCodeInternalError
+	switch {
+	case errors.Is(err, core.ErrNonceTooHigh):
+		code = simErrCodeNonceTooHigh
+	case errors.Is(err, core.ErrNonceTooLow):
+		code = simErrCodeNonceTooLow
+	case errors.Is(err, core.ErrIntrinsicGas):
+		code = simErrCodeIntrinsicGas
+	case errors.Is(err, core.ErrInsufficientFunds):
+		code = simErrCodeInsufficientFunds
+	case errors.Is(err, core.ErrGasLimitReached), errors.Is(err, core.ErrBlobGasLimitReached):
+		code = simErrCodeBlockGasLimitReached
+	case errors.Is(err, core.ErrSenderNoEOA):
+		code = simErrCodeSenderIsNotEOA
+	case errors.Is(err, core.ErrMaxInitCodeSizeExceeded):
+		code = simErrCodeMaxInitCodeSizeExceed
+	case errors.Is(err, core.ErrFeeCapTooLow):
+		code = simErrCodeFeeCapTooLow
+	case errors.Is(err, core.ErrMaxFeePerBlobGas):
+		code = simErrCodeMaxFeePerBlobGasTooLow
+	}
+	return &rpc.CustomError{Code: code, Message: err.Error()}
+}
+
+// transferTracer collects the logs of a transaction together with synthetic ERC-7528 Transfer logs for every
+// ether transfer, dropping the ones emitted by reverted call frames.
+type transferTracer struct {
+	frames [][]*types.Log
+}
+
+func (t *transferTracer) Hooks() *tracing.Hooks {
+	return &tracing.Hooks{
+		OnEnter: t.onEnter,
+		OnExit:  t.onExit,
+		OnLog:   t.onLog,
+	}
+}
+
+func (t *transferTracer) reset() {
+	t.frames = [][]*types.Log{{}}
+}
+
+func (t *transferTracer) logs() types.Logs {
+	if len(t.frames) == 0 {
+		return nil
+	}
+	return t.frames[0]
+}
+
+func (t *transferTracer) onEnter(depth int, typ byte, from common.Address, to common.Address, precompile bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	t.frames = append(t.frames, []*types.Log{})
+	if vm.OpCode(typ) == vm.DELEGATECALL || value == nil || value.IsZero() {
+		return
+	}
+	top := len(t.frames) - 1
+	t.frames[top] = append(t.frames[top], &types.Log{
+		Address: transferAddress,
+		Topics:  []common.Hash{transferTopic, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+		Data:    common.BigToHash(value.ToBig()).Bytes(),
+	})
+}
+
+func (t *transferTracer) onExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	top := len(t.frames) - 1
+	frame := t.frames[top]
+	t.frames = t.frames[:top]
+	if !reverted {
+		t.frames[top-1] = append(t.frames[top-1], frame...)
+	}
+}
+
+func (t *transferTracer) onLog(l *types.Log) {
+	top := len(t.frames) - 1
+	t.frames[top] = append(t.frames[top], l)
+}
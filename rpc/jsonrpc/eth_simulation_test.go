@@ -0,0 +1,118 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon/eth/ethconfig"
+	"github.com/erigontech/erigon/rpc"
+	"github.com/erigontech/erigon/rpc/ethapi"
+)
+
+func TestSimulateV1(t *testing.T) {
+	m, bankAddress, contractAddress := chainWithDeployedContract(t)
+	api := NewEthAPI(newBaseApiForTest(m), m.DB, nil, nil, nil, 5000000, ethconfig.Defaults.RPCTxFeeCap, 100_000, false, 100_000, 128, log.New())
+	ctx := context.Background()
+
+	store := hexutil.Bytes(contractInvocationData(7))
+	retrieve := hexutil.Bytes(hexutil.MustDecode("0x2e64cec1"))
+	receiver := common.HexToAddress("0x1234")
+	value := (*hexutil.Big)(big.NewInt(1000))
+
+	res, err := api.SimulateV1(ctx, SimulationRequest{
+		TraceTransfers: true,
+		BlockStateCalls: []SimulatedBlock{
+			{Calls: []ethapi.CallArgs{{From: &bankAddress, To: &contractAddress, Data: &store}}},
+			{
+				BlockOverrides: &ethapi.BlockOverrides{Number: (*hexutil.Big)(big.NewInt(7))},
+				Calls: []ethapi.CallArgs{
+					{From: &bankAddress, To: &contractAddress, Data: &retrieve},
+					{From: &bankAddress, To: &receiver, Value: value},
+				},
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+	// blocks 5 and 6 are inserted to fill the gap up to the requested block 7
+	require.Len(t, res, 4)
+	for i, block := range res {
+		require.Equal(t, (*hexutil.Big)(big.NewInt(int64(4+i))), block["number"])
+	}
+	require.Equal(t, res[0]["hash"], res[1]["parentHash"])
+
+	first := res[0]["calls"].([]SimulatedCallResult)
+	require.Len(t, first, 1)
+	require.Nil(t, first[0].Error)
+	require.Equal(t, hexutil.Uint64(types.ReceiptStatusSuccessful), first[0].Status)
+	require.Len(t, first[0].Logs, 1)
+	require.Equal(t, contractAddress, first[0].Logs[0].Address)
+
+	require.Empty(t, res[1]["calls"].([]SimulatedCallResult))
+
+	last := res[3]["calls"].([]SimulatedCallResult)
+	require.Len(t, last, 2)
+	// the storage written in the first simulated block is visible in the last one
+	require.Equal(t, common.BigToHash(big.NewInt(7)).Bytes(), []byte(last[0].ReturnValue))
+	require.Len(t, last[1].Logs, 1)
+	require.Equal(t, transferAddress, last[1].Logs[0].Address)
+	require.Equal(t, common.BytesToHash(receiver.Bytes()), last[1].Logs[0].Topics[2])
+	require.Equal(t, uint(1), last[1].Logs[0].TxIndex)
+}
+
+func TestSimulateV1Validation(t *testing.T) {
+	m, bankAddress, contractAddress := chainWithDeployedContract(t)
+	api := NewEthAPI(newBaseApiForTest(m), m.DB, nil, nil, nil, 5000000, ethconfig.Defaults.RPCTxFeeCap, 100_000, false, 100_000, 128, log.New())
+	ctx := context.Background()
+
+	nonce := hexutil.Uint64(100)
+	_, err := api.SimulateV1(ctx, SimulationRequest{
+		Validation: true,
+		BlockStateCalls: []SimulatedBlock{
+			{Calls: []ethapi.CallArgs{{From: &bankAddress, To: &contractAddress, Nonce: &nonce}}},
+		},
+	}, nil)
+	var rpcErr *rpc.CustomError
+	require.True(t, errors.As(err, &rpcErr))
+	require.Equal(t, simErrCodeNonceTooHigh, rpcErr.ErrorCode())
+
+	// without validation the nonce isn't checked
+	_, err = api.SimulateV1(ctx, SimulationRequest{
+		BlockStateCalls: []SimulatedBlock{
+			{Calls: []ethapi.CallArgs{{From: &bankAddress, To: &contractAddress, Nonce: &nonce}}},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	_, err = api.SimulateV1(ctx, SimulationRequest{
+		BlockStateCalls: []SimulatedBlock{
+			{BlockOverrides: &ethapi.BlockOverrides{Number: (*hexutil.Big)(big.NewInt(2))}},
+		},
+	}, &latest)
+	require.True(t, errors.As(err, &rpcErr))
+	require.Equal(t, simErrCodeBlockNumberInvalid, rpcErr.ErrorCode())
+}
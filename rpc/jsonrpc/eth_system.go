@@ -32,6 +32,7 @@ import (
 	"github.com/erigontech/erigon-lib/chain/params"
 	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/hexutil"
+	remote "github.com/erigontech/erigon-lib/gointerfaces/remoteproto"
 	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/types"
 	"github.com/erigontech/erigon/core/vm"
@@ -66,8 +67,11 @@ func (api *APIImpl) Syncing(ctx context.Context) (interface{}, error) {
 	if !reply.Syncing {
 		return false, nil
 	}
+	return syncingStatus(reply), nil
+}
 
-	// Still sync-ing, gather the block sync stats
+// syncingStatus gathers the block sync stats of a node which is still syncing.
+func syncingStatus(reply *remote.SyncingReply) map[string]interface{} {
 	highestBlock := reply.LastNewBlockSeen
 	currentBlock := reply.CurrentBlock
 	type S struct {
@@ -85,7 +89,7 @@ func (api *APIImpl) Syncing(ctx context.Context) (interface{}, error) {
 		"currentBlock":  hexutil.Uint64(currentBlock),
 		"highestBlock":  hexutil.Uint64(highestBlock),
 		"stages":        stagesMap,
-	}, nil
+	}
 }
 
 // ChainId implements eth_chainId. Returns the current ethereum chainId.
@@ -17,34 +17,59 @@
 package jsonrpc
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 
 	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/hexutil"
 	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/rlp"
 	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon/core/vm/evmtypes"
 	"github.com/erigontech/erigon/eth/ethutils"
+	"github.com/erigontech/erigon/eth/filters"
+	"github.com/erigontech/erigon/execution/consensus/misc"
 	"github.com/erigontech/erigon/rpc"
 	"github.com/erigontech/erigon/rpc/ethapi"
 	"github.com/erigontech/erigon/rpc/rpchelper"
+	"github.com/erigontech/erigon/turbo/transactions"
 )
 
 type GraphQLAPI interface {
 	GetBlockDetails(ctx context.Context, number rpc.BlockNumber) (map[string]interface{}, error)
+	GetBlockDetailsByHash(ctx context.Context, hash common.Hash) (map[string]interface{}, error)
 	GetChainID(ctx context.Context) (*big.Int, error)
+	GetTransactionByHash(ctx context.Context, hash common.Hash) (*ethapi.RPCTransaction, error)
+	GetPendingTransactions(ctx context.Context) ([]*ethapi.RPCTransaction, error)
+	GetBalance(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Big, error)
+	GetTransactionCount(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Uint64, error)
+	GetCode(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Bytes, error)
+	GetStorageAt(ctx context.Context, address common.Address, index string, blockNrOrHash rpc.BlockNumberOrHash) (string, error)
+	Call(ctx context.Context, args ethapi.CallArgs, blockNrOrHash rpc.BlockNumberOrHash) (*evmtypes.ExecutionResult, error)
+	EstimateGas(ctx context.Context, args ethapi.CallArgs, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Uint64, error)
+	GetLogs(ctx context.Context, crit filters.FilterCriteria) (types.Logs, error)
+	GasPrice(ctx context.Context) (*hexutil.Big, error)
+	MaxPriorityFeePerGas(ctx context.Context) (*hexutil.Big, error)
+	Syncing(ctx context.Context) (interface{}, error)
+	SendRawTransaction(ctx context.Context, encodedTx hexutil.Bytes) (common.Hash, error)
 }
 
 type GraphQLAPIImpl struct {
 	*BaseAPI
-	db kv.TemporalRoDB
+	db  kv.TemporalRoDB
+	eth *APIImpl
 }
 
-func NewGraphQLAPI(base *BaseAPI, db kv.TemporalRoDB) *GraphQLAPIImpl {
+// NewGraphQLAPI returns the GraphQL backend. Account state, calls, log filtering and
+// transaction submission are served by the given eth implementation.
+func NewGraphQLAPI(base *BaseAPI, db kv.TemporalRoDB, eth *APIImpl) *GraphQLAPIImpl {
 	return &GraphQLAPIImpl{
 		BaseAPI: base,
 		db:      db,
+		eth:     eth,
 	}
 }
 
@@ -78,6 +103,28 @@ func (api *GraphQLAPIImpl) GetBlockDetails(ctx context.Context, blockNumber rpc.
 		return nil, nil
 	}
 
+	return api.getBlockDetails(ctx, tx, block, blockNumber)
+}
+
+func (api *GraphQLAPIImpl) GetBlockDetailsByHash(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
+	tx, err := api.db.BeginTemporalRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	block, err := api.blockByHashWithSenders(ctx, tx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, nil
+	}
+
+	return api.getBlockDetails(ctx, tx, block, rpc.BlockNumber(block.NumberU64()))
+}
+
+func (api *GraphQLAPIImpl) getBlockDetails(ctx context.Context, tx kv.TemporalTx, block *types.Block, blockNumber rpc.BlockNumber) (map[string]interface{}, error) {
 	getBlockRes, err := api.delegateGetBlockByNumber(tx, block, blockNumber, false)
 	if err != nil {
 		return nil, err
@@ -88,6 +135,26 @@ func (api *GraphQLAPIImpl) GetBlockDetails(ctx context.Context, blockNumber rpc.
 		return nil, err
 	}
 
+	rawHeader, err := rlp.EncodeToBytes(block.HeaderNoCopy())
+	if err != nil {
+		return nil, err
+	}
+	getBlockRes["rawHeader"] = hexutil.Bytes(rawHeader)
+	rawBlock, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		return nil, err
+	}
+	getBlockRes["raw"] = hexutil.Bytes(rawBlock)
+	if chainConfig.IsLondon(block.NumberU64() + 1) {
+		getBlockRes["nextBaseFeePerGas"] = (*hexutil.Big)(misc.CalcBaseFee(chainConfig, block.HeaderNoCopy()))
+	}
+
+	ommers := make([]map[string]interface{}, 0, len(block.Uncles()))
+	for _, uncle := range block.Uncles() {
+		ommers = append(ommers, ethapi.RPCMarshalHeader(uncle))
+	}
+	getBlockRes["ommers"] = ommers
+
 	receipts, err := api.getReceipts(ctx, tx, block)
 	if err != nil {
 		return nil, fmt.Errorf("getReceipts error: %w", err)
@@ -102,6 +169,17 @@ func (api *GraphQLAPIImpl) GetBlockDetails(ctx context.Context, blockNumber rpc.
 		transaction["value"] = txn.GetValue()
 		transaction["data"] = txn.GetData()
 		transaction["logs"] = receipt.Logs
+		transaction["transaction"] = ethapi.NewRPCTransaction(txn, block.Hash(), block.NumberU64(), uint64(receipt.TransactionIndex), block.BaseFee())
+		var raw bytes.Buffer
+		if err := txn.MarshalBinary(&raw); err != nil {
+			return nil, err
+		}
+		transaction["raw"] = hexutil.Bytes(raw.Bytes())
+		rawReceipt, err := receipt.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		transaction["rawReceipt"] = hexutil.Bytes(rawReceipt)
 		result = append(result, transaction)
 	}
 
@@ -126,6 +204,105 @@ func (api *GraphQLAPIImpl) GetBlockDetails(ctx context.Context, blockNumber rpc.
 	return response, nil
 }
 
+func (api *GraphQLAPIImpl) GetTransactionByHash(ctx context.Context, hash common.Hash) (*ethapi.RPCTransaction, error) {
+	return api.eth.GetTransactionByHash(ctx, hash)
+}
+
+// GetPendingTransactions returns the transactions of the block currently being built, if any.
+func (api *GraphQLAPIImpl) GetPendingTransactions(ctx context.Context) ([]*ethapi.RPCTransaction, error) {
+	block := api.pendingBlock()
+	if block == nil {
+		return []*ethapi.RPCTransaction{}, nil
+	}
+	result := make([]*ethapi.RPCTransaction, 0, len(block.Transactions()))
+	for i, txn := range block.Transactions() {
+		rpcTxn := ethapi.NewRPCTransaction(txn, common.Hash{}, 0, uint64(i), block.BaseFee())
+		rpcTxn.BlockHash, rpcTxn.BlockNumber, rpcTxn.TransactionIndex = nil, nil, nil
+		result = append(result, rpcTxn)
+	}
+	return result, nil
+}
+
+func (api *GraphQLAPIImpl) GetBalance(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	return api.eth.GetBalance(ctx, address, blockNrOrHash)
+}
+
+func (api *GraphQLAPIImpl) GetTransactionCount(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Uint64, error) {
+	return api.eth.GetTransactionCount(ctx, address, blockNrOrHash)
+}
+
+func (api *GraphQLAPIImpl) GetCode(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	return api.eth.GetCode(ctx, address, blockNrOrHash)
+}
+
+func (api *GraphQLAPIImpl) GetStorageAt(ctx context.Context, address common.Address, index string, blockNrOrHash rpc.BlockNumberOrHash) (string, error) {
+	return api.eth.GetStorageAt(ctx, address, index, blockNrOrHash)
+}
+
+// Call executes the given call on top of the requested block. Unlike eth_call, a reverted
+// or failed execution is not an error: the result carries the gas used and the revert data.
+func (api *GraphQLAPIImpl) Call(ctx context.Context, args ethapi.CallArgs, blockNrOrHash rpc.BlockNumberOrHash) (*evmtypes.ExecutionResult, error) {
+	tx, err := api.db.BeginTemporalRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	chainConfig, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if args.Gas == nil || uint64(*args.Gas) == 0 {
+		args.Gas = (*hexutil.Uint64)(&api.eth.GasCap)
+	}
+
+	header, _, err := headerByNumberOrHash(ctx, tx, blockNrOrHash, api.eth)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, errors.New("header not found")
+	}
+
+	stateReader, err := rpchelper.CreateStateReader(ctx, tx, api._blockReader, blockNrOrHash, 0, api.filters, api.stateCache, api._txNumReader)
+	if err != nil {
+		return nil, err
+	}
+	result, err := transactions.DoCall(ctx, api.engine(), args, tx, blockNrOrHash, header, nil, api.eth.GasCap, chainConfig, stateReader, api._blockReader, api.evmCallTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.ReturnData) > api.eth.ReturnDataLimit {
+		return nil, fmt.Errorf("call returned result on length %d exceeding --rpc.returndata.limit %d", len(result.ReturnData), api.eth.ReturnDataLimit)
+	}
+	return result, nil
+}
+
+func (api *GraphQLAPIImpl) EstimateGas(ctx context.Context, args ethapi.CallArgs, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+	return api.eth.EstimateGas(ctx, &args, &blockNrOrHash, nil)
+}
+
+func (api *GraphQLAPIImpl) GetLogs(ctx context.Context, crit filters.FilterCriteria) (types.Logs, error) {
+	return api.eth.GetLogs(ctx, crit)
+}
+
+func (api *GraphQLAPIImpl) GasPrice(ctx context.Context) (*hexutil.Big, error) {
+	return api.eth.GasPrice(ctx)
+}
+
+func (api *GraphQLAPIImpl) MaxPriorityFeePerGas(ctx context.Context) (*hexutil.Big, error) {
+	return api.eth.MaxPriorityFeePerGas(ctx)
+}
+
+func (api *GraphQLAPIImpl) Syncing(ctx context.Context) (interface{}, error) {
+	return api.eth.Syncing(ctx)
+}
+
+func (api *GraphQLAPIImpl) SendRawTransaction(ctx context.Context, encodedTx hexutil.Bytes) (common.Hash, error) {
+	return api.eth.SendRawTransaction(ctx, encodedTx)
+}
+
 func (api *GraphQLAPIImpl) getBlockWithSenders(ctx context.Context, number rpc.BlockNumber, tx kv.Tx) (*types.Block, []common.Address, error) {
 	if number == rpc.PendingBlockNumber {
 		return api.pendingBlock(), nil, nil
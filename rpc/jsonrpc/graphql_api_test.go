@@ -0,0 +1,81 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/eth/ethconfig"
+	"github.com/erigontech/erigon/rpc"
+	"github.com/erigontech/erigon/rpc/ethapi"
+)
+
+func TestGraphQLBlockDetailsByHash(t *testing.T) {
+	m, _, _ := chainWithDeployedContract(t)
+	base := newBaseApiForTest(m)
+	eth := NewEthAPI(base, m.DB, nil, nil, nil, 5000000, ethconfig.Defaults.RPCTxFeeCap, 100_000, false, 100_000, 128, log.New())
+	api := NewGraphQLAPI(base, m.DB, eth)
+	ctx := context.Background()
+
+	byNumber, err := api.GetBlockDetails(ctx, rpc.BlockNumber(1))
+	require.NoError(t, err)
+	hash := byNumber["block"].(map[string]interface{})["hash"].(common.Hash)
+
+	byHash, err := api.GetBlockDetailsByHash(ctx, hash)
+	require.NoError(t, err)
+	require.Equal(t, byNumber, byHash)
+
+	receipts := byHash["receipts"].([]map[string]interface{})
+	require.NotEmpty(t, receipts)
+	txn := receipts[0]["transaction"].(*ethapi.RPCTransaction)
+	require.Equal(t, receipts[0]["transactionHash"], txn.Hash)
+	require.NotEmpty(t, receipts[0]["raw"])
+	require.NotEmpty(t, receipts[0]["rawReceipt"])
+
+	missing, err := api.GetBlockDetailsByHash(ctx, common.Hash{1})
+	require.NoError(t, err)
+	require.Nil(t, missing)
+}
+
+func TestGraphQLCall(t *testing.T) {
+	m, bankAddress, contractAddress := chainWithDeployedContract(t)
+	base := newBaseApiForTest(m)
+	eth := NewEthAPI(base, m.DB, nil, nil, nil, 5000000, ethconfig.Defaults.RPCTxFeeCap, 100_000, false, 100_000, 128, log.New())
+	api := NewGraphQLAPI(base, m.DB, eth)
+	ctx := context.Background()
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	retrieve := hexutil.Bytes(hexutil.MustDecode("0x2e64cec1"))
+	result, err := api.Call(ctx, ethapi.CallArgs{From: &bankAddress, To: &contractAddress, Data: &retrieve}, latest)
+	require.NoError(t, err)
+	require.False(t, result.Failed())
+	require.NotZero(t, result.GasUsed)
+	require.Len(t, result.ReturnData, 32)
+
+	// a failed execution is reported in the result rather than as an error
+	value := (*hexutil.Big)(big.NewInt(1))
+	result, err = api.Call(ctx, ethapi.CallArgs{From: &bankAddress, To: &contractAddress, Data: &retrieve, Value: value}, latest)
+	require.NoError(t, err)
+	require.True(t, result.Failed())
+}
@@ -41,6 +41,7 @@ import (
 	"github.com/erigontech/erigon/core/vm/evmtypes"
 	"github.com/erigontech/erigon/eth/tracers"
 	"github.com/erigontech/erigon/eth/tracers/config"
+	"github.com/erigontech/erigon/eth/tracers/parity"
 	ptracer "github.com/erigontech/erigon/polygon/tracer"
 	"github.com/erigontech/erigon/rpc"
 	"github.com/erigontech/erigon/rpc/rpchelper"
@@ -49,12 +50,12 @@ import (
 )
 
 const (
-	CALL               = "call"
-	CALLCODE           = "callcode"
-	DELEGATECALL       = "delegatecall"
-	STATICCALL         = "staticcall"
-	CREATE             = "create"
-	SUICIDE            = "suicide"
+	CALL               = parity.CALL
+	CALLCODE           = parity.CALLCODE
+	DELEGATECALL       = parity.DELEGATECALL
+	STATICCALL         = parity.STATICCALL
+	CREATE             = parity.CREATE
+	SUICIDE            = parity.SUICIDE
 	REWARD             = "reward"
 	TraceTypeTrace     = "trace"
 	TraceTypeStateDiff = "stateDiff"
@@ -263,9 +264,7 @@ type OeTracerConfig struct {
 // OeTracer is an OpenEthereum-style tracer
 type OeTracer struct {
 	r            *TraceCallResult
-	traceAddr    []int
-	traceStack   []*ParityTrace
-	precompile   bool // Whether the last CaptureStart was called with `precompile = true`
+	frames       parity.CallFrames
 	compat       bool // Bug for bug compatibility mode
 	lastVmOp     *VmTraceOp
 	lastOp       vm.OpCode
@@ -376,81 +375,12 @@ func (ot *OeTracer) captureStartOrEnter(deep bool, typ vm.OpCode, from common.Ad
 			vmTrace.Code = code
 		}
 	}
-	if precompile && deep && (value == nil || value.IsZero()) {
-		ot.precompile = true
-		if !ot.config.IncludePrecompiles {
-			return
-		}
-	}
-	if gas > 500000000 {
-		gas = 500000001 - (0x8000000000000000 - gas)
+	if !deep {
+		ot.frames.Traces = ot.r.Trace
+		ot.frames.IncludePrecompiles, ot.frames.Compat = ot.config.IncludePrecompiles, ot.compat
 	}
-	trace := &ParityTrace{}
-	if create {
-		trResult := &CreateTraceResult{}
-		trace.Type = CREATE
-		trResult.Address = new(common.Address)
-		copy(trResult.Address[:], to.Bytes())
-		trace.Result = trResult
-	} else {
-		trace.Result = &TraceResult{}
-		trace.Type = CALL
-	}
-	if deep {
-		topTrace := ot.traceStack[len(ot.traceStack)-1]
-		traceIdx := topTrace.Subtraces
-		ot.traceAddr = append(ot.traceAddr, traceIdx)
-		topTrace.Subtraces++
-		if typ == vm.DELEGATECALL {
-			switch action := topTrace.Action.(type) {
-			case *CreateTraceAction:
-				value, _ = uint256.FromBig(action.Value.ToInt())
-			case *CallTraceAction:
-				value, _ = uint256.FromBig(action.Value.ToInt())
-			}
-		}
-		if typ == vm.STATICCALL {
-			value = uint256.NewInt(0)
-		}
-	}
-	trace.TraceAddress = make([]int, len(ot.traceAddr))
-	copy(trace.TraceAddress, ot.traceAddr)
-	if create {
-		action := CreateTraceAction{}
-		action.From = from
-		action.Gas.ToInt().SetUint64(gas)
-		action.Init = common.CopyBytes(input)
-		action.Value.ToInt().Set(value.ToBig())
-		trace.Action = &action
-	} else if typ == vm.SELFDESTRUCT {
-		trace.Type = SUICIDE
-		trace.Result = nil
-		action := &SuicideTraceAction{}
-		action.Address = from
-		action.RefundAddress = to
-		action.Balance.ToInt().Set(value.ToBig())
-		trace.Action = action
-	} else {
-		action := CallTraceAction{}
-		switch typ {
-		case vm.CALL:
-			action.CallType = CALL
-		case vm.CALLCODE:
-			action.CallType = CALLCODE
-		case vm.DELEGATECALL:
-			action.CallType = DELEGATECALL
-		case vm.STATICCALL:
-			action.CallType = STATICCALL
-		}
-		action.From = from
-		action.To = to
-		action.Gas.ToInt().SetUint64(gas)
-		action.Input = common.CopyBytes(input)
-		action.Value.ToInt().Set(value.ToBig())
-		trace.Action = &action
-	}
-	ot.r.Trace = append(ot.r.Trace, trace)
-	ot.traceStack = append(ot.traceStack, trace)
+	ot.frames.Enter(deep, typ, from, to, precompile, create, input, gas, value)
+	ot.r.Trace = ot.frames.Traces
 }
 
 func (ot *OeTracer) OnEnter(depth int, typ byte, from common.Address, to common.Address, precompile bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
@@ -474,59 +404,10 @@ func (ot *OeTracer) captureEndOrExit(deep bool, output []byte, gasUsed uint64, e
 			ot.memLenStack = ot.memLenStack[:len(ot.memLenStack)-1]
 		}
 	}
-	if ot.precompile {
-		ot.precompile = false
-		if !ot.config.IncludePrecompiles {
-			return
-		}
-	}
 	if !deep {
 		ot.r.Output = common.CopyBytes(output)
 	}
-	ignoreError := false
-	topTrace := ot.traceStack[len(ot.traceStack)-1]
-	if ot.compat {
-		ignoreError = !deep && topTrace.Type == CREATE
-	}
-	if err != nil && !ignoreError {
-		if errors.Is(err, vm.ErrExecutionReverted) {
-			topTrace.Error = "Reverted"
-			switch topTrace.Type {
-			case CALL:
-				topTrace.Result.(*TraceResult).GasUsed = new(hexutil.Big)
-				topTrace.Result.(*TraceResult).GasUsed.ToInt().SetUint64(gasUsed)
-				topTrace.Result.(*TraceResult).Output = common.CopyBytes(output)
-			case CREATE:
-				topTrace.Result.(*CreateTraceResult).GasUsed = new(hexutil.Big)
-				topTrace.Result.(*CreateTraceResult).GasUsed.ToInt().SetUint64(gasUsed)
-				topTrace.Result.(*CreateTraceResult).Code = common.CopyBytes(output)
-			}
-		} else {
-			topTrace.Result = nil
-			topTrace.Error = err.Error()
-		}
-	} else {
-		if len(output) > 0 {
-			switch topTrace.Type {
-			case CALL:
-				topTrace.Result.(*TraceResult).Output = common.CopyBytes(output)
-			case CREATE:
-				topTrace.Result.(*CreateTraceResult).Code = common.CopyBytes(output)
-			}
-		}
-		switch topTrace.Type {
-		case CALL:
-			topTrace.Result.(*TraceResult).GasUsed = new(hexutil.Big)
-			topTrace.Result.(*TraceResult).GasUsed.ToInt().SetUint64(gasUsed)
-		case CREATE:
-			topTrace.Result.(*CreateTraceResult).GasUsed = new(hexutil.Big)
-			topTrace.Result.(*CreateTraceResult).GasUsed.ToInt().SetUint64(gasUsed)
-		}
-	}
-	ot.traceStack = ot.traceStack[:len(ot.traceStack)-1]
-	if deep {
-		ot.traceAddr = ot.traceAddr[:len(ot.traceAddr)-1]
-	}
+	ot.frames.Exit(deep, output, gasUsed, err)
 }
 
 func (ot *OeTracer) OnExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
@@ -1107,7 +988,6 @@ func (api *TraceAPIImpl) Call(ctx context.Context, args TraceCallParam, traceTyp
 	ot.compat = api.compatibility
 	if traceTypeTrace || traceTypeVmTrace {
 		ot.r = traceResult
-		ot.traceAddr = []int{}
 	}
 
 	// Get a new instance of the EVM.
@@ -1379,9 +1259,6 @@ func (api *TraceAPIImpl) doCallBlock(ctx context.Context, dbtx kv.Tx, stateReade
 			ot.compat = api.compatibility
 			ot.r = traceResult
 			ot.idx = []string{fmt.Sprintf("%d-", txIndex)}
-			if traceTypeTrace {
-				ot.traceAddr = []int{}
-			}
 			if traceTypeVmTrace {
 				traceResult.VmTrace = &VmTrace{Ops: []*VmTraceOp{}}
 			}
@@ -1593,9 +1470,6 @@ func (api *TraceAPIImpl) doCall(ctx context.Context, dbtx kv.Tx, stateReader sta
 		ot.compat = api.compatibility
 		ot.r = traceResult
 		ot.idx = []string{fmt.Sprintf("%d-", txIndex)}
-		if traceTypeTrace {
-			ot.traceAddr = []int{}
-		}
 		if traceTypeVmTrace {
 			traceResult.VmTrace = &VmTrace{Ops: []*VmTraceOp{}}
 		}
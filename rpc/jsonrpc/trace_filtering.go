@@ -327,6 +327,17 @@ func (api *TraceAPIImpl) Filter(ctx context.Context, req TraceFilterRequest, gas
 	if fromBlock > toBlock {
 		return errors.New("invalid parameters: fromBlock cannot be greater than toBlock")
 	}
+	if len(req.FromAddress) > 0 || len(req.ToAddress) > 0 {
+		var addrs []common.Address
+		for _, addr := range append(req.FromAddress, req.ToAddress...) {
+			if addr != nil {
+				addrs = append(addrs, *addr)
+			}
+		}
+		if err := api.checkPruneHistoryOf(dbtx, fromBlock, addrs...); err != nil {
+			return err
+		}
+	}
 
 	return api.filterV3(ctx, dbtx, fromBlock, toBlock, req, stream, *gasBailOut, traceConfig)
 }
@@ -589,7 +600,6 @@ func (api *TraceAPIImpl) filterV3(ctx context.Context, dbtx kv.TemporalTx, fromB
 		ot.compat = api.compatibility
 		ot.r = traceResult
 		ot.idx = []string{fmt.Sprintf("%d-", txIndex)}
-		ot.traceAddr = []int{}
 		vmConfig.Tracer = ot.Tracer().Hooks
 		ibs := state.New(cachedReader)
 
@@ -22,6 +22,7 @@ import (
 	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/hexutil"
 	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon/eth/tracers/parity"
 )
 
 // TODO:(tjayrush)
@@ -51,19 +52,7 @@ type GethTrace struct {
 type GethTraces []*GethTrace
 
 // ParityTrace A trace in the desired format (Parity/OpenEthereum) See: https://openethereum.github.io/JSONRPC-trace-module
-type ParityTrace struct {
-	// Do not change the ordering of these fields -- allows for easier comparison with other clients
-	Action              interface{}  `json:"action"` // Can be either CallTraceAction or CreateTraceAction
-	BlockHash           *common.Hash `json:"blockHash,omitempty"`
-	BlockNumber         *uint64      `json:"blockNumber,omitempty"`
-	Error               string       `json:"error,omitempty"`
-	Result              interface{}  `json:"result"`
-	Subtraces           int          `json:"subtraces"`
-	TraceAddress        []int        `json:"traceAddress"`
-	TransactionHash     *common.Hash `json:"transactionHash,omitempty"`
-	TransactionPosition *uint64      `json:"transactionPosition,omitempty"`
-	Type                string       `json:"type"`
-}
+type ParityTrace = parity.Trace
 
 // ParityTraces An array of parity traces
 type ParityTraces []ParityTrace
@@ -85,27 +74,11 @@ type TraceAction struct {
 	Value          string         `json:"value,omitempty"`
 }
 
-type CallTraceAction struct {
-	From     common.Address `json:"from"`
-	CallType string         `json:"callType"`
-	Gas      hexutil.Big    `json:"gas"`
-	Input    hexutil.Bytes  `json:"input"`
-	To       common.Address `json:"to"`
-	Value    hexutil.Big    `json:"value"`
-}
+type CallTraceAction = parity.CallTraceAction
 
-type CreateTraceAction struct {
-	From  common.Address `json:"from"`
-	Gas   hexutil.Big    `json:"gas"`
-	Init  hexutil.Bytes  `json:"init"`
-	Value hexutil.Big    `json:"value"`
-}
+type CreateTraceAction = parity.CreateTraceAction
 
-type SuicideTraceAction struct {
-	Address       common.Address `json:"address"`
-	RefundAddress common.Address `json:"refundAddress"`
-	Balance       hexutil.Big    `json:"balance"`
-}
+type SuicideTraceAction = parity.SuicideTraceAction
 
 type RewardTraceAction struct {
 	Author     common.Address `json:"author"`
@@ -113,19 +86,10 @@ type RewardTraceAction struct {
 	Value      hexutil.Big    `json:"value,omitempty"`
 }
 
-type CreateTraceResult struct {
-	// Do not change the ordering of these fields -- allows for easier comparison with other clients
-	Address *common.Address `json:"address,omitempty"`
-	Code    hexutil.Bytes   `json:"code"`
-	GasUsed *hexutil.Big    `json:"gasUsed"`
-}
+type CreateTraceResult = parity.CreateTraceResult
 
 // TraceResult A parity formatted trace result
-type TraceResult struct {
-	// Do not change the ordering of these fields -- allows for easier comparison with other clients
-	GasUsed *hexutil.Big  `json:"gasUsed"`
-	Output  hexutil.Bytes `json:"output"`
-}
+type TraceResult = parity.TraceResult
 
 // Allows for easy printing of a geth trace for debugging
 func (p GethTrace) String() string {
@@ -141,33 +105,6 @@ func (p GethTrace) String() string {
 	return ret
 }
 
-// Allows for easy printing of a parity trace for debugging
-func (t ParityTrace) String() string {
-	var ret string
-	//ret += fmt.Sprintf("Action.SelfDestructed: %s\n", t.Action.SelfDestructed)
-	//ret += fmt.Sprintf("Action.Balance: %s\n", t.Action.Balance)
-	//ret += fmt.Sprintf("Action.CallType: %s\n", t.Action.CallType)
-	//ret += fmt.Sprintf("Action.From: %s\n", t.Action.From)
-	//ret += fmt.Sprintf("Action.Gas: %d\n", t.Action.Gas.ToInt())
-	//ret += fmt.Sprintf("Action.Init: %s\n", t.Action.Init)
-	//ret += fmt.Sprintf("Action.Input: %s\n", t.Action.Input)
-	//ret += fmt.Sprintf("Action.RefundAddress: %s\n", t.Action.RefundAddress)
-	//ret += fmt.Sprintf("Action.To: %s\n", t.Action.To)
-	//ret += fmt.Sprintf("Action.Value: %s\n", t.Action.Value)
-	ret += fmt.Sprintf("BlockHash: %v\n", t.BlockHash)
-	ret += fmt.Sprintf("BlockNumber: %d\n", t.BlockNumber)
-	//ret += fmt.Sprintf("Result.Address: %s\n", t.Result.Address)
-	//ret += fmt.Sprintf("Result.Code: %s\n", t.Result.Code)
-	//ret += fmt.Sprintf("Result.GasUsed: %s\n", t.Result.GasUsed)
-	//ret += fmt.Sprintf("Result.Output: %s\n", t.Result.Output)
-	ret += fmt.Sprintf("Subtraces: %d\n", t.Subtraces)
-	ret += fmt.Sprintf("TraceAddress: %v\n", t.TraceAddress)
-	ret += fmt.Sprintf("TransactionHash: %v\n", t.TransactionHash)
-	ret += fmt.Sprintf("TransactionPosition: %d\n", t.TransactionPosition)
-	ret += fmt.Sprintf("Type: %s\n", t.Type)
-	return ret
-}
-
 // Takes a hierarchical Geth trace with fields of different meaning stored in the same named fields depending on 'type'. Parity traces
 // are flattened depth first and each field is put in its proper place
 func (api *TraceAPIImpl) convertToParityTrace(gethTrace GethTrace, blockHash common.Hash, blockNumber uint64, txn types.Transaction, txIndex uint64, depth []int) ParityTraces { //nolint: unused
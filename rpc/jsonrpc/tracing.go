@@ -28,6 +28,7 @@ import (
 	"github.com/erigontech/erigon-lib/common/dbg"
 	"github.com/erigontech/erigon-lib/common/hexutil"
 	"github.com/erigontech/erigon-lib/jsonstream"
+	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon-lib/types"
 	"github.com/erigontech/erigon/core"
@@ -89,6 +90,13 @@ func (api *DebugAPIImpl) traceBlock(ctx context.Context, blockNrOrHash rpc.Block
 		config.BorTraceEnabled = &disabled
 	}
 
+	return api.traceBlockWithTx(ctx, tx, block, config, stream)
+}
+
+// traceBlockWithTx writes the traces of all txns of the block to the stream.
+// The config is expected to have its defaults set already.
+func (api *DebugAPIImpl) traceBlockWithTx(ctx context.Context, tx kv.TemporalTx, block *types.Block, config *tracersConfig.TraceConfig, stream jsonstream.Stream) error {
+	blockNumber := block.NumberU64()
 	chainConfig, err := api.chainConfig(ctx, tx)
 	if err != nil {
 		return err
@@ -0,0 +1,158 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// rateLimitSweepInterval is how often buckets of idle clients are dropped.
+const rateLimitSweepInterval = time.Minute
+
+// RateLimitConfig configures per-client throttling of the rpc server. Every
+// client, identified by the subject of its JWT or else by its IP address, has
+// a token bucket refilled at Rate tokens per second up to Burst tokens. Every
+// call takes the cost of its method out of the bucket, calls that don't fit
+// are rejected with a RateLimitedError.
+//
+// The IP address is the one of the connection (http.Request.RemoteAddr),
+// X-Forwarded-For is not trusted: all clients behind a reverse proxy or NAT
+// share one bucket, unless they send JWTs with different subjects.
+type RateLimitConfig struct {
+	Rate        float64        `json:"rate"`        // Tokens added to the bucket of a client every second
+	Burst       int            `json:"burst"`       // Size of the bucket of a client, defaults to Rate
+	DefaultCost int            `json:"defaultCost"` // Cost of methods not listed in MethodCosts, defaults to 1
+	MethodCosts map[string]int `json:"methodCosts"` // Cost of expensive methods, e.g. {"trace_filter": 100}. Subscriptions are listed as <namespace>_<name>, e.g. "debug_traceChain"
+}
+
+func (c RateLimitConfig) validate() error {
+	if c.Rate <= 0 {
+		return errors.New("rate limit: rate must be positive")
+	}
+	if c.Burst < 0 || c.DefaultCost < 0 {
+		return errors.New("rate limit: burst and defaultCost can't be negative")
+	}
+	for method, cost := range c.MethodCosts {
+		if cost < 0 {
+			return fmt.Errorf("rate limit: cost of %s can't be negative", method)
+		}
+	}
+	return nil
+}
+
+type clientBucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// RateLimiter keeps the token buckets of the clients of an rpc server.
+type RateLimiter struct {
+	cfg       RateLimitConfig
+	mu        sync.Mutex
+	buckets   map[string]*clientBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewRateLimiter creates a rate limiter with the given config.
+func NewRateLimiter(cfg RateLimitConfig) (*RateLimiter, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Burst == 0 {
+		cfg.Burst = max(int(cfg.Rate), 1)
+	}
+	if cfg.DefaultCost == 0 {
+		cfg.DefaultCost = 1
+	}
+	return &RateLimiter{cfg: cfg, buckets: make(map[string]*clientBucket), now: time.Now}, nil
+}
+
+// cost returns the number of tokens a call of the method takes.
+func (l *RateLimiter) cost(method string) int {
+	if cost, ok := l.cfg.MethodCosts[method]; ok {
+		return cost
+	}
+	return l.cfg.DefaultCost
+}
+
+// allow takes the cost of the method out of the bucket of the client the
+// call came from, or returns a RateLimitedError telling it when to retry.
+func (l *RateLimiter) allow(ctx context.Context, method string) error {
+	cost := l.cost(method)
+	if cost == 0 {
+		return nil
+	}
+	if cost > l.cfg.Burst {
+		return &RateLimitedError{Method: method, Cost: cost, Burst: l.cfg.Burst}
+	}
+
+	key := rateLimitKey(PeerInfoFromContext(ctx))
+	now := l.now()
+
+	l.mu.Lock()
+	l.sweep(now)
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &clientBucket{limiter: rate.NewLimiter(rate.Limit(l.cfg.Rate), l.cfg.Burst)}
+		l.buckets[key] = bucket
+	}
+	bucket.lastSeen = now
+	l.mu.Unlock()
+
+	r := bucket.limiter.ReserveN(now, cost)
+	if delay := r.DelayFrom(now); delay > 0 {
+		r.CancelAt(now)
+		return &RateLimitedError{Method: method, RetryAfter: delay}
+	}
+	return nil
+}
+
+// sweep drops the buckets of clients idle for long enough for their bucket to
+// be full again, they are no different from new ones. Expects l.mu to be held.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	refill := time.Duration(float64(l.cfg.Burst) / l.cfg.Rate * float64(time.Second))
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.lastSeen) > refill {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// rateLimitKey identifies the client a call came from: by JWT subject, or else by
+// the address of the connection, which is the proxy's one for proxied clients.
+func rateLimitKey(info PeerInfo) string {
+	if info.JWTSubject != "" {
+		return "jwt:" + info.JWTSubject
+	}
+	host, _, err := net.SplitHostPort(info.RemoteAddr)
+	if err != nil {
+		host = info.RemoteAddr
+	}
+	return "ip:" + host
+}
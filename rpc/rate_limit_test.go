@@ -0,0 +1,95 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/log/v3"
+)
+
+func peerContext(remoteAddr, jwtSubject string) context.Context {
+	return context.WithValue(context.Background(), peerInfoContextKey{}, PeerInfo{RemoteAddr: remoteAddr, JWTSubject: jwtSubject})
+}
+
+func TestRateLimiter(t *testing.T) {
+	l, err := NewRateLimiter(RateLimitConfig{Rate: 1, Burst: 3, MethodCosts: map[string]int{"trace_filter": 2, "eth_chainId": 0, "debug_traceChain": 4}})
+	require.NoError(t, err)
+	now := time.Unix(1_000_000, 0)
+	l.now = func() time.Time { return now }
+
+	client := peerContext("10.0.0.1:30000", "")
+	sameHost := peerContext("10.0.0.1:40000", "")
+	otherHost := peerContext("10.0.0.2:30000", "")
+	jwtClient := peerContext("10.0.0.1:30000", "alice")
+
+	require.NoError(t, l.allow(client, "trace_filter"))
+	require.NoError(t, l.allow(sameHost, "eth_blockNumber"))
+	// the bucket of 10.0.0.1 is empty now, other clients have their own
+	var rateErr *RateLimitedError
+	require.True(t, errors.As(l.allow(client, "eth_blockNumber"), &rateErr))
+	require.Equal(t, time.Second, rateErr.RetryAfter)
+	require.NoError(t, l.allow(otherHost, "trace_filter"))
+	require.NoError(t, l.allow(jwtClient, "trace_filter"))
+	// free methods are never limited
+	require.NoError(t, l.allow(client, "eth_chainId"))
+
+	// rejected calls don't take tokens
+	now = now.Add(time.Second)
+	require.NoError(t, l.allow(client, "eth_blockNumber"))
+
+	// a method costing more than the burst can never succeed
+	require.True(t, errors.As(l.allow(otherHost, "debug_traceChain"), &rateErr))
+	require.Zero(t, rateErr.RetryAfter)
+
+	// buckets of clients idle until their bucket is full again are dropped
+	now = now.Add(rateLimitSweepInterval)
+	require.NoError(t, l.allow(otherHost, "eth_blockNumber"))
+	require.Len(t, l.buckets, 1)
+
+	_, err = NewRateLimiter(RateLimitConfig{})
+	require.Error(t, err)
+}
+
+func TestServerRateLimit(t *testing.T) {
+	logger := log.New()
+	server := newTestServer(logger)
+	defer server.Stop()
+	rateLimiter, err := NewRateLimiter(RateLimitConfig{Rate: 0.001, Burst: 2, MethodCosts: map[string]int{"test_sleep": 2}})
+	require.NoError(t, err)
+	server.SetRateLimiter(rateLimiter)
+
+	client := DialInProc(server, logger)
+	defer client.Close()
+
+	var resp echoResult
+	require.NoError(t, client.Call(&resp, "test_echo", "hello", 10, &echoArgs{"world"}))
+	err = client.Call(nil, "test_sleep", 0)
+	var rpcErr Error
+	require.True(t, errors.As(err, &rpcErr))
+	require.Equal(t, -32005, rpcErr.ErrorCode())
+	var dataErr DataError
+	require.True(t, errors.As(err, &dataErr))
+	require.Contains(t, dataErr.ErrorData(), "retryAfter")
+
+	require.NoError(t, client.Call(&resp, "test_echo", "hello", 10, &echoArgs{"world"}))
+}
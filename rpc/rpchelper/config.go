@@ -16,6 +16,8 @@
 
 package rpchelper
 
+import "time"
+
 // FiltersConfig defines the configuration settings for RPC subscription filters.
 // Each field represents a limit on the number of respective items that can be stored per subscription.
 type FiltersConfig struct {
@@ -24,6 +26,9 @@ type FiltersConfig struct {
 	RpcSubscriptionFiltersMaxTxs       int // Maximum number of transactions to store per subscription. Default: 0 (no limit)
 	RpcSubscriptionFiltersMaxAddresses int // Maximum number of addresses per subscription to filter logs by. Default: 0 (no limit)
 	RpcSubscriptionFiltersMaxTopics    int // Maximum number of topics per subscription to filter logs by. Default: 0 (no limit)
+
+	RpcFiltersStoreDir string        // Directory filters installed with eth_new*Filter are persisted to, can be shared by rpcdaemons. Default: "" (kept in memory)
+	RpcFiltersStoreTTL time.Duration // Time after which persisted filters that weren't polled are removed. Default: 1h
 }
 
 // DefaultFiltersConfig defines the default settings for filter configurations.
@@ -35,4 +40,5 @@ var DefaultFiltersConfig = FiltersConfig{
 	RpcSubscriptionFiltersMaxTxs:       0, // No limit on the number of transactions per subscription
 	RpcSubscriptionFiltersMaxAddresses: 0, // No limit on the number of addresses per subscription to filter logs by
 	RpcSubscriptionFiltersMaxTopics:    0, // No limit on the number of topics per subscription to filter logs by
+	RpcFiltersStoreTTL:                 time.Hour,
 }
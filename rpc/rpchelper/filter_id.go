@@ -31,6 +31,8 @@ type (
 	PendingBlockSubID SubscriptionID
 	PendingTxsSubID   SubscriptionID
 	LogsSubID         SubscriptionID
+	HeadChangesSubID  SubscriptionID
+	SyncingSubID      SubscriptionID
 )
 
 var globalSubscriptionId uint64
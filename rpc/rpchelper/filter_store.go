@@ -0,0 +1,307 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package rpchelper
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon/eth/filters"
+)
+
+// StoredFilterType is the kind of a filter kept in a FilterStore.
+type StoredFilterType string
+
+const (
+	LogsStoredFilter       StoredFilterType = "logs"       // Installed with eth_newFilter
+	BlocksStoredFilter     StoredFilterType = "blocks"     // Installed with eth_newBlockFilter
+	PendingTxsStoredFilter StoredFilterType = "pendingTxs" // Installed with eth_newPendingTransactionFilter
+)
+
+// StoredFilterReorgDepth is for how many of the latest blocks a log filter
+// keeps the logs it returned, to return them as removed if a reorg drops them.
+const StoredFilterReorgDepth = 128
+
+// filterStoreSweepInterval is how often expired filters are removed from a FileFilterStore.
+const filterStoreSweepInterval = time.Minute
+
+// filterStoreLockTimeout is how long a FileFilterStore waits for the lock of
+// a filter, a lock that old is left by a crashed rpcdaemon and is taken over.
+const filterStoreLockTimeout = 10 * time.Second
+
+// ErrStoredFilterChanged is returned by FilterStore.Put if the filter was
+// changed or removed since it was read: another poll of it won, the caller
+// reads it again.
+var ErrStoredFilterChanged = errors.New("filter store: filter was changed concurrently")
+
+// StoredFilter is a filter installed with eth_new*Filter as kept in a
+// FilterStore. Log and block filters only keep the last block whose changes
+// were returned by a poll, so any rpcdaemon sharing the store can compute the
+// changes of the following blocks from the chain, including the ones of the
+// blocks executed while no rpcdaemon was running. Its hash tells whether a
+// reorg dropped the blocks whose changes were returned.
+type StoredFilter struct {
+	ID            string           `json:"id"`
+	Type          StoredFilterType `json:"type"`
+	Addresses     []common.Address `json:"addresses,omitempty"`  // Criteria of log filters
+	Topics        [][]common.Hash  `json:"topics,omitempty"`     // Criteria of log filters
+	LastBlock     uint64           `json:"lastBlock"`            // Last block whose changes were returned
+	LastBlockHash common.Hash      `json:"lastBlockHash"`        // Hash of LastBlock when its changes were returned
+	RecentLogs    []*types.Log     `json:"recentLogs,omitempty"` // Logs returned for the last StoredFilterReorgDepth blocks
+	LastPoll      time.Time        `json:"lastPoll"`
+	Version       uint64           `json:"version"` // Number of updates, for compare-and-swap in FilterStore.Put
+}
+
+// Criteria returns the criteria of a log filter.
+func (f *StoredFilter) Criteria() filters.FilterCriteria {
+	return filters.FilterCriteria{Addresses: f.Addresses, Topics: f.Topics}
+}
+
+// Advance moves the filter past the changes returned up to the block number
+// with hash, logs are the ones returned for the blocks after LastBlock.
+func (f *StoredFilter) Advance(number uint64, hash common.Hash, logs []*types.Log) {
+	f.LastBlock, f.LastBlockHash = number, hash
+	recent := f.RecentLogs[:0]
+	for _, lg := range append(f.RecentLogs, logs...) {
+		if lg.BlockNumber+StoredFilterReorgDepth > number {
+			recent = append(recent, lg)
+		}
+	}
+	f.RecentLogs = recent
+}
+
+// Rewind moves the filter back to the block number with hash, after a reorg
+// dropped the blocks above it, and returns the logs returned for them marked as removed.
+// The caller advances the filter to the new canonical chain.
+func (f *StoredFilter) Rewind(number uint64, hash common.Hash) []*types.Log {
+	var removed []*types.Log
+	recent := f.RecentLogs[:0]
+	for _, lg := range f.RecentLogs {
+		if lg.BlockNumber > number {
+			removedLog := *lg
+			removedLog.Removed = true
+			removed = append(removed, &removedLog)
+		} else {
+			recent = append(recent, lg)
+		}
+	}
+	f.RecentLogs = recent
+	f.LastBlock, f.LastBlockHash = number, hash
+	return removed
+}
+
+// FilterStore persists the filters installed with eth_new*Filter, it can be
+// shared by rpcdaemons to keep filters across restarts and load balanced
+// instances.
+type FilterStore interface {
+	// Put creates a filter of Version 0, or updates a filter if its Version is
+	// still the stored one, and increments its Version. Otherwise it returns
+	// ErrStoredFilterChanged, so concurrent polls of a filter don't return
+	// the same changes twice.
+	Put(f *StoredFilter) error
+	// Get returns a filter or nil if there is no such filter or it expired.
+	Get(id string) (*StoredFilter, error)
+	// Delete removes a filter and reports whether it existed.
+	Delete(id string) (bool, error)
+}
+
+// NewStoredFilterID generates the id of a new filter.
+func NewStoredFilterID() string {
+	return string(generateSubscriptionID())
+}
+
+// FileFilterStore keeps every filter in a JSON file of its own in a directory,
+// e.g. on a volume mounted by all rpcdaemons. Filters that weren't polled for
+// ttl expire.
+type FileFilterStore struct {
+	dir       string
+	ttl       time.Duration
+	mu        sync.Mutex
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewFileFilterStore creates a FileFilterStore in dir, creating it if needed.
+func NewFileFilterStore(dir string, ttl time.Duration) (*FileFilterStore, error) {
+	if ttl <= 0 {
+		return nil, errors.New("filter store: ttl must be positive")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("filter store: %w", err)
+	}
+	return &FileFilterStore{dir: dir, ttl: ttl, now: time.Now}, nil
+}
+
+func (s *FileFilterStore) Put(f *StoredFilter) error {
+	path, err := s.path(f.ID)
+	if err != nil {
+		return err
+	}
+	s.sweep()
+	unlock, err := s.lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	stored, err := s.read(path)
+	if err != nil {
+		return err
+	}
+	if (stored == nil && f.Version != 0) || (stored != nil && stored.Version != f.Version) {
+		return ErrStoredFilterChanged
+	}
+
+	next := *f
+	next.Version++
+	data, err := json.Marshal(&next)
+	if err != nil {
+		return err
+	}
+	// write and rename, so that other rpcdaemons never read a partial filter
+	tmp, err := os.CreateTemp(s.dir, f.ID+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	f.Version = next.Version
+	return nil
+}
+
+// lock takes the lock of a filter file for all rpcdaemons sharing the
+// directory: the lock file is created exclusively and removed by unlock.
+func (s *FileFilterStore) lock(path string) (unlock func(), err error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(filterStoreLockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if stat, err := os.Stat(lockPath); err == nil && time.Since(stat.ModTime()) > filterStoreLockTimeout {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("filter store: %s is locked", filepath.Base(path))
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func (s *FileFilterStore) Get(id string) (*StoredFilter, error) {
+	path, err := s.path(id)
+	if err != nil {
+		// not an id of ours
+		return nil, nil
+	}
+	f, err := s.read(path)
+	if err != nil || f == nil {
+		return nil, err
+	}
+	if s.expired(f) {
+		os.Remove(path)
+		return nil, nil
+	}
+	return f, nil
+}
+
+func (s *FileFilterStore) Delete(id string) (bool, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return false, nil
+	}
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *FileFilterStore) read(path string) (*StoredFilter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var f StoredFilter
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("filter store: %s: %w", filepath.Base(path), err)
+	}
+	return &f, nil
+}
+
+func (s *FileFilterStore) expired(f *StoredFilter) bool {
+	return s.now().Sub(f.LastPoll) > s.ttl
+}
+
+// sweep removes the expired filters, at most once per filterStoreSweepInterval.
+func (s *FileFilterStore) sweep() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.now().Sub(s.lastSweep) < filterStoreSweepInterval {
+		return
+	}
+	s.lastSweep = s.now()
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return
+	}
+	for _, path := range paths {
+		if f, err := s.read(path); err == nil && f != nil && s.expired(f) {
+			os.Remove(path)
+		}
+	}
+}
+
+// path returns the file of a filter, ids are checked to be ours as they come
+// from clients.
+func (s *FileFilterStore) path(id string) (string, error) {
+	id = strings.TrimPrefix(id, "0x")
+	if _, err := hex.DecodeString(id); err != nil || len(id) != 32 {
+		return "", fmt.Errorf("filter store: invalid filter id %q", id)
+	}
+	return filepath.Join(s.dir, id+".json"), nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package rpchelper
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common"
+)
+
+func TestFileFilterStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileFilterStore(dir, time.Hour)
+	require.NoError(t, err)
+	now := time.Unix(1_000_000, 0)
+	store.now = func() time.Time { return now }
+
+	f := &StoredFilter{
+		ID:            NewStoredFilterID(),
+		Type:          LogsStoredFilter,
+		Addresses:     []common.Address{{1}},
+		Topics:        [][]common.Hash{nil, {{2}}},
+		LastBlock:     10,
+		LastBlockHash: common.Hash{3},
+		LastPoll:      now,
+	}
+	require.NoError(t, store.Put(f))
+
+	// another rpcdaemon sharing the directory
+	other, err := NewFileFilterStore(dir, time.Hour)
+	require.NoError(t, err)
+	other.now = store.now
+	got, err := other.Get(f.ID)
+	require.NoError(t, err)
+	require.Equal(t, f.Addresses, got.Addresses)
+	require.Equal(t, f.Topics, got.Topics)
+	require.Equal(t, f.LastBlock, got.LastBlock)
+	require.Equal(t, f.LastBlockHash, got.LastBlockHash)
+
+	// ids come from clients
+	got, err = store.Get("../" + f.ID)
+	require.NoError(t, err)
+	require.Nil(t, got)
+
+	// filters not polled for the ttl expire
+	now = now.Add(time.Hour + time.Second)
+	got, err = store.Get(f.ID)
+	require.NoError(t, err)
+	require.Nil(t, got)
+	_, err = os.Stat(filepath.Join(dir, f.ID+".json"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	f.ID, f.LastPoll, f.Version = NewStoredFilterID(), now, 0
+	require.NoError(t, store.Put(f))
+	deleted, err := store.Delete("0x" + f.ID)
+	require.NoError(t, err)
+	require.True(t, deleted)
+	deleted, err = store.Delete(f.ID)
+	require.NoError(t, err)
+	require.False(t, deleted)
+}
+
+func TestFileFilterStoreConcurrentPolls(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileFilterStore(dir, time.Hour)
+	require.NoError(t, err)
+	other, err := NewFileFilterStore(dir, time.Hour)
+	require.NoError(t, err)
+
+	f := &StoredFilter{ID: NewStoredFilterID(), Type: BlocksStoredFilter, LastBlock: 10, LastPoll: time.Now()}
+	require.NoError(t, store.Put(f))
+	require.Equal(t, uint64(1), f.Version)
+
+	// two rpcdaemons poll the filter at once: the second one must read it again
+	first, err := store.Get(f.ID)
+	require.NoError(t, err)
+	second, err := other.Get(f.ID)
+	require.NoError(t, err)
+	first.LastBlock, second.LastBlock = 11, 12
+	require.NoError(t, store.Put(first))
+	require.ErrorIs(t, other.Put(second), ErrStoredFilterChanged)
+	second, err = other.Get(f.ID)
+	require.NoError(t, err)
+	require.Equal(t, uint64(11), second.LastBlock)
+	second.LastBlock = 12
+	require.NoError(t, other.Put(second))
+
+	// a removed filter isn't created again by a poll
+	_, err = store.Delete(f.ID)
+	require.NoError(t, err)
+	require.ErrorIs(t, store.Put(second), ErrStoredFilterChanged)
+
+	// lock left by a crashed rpcdaemon is taken over
+	f = &StoredFilter{ID: NewStoredFilterID(), Type: BlocksStoredFilter, LastPoll: time.Now()}
+	lockPath := filepath.Join(dir, f.ID+".json.lock")
+	require.NoError(t, os.WriteFile(lockPath, nil, 0o644))
+	old := time.Now().Add(-filterStoreLockTimeout - time.Second)
+	require.NoError(t, os.Chtimes(lockPath, old, old))
+	require.NoError(t, store.Put(f))
+	_, err = os.Stat(lockPath)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -56,15 +56,22 @@ type Filters struct {
 	pendingLogsSubs  *concurrent.SyncMap[PendingLogsSubID, Sub[types.Logs]]
 	pendingBlockSubs *concurrent.SyncMap[PendingBlockSubID, Sub[*types.Block]]
 	pendingTxsSubs   *concurrent.SyncMap[PendingTxsSubID, Sub[[]types.Transaction]]
+	headChangesSubs  *concurrent.SyncMap[HeadChangesSubID, Sub[*HeadChange]]
+	syncingSubs      *concurrent.SyncMap[SyncingSubID, Sub[*remote.SyncingReply]]
 	logsSubs         *LogsFilterAggregator
 	logsRequestor    atomic.Value
 	onNewSnapshot    func()
+	canonicalHeads   *canonicalHeads
+	syncingPoll      chan struct{}
 
 	logsStores         *concurrent.SyncMap[LogsSubID, []*types.Log]
 	pendingHeadsStores *concurrent.SyncMap[HeadsSubID, []*types.Header]
 	pendingTxsStores   *concurrent.SyncMap[PendingTxsSubID, [][]types.Transaction]
 	logger             log.Logger
 
+	store            FilterStore
+	storedPendingTxs *concurrent.SyncMap[string, *storedPendingTxsSub]
+
 	config FiltersConfig
 }
 
@@ -79,6 +86,10 @@ func New(ctx context.Context, config FiltersConfig, ethBackend ApiBackend, txPoo
 		pendingTxsSubs:     concurrent.NewSyncMap[PendingTxsSubID, Sub[[]types.Transaction]](),
 		pendingLogsSubs:    concurrent.NewSyncMap[PendingLogsSubID, Sub[types.Logs]](),
 		pendingBlockSubs:   concurrent.NewSyncMap[PendingBlockSubID, Sub[*types.Block]](),
+		headChangesSubs:    concurrent.NewSyncMap[HeadChangesSubID, Sub[*HeadChange]](),
+		syncingSubs:        concurrent.NewSyncMap[SyncingSubID, Sub[*remote.SyncingReply]](),
+		canonicalHeads:     newCanonicalHeads(),
+		syncingPoll:        make(chan struct{}, 1),
 		logsSubs:           NewLogsFilterAggregator(),
 		onNewSnapshot:      onNewSnapshot,
 		logsStores:         concurrent.NewSyncMap[LogsSubID, []*types.Log](),
@@ -86,6 +97,16 @@ func New(ctx context.Context, config FiltersConfig, ethBackend ApiBackend, txPoo
 		pendingTxsStores:   concurrent.NewSyncMap[PendingTxsSubID, [][]types.Transaction](),
 		logger:             logger,
 		config:             config,
+		storedPendingTxs:   concurrent.NewSyncMap[string, *storedPendingTxsSub](),
+	}
+
+	if config.RpcFiltersStoreDir != "" {
+		store, err := NewFileFilterStore(config.RpcFiltersStoreDir, config.RpcFiltersStoreTTL)
+		if err != nil {
+			logger.Error("rpc filters: can't open filter store, filters are kept in memory", "err", err)
+		} else {
+			ff.store = store
+		}
 	}
 
 	go func() {
@@ -117,6 +138,10 @@ func New(ctx context.Context, config FiltersConfig, ethBackend ApiBackend, txPoo
 		}
 	}()
 
+	if ethBackend != nil {
+		go ff.pollSyncing(ctx, ethBackend)
+	}
+
 	go func() {
 		if ethBackend == nil {
 			return
@@ -621,6 +646,12 @@ func (ff *Filters) onNewHeader(event *remote.SubscribeReply) error {
 	if err != nil {
 		return fmt.Errorf("unprocessable payload: %w", err)
 	}
+	ff.onNewCanonicalHeader(&header)
+	// new headers are notified at the end of a sync cycle, update the sync status
+	select {
+	case ff.syncingPoll <- struct{}{}:
+	default:
+	}
 	return ff.headsSubs.Range(func(k HeadsSubID, v Sub[*types.Header]) error {
 		v.Send(&header)
 		return nil
@@ -782,3 +813,71 @@ func (ff *Filters) ReadPendingTxs(id PendingTxsSubID) ([][]types.Transaction, bo
 	}
 	return res, true
 }
+
+// storedPendingTxsSub is the local subscription of a stored pending transactions filter.
+type storedPendingTxsSub struct {
+	id       PendingTxsSubID
+	lastRead atomic.Int64
+}
+
+// Store returns the store filters installed with eth_new*Filter are persisted
+// to, or nil if they are kept in memory.
+func (ff *Filters) Store() FilterStore {
+	return ff.store
+}
+
+// ReadStoredPendingTxs returns the pending transactions received by this
+// rpcdaemon for a stored pending transactions filter since the filter was last
+// read here. Pending transactions can't be recomputed from the chain: the
+// first read subscribes to them, transactions that arrived before, or while no
+// rpcdaemon was serving the filter, are not returned.
+func (ff *Filters) ReadStoredPendingTxs(id string) []types.Transaction {
+	ff.sweepStoredPendingTxs()
+	sub, _ := ff.storedPendingTxs.DoAndStore(id, func(sub *storedPendingTxsSub, ok bool) *storedPendingTxsSub {
+		if ok {
+			return sub
+		}
+		ch, subID := ff.SubscribePendingTxs(256)
+		go func() {
+			for txs := range ch {
+				ff.AddPendingTxs(subID, txs)
+			}
+		}()
+		sub = &storedPendingTxsSub{id: subID}
+		sub.lastRead.Store(time.Now().UnixNano())
+		return sub
+	})
+	sub.lastRead.Store(time.Now().UnixNano())
+
+	batches, _ := ff.ReadPendingTxs(sub.id)
+	var txs []types.Transaction
+	for _, batch := range batches {
+		txs = append(txs, batch...)
+	}
+	return txs
+}
+
+// UnsubscribeStoredPendingTxs drops the local subscription of a stored pending
+// transactions filter.
+func (ff *Filters) UnsubscribeStoredPendingTxs(id string) {
+	if sub, ok := ff.storedPendingTxs.Delete(id); ok {
+		ff.UnsubscribePendingTxs(sub.id)
+	}
+}
+
+// sweepStoredPendingTxs drops the local subscriptions of the stored pending
+// transactions filters that weren't read here for longer than they are kept
+// in the store, they are either expired or polled from other rpcdaemons.
+func (ff *Filters) sweepStoredPendingTxs() {
+	deadline := time.Now().Add(-ff.config.RpcFiltersStoreTTL).UnixNano()
+	var expired []string
+	ff.storedPendingTxs.Range(func(id string, sub *storedPendingTxsSub) error {
+		if sub.lastRead.Load() < deadline {
+			expired = append(expired, id)
+		}
+		return nil
+	})
+	for _, id := range expired {
+		ff.UnsubscribeStoredPendingTxs(id)
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package rpchelper
+
+import (
+	"sync"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/types"
+)
+
+// headChangesDepth is how many of the latest canonical headers are kept to
+// tell which ones a reorg replaced.
+const headChangesDepth = 128
+
+// HeadChange is a change of the canonical chain: the headers which are no
+// longer canonical, highest first, and the new canonical header.
+type HeadChange struct {
+	Removed []*types.Header `json:"removed"`
+	Added   []*types.Header `json:"added"`
+}
+
+type canonicalHeader struct {
+	header *types.Header
+	hash   common.Hash
+}
+
+// canonicalHeads tracks the latest canonical headers notified by the node.
+type canonicalHeads struct {
+	mu      sync.Mutex
+	headers map[uint64]canonicalHeader
+	head    uint64
+}
+
+func newCanonicalHeads() *canonicalHeads {
+	return &canonicalHeads{headers: make(map[uint64]canonicalHeader)}
+}
+
+// add makes header the canonical head and returns how the canonical chain
+// changed, or nil if header already was the head. On reorgs the node notifies
+// the headers of the new branch from the fork point up, so the first one of
+// them replaces all tracked headers from its number up.
+func (c *canonicalHeads) add(header *types.Header) *HeadChange {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	number, hash := header.Number.Uint64(), header.Hash()
+	change := &HeadChange{}
+	for n := c.head; n > number; n-- {
+		if h, ok := c.headers[n]; ok {
+			change.Removed = append(change.Removed, h.header)
+			delete(c.headers, n)
+		}
+	}
+	if h, ok := c.headers[number]; !ok || h.hash != hash {
+		if ok {
+			change.Removed = append(change.Removed, h.header)
+		}
+		if parent, ok := c.headers[number-1]; ok && number > 0 && parent.hash != header.ParentHash {
+			// the headers from the fork point up weren't all notified, the
+			// fork point is unknown and none of the tracked ancestors can be
+			// trusted anymore
+			for n := number - 1; ; n-- {
+				h, ok := c.headers[n]
+				if !ok {
+					break
+				}
+				change.Removed = append(change.Removed, h.header)
+				if n == 0 {
+					break
+				}
+			}
+			clear(c.headers)
+		}
+		c.headers[number] = canonicalHeader{header: header, hash: hash}
+		change.Added = append(change.Added, header)
+	}
+	c.head = number
+
+	for n := range c.headers {
+		if n+headChangesDepth <= number {
+			delete(c.headers, n)
+		}
+	}
+	if len(change.Removed) == 0 && len(change.Added) == 0 {
+		return nil
+	}
+	return change
+}
+
+// SubscribeHeadChanges subscribes to changes of the canonical chain and
+// returns a channel to receive them and a subscription ID to manage the
+// subscription. Unlike SubscribeNewHeads it tells which headers reorgs
+// replaced.
+func (ff *Filters) SubscribeHeadChanges(size int) (<-chan *HeadChange, HeadChangesSubID) {
+	id := HeadChangesSubID(generateSubscriptionID())
+	sub := newChanSub[*HeadChange](size)
+	ff.headChangesSubs.Put(id, sub)
+	return sub.ch, id
+}
+
+// UnsubscribeHeadChanges unsubscribes from changes of the canonical chain
+// using the given subscription ID. It returns true if the unsubscription was
+// successful, otherwise false.
+func (ff *Filters) UnsubscribeHeadChanges(id HeadChangesSubID) bool {
+	sub, ok := ff.headChangesSubs.Delete(id)
+	if !ok {
+		return false
+	}
+	sub.Close()
+	return true
+}
+
+// onNewCanonicalHeader tracks a header notified by the node and notifies the
+// resulting change of the canonical chain.
+func (ff *Filters) onNewCanonicalHeader(header *types.Header) {
+	change := ff.canonicalHeads.add(header)
+	if change == nil {
+		return
+	}
+	ff.headChangesSubs.Range(func(k HeadChangesSubID, v Sub[*HeadChange]) error {
+		v.Send(change)
+		return nil
+	})
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package rpchelper
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common"
+	remote "github.com/erigontech/erigon-lib/gointerfaces/remoteproto"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/rlp"
+	"github.com/erigontech/erigon-lib/types"
+)
+
+func TestFilters_HeadChanges(t *testing.T) {
+	t.Parallel()
+	f := New(context.TODO(), FiltersConfig{}, nil, nil, nil, func() {}, log.New())
+	changes, id := f.SubscribeHeadChanges(10)
+
+	header := func(number int64, parent *types.Header, extra byte) *types.Header {
+		h := &types.Header{Number: big.NewInt(number), Extra: []byte{extra}}
+		if parent != nil {
+			h.ParentHash = parent.Hash()
+		}
+		return h
+	}
+	notify := func(h *types.Header) {
+		data, err := rlp.EncodeToBytes(h)
+		require.NoError(t, err)
+		f.OnNewEvent(&remote.SubscribeReply{Type: remote.Event_HEADER, Data: data})
+	}
+	hashes := func(headers []*types.Header) []common.Hash {
+		var res []common.Hash
+		for _, h := range headers {
+			res = append(res, h.Hash())
+		}
+		return res
+	}
+
+	h1 := header(1, nil, 0)
+	h2 := header(2, h1, 0)
+	h3 := header(3, h2, 0)
+	for _, h := range []*types.Header{h1, h2, h3} {
+		notify(h)
+		change := <-changes
+		require.Empty(t, change.Removed)
+		require.Equal(t, []common.Hash{h.Hash()}, hashes(change.Added))
+	}
+
+	// the same head again is not a change
+	notify(h3)
+	require.Empty(t, changes)
+
+	// reorg from block 2: the node notifies the new branch from the fork point up
+	h2b := header(2, h1, 1)
+	h3b := header(3, h2b, 1)
+	h4b := header(4, h3b, 1)
+	notify(h2b)
+	change := <-changes
+	require.Equal(t, []common.Hash{h3.Hash(), h2.Hash()}, hashes(change.Removed))
+	require.Equal(t, []common.Hash{h2b.Hash()}, hashes(change.Added))
+	notify(h3b)
+	notify(h4b)
+	require.Empty(t, (<-changes).Removed)
+	require.Empty(t, (<-changes).Removed)
+
+	// unwind without new blocks
+	notify(h3b)
+	change = <-changes
+	require.Equal(t, []common.Hash{h4b.Hash()}, hashes(change.Removed))
+	require.Empty(t, change.Added)
+
+	// reorg from block 2 with the fork point not notified: all tracked
+	// ancestors are removed
+	h2c := header(2, h1, 2)
+	h3c := header(3, h2c, 2)
+	h4c := header(4, h3c, 2)
+	notify(h4c)
+	change = <-changes
+	require.Equal(t, []common.Hash{h3b.Hash(), h2b.Hash(), h1.Hash()}, hashes(change.Removed))
+	require.Equal(t, []common.Hash{h4c.Hash()}, hashes(change.Added))
+	notify(h4c)
+	require.Empty(t, changes)
+
+	require.True(t, f.UnsubscribeHeadChanges(id))
+	_, ok := <-changes
+	require.False(t, ok)
+}
+
+type syncingBackend struct {
+	ApiBackend
+	reply *remote.SyncingReply
+}
+
+func (b *syncingBackend) Syncing(context.Context) (*remote.SyncingReply, error) {
+	return b.reply, nil
+}
+
+func TestFilters_Syncing(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	f := New(ctx, FiltersConfig{}, nil, nil, nil, func() {}, log.New())
+	go f.pollSyncing(ctx, &syncingBackend{reply: &remote.SyncingReply{Syncing: true, CurrentBlock: 10}})
+
+	replies, id := f.SubscribeSyncing(10)
+	// a new header triggers a poll right away
+	data, err := rlp.EncodeToBytes(&types.Header{Number: big.NewInt(1)})
+	require.NoError(t, err)
+	f.OnNewEvent(&remote.SubscribeReply{Type: remote.Event_HEADER, Data: data})
+	select {
+	case reply := <-replies:
+		require.True(t, reply.Syncing)
+		require.Equal(t, uint64(10), reply.CurrentBlock)
+	case <-time.After(syncingPollInterval / 2):
+		t.Fatal("sync status wasn't polled on a new header")
+	}
+	require.True(t, f.UnsubscribeSyncing(id))
+}
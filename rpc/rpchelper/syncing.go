@@ -0,0 +1,83 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package rpchelper
+
+import (
+	"context"
+	"time"
+
+	remote "github.com/erigontech/erigon-lib/gointerfaces/remoteproto"
+)
+
+// syncingPollInterval is how often the progress of the stages is polled while
+// there are syncing subscriptions. It's also polled on every new header.
+const syncingPollInterval = 2 * time.Second
+
+// SubscribeSyncing subscribes to the sync status and returns a channel to
+// receive it and a subscription ID to manage the subscription. The status is
+// sent after every poll of the progress of the stages, even if it didn't
+// change.
+func (ff *Filters) SubscribeSyncing(size int) (<-chan *remote.SyncingReply, SyncingSubID) {
+	id := SyncingSubID(generateSubscriptionID())
+	sub := newChanSub[*remote.SyncingReply](size)
+	ff.syncingSubs.Put(id, sub)
+	return sub.ch, id
+}
+
+// UnsubscribeSyncing unsubscribes from the sync status using the given
+// subscription ID. It returns true if the unsubscription was successful,
+// otherwise false.
+func (ff *Filters) UnsubscribeSyncing(id SyncingSubID) bool {
+	sub, ok := ff.syncingSubs.Delete(id)
+	if !ok {
+		return false
+	}
+	sub.Close()
+	return true
+}
+
+// pollSyncing polls the sync status for the syncing subscriptions until ctx
+// is done.
+func (ff *Filters) pollSyncing(ctx context.Context, ethBackend ApiBackend) {
+	ticker := time.NewTicker(syncingPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-ff.syncingPoll:
+		}
+
+		var subs []Sub[*remote.SyncingReply]
+		ff.syncingSubs.Range(func(k SyncingSubID, v Sub[*remote.SyncingReply]) error {
+			subs = append(subs, v)
+			return nil
+		})
+		if len(subs) == 0 {
+			continue
+		}
+		reply, err := ethBackend.Syncing(ctx)
+		if err != nil {
+			ff.logger.Debug("rpc filters: failed to poll sync status", "err", err)
+			continue
+		}
+		for _, sub := range subs {
+			sub.Send(reply)
+		}
+	}
+}
@@ -51,6 +51,7 @@ const (
 type Server struct {
 	services        serviceRegistry
 	methodAllowList AllowList
+	rateLimiter     *RateLimiter
 	idgen           func() ID
 	run             int32
 	codecs          mapset.Set // mapset.Set[ServerCodec] requires go 1.20
@@ -80,6 +81,11 @@ func (s *Server) SetAllowList(allowList AllowList) {
 	s.methodAllowList = allowList
 }
 
+// SetRateLimiter sets the per-client rate limiter of the calls handled by this server
+func (s *Server) SetRateLimiter(rateLimiter *RateLimiter) {
+	s.rateLimiter = rateLimiter
+}
+
 // SetBatchLimit sets limit of number of requests in a batch
 func (s *Server) SetBatchLimit(limit int) {
 	s.batchLimit = limit
@@ -110,7 +116,7 @@ func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 	s.codecs.Add(codec)
 	defer s.codecs.Remove(codec)
 
-	c := initClient(codec, s.idgen, &s.services, s.batchLimit, s.logger)
+	c := initClient(codec, s.idgen, &s.services, s.batchLimit, s.rateLimiter, s.logger)
 	<-codec.closed()
 	c.Close()
 }
@@ -126,6 +132,7 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec, stre
 
 	h := newHandler(ctx, codec, s.idgen, &s.services, s.methodAllowList, s.batchConcurrency, s.traceRequests, s.logger, s.rpcSlowLogThreshold)
 	h.allowSubscribe = false
+	h.rateLimiter = s.rateLimiter
 	defer h.close(io.EOF, nil)
 
 	reqs, batch, err := codec.ReadBatch()
@@ -191,6 +198,9 @@ type PeerInfo struct {
 	// Address of client. This will usually contain the IP address and port.
 	RemoteAddr string
 
+	// Subject of the verified JWT the client authenticated with, if any.
+	JWTSubject string
+
 	// Additional information for HTTP and WebSocket connections.
 	HTTP struct {
 		// Protocol version, i.e. "HTTP/1.1". This is not set for WebSocket.
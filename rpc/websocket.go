@@ -59,8 +59,12 @@ func (s *Server) WebsocketHandler(allowedOrigins []string, jwtSecret []byte, com
 		CheckOrigin:       wsHandshakeValidator(allowedOrigins, logger),
 	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if jwtSecret != nil && !CheckJwtSecret(w, r, jwtSecret) {
-			return
+		if jwtSecret != nil {
+			ctx, ok := CheckJwtSecret(w, r, jwtSecret)
+			if !ok {
+				return
+			}
+			r = r.WithContext(ctx)
 		}
 		conn, err := upgrader.Upgrade(w, r, nil)
 		if err != nil {
@@ -68,6 +72,9 @@ func (s *Server) WebsocketHandler(allowedOrigins []string, jwtSecret []byte, com
 			return
 		}
 		codec := NewWebsocketCodec(conn, r.Host, r.Header)
+		if subject, ok := r.Context().Value(jwtSubjectContextKey{}).(string); ok {
+			codec.(*websocketCodec).info.JWTSubject = subject
+		}
 		s.ServeCodec(codec, 0)
 	})
 }
@@ -0,0 +1,74 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package tests
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/erigontech/erigon-lib/common/datadir"
+	"github.com/erigontech/erigon-lib/kv/temporal/temporaltest"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/execution/testutil"
+)
+
+func TestExecutionSpecEOF(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	et := new(testMatcher)
+
+	dir := filepath.Join(".", "execution-spec-tests", "eof_tests")
+
+	et.walk(t, dir, func(t *testing.T, name string, test *EOFTest) {
+		t.Parallel()
+		if err := et.checkFailure(t, test.Run()); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+// TestExecutionSpecEOFState executes the state tests of the forks with EOF,
+// e.g. the EOFv1 ones of EIP-7692, with the runner of TestState.
+func TestExecutionSpecEOFState(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	defer log.Root().SetHandler(log.Root().GetHandler())
+	log.Root().SetHandler(log.LvlFilterHandler(log.LvlError, log.StderrHandler))
+
+	st := new(testMatcher)
+
+	dir := filepath.Join(".", "execution-spec-tests", "state_tests")
+
+	dirs := datadir.New(t.TempDir())
+	db := temporaltest.NewTestDB(t, dirs)
+	st.walk(t, dir, func(t *testing.T, name string, test *StateTest) {
+		for _, subtest := range test.Subtests() {
+			if config, ok := testutil.Forks[subtest.Fork]; !ok || !config.IsEOF(0) {
+				continue
+			}
+			key := fmt.Sprintf("%s/%d", subtest.Fork, subtest.Index)
+			t.Run(key, func(t *testing.T) {
+				runStateSubtest(t, st, db, dirs, test, subtest)
+			})
+		}
+	})
+}
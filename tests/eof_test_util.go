@@ -0,0 +1,72 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package tests
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon/core/vm"
+	"github.com/erigontech/erigon/execution/testutil"
+)
+
+// EOFTest checks the validation of EOF containers.
+type EOFTest struct {
+	Vectors map[string]eofVector `json:"vectors"`
+}
+
+type eofVector struct {
+	Code          hexutil.Bytes        `json:"code"`
+	ContainerKind string               `json:"containerKind"`
+	Results       map[string]eofResult `json:"results"`
+}
+
+type eofResult struct {
+	Result    bool   `json:"result"`
+	Exception string `json:"exception,omitempty"`
+}
+
+// Run validates every vector for each of its forks which have EOF enabled.
+func (t *EOFTest) Run() error {
+	names := make([]string, 0, len(t.Vectors))
+	for name := range t.Vectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		vector := t.Vectors[name]
+		for fork, result := range vector.Results {
+			config, ok := testutil.Forks[fork]
+			if !ok {
+				return testutil.UnsupportedForkError{Name: fork}
+			}
+			if !config.IsEOF(0) {
+				continue
+			}
+			err := vm.ValidateEOF(vector.Code, vector.ContainerKind == "INITCODE")
+			if result.Result && err != nil {
+				return fmt.Errorf("vector %s, fork %s: unexpected error: %w", name, fork, err)
+			}
+			if !result.Result && err == nil {
+				return fmt.Errorf("vector %s, fork %s: expected error %s, got none", name, fork, result.Exception)
+			}
+		}
+	}
+	return nil
+}
@@ -29,6 +29,7 @@ import (
 	"testing"
 
 	"github.com/erigontech/erigon-lib/common/datadir"
+	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/kv/temporal/temporaltest"
 	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon/core/vm"
@@ -103,25 +104,29 @@ func TestState(t *testing.T) {
 			subtest := subtest
 			key := fmt.Sprintf("%s/%d", subtest.Fork, subtest.Index)
 			t.Run(key, func(t *testing.T) {
-				withTrace(t, func(vmconfig vm.Config) error {
-					tx, err := db.BeginTemporalRw(context.Background())
-					if err != nil {
-						t.Fatal(err)
-					}
-					defer tx.Rollback()
-					_, _, err = test.Run(tx, subtest, vmconfig, dirs)
-					tx.Rollback()
-					if err != nil && len(test.json.Post[subtest.Fork][subtest.Index].ExpectException) > 0 {
-						// Ignore expected errors
-						return nil
-					}
-					return st.checkFailure(t, err)
-				})
+				runStateSubtest(t, st, db, dirs, test, subtest)
 			})
 		}
 	})
 }
 
+func runStateSubtest(t *testing.T, st *testMatcher, db kv.TemporalRwDB, dirs datadir.Dirs, test *StateTest, subtest StateSubtest) {
+	withTrace(t, func(vmconfig vm.Config) error {
+		tx, err := db.BeginTemporalRw(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Rollback()
+		_, _, err = test.Run(tx, subtest, vmconfig, dirs)
+		tx.Rollback()
+		if err != nil && len(test.json.Post[subtest.Fork][subtest.Index].ExpectException) > 0 {
+			// Ignore expected errors
+			return nil
+		}
+		return st.checkFailure(t, err)
+	})
+}
+
 func withTrace(t *testing.T, test func(vm.Config) error) {
 	// Use config from command line arguments.
 	config := vm.Config{}
@@ -0,0 +1,153 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon/rpc"
+	"github.com/erigontech/erigon/turbo/debug"
+	"github.com/erigontech/erigon/txnprovider/txpool"
+)
+
+var (
+	txpoolRPCURLFlag = cli.StringFlag{
+		Name:  "rpc.url",
+		Usage: "URL of the rpc endpoint of the node",
+		Value: "http://127.0.0.1:8545",
+	}
+
+	txpoolDumpFileFlag = cli.StringFlag{
+		Name:     "file",
+		Usage:    "Path of the pool dump",
+		Required: true,
+	}
+
+	txpoolReplaySpeedFlag = cli.Float64Flag{
+		Name:  "speed",
+		Usage: "Replay speed, 2 sends the transactions twice as fast as they originally arrived",
+		Value: 1,
+	}
+)
+
+var txpoolCommand = cli.Command{
+	Name:  "txpool",
+	Usage: "Export and replay the transaction pool of a node",
+	Subcommands: []*cli.Command{
+		{
+			Name:   "dump",
+			Action: txpoolDump,
+			Usage:  "Write all transactions of the pool, with their senders, sub-pools and arrival times, to a file",
+			Flags:  append([]cli.Flag{&txpoolRPCURLFlag, &txpoolDumpFileFlag}, debug.Flags...),
+			Description: `Fetches the pool of the node with txpool_dump, which requires the txpool api
+and a txpool running in the node's process, and writes it one transaction per line.`,
+		},
+		{
+			Name:   "replay",
+			Action: txpoolReplay,
+			Usage:  "Send the transactions of a pool dump to a node at their original relative timing",
+			Flags:  append([]cli.Flag{&txpoolRPCURLFlag, &txpoolDumpFileFlag, &txpoolReplaySpeedFlag}, debug.Flags...),
+			Description: `Sends the transactions of a dump written by 'txpool dump' with eth_sendRawTransaction,
+e.g. to a dev chain, keeping the time between their arrivals divided by --speed.
+Transactions rejected by the node are logged and skipped.`,
+		},
+	},
+}
+
+func txpoolDump(cliCtx *cli.Context) error {
+	logger, _, _, _, err := debug.Setup(cliCtx, true /* rootLogger */)
+	if err != nil {
+		return err
+	}
+	client, err := rpc.DialContext(cliCtx.Context, cliCtx.String(txpoolRPCURLFlag.Name), logger)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var txns []txpool.DumpedTxn
+	if err := client.CallContext(cliCtx.Context, &txns, "txpool_dump"); err != nil {
+		return err
+	}
+
+	f, err := os.Create(cliCtx.String(txpoolDumpFileFlag.Name))
+	if err != nil {
+		return err
+	}
+	if err := txpool.WriteDump(f, txns); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	logger.Info("[txpool] dumped pool", "txns", len(txns), "file", f.Name())
+	return nil
+}
+
+func txpoolReplay(cliCtx *cli.Context) error {
+	logger, _, _, _, err := debug.Setup(cliCtx, true /* rootLogger */)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(cliCtx.String(txpoolDumpFileFlag.Name))
+	if err != nil {
+		return err
+	}
+	txns, err := txpool.ReadDump(f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	client, err := rpc.DialContext(cliCtx.Context, cliCtx.String(txpoolRPCURLFlag.Name), logger)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var sent, rejected int
+	err = txpool.Replay(cliCtx.Context, txns, cliCtx.Float64(txpoolReplaySpeedFlag.Name), func(ctx context.Context, batch []txpool.DumpedTxn) error {
+		reqs := make([]rpc.BatchElem, len(batch))
+		for i := range batch {
+			reqs[i] = rpc.BatchElem{Method: "eth_sendRawTransaction", Args: []interface{}{batch[i].Rlp}, Result: new(common.Hash)}
+		}
+		if err := client.BatchCallContext(ctx, reqs); err != nil {
+			return err
+		}
+		for i, req := range reqs {
+			if req.Error != nil {
+				rejected++
+				logger.Warn("[txpool] replayed txn rejected", "hash", batch[i].Hash, "sender", batch[i].Sender, "err", req.Error)
+				continue
+			}
+			sent++
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	logger.Info("[txpool] replayed pool", "sent", sent, "rejected", rejected, "of", len(txns))
+	return nil
+}
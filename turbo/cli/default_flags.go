@@ -39,8 +39,13 @@ var DefaultFlags = []cli.Flag{
 	&utils.TxPoolGlobalQueueFlag,
 	&utils.TxPoolTraceSendersFlag,
 	&utils.TxPoolCommitEveryFlag,
+	&utils.TxPoolLifetimeFlag,
+	&utils.TxPoolLocalSlotsFlag,
+	&utils.TxPoolRebroadcastEveryFlag,
+	&utils.TxPoolPrivateLifetimeFlag,
 	&PruneDistanceFlag,
 	&PruneBlocksDistanceFlag,
+	&PruneHistoryWatchFlag,
 	&PruneModeFlag,
 	&utils.KeepExecutionProofsFlag,
 
@@ -81,6 +86,7 @@ var DefaultFlags = []cli.Flag{
 	&utils.RpcStreamingDisableFlag,
 	&utils.DBReadConcurrencyFlag,
 	&utils.RpcAccessListFlag,
+	&utils.RpcRateLimitFlag,
 	&utils.RpcTraceCompatFlag,
 	&utils.RpcGasCapFlag,
 	&utils.RpcBatchLimit,
@@ -89,6 +95,7 @@ var DefaultFlags = []cli.Flag{
 	&utils.RPCGlobalTxFeeCapFlag,
 	&utils.TxpoolApiAddrFlag,
 	&utils.TraceMaxtracesFlag,
+	&utils.TraceChainMaxBlocksFlag,
 
 	&HTTPReadTimeoutFlag,
 	&HTTPWriteTimeoutFlag,
@@ -105,11 +112,16 @@ var DefaultFlags = []cli.Flag{
 	&RpcSubscriptionFiltersMaxTxsFlag,
 	&RpcSubscriptionFiltersMaxAddressesFlag,
 	&RpcSubscriptionFiltersMaxTopicsFlag,
+	&RpcFiltersStoreFlag,
+	&RpcFiltersStoreTTLFlag,
 
 	&utils.SnapKeepBlocksFlag,
 	&utils.SnapStopFlag,
 	&utils.SnapStateStopFlag,
 	&utils.SnapSkipStateSnapshotDownloadFlag,
+	&utils.SnapScrubIntervalFlag,
+	&utils.SnapScrubRateFlag,
+	&utils.SnapServeAddrFlag,
 	&utils.DbPageSizeFlag,
 	&utils.DbSizeLimitFlag,
 	&utils.DbWriteMapFlag,
@@ -171,6 +183,7 @@ var DefaultFlags = []cli.Flag{
 	&HealthCheckFlag,
 	&utils.HeimdallURLFlag,
 	&utils.WebSeedsFlag,
+	&utils.WebSeedsOnlyFlag,
 	&utils.WithoutHeimdallFlag,
 	&utils.BorBlockPeriodFlag,
 	&utils.BorBlockSizeFlag,
@@ -239,6 +252,8 @@ var DefaultFlags = []cli.Flag{
 	&SyncLoopBlockLimitFlag,
 	&SyncLoopBreakAfterFlag,
 	&SyncParallelStateFlushing,
+	&SyncParallelExecFlag,
+	&SyncParallelExecVerifyEveryFlag,
 
 	&utils.ChaosMonkeyFlag,
 
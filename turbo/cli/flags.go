@@ -92,6 +92,10 @@ var (
 		Name:  "prune.distance.blocks",
 		Usage: `Keep block history for the latest N blocks (default: everything)`,
 	}
+	PruneHistoryWatchFlag = cli.StringFlag{
+		Name:  "prune.history.watch",
+		Usage: `Keep state history, logs and traces older than state history prune distance for these accounts only: comma-separated addresses or path to file with one address per line. Older state reads of other accounts (including by eth_call, tracing and receipts re-execution) return a pruned-data error`,
+	}
 	// mTLS flags
 	TLSFlag = cli.BoolFlag{
 		Name:  "tls",
@@ -142,9 +146,21 @@ var (
 		Value: true,
 	}
 
+	SyncParallelExecFlag = cli.BoolFlag{
+		Name:  "sync.parallel-exec",
+		Usage: "Execute txns of a block in parallel during initial sync, conflicting txns are re-executed. Per-block conflict reports are logged at debug level",
+		Value: false,
+	}
+
+	SyncParallelExecVerifyEveryFlag = cli.Uint64Flag{
+		Name:  "sync.parallel-exec.verify-every",
+		Usage: "Cross-check results of parallel execution against serial execution of every N-th block, 0 - never",
+		Value: 0,
+	}
+
 	UploadLocationFlag = cli.StringFlag{
 		Name:  "upload.location",
-		Usage: "Location to upload snapshot segments to",
+		Usage: "Location to upload snapshot segments to: rclone remote, local dir or s3://bucket/prefix?endpoint=url (S3-compatible storage without rclone, credentials from AWS_* env variables)",
 		Value: "",
 	}
 
@@ -246,6 +262,16 @@ var (
 		Usage: "Maximum number of topics per subscription to filter logs by.",
 		Value: rpchelper.DefaultFiltersConfig.RpcSubscriptionFiltersMaxTopics,
 	}
+	RpcFiltersStoreFlag = cli.StringFlag{
+		Name:  "rpc.filters.store",
+		Usage: "Directory to persist filters installed with eth_new*Filter to, so that they survive restarts. Can be shared by several rpcdaemons. Pending transactions of eth_newPendingTransactionFilter are not persisted: each rpcdaemon returns only the ones it received itself.",
+		Value: rpchelper.DefaultFiltersConfig.RpcFiltersStoreDir,
+	}
+	RpcFiltersStoreTTLFlag = cli.DurationFlag{
+		Name:  "rpc.filters.store.ttl",
+		Usage: "Time after which persisted filters that weren't polled are removed.",
+		Value: rpchelper.DefaultFiltersConfig.RpcFiltersStoreTTL,
+	}
 )
 
 func ApplyFlagsForEthConfig(ctx *cli.Context, cfg *ethconfig.Config, logger log.Logger) {
@@ -274,6 +300,12 @@ func ApplyFlagsForEthConfig(ctx *cli.Context, cfg *ethconfig.Config, logger log.
 	if err != nil {
 		utils.Fatalf(fmt.Sprintf("error while parsing mode: %v", err))
 	}
+	if mode.Watched, err = prune.ParseWatched(ctx.String(PruneHistoryWatchFlag.Name)); err != nil {
+		utils.Fatalf("%v", err)
+	}
+	if len(mode.Watched) > 0 && !mode.History.Enabled() {
+		utils.Fatalf("--%s requires state history pruning: --prune.mode=full or --prune.distance", PruneHistoryWatchFlag.Name)
+	}
 
 	cfg.Prune = mode
 	if ctx.String(BatchSizeFlag.Name) != "" {
@@ -317,6 +349,8 @@ func ApplyFlagsForEthConfig(ctx *cli.Context, cfg *ethconfig.Config, logger log.
 		cfg.Sync.LoopBlockLimit = limit
 	}
 	cfg.Sync.ParallelStateFlushing = ctx.Bool(SyncParallelStateFlushing.Name)
+	cfg.Sync.ParallelExec = ctx.Bool(SyncParallelExecFlag.Name)
+	cfg.Sync.ParallelExecVerifyEvery = ctx.Uint64(SyncParallelExecVerifyEveryFlag.Name)
 
 	if location := ctx.String(UploadLocationFlag.Name); len(location) > 0 {
 		cfg.Sync.UploadLocation = location
@@ -461,16 +495,20 @@ func setEmbeddedRpcDaemon(ctx *cli.Context, cfg *nodecfg.Config, logger log.Logg
 		RpcStreamingDisable:       ctx.Bool(utils.RpcStreamingDisableFlag.Name),
 		DBReadConcurrency:         ctx.Int(utils.DBReadConcurrencyFlag.Name),
 		RpcAllowListFilePath:      ctx.String(utils.RpcAccessListFlag.Name),
+		RpcRateLimitFilePath:      ctx.String(utils.RpcRateLimitFlag.Name),
 		RpcFiltersConfig: rpchelper.FiltersConfig{
 			RpcSubscriptionFiltersMaxLogs:      ctx.Int(RpcSubscriptionFiltersMaxLogsFlag.Name),
 			RpcSubscriptionFiltersMaxHeaders:   ctx.Int(RpcSubscriptionFiltersMaxHeadersFlag.Name),
 			RpcSubscriptionFiltersMaxTxs:       ctx.Int(RpcSubscriptionFiltersMaxTxsFlag.Name),
 			RpcSubscriptionFiltersMaxAddresses: ctx.Int(RpcSubscriptionFiltersMaxAddressesFlag.Name),
 			RpcSubscriptionFiltersMaxTopics:    ctx.Int(RpcSubscriptionFiltersMaxTopicsFlag.Name),
+			RpcFiltersStoreDir:                 ctx.String(RpcFiltersStoreFlag.Name),
+			RpcFiltersStoreTTL:                 ctx.Duration(RpcFiltersStoreTTLFlag.Name),
 		},
 		Gascap:              ctx.Uint64(utils.RpcGasCapFlag.Name),
 		Feecap:              ctx.Float64(utils.RPCGlobalTxFeeCapFlag.Name),
 		MaxTraces:           ctx.Uint64(utils.TraceMaxtracesFlag.Name),
+		MaxTraceChainBlocks: ctx.Uint64(utils.TraceChainMaxBlocksFlag.Name),
 		TraceCompatibility:  ctx.Bool(utils.RpcTraceCompatFlag.Name),
 		BatchLimit:          ctx.Int(utils.RpcBatchLimit.Name),
 		ReturnDataLimit:     ctx.Int(utils.RpcReturnDataLimit.Name),
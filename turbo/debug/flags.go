@@ -45,12 +45,12 @@ import (
 var (
 	vmTraceFlag = cli.StringFlag{
 		Name:  "vmtrace",
-		Usage: "Set the provider tracer",
+		Usage: "Set the provider tracer, e.g. jsonlCallTracer or balanceDeltaTracer to persist call traces or balance deltas of executed blocks",
 	}
 
 	vmTraceJsonConfigFlag = cli.StringFlag{
 		Name:  "vmtrace.jsonconfig",
-		Usage: "Set the config of the tracer",
+		Usage: "Set the config of the tracer, e.g. {\"path\":\"/data/traces\",\"sync\":true}",
 	}
 	//nolint
 	vmoduleFlag = cli.StringFlag{
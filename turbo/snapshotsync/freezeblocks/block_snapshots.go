@@ -40,6 +40,7 @@ import (
 	"github.com/erigontech/erigon-lib/chain/snapcfg"
 	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/background"
+	"github.com/erigontech/erigon-lib/common/checksum"
 	"github.com/erigontech/erigon-lib/common/datadir"
 	"github.com/erigontech/erigon-lib/common/dbg"
 	dir2 "github.com/erigontech/erigon-lib/common/dir"
@@ -1090,6 +1091,7 @@ func RemoveIncompatibleIndices(dirs datadir.Dirs) error {
 				} else {
 					log.Info("Removing incompatible index", "file", fName)
 				}
+				checksum.Remove(fPath)
 				continue
 			}
 			return fmt.Errorf("%w, %s", err, fPath)
@@ -24,6 +24,7 @@ import (
 	"reflect"
 
 	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/checksum"
 	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon-lib/snaptype"
 	"github.com/erigontech/erigon/cmd/hack/tool/fromdb"
@@ -204,9 +205,11 @@ func removeBorOverlaps(dir string, active []snaptype.FileInfo, _max uint64) {
 	for _, f := range toDel {
 		_ = os.Remove(f)
 		_ = os.Remove(f + ".torrent")
+		checksum.Remove(f)
 		ext := filepath.Ext(f)
 		withoutExt := f[:len(f)-len(ext)]
 		_ = os.Remove(withoutExt + ".idx")
 		_ = os.Remove(withoutExt + ".idx.torrent")
+		checksum.Remove(withoutExt + ".idx")
 	}
 }
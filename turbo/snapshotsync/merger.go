@@ -14,6 +14,7 @@ import (
 	"github.com/erigontech/erigon-lib/chain"
 	"github.com/erigontech/erigon-lib/chain/snapcfg"
 	"github.com/erigontech/erigon-lib/common/background"
+	"github.com/erigontech/erigon-lib/common/checksum"
 	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon-lib/seg"
@@ -96,14 +97,17 @@ func (m *Merger) mergeSubSegment(ctx context.Context, v *View, sn snaptype.FileI
 			f := sn.Path
 			_ = os.Remove(f)
 			_ = os.Remove(f + ".torrent")
+			checksum.Remove(f)
 			ext := filepath.Ext(f)
 			withoutExt := f[:len(f)-len(ext)]
 			_ = os.Remove(withoutExt + ".idx")
 			_ = os.Remove(withoutExt + ".idx.torrent")
+			checksum.Remove(withoutExt + ".idx")
 			isTxnType := strings.HasSuffix(withoutExt, coresnaptype.Transactions.Name())
 			if isTxnType {
 				_ = os.Remove(withoutExt + "-to-block.idx")
 				_ = os.Remove(withoutExt + "-to-block.idx.torrent")
+				checksum.Remove(withoutExt + "-to-block.idx")
 			}
 		}
 	}()
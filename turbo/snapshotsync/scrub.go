@@ -0,0 +1,229 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshotsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/anacrolix/torrent/metainfo"
+
+	"github.com/erigontech/erigon-db/downloader"
+	"github.com/erigontech/erigon-lib/chain/snapcfg"
+	"github.com/erigontech/erigon-lib/common/checksum"
+	"github.com/erigontech/erigon-lib/common/datadir"
+	"github.com/erigontech/erigon-lib/common/dir"
+	proto_downloader "github.com/erigontech/erigon-lib/gointerfaces/downloaderproto"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/recsplit"
+	"github.com/erigontech/erigon-lib/seg"
+	"github.com/erigontech/erigon-lib/snaptype"
+)
+
+var errDamaged = errors.New("damaged file")
+
+// Scrubber - periodically checks all blocks of snapshot files at limited read speed: against their checksum sidecars
+// (see common/checksum), or - for downloaded files which have no sidecar yet - against piece hashes of their torrents,
+// then sidecar is written. Torrent of preverified file must also have preverified info-hash - then file is checked
+// against hashes which node didn't produce by self. Damaged preverified files are removed and downloaded again,
+// other damaged files must be removed and re-built by user.
+type Scrubber struct {
+	dirs        datadir.Dirs
+	downloader  proto_downloader.DownloaderClient // nil - no repair
+	preverified snapcfg.PreverifiedItems
+	interval    time.Duration
+	bytesPerSec uint64
+	ready       func() bool // files are downloaded: don't check files in progress
+
+	repairing map[string]struct{} // re-downloading files, relative to dirs.Snap
+	logger    log.Logger
+}
+
+type ScrubStat struct {
+	Checked, Unchecked, Damaged, Repairing int
+}
+
+func NewScrubber(dirs datadir.Dirs, downloader proto_downloader.DownloaderClient, preverified snapcfg.PreverifiedItems,
+	interval time.Duration, bytesPerSec uint64, ready func() bool, logger log.Logger) *Scrubber {
+	return &Scrubber{
+		dirs:        dirs,
+		downloader:  downloader,
+		preverified: preverified,
+		interval:    interval,
+		bytesPerSec: bytesPerSec,
+		ready:       ready,
+		repairing:   map[string]struct{}{},
+		logger:      logger,
+	}
+}
+
+func (s *Scrubber) Run(ctx context.Context) {
+	wait := min(s.interval, time.Minute) // first pass soon after start
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		if s.ready != nil && !s.ready() {
+			continue
+		}
+		wait = s.interval
+		t := time.Now()
+		stat, err := s.Scrub(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				s.logger.Warn("[snapshots] scrub", "err", err)
+			}
+			continue
+		}
+		s.logger.Info("[snapshots] scrub done", "checked", stat.Checked, "unchecked", stat.Unchecked, "damaged", stat.Damaged,
+			"repairing", stat.Repairing, "took", time.Since(t))
+	}
+}
+
+// Scrub - one pass over all files
+func (s *Scrubber) Scrub(ctx context.Context) (stat ScrubStat, err error) {
+	if len(s.repairing) > 0 {
+		reply, err := s.downloader.Completed(ctx, &proto_downloader.CompletedRequest{})
+		if err != nil {
+			return stat, err
+		}
+		if reply.Completed {
+			clear(s.repairing) // downloaded files are checked in this pass
+		}
+	}
+
+	names, err := s.files()
+	if err != nil {
+		return stat, err
+	}
+	for _, name := range names {
+		if _, ok := s.repairing[name]; ok {
+			stat.Repairing++
+			continue
+		}
+		err := s.verify(ctx, name)
+		switch {
+		case err == nil:
+			stat.Checked++
+		case errors.Is(err, errDamaged), errors.Is(err, checksum.ErrMismatch), errors.Is(err, downloader.ErrPieceHashMismatch):
+			stat.Damaged++
+			if err := s.repair(ctx, name, err); err != nil {
+				return stat, err
+			}
+		case errors.Is(err, checksum.ErrNoSums), errors.Is(err, os.ErrNotExist): // no sidecar nor .torrent yet, or file removed by merge
+			stat.Unchecked++
+		case ctx.Err() != nil:
+			return stat, ctx.Err()
+		default:
+			return stat, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return stat, nil
+}
+
+// verify - checks file against its checksum sidecar or .torrent, and .torrent of preverified file against preverified info-hash
+func (s *Scrubber) verify(ctx context.Context, name string) error {
+	path := filepath.Join(s.dirs.Snap, filepath.FromSlash(name))
+	mi, err := metainfo.LoadFromFile(path + ".torrent")
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: can't read .torrent: %w", errDamaged, err)
+	}
+	if item, ok := s.preverified.Get(name); ok && mi != nil && mi.HashInfoBytes().HexString() != item.Hash {
+		return fmt.Errorf("%w: .torrent has info-hash %s, preverified %s", errDamaged, mi.HashInfoBytes().HexString(), item.Hash)
+	}
+	if err := checksum.Verify(ctx, path, s.bytesPerSec); !errors.Is(err, checksum.ErrNoSums) || mi == nil {
+		return err
+	}
+
+	info, err := mi.UnmarshalInfo()
+	if err != nil {
+		return fmt.Errorf("%w: can't read .torrent: %w", errDamaged, err)
+	}
+	if err := downloader.VerifyFile(ctx, path, &info, s.bytesPerSec); err != nil {
+		return err
+	}
+	// now blocks of file are also checked when node reads them
+	if err := buildChecksums(ctx, path); err != nil {
+		s.logger.Warn("[snapshots] scrub: can't write checksums", "file", name, "err", err)
+	}
+	return nil
+}
+
+// buildChecksums - writes checksum sidecar of file, with sections of formats which have them
+func buildChecksums(ctx context.Context, path string) error {
+	switch filepath.Ext(path) {
+	case ".seg", ".kv", ".v", ".ef":
+		return seg.BuildChecksums(ctx, path)
+	case ".idx", ".kvi", ".vi", ".efi":
+		return recsplit.BuildChecksums(ctx, path)
+	default:
+		return checksum.Build(ctx, path)
+	}
+}
+
+func (s *Scrubber) repair(ctx context.Context, name string, cause error) error {
+	item, ok := s.preverified.Get(name)
+	if !ok || s.downloader == nil {
+		s.logger.Crit("[snapshots] damaged file: remove it and re-build", "file", name, "err", cause)
+		return nil
+	}
+	s.logger.Crit("[snapshots] damaged file: downloading it again, restart node when download completed", "file", name, "err", cause)
+	checksum.Remove(filepath.Join(s.dirs.Snap, filepath.FromSlash(name)))
+	if _, err := s.downloader.Delete(ctx, &proto_downloader.DeleteRequest{Paths: []string{name}}); err != nil {
+		return err
+	}
+	if err := RequestSnapshotsDownload(ctx, []DownloadRequest{NewDownloadRequest(item.Name, item.Hash)}, s.downloader, "snapshots"); err != nil {
+		return err
+	}
+	s.repairing[name] = struct{}{}
+	return nil
+}
+
+func (s *Scrubber) dirList() []string {
+	return []string{s.dirs.Snap, s.dirs.SnapIdx, s.dirs.SnapHistory, s.dirs.SnapDomain, s.dirs.SnapAccessors, s.dirs.SnapCaplin}
+}
+
+// files - seedable files, relative to dirs.Snap with slash separators (as in preverified list)
+func (s *Scrubber) files() ([]string, error) {
+	var names []string
+	for _, d := range s.dirList() {
+		paths, err := dir.ListFiles(d)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		for _, path := range paths {
+			if !snaptype.IsSeedableExtension(path) {
+				continue
+			}
+			name, err := filepath.Rel(s.dirs.Snap, path)
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, filepath.ToSlash(name))
+		}
+	}
+	return names, nil
+}
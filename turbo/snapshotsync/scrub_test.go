@@ -0,0 +1,146 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshotsync
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anacrolix/torrent/metainfo"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/erigontech/erigon-db/downloader"
+	"github.com/erigontech/erigon-lib/chain/snapcfg"
+	"github.com/erigontech/erigon-lib/common/checksum"
+	"github.com/erigontech/erigon-lib/common/datadir"
+	proto_downloader "github.com/erigontech/erigon-lib/gointerfaces/downloaderproto"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/seg"
+)
+
+func TestScrubber(t *testing.T) {
+	ctx := context.Background()
+	dirs := datadir.New(t.TempDir())
+	const (
+		headers = "v1.0-000000-000500-headers.seg"
+		ef      = "idx/v1.0-accounts.0-64.ef"
+		partial = "v1.0-000500-001000-headers.seg"
+	)
+	torrents := downloader.NewAtomicTorrentFS(dirs.Snap)
+	path := func(name string) string { return filepath.Join(dirs.Snap, filepath.FromSlash(name)) }
+	write := func(name string) {
+		c, err := seg.NewCompressor(ctx, t.Name(), path(name), dirs.Tmp, seg.DefaultCfg, log.LvlDebug, log.New())
+		require.NoError(t, err)
+		defer c.Close()
+		for i := 0; i < 100; i++ {
+			require.NoError(t, c.AddWord([]byte(fmt.Sprintf("%s word %d", name, i))))
+		}
+		require.NoError(t, c.Compress())
+	}
+	// damage - flips last byte of file, keeping its modification time as bit-rot does
+	damage := func(name string) {
+		stat, err := os.Stat(path(name))
+		require.NoError(t, err)
+		data, err := os.ReadFile(path(name))
+		require.NoError(t, err)
+		data[len(data)-1] ^= 0xff
+		require.NoError(t, os.WriteFile(path(name), data, 0644))
+		require.NoError(t, os.Chtimes(path(name), stat.ModTime(), stat.ModTime()))
+	}
+	// built files have checksum sidecars, downloaded - don't
+	for _, name := range []string{headers, ef, partial} {
+		write(name)
+	}
+	for _, name := range []string{headers, ef} {
+		_, err := downloader.BuildTorrentIfNeed(ctx, name, dirs.Snap, torrents)
+		require.NoError(t, err)
+	}
+	checksum.Remove(path(ef))
+	mi, err := metainfo.LoadFromFile(filepath.Join(dirs.Snap, filepath.FromSlash(ef)+".torrent"))
+	require.NoError(t, err)
+	preverified := snapcfg.PreverifiedItems{{Name: ef, Hash: mi.HashInfoBytes().HexString()}}
+
+	ctrl := gomock.NewController(t)
+	client := proto_downloader.NewMockDownloaderClient(ctrl)
+	s := NewScrubber(dirs, client, preverified, 0, 1024, nil, log.New())
+
+	stat, err := s.Scrub(ctx)
+	require.NoError(t, err)
+	require.Equal(t, ScrubStat{Checked: 3}, stat)
+	// downloaded file passed check against .torrent: sidecar is written
+	_, err = checksum.Read(path(ef))
+	require.NoError(t, err)
+
+	// not preverified files: only reported, also files without .torrent
+	damage(headers)
+	damage(partial)
+	stat, err = s.Scrub(ctx)
+	require.NoError(t, err)
+	require.Equal(t, ScrubStat{Checked: 1, Damaged: 2}, stat)
+
+	// file without sidecar nor .torrent is not checked
+	checksum.Remove(path(partial))
+	stat, err = s.Scrub(ctx)
+	require.NoError(t, err)
+	require.Equal(t, ScrubStat{Checked: 1, Unchecked: 1, Damaged: 1}, stat)
+
+	// .torrent of preverified file doesn't match preverified info-hash: file is not trusted
+	other := NewScrubber(dirs, nil, snapcfg.PreverifiedItems{{Name: ef, Hash: "0102030405060708090a0b0c0d0e0f1011121314"}}, 0, 0, nil, log.New())
+	stat, err = other.Scrub(ctx)
+	require.NoError(t, err)
+	require.Equal(t, ScrubStat{Unchecked: 1, Damaged: 2}, stat)
+
+	// preverified file: re-downloaded
+	damage(ef)
+	client.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, req *proto_downloader.DeleteRequest, _ ...grpc.CallOption) (*emptypb.Empty, error) {
+			require.Equal(t, []string{ef}, req.Paths)
+			return nil, nil
+		})
+	client.EXPECT().SetLogPrefix(gomock.Any(), gomock.Any()).Return(nil, nil)
+	client.EXPECT().Add(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, req *proto_downloader.AddRequest, _ ...grpc.CallOption) (*emptypb.Empty, error) {
+			require.Len(t, req.Items, 1)
+			require.Equal(t, ef, req.Items[0].Path)
+			require.NotNil(t, req.Items[0].TorrentHash)
+			return nil, nil
+		})
+	stat, err = s.Scrub(ctx)
+	require.NoError(t, err)
+	require.Equal(t, ScrubStat{Unchecked: 1, Damaged: 2}, stat)
+	_, err = checksum.Read(path(ef))
+	require.ErrorIs(t, err, checksum.ErrNoSums)
+
+	// download is in progress
+	client.EXPECT().Completed(gomock.Any(), gomock.Any()).Return(&proto_downloader.CompletedReply{}, nil)
+	stat, err = s.Scrub(ctx)
+	require.NoError(t, err)
+	require.Equal(t, ScrubStat{Unchecked: 1, Damaged: 1, Repairing: 1}, stat)
+
+	// downloaded: file is checked again
+	damage(ef)
+	client.EXPECT().Completed(gomock.Any(), gomock.Any()).Return(&proto_downloader.CompletedReply{Completed: true}, nil)
+	stat, err = s.Scrub(ctx)
+	require.NoError(t, err)
+	require.Equal(t, ScrubStat{Checked: 1, Unchecked: 1, Damaged: 1}, stat)
+}
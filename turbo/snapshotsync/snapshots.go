@@ -37,6 +37,7 @@ import (
 	"github.com/erigontech/erigon-lib/chain/snapcfg"
 	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/background"
+	"github.com/erigontech/erigon-lib/common/checksum"
 	"github.com/erigontech/erigon-lib/common/datadir"
 	"github.com/erigontech/erigon-lib/common/dbg"
 	"github.com/erigontech/erigon-lib/diagnostics"
@@ -499,6 +500,7 @@ type BlockSnapshots interface {
 	OpenSegments(types []snaptype.Type, allowGaps, allignMin bool) error
 	SegmentsMax() uint64
 	SegmentsMin() uint64
+	Ranges() []Range
 	Delete(fileName string) error
 	Types() []snaptype.Type
 	Close()
@@ -1592,14 +1594,17 @@ func removeOldFiles(toDel []string, snapDir string) {
 	for _, f := range toDel {
 		_ = os.Remove(f)
 		_ = os.Remove(f + ".torrent")
+		checksum.Remove(f)
 		ext := filepath.Ext(f)
 		withoutExt := f[:len(f)-len(ext)]
 		_ = os.Remove(withoutExt + ".idx")
 		_ = os.Remove(withoutExt + ".idx.torrent")
+		checksum.Remove(withoutExt + ".idx")
 		isTxnType := strings.HasSuffix(withoutExt, coresnaptype.Transactions.Name())
 		if isTxnType {
 			_ = os.Remove(withoutExt + "-to-block.idx")
 			_ = os.Remove(withoutExt + "-to-block.idx.torrent")
+			checksum.Remove(withoutExt + "-to-block.idx")
 		}
 	}
 	tmpFiles, err := snaptype.TmpFiles(snapDir)
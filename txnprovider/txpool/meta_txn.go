@@ -16,10 +16,14 @@
 
 package txpool
 
-import "github.com/holiman/uint256"
+import (
+	"time"
+
+	"github.com/holiman/uint256"
+)
 
 func newMetaTxn(slot *TxnSlot, isLocal bool, timestamp uint64) *metaTxn {
-	mt := &metaTxn{TxnSlot: slot, worstIndex: -1, bestIndex: -1, timestamp: timestamp}
+	mt := &metaTxn{TxnSlot: slot, worstIndex: -1, bestIndex: -1, timestamp: timestamp, arrival: time.Now().UnixMilli()}
 	if isLocal {
 		mt.subPool = IsLocal
 	}
@@ -36,9 +40,11 @@ type metaTxn struct {
 	bestIndex                 int
 	worstIndex                int
 	timestamp                 uint64 // when it was added to pool
+	arrival                   int64  // unix milliseconds of when it was added to pool, reset on restart
 	subPool                   SubPoolMarker
 	currentSubPool            SubPoolType
 	minedBlockNum             uint64
+	conditions                *TxnConditions // preconditions of eth_sendRawTransactionConditional, nil for unconditional txns
 }
 
 // Returns true if the txn "mt" is better than the parameter txn "than"
@@ -119,6 +119,8 @@ type TxPool struct {
 	minedBlobTxnsByBlock    map[uint64][]*metaTxn            // (blockNum => slice): cache of recently mined blobs
 	minedBlobTxnsByHash     map[string]*metaTxn              // (hash => mt): map of recently mined blobs
 	isLocalLRU              *simplelru.LRU[string, struct{}] // txn_hash => is_local : to restore isLocal flag of unwinded transactions
+	locals                  *localsJournal                   // local txns which survive restarts until mined, replaced or expired
+	private                 map[string]uint64                // txn_hash => last block of private txn : never leaves the node, not persisted
 	newPendingTxns          chan Announcements               // notifications about new txns in Pending sub-pool
 	all                     *BySenderAndNonce                // senderID => (sorted map of txn nonce => *metaTxn)
 	deletedTxns             []*metaTxn                       // list of discarded txns since last db commit
@@ -127,6 +129,7 @@ type TxPool struct {
 	chainID                 uint256.Int
 	chainConfig             *chain.Config
 	lastSeenBlock           atomic.Uint64
+	lastSeenBlockTime       atomic.Uint64
 	lastSeenCond            *sync.Cond
 	lastFinalizedBlock      atomic.Uint64
 	started                 atomic.Bool
@@ -217,6 +220,8 @@ func New(
 		lastSeenCond:            sync.NewCond(lock),
 		byHash:                  map[string]*metaTxn{},
 		isLocalLRU:              localsHistory,
+		locals:                  newLocalsJournal(cfg.LocalSlots),
+		private:                 map[string]uint64{},
 		discardReasonsLRU:       discardHistory,
 		all:                     byNonce,
 		recentlyConnectedPeers:  &recentlyConnectedPeers{},
@@ -341,6 +346,7 @@ func (p *TxPool) OnNewBlock(ctx context.Context, stateChanges *remote.StateChang
 	defer coreTx.Rollback()
 
 	block := stateChanges.ChangeBatch[len(stateChanges.ChangeBatch)-1].BlockHeight
+	blockTime := stateChanges.ChangeBatch[len(stateChanges.ChangeBatch)-1].BlockTime
 	baseFee := stateChanges.PendingBlockBaseFee
 
 	if err = minedTxns.Valid(); err != nil {
@@ -356,6 +362,7 @@ func (p *TxPool) OnNewBlock(ctx context.Context, stateChanges *remote.StateChang
 	defer func() {
 		if err == nil {
 			p.lastSeenBlock.Store(block)
+			p.lastSeenBlockTime.Store(blockTime)
 			p.lastSeenCond.Broadcast()
 		}
 
@@ -463,6 +470,11 @@ func (p *TxPool) OnNewBlock(ctx context.Context, stateChanges *remote.StateChang
 	if err = p.removeMined(p.all, minedTxns.Txns); err != nil {
 		return err
 	}
+	p.expireLocalsLocked(time.Now())
+	p.expirePrivateLocked(block)
+	if err = p.evictUnmetConditionsLocked(block, blockTime, stateChanges, cacheView); err != nil {
+		return err
+	}
 
 	var announcements Announcements
 	announcements, err = p.addTxnsOnNewBlock(block, cacheView, stateChanges, p.senders, unwindTxns, /* newTxns */
@@ -525,6 +537,13 @@ func (p *TxPool) processRemoteTxns(ctx context.Context) (err error) {
 		return err
 	}
 
+	// txns of local senders are treated as local, even if they came from the network
+	for i := range p.unprocessedRemoteTxns.Txns {
+		if p.locals.isLocalSender(p.unprocessedRemoteTxns.Senders.AddressAt(i)) {
+			p.unprocessedRemoteTxns.IsLocal[i] = true
+		}
+	}
+
 	_, newTxns, err := p.validateTxns(p.unprocessedRemoteTxns, cacheView)
 	if err != nil {
 		return err
@@ -642,9 +661,13 @@ func (p *TxPool) getRlpLocked(tx kv.Tx, hash []byte) (rlpTxn []byte, sender comm
 	return v[20:], *(*[20]byte)(v[:20]), txn != nil && txn.subPool&IsLocal > 0, nil
 }
 
+// GetRlp returns the rlp of a pooled transaction, except for private and conditional ones - they must never leave the node
 func (p *TxPool) GetRlp(tx kv.Tx, hash []byte) ([]byte, error) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
+	if p.keptLocallyLocked(string(hash)) {
+		return nil, nil
+	}
 	rlpTx, _, _, err := p.getRlpLocked(tx, hash)
 	return common.Copy(rlpTx), err
 }
@@ -653,7 +676,7 @@ func (p *TxPool) AppendLocalAnnouncements(types []byte, sizes []uint32, hashes [
 	p.lock.Lock()
 	defer p.lock.Unlock()
 	for hash, txn := range p.byHash {
-		if txn.subPool&IsLocal == 0 {
+		if p.keptLocallyLocked(hash) || txn.subPool&IsLocal == 0 {
 			continue
 		}
 		types = append(types, txn.TxnSlot.Type)
@@ -668,7 +691,7 @@ func (p *TxPool) AppendRemoteAnnouncements(types []byte, sizes []uint32, hashes
 	defer p.lock.Unlock()
 
 	for hash, txn := range p.byHash {
-		if txn.subPool&IsLocal != 0 {
+		if p.keptLocallyLocked(hash) || txn.subPool&IsLocal != 0 {
 			continue
 		}
 		types = append(types, txn.TxnSlot.Type)
@@ -777,7 +800,9 @@ func (p *TxPool) Started() bool {
 	return p.started.Load()
 }
 
-func (p *TxPool) best(ctx context.Context, n int, txns *TxnsRlp, onTopOf, availableGas, availableBlobGas uint64, yielded mapset.Set[[32]byte], availableRlpSpace int) (bool, int, error) {
+// best - yields the best pending txns for the block built on top of block onTopOf. blockTime is the
+// timestamp of that block, 0 if it's not known yet: then conditional txns with timestamp bounds are skipped
+func (p *TxPool) best(ctx context.Context, n int, txns *TxnsRlp, onTopOf, blockTime, availableGas, availableBlobGas uint64, yielded mapset.Set[[32]byte], availableRlpSpace int) (bool, int, error) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
@@ -787,6 +812,7 @@ func (p *TxPool) best(ctx context.Context, n int, txns *TxnsRlp, onTopOf, availa
 	}
 
 	best := p.pending.best
+	blockNum := onTopOf + 1
 
 	isEIP3860 := p.isShanghai() || p.isAgra()
 	isEIP7623 := p.isPrague() || p.isBhilai()
@@ -821,6 +847,11 @@ func (p *TxPool) best(ctx context.Context, n int, txns *TxnsRlp, onTopOf, availa
 			continue
 		}
 
+		// state conditions are checked against the last seen block, block bounds - against the built block
+		if mt.conditions != nil && !mt.conditions.blockMet(blockNum, blockTime) {
+			continue
+		}
+
 		if mt.TxnSlot.Gas >= p.blockGasLimit.Load() {
 			// Skip transactions with very large gas limit
 			continue
@@ -897,11 +928,12 @@ func (p *TxPool) best(ctx context.Context, n int, txns *TxnsRlp, onTopOf, availa
 func (p *TxPool) ProvideTxns(ctx context.Context, opts ...txnprovider.ProvideOption) ([]types.Transaction, error) {
 	provideOptions := txnprovider.ApplyProvideOptions(opts...)
 	var txnsRlp TxnsRlp
-	_, _, err := p.YieldBest(
+	_, _, err := p.best(
 		ctx,
 		provideOptions.Amount,
 		&txnsRlp,
 		provideOptions.ParentBlockNum,
+		provideOptions.BlockTime,
 		provideOptions.GasTarget,
 		provideOptions.BlobGasTarget,
 		provideOptions.TxnIdsFilter,
@@ -928,7 +960,7 @@ func (p *TxPool) ProvideTxns(ctx context.Context, opts ...txnprovider.ProvideOpt
 }
 
 func (p *TxPool) YieldBest(ctx context.Context, n int, txns *TxnsRlp, onTopOf, availableGas, availableBlobGas uint64, toSkip mapset.Set[[32]byte], availableRlpSpace int) (bool, int, error) {
-	return p.best(ctx, n, txns, onTopOf, availableGas, availableBlobGas, toSkip, availableRlpSpace)
+	return p.best(ctx, n, txns, onTopOf, 0 /* blockTime */, availableGas, availableBlobGas, toSkip, availableRlpSpace)
 }
 
 func (p *TxPool) PeekBest(ctx context.Context, n int, txns *TxnsRlp, onTopOf, availableGas, availableBlobGas uint64, availableRlpSpace int) (bool, error) {
@@ -1408,6 +1440,12 @@ func fillDiscardReasons(reasons []txpoolcfg.DiscardReason, newTxns TxnSlots, dis
 }
 
 func (p *TxPool) AddLocalTxns(ctx context.Context, newTxns TxnSlots) ([]txpoolcfg.DiscardReason, error) {
+	return p.addLocalTxns(ctx, newTxns, false, nil)
+}
+
+// addLocalTxns - adds local txns, either private or public ones, and for conditional txns - the
+// conditions they are subject to, which must hold on top of the last seen block
+func (p *TxPool) addLocalTxns(ctx context.Context, newTxns TxnSlots, private bool, conditions *TxnConditions) ([]txpoolcfg.DiscardReason, error) {
 	coreDb, cache := p.chainDB()
 	coreTx, err := coreDb.BeginTemporalRo(ctx)
 	if err != nil {
@@ -1427,11 +1465,34 @@ func (p *TxPool) AddLocalTxns(ctx context.Context, newTxns TxnSlots) ([]txpoolcf
 		return nil, err
 	}
 
+	if conditions != nil {
+		met, err := p.conditionsMetLocked(conditions, cacheView)
+		if err != nil {
+			return nil, err
+		}
+		if !met {
+			reasons := make([]txpoolcfg.DiscardReason, len(newTxns.Txns))
+			for i := range reasons {
+				reasons[i] = txpoolcfg.ConditionsNotMet
+			}
+			return reasons, nil
+		}
+	}
+
 	reasons, newTxns, err := p.validateTxns(&newTxns, cacheView)
 	if err != nil {
 		return nil, err
 	}
 
+	var unknown []string
+	if private {
+		for _, txn := range newTxns.Txns {
+			if _, ok := p.byHash[string(txn.IDHash[:])]; !ok {
+				unknown = append(unknown, string(txn.IDHash[:]))
+			}
+		}
+	}
+
 	announcements, addReasons, err := p.addTxns(p.lastSeenBlock.Load(), cacheView, p.senders, newTxns,
 		p.pendingBaseFee.Load(), p.pendingBlobFee.Load(), p.blockGasLimit.Load(), true, p.logger)
 	if err == nil {
@@ -1443,6 +1504,14 @@ func (p *TxPool) AddLocalTxns(ctx context.Context, newTxns TxnSlots) ([]txpoolcf
 	} else {
 		return nil, err
 	}
+	if conditions != nil {
+		p.setConditionsLocked(newTxns, conditions)
+	}
+	if private {
+		p.markPrivateLocked(unknown)
+	} else if conditions == nil {
+		p.journalLocalsLocked(newTxns)
+	}
 	p.promoted.Reset()
 	p.promoted.AppendOther(announcements)
 
@@ -1612,35 +1681,12 @@ func (p *TxPool) addLocked(mt *metaTxn, announcements *Announcements) txpoolcfg.
 		if found.TxnSlot.Type == BlobTxnType && mt.TxnSlot.Type != BlobTxnType {
 			return txpoolcfg.BlobTxReplace
 		}
-		priceBump := p.cfg.PriceBump
-
-		if mt.TxnSlot.Type == BlobTxnType {
-			//Blob txn threshold checks for replace txn
-			priceBump = p.cfg.BlobPriceBump
-			blobFeeThreshold, overflow := (&uint256.Int{}).MulDivOverflow(
-				&found.TxnSlot.BlobFeeCap,
-				uint256.NewInt(100+priceBump),
-				uint256.NewInt(100),
-			)
-			if mt.TxnSlot.BlobFeeCap.Lt(blobFeeThreshold) && !overflow {
-				if bytes.Equal(found.TxnSlot.IDHash[:], mt.TxnSlot.IDHash[:]) {
-					return txpoolcfg.NotSet
-				}
-				return txpoolcfg.ReplaceUnderpriced // TODO: This is the same as NotReplaced
+		tipThreshold, feecapThreshold, blobFeeThreshold := p.replacementThresholds(found, mt.TxnSlot)
+		if blobFeeThreshold != nil && mt.TxnSlot.BlobFeeCap.Lt(blobFeeThreshold) {
+			if bytes.Equal(found.TxnSlot.IDHash[:], mt.TxnSlot.IDHash[:]) {
+				return txpoolcfg.NotSet
 			}
-		}
-
-		//Regular txn threshold checks
-		tipThreshold := uint256.NewInt(0)
-		tipThreshold = tipThreshold.Mul(&found.TxnSlot.Tip, uint256.NewInt(100+priceBump))
-		tipThreshold.Div(tipThreshold, u256.N100)
-		feecapThreshold := uint256.NewInt(0)
-		feecapThreshold.Mul(&found.TxnSlot.FeeCap, uint256.NewInt(100+priceBump))
-		feecapThreshold.Div(feecapThreshold, u256.N100)
-
-		if mt.TxnSlot.Value.Cmp(&found.TxnSlot.Value) > 0 {
-			//Potential latent overdraft attack
-			tipThreshold.Mul(tipThreshold, uint256.NewInt(uint64(p.all.count(mt.TxnSlot.SenderID))))
+			return txpoolcfg.ReplaceUnderpriced // TODO: This is the same as NotReplaced
 		}
 		if mt.TxnSlot.Tip.Cmp(tipThreshold) < 0 || mt.TxnSlot.FeeCap.Cmp(feecapThreshold) < 0 {
 			// Both tip and feecap need to be larger than previously to replace the transaction
@@ -1755,6 +1801,39 @@ func (p *TxPool) addLocked(mt *metaTxn, announcements *Announcements) txpoolcfg.
 	return txpoolcfg.NotSet
 }
 
+// replacementThresholds returns the tip and fee cap txn needs to replace found,
+// the pooled transaction with the same sender and nonce, and for blob
+// transactions the blob fee cap, nil otherwise.
+func (p *TxPool) replacementThresholds(found *metaTxn, txn *TxnSlot) (tip, feeCap, blobFeeCap *uint256.Int) {
+	priceBump := p.cfg.PriceBump
+	if txn.Type == BlobTxnType {
+		//Blob txn threshold checks for replace txn
+		priceBump = p.cfg.BlobPriceBump
+		threshold, overflow := (&uint256.Int{}).MulDivOverflow(
+			&found.TxnSlot.BlobFeeCap,
+			uint256.NewInt(100+priceBump),
+			uint256.NewInt(100),
+		)
+		if !overflow {
+			blobFeeCap = threshold
+		}
+	}
+
+	//Regular txn threshold checks
+	tip = uint256.NewInt(0)
+	tip.Mul(&found.TxnSlot.Tip, uint256.NewInt(100+priceBump))
+	tip.Div(tip, u256.N100)
+	feeCap = uint256.NewInt(0)
+	feeCap.Mul(&found.TxnSlot.FeeCap, uint256.NewInt(100+priceBump))
+	feeCap.Div(feeCap, u256.N100)
+
+	if txn.Value.Cmp(&found.TxnSlot.Value) > 0 {
+		//Potential latent overdraft attack
+		tip.Mul(tip, uint256.NewInt(uint64(p.all.count(txn.SenderID))))
+	}
+	return tip, feeCap, blobFeeCap
+}
+
 // dropping transaction from all sub-structures and from db
 // Important: don't call it while iterating by all
 func (p *TxPool) discardLocked(mt *metaTxn, reason txpoolcfg.DiscardReason) {
@@ -1763,6 +1842,11 @@ func (p *TxPool) discardLocked(mt *metaTxn, reason txpoolcfg.DiscardReason) {
 	p.deletedTxns = append(p.deletedTxns, mt)
 	p.all.delete(mt, reason, p.logger)
 	p.discardReasonsLRU.Add(hashStr, reason)
+	switch reason {
+	case txpoolcfg.Mined, txpoolcfg.ReplacedByHigherTip, txpoolcfg.NonceTooLow:
+		p.locals.remove(hashStr)
+	}
+	delete(p.private, hashStr)
 	if mt.TxnSlot.Type == BlobTxnType {
 		t := p.totalBlobsInPool.Load()
 		p.totalBlobsInPool.Store(t - uint64(len(mt.TxnSlot.BlobHashes)))
@@ -1774,6 +1858,19 @@ func (p *TxPool) discardLocked(mt *metaTxn, reason txpoolcfg.DiscardReason) {
 	}
 }
 
+// dropLocked - removes txn from its sub-pool and discards it
+func (p *TxPool) dropLocked(mt *metaTxn, reason txpoolcfg.DiscardReason) {
+	switch mt.currentSubPool {
+	case PendingSubPool:
+		p.pending.Remove(mt, reason.String(), p.logger)
+	case BaseFeeSubPool:
+		p.baseFee.Remove(mt, reason.String(), p.logger)
+	case QueuedSubPool:
+		p.queued.Remove(mt, reason.String(), p.logger)
+	}
+	p.discardLocked(mt, reason)
+}
+
 func (p *TxPool) getBlobsAndProofByBlobHashLocked(blobHashes []common.Hash) []PoolBlobBundle {
 	p.lock.Lock()
 	defer p.lock.Unlock()
@@ -2133,10 +2230,18 @@ func (p *TxPool) promote(pendingBaseFee uint64, pendingBlobFee uint64, announcem
 	// Discard worst transactions from the queued sub pool if they do not qualify
 	// <FUNCTIONALITY REMOVED>
 
-	// Discard worst transactions from pending pool until it is within capacity limit
-	for p.pending.Len() > p.pending.limit {
+	// Discard worst transactions from pending pool until it is within capacity limit.
+	// Local transactions (up to LocalSlots of them) are exempt from the limits, they are set aside and put back afterwards
+	var locals []*metaTxn
+	exempt := p.cfg.LocalSlots
+	for p.pending.Len() > 0 && p.pending.Len()+len(locals) > p.pending.limit {
 		tx := p.pending.PopWorst()
-		p.discardLocked(p.pending.PopWorst(), txpoolcfg.PendingPoolOverflow)
+		if tx.subPool&IsLocal != 0 && exempt > 0 {
+			exempt--
+			locals = append(locals, tx)
+			continue
+		}
+		p.discardLocked(tx, txpoolcfg.PendingPoolOverflow)
 		sendChangeBatchEventToDiagnostics("Pending", "remove", []diagnostics.TxnHashOrder{
 			{
 				OrderMarker: uint8(tx.subPool),
@@ -2145,9 +2250,19 @@ func (p *TxPool) promote(pendingBaseFee uint64, pendingBlobFee uint64, announcem
 		})
 	}
 
+	for _, tx := range locals {
+		p.pending.Add(tx, logger)
+	}
+
 	// Discard worst transactions from pending sub pool until it is within capacity limits
-	for p.baseFee.Len() > p.baseFee.limit {
+	locals = locals[:0]
+	for p.baseFee.Len() > 0 && p.baseFee.Len()+len(locals) > p.baseFee.limit {
 		tx := p.baseFee.PopWorst()
+		if tx.subPool&IsLocal != 0 && exempt > 0 {
+			exempt--
+			locals = append(locals, tx)
+			continue
+		}
 		p.discardLocked(tx, txpoolcfg.BaseFeePoolOverflow)
 		sendChangeBatchEventToDiagnostics("BaseFee", "remove", []diagnostics.TxnHashOrder{
 			{
@@ -2156,10 +2271,19 @@ func (p *TxPool) promote(pendingBaseFee uint64, pendingBlobFee uint64, announcem
 			},
 		})
 	}
+	for _, tx := range locals {
+		p.baseFee.Add(tx, "locals", logger)
+	}
 
 	// Discard worst transactions from the queued sub pool until it is within its capacity limits
-	for _ = p.queued.Worst(); p.queued.Len() > p.queued.limit; _ = p.queued.Worst() {
+	locals = locals[:0]
+	for p.queued.Len() > 0 && p.queued.Len()+len(locals) > p.queued.limit {
 		tx := p.queued.PopWorst()
+		if tx.subPool&IsLocal != 0 && exempt > 0 {
+			exempt--
+			locals = append(locals, tx)
+			continue
+		}
 		p.discardLocked(tx, txpoolcfg.QueuedPoolOverflow)
 		sendChangeBatchEventToDiagnostics("Queued", "remove", []diagnostics.TxnHashOrder{
 			{
@@ -2168,13 +2292,16 @@ func (p *TxPool) promote(pendingBaseFee uint64, pendingBlobFee uint64, announcem
 			},
 		})
 	}
+	for _, tx := range locals {
+		p.queued.Add(tx, "locals", logger)
+	}
 }
 
 // Run - does:
 // send pending byHash to p2p:
 //   - new byHash
 //   - all pooled byHash to recently connected peers
-//   - all local pooled byHash to random peers periodically
+//   - all journaled local byHash to random peers periodically
 //
 // promote/demote transactions
 // reorgs
@@ -2198,6 +2325,10 @@ func (p *TxPool) Run(ctx context.Context) error {
 		return err
 	}
 
+	if !p.cfg.NoGossip && p.cfg.RebroadcastEvery > 0 {
+		go p.p2pSender.RebroadcastLocalTxns(ctx, p.cfg.RebroadcastEvery, p.localTxnsToRebroadcast)
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -2321,7 +2452,6 @@ func (p *TxPool) Run(ctx context.Context) error {
 				}
 
 				// broadcast local transactions
-				const localTxnsBroadcastMaxPeers uint64 = 10
 				txnSentTo := p.p2pSender.BroadcastPooledTxns(localTxnRlps, localTxnsBroadcastMaxPeers)
 				for i, peer := range txnSentTo {
 					p.logger.Trace("Local txn broadcast", "txHash", hex.EncodeToString(broadcastHashes.At(i)), "to peer", peer)
@@ -2449,6 +2579,9 @@ func (p *TxPool) flushLocked(tx kv.RwTx) (err error) {
 		if metaTx.TxnSlot.Rlp == nil {
 			continue
 		}
+		if p.keptLocallyLocked(txHash) {
+			continue // private and conditional txns stay in memory only
+		}
 		v = common.EnsureEnoughSize(v, 20+len(metaTx.TxnSlot.Rlp))
 
 		addr, ok := p.senders.senderID2Addr[metaTx.TxnSlot.SenderID]
@@ -2472,6 +2605,10 @@ func (p *TxPool) flushLocked(tx kv.RwTx) (err error) {
 		metaTx.TxnSlot.Rlp = nil
 	}
 
+	if err := p.locals.flush(tx); err != nil {
+		return err
+	}
+
 	binary.BigEndian.PutUint64(encID, p.pendingBaseFee.Load())
 	if err := tx.Put(kv.PoolInfo, PoolPendingBaseFeeKey, encID); err != nil {
 		return err
@@ -2488,6 +2625,7 @@ func (p *TxPool) flushLocked(tx kv.RwTx) (err error) {
 	// DB will stay consistent but some in-memory structures may be already cleaned, and retry will not work
 	// failed write transaction must not create side-effects
 	p.deletedTxns = p.deletedTxns[:0]
+	clear(p.locals.dirty)
 	return nil
 }
 
@@ -2532,6 +2670,9 @@ func (p *TxPool) fromDB(ctx context.Context, tx kv.Tx, coreTx kv.TemporalTx) err
 		}
 		p.isLocalLRU.Add(string(v), struct{}{})
 	}
+	if err := p.loadLocals(tx); err != nil {
+		return err
+	}
 
 	txns := TxnSlots{}
 	parseCtx := NewTxnParseContext(p.chainID)
@@ -2572,6 +2713,14 @@ func (p *TxPool) fromDB(ctx context.Context, tx kv.Tx, coreTx kv.TemporalTx) err
 		i++
 	}
 
+	restored, err := p.restoreLocals(tx, parseCtx, cacheView)
+	if err != nil {
+		return err
+	}
+	for j, txn := range restored.Txns {
+		txns.Append(txn, restored.Senders.At(j), true)
+	}
+
 	var pendingBaseFee, pendingBlobFee, minBlobGasPrice, blockGasLimit uint64
 
 	if p.feeCalculator != nil {
@@ -2676,6 +2825,9 @@ func (p *TxPool) deprecatedForEach(_ context.Context, f func(rlp []byte, sender
 	p.lock.Lock()
 
 	p.all.ascendAll(func(mt *metaTxn) bool {
+		if _, ok := p.private[string(mt.TxnSlot.IDHash[:])]; ok {
+			return true
+		}
 		if sender, found := p.senders.senderID2Addr[mt.TxnSlot.SenderID]; found {
 			txns = append(txns, mt)
 			senders = append(senders, sender)
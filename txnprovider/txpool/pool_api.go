@@ -0,0 +1,127 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/common/length"
+	"github.com/erigontech/erigon/txnprovider/txpool/txpoolcfg"
+)
+
+// API serves the txpool_ methods which need the pool itself, on nodes running
+// the pool in-process.
+type API struct {
+	pool *TxPool
+}
+
+func NewAPI(pool *TxPool) *API {
+	return &API{pool: pool}
+}
+
+// Dump implements txpool_dump. It returns all transactions of the pool, see
+// TxPool.Dump.
+func (api *API) Dump(ctx context.Context) ([]DumpedTxn, error) {
+	return api.pool.Dump(ctx)
+}
+
+// Explain implements txpool_explain. It takes either the hash of a
+// transaction, see TxPool.Explain, or a signed transaction, see
+// TxPool.ExplainRaw. A signed transaction is always longer than a hash.
+func (api *API) Explain(ctx context.Context, hashOrTxn hexutil.Bytes) (*Explanation, error) {
+	if len(hashOrTxn) == length.Hash {
+		return api.pool.Explain(ctx, common.BytesToHash(hashOrTxn))
+	}
+	return api.pool.ExplainRaw(ctx, hashOrTxn)
+}
+
+// EthAPI serves the eth_ methods which need the pool itself, on nodes running
+// the pool in-process.
+type EthAPI struct {
+	pool        *TxPool
+	storageRoot StorageRootReader
+}
+
+// NewEthAPI - storageRoot is used to check the knownAccounts storage roots of
+// eth_sendRawTransactionConditional, it may be nil if those aren't supported.
+func NewEthAPI(pool *TxPool, storageRoot StorageRootReader) *EthAPI {
+	return &EthAPI{pool: pool, storageRoot: storageRoot}
+}
+
+// SendPrivateRawTransaction implements eth_sendPrivateRawTransaction. The
+// transaction is only included into the blocks built by this node, see
+// TxPool.AddPrivateTxns.
+func (api *EthAPI) SendPrivateRawTransaction(ctx context.Context, encodedTx hexutil.Bytes) (common.Hash, error) {
+	txns, err := api.parseTxn(encodedTx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	reasons, err := api.pool.AddPrivateTxns(ctx, txns)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.Hash(txns.Txns[0].IDHash), reasonErr(reasons[0])
+}
+
+// SendRawTransactionConditional implements eth_sendRawTransactionConditional.
+// The transaction is only included into a block meeting the conditions, see
+// TxPool.AddConditionalTxns.
+func (api *EthAPI) SendRawTransactionConditional(ctx context.Context, encodedTx hexutil.Bytes, conditions TxnConditions) (common.Hash, error) {
+	if cost := conditions.cost(); cost > maxConditionsCost {
+		return common.Hash{}, fmt.Errorf("too many known accounts storage roots and slots: %d, max %d", cost, maxConditionsCost)
+	}
+	txns, err := api.parseTxn(encodedTx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	for _, account := range conditions.KnownAccounts {
+		if account.StorageRoot != nil && api.storageRoot == nil {
+			return common.Hash{}, errors.New("known accounts storage roots are not supported")
+		}
+	}
+	if err := conditions.storageRootsMet(ctx, api.storageRoot); err != nil {
+		return common.Hash{}, fmt.Errorf("%s: %w", txpoolcfg.ConditionsNotMet, err)
+	}
+	reasons, err := api.pool.AddConditionalTxns(ctx, txns, &conditions)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.Hash(txns.Txns[0].IDHash), reasonErr(reasons[0])
+}
+
+func (api *EthAPI) parseTxn(encodedTx hexutil.Bytes) (TxnSlots, error) {
+	parseCtx := NewTxnParseContext(api.pool.chainID).ChainIDRequired()
+	parseCtx.ValidateRLP(api.pool.ValidateSerializedTxn)
+	txn, sender := &TxnSlot{}, make([]byte, length.Addr)
+	var txns TxnSlots
+	if _, err := parseCtx.ParseTransaction(encodedTx, 0, txn, sender, false /* hasEnvelope */, true /* wrappedWithBlobs */, nil); err != nil {
+		return txns, err
+	}
+	txns.Append(txn, sender, true)
+	return txns, nil
+}
+
+func reasonErr(reason txpoolcfg.DiscardReason) error {
+	if reason != txpoolcfg.Success {
+		return errors.New(reason.String())
+	}
+	return nil
+}
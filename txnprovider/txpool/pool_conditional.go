@@ -0,0 +1,218 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/gointerfaces"
+	remote "github.com/erigontech/erigon-lib/gointerfaces/remoteproto"
+	"github.com/erigontech/erigon-lib/kv/kvcache"
+	"github.com/erigontech/erigon/txnprovider/txpool/txpoolcfg"
+)
+
+// maxConditionsCost - max number of storage roots and slots a conditional txn may depend on
+const maxConditionsCost = 1000
+
+// TxnConditions - preconditions of a transaction submitted with eth_sendRawTransactionConditional.
+// The transaction is only included into a block which satisfies all of them.
+type TxnConditions struct {
+	KnownAccounts  map[common.Address]KnownAccount `json:"knownAccounts,omitempty"`
+	BlockNumberMin *hexutil.Uint64                 `json:"blockNumberMin,omitempty"`
+	BlockNumberMax *hexutil.Uint64                 `json:"blockNumberMax,omitempty"`
+	TimestampMin   *hexutil.Uint64                 `json:"timestampMin,omitempty"`
+	TimestampMax   *hexutil.Uint64                 `json:"timestampMax,omitempty"`
+}
+
+// KnownAccount - expected storage of an account: either its storage root, or values of
+// some of its storage slots
+type KnownAccount struct {
+	StorageRoot  *common.Hash
+	StorageSlots map[common.Hash]common.Hash
+}
+
+func (a KnownAccount) MarshalJSON() ([]byte, error) {
+	if a.StorageRoot != nil {
+		return json.Marshal(a.StorageRoot)
+	}
+	return json.Marshal(a.StorageSlots)
+}
+
+func (a *KnownAccount) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		a.StorageRoot = new(common.Hash)
+		return json.Unmarshal(data, a.StorageRoot)
+	}
+	return json.Unmarshal(data, &a.StorageSlots)
+}
+
+// cost - number of storage roots and slots to check
+func (c *TxnConditions) cost() int {
+	cost := 0
+	for _, account := range c.KnownAccounts {
+		if account.StorageRoot != nil {
+			cost++
+		}
+		cost += len(account.StorageSlots)
+	}
+	return cost
+}
+
+// blockMet - whether the block bounds hold for the given block. Timestamp bounds are not met
+// if blockTime isn't known (0).
+func (c *TxnConditions) blockMet(blockNum, blockTime uint64) bool {
+	if c.BlockNumberMin != nil && blockNum < uint64(*c.BlockNumberMin) {
+		return false
+	}
+	if c.BlockNumberMax != nil && blockNum > uint64(*c.BlockNumberMax) {
+		return false
+	}
+	if blockTime == 0 {
+		return c.TimestampMin == nil && c.TimestampMax == nil
+	}
+	if c.TimestampMin != nil && blockTime < uint64(*c.TimestampMin) {
+		return false
+	}
+	if c.TimestampMax != nil && blockTime > uint64(*c.TimestampMax) {
+		return false
+	}
+	return true
+}
+
+// blockExpired - whether the block bounds can't hold for any block after the given one
+func (c *TxnConditions) blockExpired(blockNum, blockTime uint64) bool {
+	if c.BlockNumberMax != nil && blockNum >= uint64(*c.BlockNumberMax) {
+		return true
+	}
+	return c.TimestampMax != nil && blockTime >= uint64(*c.TimestampMax)
+}
+
+// slotsMet - whether the known storage slots have the expected values in the given state
+func (c *TxnConditions) slotsMet(cacheView kvcache.CacheView) (bool, error) {
+	key := make([]byte, 20+32)
+	for addr, account := range c.KnownAccounts {
+		copy(key, addr[:])
+		for slot, expected := range account.StorageSlots {
+			copy(key[20:], slot[:])
+			v, err := cacheView.Get(key)
+			if err != nil {
+				return false, err
+			}
+			if common.BytesToHash(v) != expected {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// StorageRootReader - reads the storage root of an account as of the latest block
+type StorageRootReader func(ctx context.Context, addr common.Address) (common.Hash, error)
+
+// storageRootsMet - whether the known storage roots match the latest state
+func (c *TxnConditions) storageRootsMet(ctx context.Context, storageRoot StorageRootReader) error {
+	for addr, account := range c.KnownAccounts {
+		if account.StorageRoot == nil {
+			continue
+		}
+		root, err := storageRoot(ctx, addr)
+		if err != nil {
+			return err
+		}
+		if root != *account.StorageRoot {
+			return fmt.Errorf("storage root of %x mismatch: have %x, want %x", addr, root, *account.StorageRoot)
+		}
+	}
+	return nil
+}
+
+// AddConditionalTxns - adds local transactions which may only be included into a block meeting
+// the conditions. Storage roots aren't known to the pool: the caller is responsible to check them
+// against the last seen block, then the txns are evicted as soon as the storage of any of those
+// accounts is changed. Conditions are kept in memory only, so such txns are never sent to peers,
+// journaled or persisted - like private ones.
+func (p *TxPool) AddConditionalTxns(ctx context.Context, newTxns TxnSlots, conditions *TxnConditions) ([]txpoolcfg.DiscardReason, error) {
+	return p.addLocalTxns(ctx, newTxns, false, conditions)
+}
+
+func (p *TxPool) conditionsMetLocked(conditions *TxnConditions, cacheView kvcache.CacheView) (bool, error) {
+	if conditions.blockExpired(p.lastSeenBlock.Load(), p.lastSeenBlockTime.Load()) {
+		return false, nil
+	}
+	return conditions.slotsMet(cacheView)
+}
+
+func (p *TxPool) setConditionsLocked(txns TxnSlots, conditions *TxnConditions) {
+	for _, txn := range txns.Txns {
+		if mt, ok := p.byHash[string(txn.IDHash[:])]; ok && mt.TxnSlot == txn {
+			mt.conditions = conditions
+		}
+	}
+}
+
+// evictUnmetConditionsLocked - drops conditional txns whose conditions can no longer hold after the new block
+func (p *TxPool) evictUnmetConditionsLocked(blockNum, blockTime uint64, stateChanges *remote.StateChangeBatch, cacheView kvcache.CacheView) error {
+	var changedStorage map[common.Address]struct{}
+	var toDrop []*metaTxn
+	for _, mt := range p.byHash {
+		if mt.conditions == nil {
+			continue
+		}
+		met := !mt.conditions.blockExpired(blockNum, blockTime)
+		if met {
+			var err error
+			if met, err = mt.conditions.slotsMet(cacheView); err != nil {
+				return err
+			}
+		}
+		if met {
+			if changedStorage == nil {
+				changedStorage = storageChangedAccounts(stateChanges)
+			}
+			for addr, account := range mt.conditions.KnownAccounts {
+				if _, changed := changedStorage[addr]; changed && account.StorageRoot != nil {
+					met = false
+					break
+				}
+			}
+		}
+		if !met {
+			toDrop = append(toDrop, mt)
+		}
+	}
+	for _, mt := range toDrop {
+		p.dropLocked(mt, txpoolcfg.ConditionsNotMet)
+	}
+	return nil
+}
+
+func storageChangedAccounts(stateChanges *remote.StateChangeBatch) map[common.Address]struct{} {
+	changed := map[common.Address]struct{}{}
+	for _, change := range stateChanges.ChangeBatch {
+		for _, accountChange := range change.Changes {
+			if len(accountChange.StorageChanges) == 0 && accountChange.Action != remote.Action_REMOVE {
+				continue
+			}
+			changed[gointerfaces.ConvertH160toAddress(accountChange.Address)] = struct{}{}
+		}
+	}
+	return changed
+}
@@ -0,0 +1,263 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/chain"
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/datadir"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/crypto"
+	"github.com/erigontech/erigon-lib/gointerfaces"
+	remote "github.com/erigontech/erigon-lib/gointerfaces/remoteproto"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/kvcache"
+	"github.com/erigontech/erigon-lib/kv/memdb"
+	"github.com/erigontech/erigon-lib/kv/temporal/temporaltest"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/types"
+	accounts3 "github.com/erigontech/erigon-lib/types/accounts"
+	"github.com/erigontech/erigon/txnprovider"
+	"github.com/erigontech/erigon/txnprovider/txpool/txpoolcfg"
+)
+
+func TestConditionalTxns(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	coreDB := temporaltest.NewTestDB(t, datadir.New(t.TempDir()))
+	db := memdb.NewTestPoolDB(t)
+	pool, err := New(ctx, make(chan Announcements, 100), db, coreDB, txpoolcfg.DefaultConfig, kvcache.New(kvcache.DefaultCoherentConfig), chain.TestChainConfig, nil, nil, func() {}, nil, nil, log.New(), WithFeeCalculator(nil))
+	require.NoError(t, err)
+	pool.started.Store(true)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	acc := accounts3.Account{Nonce: 2, Balance: *uint256.NewInt(common.Ether)}
+	newBlock := func(blockNum uint64) {
+		change := &remote.StateChangeBatch{
+			StateVersionId:      blockNum,
+			PendingBlockBaseFee: 200000,
+			BlockGasLimit:       1000000,
+			ChangeBatch: []*remote.StateChange{{
+				BlockHeight: blockNum,
+				BlockTime:   1000 + blockNum,
+				BlockHash:   gointerfaces.ConvertHashToH256([32]byte{byte(blockNum)}),
+				Changes: []*remote.AccountChange{{
+					Action:  remote.Action_UPSERT,
+					Address: gointerfaces.ConvertAddressToH160(addr),
+					Data:    accounts3.SerialiseV3(&acc),
+				}},
+			}},
+		}
+		require.NoError(t, pool.OnNewBlock(ctx, change, TxnSlots{}, TxnSlots{}, TxnSlots{}))
+	}
+	newBlock(0)
+	newBlock(1)
+
+	signer := types.LatestSignerForChainID(chain.TestChainConfig.ChainID)
+	to := common.Address{1}
+	encode := func(nonce, feeCap uint64) []byte {
+		txn := types.MustSignNewTx(key, *signer, &types.DynamicFeeTransaction{
+			CommonTx: types.CommonTx{Nonce: nonce, GasLimit: 100000, To: &to, Value: uint256.NewInt(0)},
+			ChainID:  uint256.MustFromBig(chain.TestChainConfig.ChainID),
+			TipCap:   uint256.NewInt(feeCap),
+			FeeCap:   uint256.NewInt(feeCap),
+		})
+		var buf bytes.Buffer
+		require.NoError(t, txn.MarshalBinary(&buf))
+		return buf.Bytes()
+	}
+	peekBest := func(onTopOf uint64) int {
+		var best TxnsRlp
+		_, err := pool.PeekBest(ctx, 10, &best, onTopOf, math.MaxUint64, math.MaxUint64, math.MaxInt)
+		require.NoError(t, err)
+		return len(best.Txns)
+	}
+	api := NewEthAPI(pool, func(ctx context.Context, addr common.Address) (common.Hash, error) {
+		return common.Hash{0xaa}, nil
+	})
+
+	// expired bounds and mismatching slots are rejected at submission
+	_, err = api.SendRawTransactionConditional(ctx, encode(2, 300000), TxnConditions{BlockNumberMax: (*hexutil.Uint64)(new(uint64))})
+	require.ErrorContains(t, err, txpoolcfg.ConditionsNotMet.String())
+	slots := TxnConditions{KnownAccounts: map[common.Address]KnownAccount{to: {StorageSlots: map[common.Hash]common.Hash{{}: {1}}}}}
+	_, err = api.SendRawTransactionConditional(ctx, encode(2, 300000), slots)
+	require.ErrorContains(t, err, txpoolcfg.ConditionsNotMet.String())
+	roots := TxnConditions{KnownAccounts: map[common.Address]KnownAccount{to: {StorageRoot: &common.Hash{0xbb}}}}
+	_, err = api.SendRawTransactionConditional(ctx, encode(2, 300000), roots)
+	require.ErrorContains(t, err, txpoolcfg.ConditionsNotMet.String())
+	require.Empty(t, pool.byHash)
+
+	// not included before blockNumberMin
+	minBlock, maxBlock := hexutil.Uint64(3), hexutil.Uint64(3)
+	hash, err := api.SendRawTransactionConditional(ctx, encode(2, 300000), TxnConditions{BlockNumberMin: &minBlock, BlockNumberMax: &maxBlock})
+	require.NoError(t, err)
+	require.Zero(t, peekBest(1))
+	newBlock(2)
+	require.Equal(t, 1, peekBest(2))
+	// block bounds are checked against the built block, not the last seen one
+	require.Zero(t, peekBest(1))
+
+	// evicted once blockNumberMax is reached
+	newBlock(3)
+	require.NotContains(t, pool.byHash, string(hash[:]))
+	reason, ok := pool.discardReasonsLRU.Get(string(hash[:]))
+	require.True(t, ok)
+	require.Equal(t, txpoolcfg.ConditionsNotMet, reason)
+
+	// evicted once the storage of an account with known root changes, the txn is a new one
+	// as discarded txns are rejected with the same reason
+	roots.KnownAccounts[to] = KnownAccount{StorageRoot: &common.Hash{0xaa}}
+	hash, err = api.SendRawTransactionConditional(ctx, encode(2, 310000), roots)
+	require.NoError(t, err)
+	newBlock(4)
+	require.Contains(t, pool.byHash, string(hash[:]))
+	require.NoError(t, pool.OnNewBlock(ctx, &remote.StateChangeBatch{
+		StateVersionId:      5,
+		PendingBlockBaseFee: 200000,
+		BlockGasLimit:       1000000,
+		ChangeBatch: []*remote.StateChange{{
+			BlockHeight: 5,
+			BlockTime:   1005,
+			BlockHash:   gointerfaces.ConvertHashToH256([32]byte{5}),
+			Changes: []*remote.AccountChange{{
+				Action:         remote.Action_STORAGE,
+				Address:        gointerfaces.ConvertAddressToH160(to),
+				StorageChanges: []*remote.StorageChange{{Location: gointerfaces.ConvertHashToH256([32]byte{}), Data: []byte{1}}},
+			}},
+		}},
+	}, TxnSlots{}, TxnSlots{}, TxnSlots{}))
+	require.NotContains(t, pool.byHash, string(hash[:]))
+
+	// timestamp bounds are checked against the time of the built block, unknown time doesn't meet them
+	minTime := hexutil.Uint64(1010)
+	_, err = api.SendRawTransactionConditional(ctx, encode(2, 320000), TxnConditions{TimestampMin: &minTime})
+	require.NoError(t, err)
+	require.Zero(t, peekBest(5))
+	provide := func(blockTime uint64) int {
+		txns, err := pool.ProvideTxns(ctx, txnprovider.WithParentBlockNum(5), txnprovider.WithBlockTime(blockTime))
+		require.NoError(t, err)
+		return len(txns)
+	}
+	require.Zero(t, provide(1009))
+	require.Equal(t, 1, provide(1010))
+}
+
+// conditions are known to this node only: conditional txns must not reach peers, which would include them
+// unconditionally, and must not be restored after restart without their conditions
+func TestConditionalTxnsStayLocal(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	coreDB := temporaltest.NewTestDB(t, datadir.New(t.TempDir()))
+	db := memdb.NewTestPoolDB(t)
+	cache := kvcache.New(kvcache.DefaultCoherentConfig)
+	pool, err := New(ctx, make(chan Announcements, 100), db, coreDB, txpoolcfg.DefaultConfig, cache, chain.TestChainConfig, nil, nil, func() {}, nil, nil, log.New(), WithFeeCalculator(nil))
+	require.NoError(t, err)
+	pool.started.Store(true)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	acc := accounts3.Account{Nonce: 2, Balance: *uint256.NewInt(common.Ether)}
+	require.NoError(t, pool.OnNewBlock(ctx, &remote.StateChangeBatch{
+		PendingBlockBaseFee: 200000,
+		BlockGasLimit:       1000000,
+		ChangeBatch: []*remote.StateChange{{
+			BlockHeight: 0,
+			BlockTime:   1000,
+			BlockHash:   gointerfaces.ConvertHashToH256([32]byte{}),
+			Changes: []*remote.AccountChange{{
+				Action:  remote.Action_UPSERT,
+				Address: gointerfaces.ConvertAddressToH160(addr),
+				Data:    accounts3.SerialiseV3(&acc),
+			}},
+		}},
+	}, TxnSlots{}, TxnSlots{}, TxnSlots{}))
+
+	signer := types.LatestSignerForChainID(chain.TestChainConfig.ChainID)
+	to := common.Address{1}
+	txn := types.MustSignNewTx(key, *signer, &types.DynamicFeeTransaction{
+		CommonTx: types.CommonTx{Nonce: 2, GasLimit: 100000, To: &to, Value: uint256.NewInt(0)},
+		ChainID:  uint256.MustFromBig(chain.TestChainConfig.ChainID),
+		TipCap:   uint256.NewInt(300000),
+		FeeCap:   uint256.NewInt(300000),
+	})
+	var buf bytes.Buffer
+	require.NoError(t, txn.MarshalBinary(&buf))
+	maxBlock := hexutil.Uint64(100)
+	hash, err := NewEthAPI(pool, nil).SendRawTransactionConditional(ctx, buf.Bytes(), TxnConditions{BlockNumberMax: &maxBlock})
+	require.NoError(t, err)
+	require.Equal(t, txn.Hash(), hash)
+
+	// available for our own blocks
+	var best TxnsRlp
+	_, err = pool.PeekBest(ctx, 10, &best, 0, math.MaxUint64, math.MaxUint64, math.MaxInt)
+	require.NoError(t, err)
+	require.Len(t, best.Txns, 1)
+
+	// gossip: not announced, not served to peers
+	announced, _, _ := pool.AppendAllAnnouncements(nil, nil, nil)
+	require.Empty(t, announced)
+	_, _, hashes, _ := pool.localTxnsToRebroadcast()
+	require.Zero(t, hashes.Len())
+	require.NoError(t, db.View(ctx, func(tx kv.Tx) error {
+		v, err := pool.GetRlp(tx, hash[:])
+		require.Nil(t, v)
+		return err
+	}))
+
+	// restore: neither journaled nor persisted
+	require.Empty(t, pool.locals.txns)
+	require.NoError(t, db.Update(ctx, func(tx kv.RwTx) error {
+		if err := pool.flushLocked(tx); err != nil {
+			return err
+		}
+		v, err := tx.GetOne(kv.PoolTransaction, hash[:])
+		require.Nil(t, v)
+		return err
+	}))
+	p2, err := New(ctx, make(chan Announcements, 100), db, coreDB, txpoolcfg.DefaultConfig, cache, chain.TestChainConfig, nil, nil, func() {}, nil, nil, log.New(), WithFeeCalculator(nil))
+	require.NoError(t, err)
+	p2.senders = pool.senders // senders are not persisted
+	require.NoError(t, db.View(ctx, func(tx kv.Tx) error {
+		return coreDB.ViewTemporal(ctx, func(coreTx kv.TemporalTx) error { return p2.fromDB(ctx, tx, coreTx) })
+	}))
+	require.NotContains(t, p2.byHash, string(hash[:]))
+}
+
+func TestKnownAccountJSON(t *testing.T) {
+	var conditions TxnConditions
+	in := `{"knownAccounts":{"0x0100000000000000000000000000000000000000":"0xaa00000000000000000000000000000000000000000000000000000000000000","0x0200000000000000000000000000000000000000":{"0x0000000000000000000000000000000000000000000000000000000000000001":"0x0000000000000000000000000000000000000000000000000000000000000002"}},"blockNumberMax":"0x10"}`
+	require.NoError(t, json.Unmarshal([]byte(in), &conditions))
+	require.Equal(t, common.Hash{0xaa}, *conditions.KnownAccounts[common.Address{1}].StorageRoot)
+	require.Equal(t, common.HexToHash("0x2"), conditions.KnownAccounts[common.Address{2}].StorageSlots[common.HexToHash("0x1")])
+	require.Equal(t, hexutil.Uint64(16), *conditions.BlockNumberMax)
+	require.Equal(t, 2, conditions.cost())
+	out, err := json.Marshal(conditions)
+	require.NoError(t, err)
+	require.JSONEq(t, in, string(out))
+}
@@ -0,0 +1,170 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/kv"
+)
+
+// DumpedTxn is a transaction of a pool dump. Dumps are written one
+// transaction per line, ordered by arrival.
+type DumpedTxn struct {
+	Hash    common.Hash    `json:"hash"`
+	Sender  common.Address `json:"sender"`
+	SubPool string         `json:"subPool"` // Pending, BaseFee or Queued
+	Local   bool           `json:"local"`
+	Arrival int64          `json:"arrival"` // Unix milliseconds of when the transaction was added to the pool
+	Rlp     hexutil.Bytes  `json:"rlp"`
+}
+
+// Dump returns all transactions of the pool, with their senders, sub-pools
+// and arrival times, ordered by arrival. Transactions loaded from the db on
+// start arrived at the time the pool was started. Private transactions are
+// not dumped.
+func (p *TxPool) Dump(ctx context.Context) ([]DumpedTxn, error) {
+	tx, err := p.poolDB.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var txns []DumpedTxn
+	p.lock.Lock()
+	p.all.ascendAll(func(mt *metaTxn) bool {
+		if _, ok := p.private[string(mt.TxnSlot.IDHash[:])]; ok {
+			return true
+		}
+		sender, found := p.senders.senderID2Addr[mt.TxnSlot.SenderID]
+		if !found {
+			return true
+		}
+		txns = append(txns, DumpedTxn{
+			Hash:    mt.TxnSlot.IDHash,
+			Sender:  sender,
+			SubPool: mt.currentSubPool.String(),
+			Local:   mt.subPool&IsLocal != 0,
+			Arrival: mt.arrival,
+			Rlp:     mt.TxnSlot.Rlp,
+		})
+		return true
+	})
+	p.lock.Unlock()
+
+	dumped := txns[:0]
+	for _, txn := range txns {
+		if txn.Rlp == nil {
+			rlp, err := dumpedTxnRlp(tx, txn.Hash)
+			if err != nil {
+				return nil, err
+			}
+			if rlp == nil {
+				// mined or evicted since it was listed
+				continue
+			}
+			txn.Rlp = rlp
+		} else {
+			txn.Rlp = common.Copy(txn.Rlp)
+		}
+		dumped = append(dumped, txn)
+	}
+	slices.SortStableFunc(dumped, func(a, b DumpedTxn) int { return cmp.Compare(a.Arrival, b.Arrival) })
+	return dumped, nil
+}
+
+func dumpedTxnRlp(tx kv.Tx, hash common.Hash) ([]byte, error) {
+	v, err := tx.GetOne(kv.PoolTransaction, hash[:])
+	if err != nil || v == nil {
+		return nil, err
+	}
+	return common.Copy(v[20:]), nil
+}
+
+// WriteDump writes the transactions of a pool dump, one JSON object per line.
+func WriteDump(w io.Writer, txns []DumpedTxn) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for i := range txns {
+		if err := enc.Encode(&txns[i]); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// ReadDump reads a pool dump written by WriteDump.
+func ReadDump(r io.Reader) ([]DumpedTxn, error) {
+	var txns []DumpedTxn
+	dec := json.NewDecoder(r)
+	for {
+		var txn DumpedTxn
+		if err := dec.Decode(&txn); err != nil {
+			if errors.Is(err, io.EOF) {
+				return txns, nil
+			}
+			return nil, err
+		}
+		txns = append(txns, txn)
+	}
+}
+
+// Replay feeds the transactions of a pool dump to send at their original
+// relative timing divided by speed: the first one right away, the others
+// when as much time has passed since the first one as had passed between
+// their arrivals. Transactions that arrived at the same millisecond are sent
+// together. Replay stops at the first error returned by send.
+func Replay(ctx context.Context, txns []DumpedTxn, speed float64, send func(ctx context.Context, batch []DumpedTxn) error) error {
+	if speed <= 0 {
+		return errors.New("replay speed must be positive")
+	}
+	if len(txns) == 0 {
+		return nil
+	}
+	txns = slices.Clone(txns)
+	slices.SortStableFunc(txns, func(a, b DumpedTxn) int { return cmp.Compare(a.Arrival, b.Arrival) })
+
+	start, first := time.Now(), txns[0].Arrival
+	for i := 0; i < len(txns); {
+		j := i + 1
+		for j < len(txns) && txns[j].Arrival == txns[i].Arrival {
+			j++
+		}
+		offset := time.Duration(float64(txns[i].Arrival-first) * float64(time.Millisecond) / speed)
+		if wait := time.Until(start.Add(offset)); wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if err := send(ctx, txns[i:j]); err != nil {
+			return err
+		}
+		i = j
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/chain"
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/datadir"
+	"github.com/erigontech/erigon-lib/gointerfaces"
+	remote "github.com/erigontech/erigon-lib/gointerfaces/remoteproto"
+	"github.com/erigontech/erigon-lib/kv/kvcache"
+	"github.com/erigontech/erigon-lib/kv/memdb"
+	"github.com/erigontech/erigon-lib/kv/temporal/temporaltest"
+	"github.com/erigontech/erigon-lib/log/v3"
+	accounts3 "github.com/erigontech/erigon-lib/types/accounts"
+	"github.com/erigontech/erigon/txnprovider/txpool/txpoolcfg"
+)
+
+func TestDump(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	coreDB := temporaltest.NewTestDB(t, datadir.New(t.TempDir()))
+	db := memdb.NewTestPoolDB(t)
+	pool, err := New(ctx, make(chan Announcements, 100), db, coreDB, txpoolcfg.DefaultConfig, kvcache.New(kvcache.DefaultCoherentConfig), chain.TestChainConfig, nil, nil, func() {}, nil, nil, log.New(), WithFeeCalculator(nil))
+	require.NoError(t, err)
+
+	var addr [20]byte
+	addr[0] = 1
+	acc := accounts3.Account{Nonce: 2, Balance: *uint256.NewInt(common.Ether)}
+	change := &remote.StateChangeBatch{
+		PendingBlockBaseFee: 200000,
+		BlockGasLimit:       1000000,
+		ChangeBatch: []*remote.StateChange{{
+			BlockHeight: 0,
+			BlockHash:   gointerfaces.ConvertHashToH256([32]byte{}),
+			Changes: []*remote.AccountChange{{
+				Action:  remote.Action_UPSERT,
+				Address: gointerfaces.ConvertAddressToH160(addr),
+				Data:    accounts3.SerialiseV3(&acc),
+			}},
+		}},
+	}
+	require.NoError(t, pool.OnNewBlock(ctx, change, TxnSlots{}, TxnSlots{}, TxnSlots{}))
+
+	for i, nonce := range []uint64{2, 4} { // the second one has a nonce gap
+		var slots TxnSlots
+		slot := &TxnSlot{Tip: *uint256.NewInt(300000), FeeCap: *uint256.NewInt(300000), Gas: 100000, Nonce: nonce, Rlp: []byte{byte(i + 1)}}
+		slot.IDHash[0] = byte(i + 1)
+		slots.Append(slot, addr[:], true)
+		reasons, err := pool.AddLocalTxns(ctx, slots)
+		require.NoError(t, err)
+		require.Equal(t, txpoolcfg.Success, reasons[0], reasons[0].String())
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	txns, err := pool.Dump(ctx)
+	require.NoError(t, err)
+	require.Len(t, txns, 2)
+	require.Equal(t, common.Hash{1}, txns[0].Hash)
+	require.Equal(t, common.Address(addr), txns[0].Sender)
+	require.Equal(t, PendingSubPool.String(), txns[0].SubPool)
+	require.Equal(t, QueuedSubPool.String(), txns[1].SubPool)
+	require.True(t, txns[0].Local)
+	require.Less(t, txns[0].Arrival, txns[1].Arrival)
+	require.Equal(t, []byte{2}, []byte(txns[1].Rlp))
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteDump(&buf, txns))
+	read, err := ReadDump(&buf)
+	require.NoError(t, err)
+	require.Equal(t, txns, read)
+}
+
+func TestReplay(t *testing.T) {
+	txns := []DumpedTxn{
+		{Hash: common.Hash{3}, Arrival: 1_000_400},
+		{Hash: common.Hash{1}, Arrival: 1_000_000},
+		{Hash: common.Hash{2}, Arrival: 1_000_000},
+	}
+
+	var batches [][]common.Hash
+	var sentAt []time.Duration
+	start := time.Now()
+	err := Replay(context.Background(), txns, 4, func(_ context.Context, batch []DumpedTxn) error {
+		var hashes []common.Hash
+		for _, txn := range batch {
+			hashes = append(hashes, txn.Hash)
+		}
+		batches = append(batches, hashes)
+		sentAt = append(sentAt, time.Since(start))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][]common.Hash{{{1}, {2}}, {{3}}}, batches)
+	// 400ms apart at 4x speed
+	require.GreaterOrEqual(t, sentAt[1], 100*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = Replay(ctx, txns, 1, func(context.Context, []DumpedTxn) error { return nil })
+	require.ErrorIs(t, err, context.Canceled)
+
+	require.Error(t, Replay(context.Background(), txns, 0, nil))
+}
@@ -0,0 +1,370 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/holiman/uint256"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/kvcache"
+	"github.com/erigontech/erigon/txnprovider/txpool/txpoolcfg"
+)
+
+// Explanation tells which sub-pool a transaction is in or why it isn't in the
+// pool, with the sender state and pool settings it is judged against.
+type Explanation struct {
+	Hash   common.Hash     `json:"hash"`
+	Sender *common.Address `json:"sender,omitempty"`
+	Nonce  *hexutil.Uint64 `json:"nonce,omitempty"`
+	Pooled bool            `json:"pooled"`
+	Local  bool            `json:"local"`
+	// SubPool is Pending, BaseFee or Queued: the sub-pool of a pooled
+	// transaction, or the one a raw transaction would be added to.
+	SubPool string `json:"subPool,omitempty"`
+	// DiscardReason is why the transaction was discarded, or why a raw
+	// transaction would be.
+	DiscardReason string `json:"discardReason,omitempty"`
+
+	Conditions  *SubPoolConditions      `json:"conditions,omitempty"`
+	Account     *AccountExplanation     `json:"account,omitempty"`
+	Fees        *FeeExplanation         `json:"fees,omitempty"`
+	Replacement *ReplacementExplanation `json:"replacement,omitempty"`
+}
+
+// SubPoolConditions are the SubPoolMarker bits sorting the transaction into
+// sub-pools: Pending needs all of them, BaseFee all but EnoughFeeCap.
+type SubPoolConditions struct {
+	NoNonceGaps   bool `json:"noNonceGaps"`
+	EnoughBalance bool `json:"enoughBalance"`
+	NotTooMuchGas bool `json:"notTooMuchGas"`
+	EnoughFeeCap  bool `json:"enoughFeeCap"`
+}
+
+// AccountExplanation is the state of the sender and its use of the per-sender
+// limits of the pool.
+type AccountExplanation struct {
+	StateNonce hexutil.Uint64 `json:"stateNonce"`
+	// NonceGap is the number of nonces between the state nonce and the
+	// transaction's without a pooled transaction.
+	NonceGap hexutil.Uint64 `json:"nonceGap"`
+	Balance  *hexutil.Big   `json:"balance"`
+	// RequiredBalance is the gas, blob gas and value the transaction and the
+	// sender's pooled transactions with lower nonces can spend together.
+	RequiredBalance  *hexutil.Big   `json:"requiredBalance"`
+	BalanceShortfall *hexutil.Big   `json:"balanceShortfall"`
+	Txns             hexutil.Uint64 `json:"txns"`
+	AccountSlots     hexutil.Uint64 `json:"accountSlots"`
+	Blobs            hexutil.Uint64 `json:"blobs"`
+	BlobSlots        hexutil.Uint64 `json:"blobSlots"`
+}
+
+// FeeExplanation compares the fees of the transaction with the ones of the
+// pending block.
+type FeeExplanation struct {
+	FeeCap *hexutil.Big `json:"feeCap"`
+	Tip    *hexutil.Big `json:"tip"`
+	// MinFeeCap is the lowest fee cap of the transaction and the sender's
+	// pooled transactions with lower nonces, it has to cover the base fee.
+	MinFeeCap      *hexutil.Big   `json:"minFeeCap"`
+	PendingBaseFee hexutil.Uint64 `json:"pendingBaseFee"`
+	BlobFeeCap     *hexutil.Big   `json:"blobFeeCap,omitempty"`
+	PendingBlobFee hexutil.Uint64 `json:"pendingBlobFee"`
+	Gas            hexutil.Uint64 `json:"gas"`
+	BlockGasLimit  hexutil.Uint64 `json:"blockGasLimit"`
+}
+
+// ReplacementExplanation describes the pooled transaction with the same sender
+// and nonce and the fees needed to replace it.
+type ReplacementExplanation struct {
+	Hash          common.Hash    `json:"hash"`
+	PriceBump     hexutil.Uint64 `json:"priceBump"` // percent
+	MinTip        *hexutil.Big   `json:"minTip"`
+	MinFeeCap     *hexutil.Big   `json:"minFeeCap"`
+	MinBlobFeeCap *hexutil.Big   `json:"minBlobFeeCap,omitempty"`
+}
+
+// Explain explains a pooled or recently discarded transaction. It returns nil
+// for transactions unknown to the pool.
+func (p *TxPool) Explain(ctx context.Context, hash common.Hash) (*Explanation, error) {
+	coreDb, cache := p.chainDB()
+	coreTx, err := coreDb.BeginTemporalRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer coreTx.Rollback()
+	cacheView, err := cache.View(ctx, coreTx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := p.poolDB.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	mt, err := p.pooledLocked(tx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if mt != nil {
+		return p.explainLocked(mt.TxnSlot, mt, cacheView)
+	}
+	if reason, ok := p.discardReasonsLRU.Get(string(hash[:])); ok {
+		return &Explanation{Hash: hash, DiscardReason: reason.String()}, nil
+	}
+	return nil, nil
+}
+
+// ExplainRaw explains a signed transaction as submitted by
+// eth_sendRawTransaction: where it is if it's pooled, otherwise why it would
+// be rejected or which sub-pool it would be added to.
+func (p *TxPool) ExplainRaw(ctx context.Context, rlpTxn []byte) (*Explanation, error) {
+	parseCtx := NewTxnParseContext(p.chainID).ChainIDRequired()
+	parseCtx.ValidateRLP(p.ValidateSerializedTxn)
+	txn, sender := &TxnSlot{}, make([]byte, 20)
+	if _, err := parseCtx.ParseTransaction(rlpTxn, 0, txn, sender, false /* hasEnvelope */, true /* wrappedWithBlobs */, nil); err != nil {
+		return nil, err
+	}
+
+	coreDb, cache := p.chainDB()
+	coreTx, err := coreDb.BeginTemporalRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer coreTx.Rollback()
+	cacheView, err := cache.View(ctx, coreTx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := p.poolDB.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	mt, err := p.pooledLocked(tx, txn.IDHash)
+	if err != nil {
+		return nil, err
+	}
+	if mt != nil {
+		return p.explainLocked(mt.TxnSlot, mt, cacheView)
+	}
+
+	// Like AddLocalTxns, register the sender before validating
+	txn.SenderID, _ = p.senders.getOrCreateID(common.BytesToAddress(sender), p.logger)
+	e, err := p.explainLocked(txn, nil, cacheView)
+	if err != nil {
+		return nil, err
+	}
+	e.Local = true
+	if reason := p.admissionLocked(txn, cacheView); reason != txpoolcfg.Success {
+		e.SubPool, e.DiscardReason = "", reason.String()
+	}
+	return e, nil
+}
+
+// pooledLocked returns the pooled transaction with the given hash, or nil.
+// Private transactions are not reported.
+func (p *TxPool) pooledLocked(tx kv.Tx, hash common.Hash) (*metaTxn, error) {
+	if _, ok := p.private[string(hash[:])]; ok {
+		return nil, nil
+	}
+	if mt, ok := p.byHash[string(hash[:])]; ok {
+		return mt, nil
+	}
+	v, err := tx.GetOne(kv.PoolTransaction, hash[:])
+	if err != nil || len(v) == 0 {
+		return nil, err
+	}
+	senderID, ok := p.senders.getID(common.BytesToAddress(v[:20]))
+	if !ok {
+		return nil, nil
+	}
+	parseCtx := NewTxnParseContext(p.chainID)
+	parseCtx.WithSender(false)
+	txn := &TxnSlot{}
+	if _, err := parseCtx.ParseTransaction(v[20:], 0, txn, nil, false /* hasEnvelope */, true /* wrappedWithBlobs */, nil); err != nil {
+		return nil, fmt.Errorf("parsing pooled txn %x: %w", hash, err)
+	}
+	// the db may still hold transactions discarded since the last flush
+	mt := p.all.get(senderID, txn.Nonce)
+	if mt == nil || mt.TxnSlot.IDHash != hash {
+		return nil, nil
+	}
+	return mt, nil
+}
+
+// admissionLocked runs the checks of AddLocalTxns against txn without adding
+// it. txn must not be pooled.
+func (p *TxPool) admissionLocked(txn *TxnSlot, cacheView kvcache.CacheView) txpoolcfg.DiscardReason {
+	if reason := p.validateTx(txn, true /* isLocal */, cacheView); reason != txpoolcfg.Success {
+		return reason
+	}
+	if found := p.all.get(txn.SenderID, txn.Nonce); found != nil {
+		if found.TxnSlot.Type == BlobTxnType && txn.Type != BlobTxnType {
+			return txpoolcfg.BlobTxReplace
+		}
+		tipThreshold, feecapThreshold, blobFeeThreshold := p.replacementThresholds(found, txn)
+		if blobFeeThreshold != nil && txn.BlobFeeCap.Lt(blobFeeThreshold) {
+			return txpoolcfg.ReplaceUnderpriced
+		}
+		if txn.Tip.Lt(tipThreshold) || txn.FeeCap.Lt(feecapThreshold) {
+			return txpoolcfg.NotReplaced
+		}
+	}
+	if txn.Type == BlobTxnType && txn.BlobFeeCap.LtUint64(p.pendingBlobFee.Load()) {
+		return txpoolcfg.FeeTooLow
+	}
+	senderAddr, _ := p.senders.getAddr(txn.SenderID)
+	if _, ok := p.auths[AuthAndNonce{senderAddr.String(), txn.Nonce}]; ok {
+		return txpoolcfg.ErrAuthorityReserved
+	}
+	for _, a := range txn.AuthAndNonces {
+		if a.authority == senderAddr.String() && a.nonce != txn.Nonce+1 {
+			return txpoolcfg.NonceTooLow
+		}
+		if _, ok := p.auths[AuthAndNonce{a.authority, a.nonce}]; ok {
+			return txpoolcfg.ErrAuthorityReserved
+		}
+	}
+	return txpoolcfg.Success
+}
+
+// explainLocked explains txn, which is pooled as mt or, when mt is nil, would
+// be added to the pool. The conditions are computed the way
+// onSenderStateChange and promote do, with txn in place of a pooled
+// transaction with the same nonce.
+func (p *TxPool) explainLocked(txn *TxnSlot, mt *metaTxn, cacheView kvcache.CacheView) (*Explanation, error) {
+	senderAddr, _ := p.senders.getAddr(txn.SenderID)
+	senderNonce, senderBalance, err := p.senders.info(cacheView, txn.SenderID)
+	if err != nil {
+		return nil, err
+	}
+	nonce := hexutil.Uint64(txn.Nonce)
+	e := &Explanation{Hash: txn.IDHash, Sender: &senderAddr, Nonce: &nonce}
+
+	expectedNonce, nonceGap := senderNonce, uint64(0)
+	required := uint256.NewInt(0)
+	minFeeCap := txn.FeeCap
+	p.all.ascend(txn.SenderID, func(prev *metaTxn) bool {
+		if prev.TxnSlot.Nonce >= txn.Nonce {
+			return false
+		}
+		if prev.TxnSlot.Nonce < senderNonce {
+			return true
+		}
+		nonceGap += prev.TxnSlot.Nonce - expectedNonce
+		expectedNonce = prev.TxnSlot.Nonce + 1
+		addSaturating(required, requiredBalance(prev.TxnSlot))
+		if prev.TxnSlot.FeeCap.Lt(&minFeeCap) {
+			minFeeCap = prev.TxnSlot.FeeCap
+		}
+		return true
+	})
+	if txn.Nonce >= expectedNonce {
+		nonceGap += txn.Nonce - expectedNonce
+	}
+	addSaturating(required, requiredBalance(txn))
+
+	pendingBaseFee, pendingBlobFee, blockGasLimit := p.pendingBaseFee.Load(), p.pendingBlobFee.Load(), p.blockGasLimit.Load()
+	inNonceRange := txn.Nonce >= senderNonce
+	e.Conditions = &SubPoolConditions{
+		NoNonceGaps:   inNonceRange && nonceGap == 0,
+		EnoughBalance: inNonceRange && !senderBalance.Lt(required),
+		NotTooMuchGas: txn.Gas < blockGasLimit,
+		EnoughFeeCap:  minFeeCap.CmpUint64(pendingBaseFee) >= 0 && (txn.Type != BlobTxnType || txn.BlobFeeCap.CmpUint64(pendingBlobFee) >= 0),
+	}
+
+	shortfall := uint256.NewInt(0)
+	if senderBalance.Lt(required) {
+		shortfall.Sub(required, &senderBalance)
+	}
+	e.Account = &AccountExplanation{
+		StateNonce:       hexutil.Uint64(senderNonce),
+		NonceGap:         hexutil.Uint64(nonceGap),
+		Balance:          (*hexutil.Big)(senderBalance.ToBig()),
+		RequiredBalance:  (*hexutil.Big)(required.ToBig()),
+		BalanceShortfall: (*hexutil.Big)(shortfall.ToBig()),
+		Txns:             hexutil.Uint64(p.all.count(txn.SenderID)),
+		AccountSlots:     hexutil.Uint64(p.cfg.AccountSlots),
+		Blobs:            hexutil.Uint64(p.all.blobCount(txn.SenderID)),
+		BlobSlots:        hexutil.Uint64(p.cfg.BlobSlots),
+	}
+
+	e.Fees = &FeeExplanation{
+		FeeCap:         (*hexutil.Big)(txn.FeeCap.ToBig()),
+		Tip:            (*hexutil.Big)(txn.Tip.ToBig()),
+		MinFeeCap:      (*hexutil.Big)(minFeeCap.ToBig()),
+		PendingBaseFee: hexutil.Uint64(pendingBaseFee),
+		PendingBlobFee: hexutil.Uint64(pendingBlobFee),
+		Gas:            hexutil.Uint64(txn.Gas),
+		BlockGasLimit:  hexutil.Uint64(blockGasLimit),
+	}
+	if txn.Type == BlobTxnType {
+		e.Fees.BlobFeeCap = (*hexutil.Big)(txn.BlobFeeCap.ToBig())
+	}
+
+	if mt != nil {
+		e.Pooled = true
+		e.Local = mt.subPool&IsLocal != 0
+		e.SubPool = mt.currentSubPool.String()
+		return e, nil
+	}
+
+	switch {
+	case !e.Conditions.NoNonceGaps || !e.Conditions.EnoughBalance || !e.Conditions.NotTooMuchGas:
+		e.SubPool = QueuedSubPool.String()
+	case !e.Conditions.EnoughFeeCap:
+		e.SubPool = BaseFeeSubPool.String()
+	default:
+		e.SubPool = PendingSubPool.String()
+	}
+	if found := p.all.get(txn.SenderID, txn.Nonce); found != nil {
+		tip, feeCap, blobFeeCap := p.replacementThresholds(found, txn)
+		priceBump := p.cfg.PriceBump
+		if txn.Type == BlobTxnType {
+			priceBump = p.cfg.BlobPriceBump
+		}
+		e.Replacement = &ReplacementExplanation{
+			Hash:      found.TxnSlot.IDHash,
+			PriceBump: hexutil.Uint64(priceBump),
+			MinTip:    (*hexutil.Big)(tip.ToBig()),
+			MinFeeCap: (*hexutil.Big)(feeCap.ToBig()),
+		}
+		if blobFeeCap != nil {
+			e.Replacement.MinBlobFeeCap = (*hexutil.Big)(blobFeeCap.ToBig())
+		}
+	}
+	return e, nil
+}
+
+func addSaturating(sum, x *uint256.Int) {
+	if _, overflow := sum.AddOverflow(sum, x); overflow {
+		sum.SetAllOne()
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/chain"
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/datadir"
+	"github.com/erigontech/erigon-lib/crypto"
+	"github.com/erigontech/erigon-lib/gointerfaces"
+	remote "github.com/erigontech/erigon-lib/gointerfaces/remoteproto"
+	"github.com/erigontech/erigon-lib/kv/kvcache"
+	"github.com/erigontech/erigon-lib/kv/memdb"
+	"github.com/erigontech/erigon-lib/kv/temporal/temporaltest"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/types"
+	accounts3 "github.com/erigontech/erigon-lib/types/accounts"
+	"github.com/erigontech/erigon/txnprovider/txpool/txpoolcfg"
+)
+
+func TestExplain(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	coreDB := temporaltest.NewTestDB(t, datadir.New(t.TempDir()))
+	db := memdb.NewTestPoolDB(t)
+	pool, err := New(ctx, make(chan Announcements, 100), db, coreDB, txpoolcfg.DefaultConfig, kvcache.New(kvcache.DefaultCoherentConfig), chain.TestChainConfig, nil, nil, func() {}, nil, nil, log.New(), WithFeeCalculator(nil))
+	require.NoError(t, err)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	acc := accounts3.Account{Nonce: 2, Balance: *uint256.NewInt(common.Ether)}
+	change := &remote.StateChangeBatch{
+		PendingBlockBaseFee: 200000,
+		BlockGasLimit:       1000000,
+		ChangeBatch: []*remote.StateChange{{
+			BlockHeight: 0,
+			BlockHash:   gointerfaces.ConvertHashToH256([32]byte{}),
+			Changes: []*remote.AccountChange{{
+				Action:  remote.Action_UPSERT,
+				Address: gointerfaces.ConvertAddressToH160(addr),
+				Data:    accounts3.SerialiseV3(&acc),
+			}},
+		}},
+	}
+	require.NoError(t, pool.OnNewBlock(ctx, change, TxnSlots{}, TxnSlots{}, TxnSlots{}))
+
+	signer := types.LatestSignerForChainID(chain.TestChainConfig.ChainID)
+	signed := func(nonce, feeCap uint64) []byte {
+		to := common.Address{1}
+		txn := types.MustSignNewTx(key, *signer, &types.DynamicFeeTransaction{
+			CommonTx: types.CommonTx{Nonce: nonce, GasLimit: 100000, To: &to, Value: uint256.NewInt(0)},
+			ChainID:  uint256.MustFromBig(chain.TestChainConfig.ChainID),
+			TipCap:   uint256.NewInt(feeCap),
+			FeeCap:   uint256.NewInt(feeCap),
+		})
+		var buf bytes.Buffer
+		require.NoError(t, txn.MarshalBinary(&buf))
+		return buf.Bytes()
+	}
+
+	var slots TxnSlots
+	slot := &TxnSlot{Tip: *uint256.NewInt(300000), FeeCap: *uint256.NewInt(300000), Gas: 100000, Nonce: 2}
+	slot.IDHash[0] = 1
+	slots.Append(slot, addr[:], true)
+	reasons, err := pool.AddLocalTxns(ctx, slots)
+	require.NoError(t, err)
+	require.Equal(t, txpoolcfg.Success, reasons[0], reasons[0].String())
+
+	e, err := pool.Explain(ctx, common.Hash{1})
+	require.NoError(t, err)
+	require.True(t, e.Pooled)
+	require.True(t, e.Local)
+	require.Equal(t, PendingSubPool.String(), e.SubPool)
+	require.Equal(t, SubPoolConditions{NoNonceGaps: true, EnoughBalance: true, NotTooMuchGas: true, EnoughFeeCap: true}, *e.Conditions)
+	require.Equal(t, uint64(1), uint64(e.Account.Txns))
+	require.Equal(t, txpoolcfg.DefaultConfig.AccountSlots, uint64(e.Account.AccountSlots))
+
+	e, err = pool.Explain(ctx, common.Hash{2})
+	require.NoError(t, err)
+	require.Nil(t, e)
+
+	// same nonce without the price bump
+	e, err = pool.ExplainRaw(ctx, signed(2, 300000))
+	require.NoError(t, err)
+	require.False(t, e.Pooled)
+	require.Equal(t, txpoolcfg.NotReplaced.String(), e.DiscardReason)
+	require.Equal(t, common.Hash{1}, e.Replacement.Hash)
+	require.Equal(t, uint64(330000), e.Replacement.MinFeeCap.ToInt().Uint64())
+
+	// nonce gap
+	e, err = pool.ExplainRaw(ctx, signed(4, 300000))
+	require.NoError(t, err)
+	require.Empty(t, e.DiscardReason)
+	require.Equal(t, QueuedSubPool.String(), e.SubPool)
+	require.Equal(t, uint64(1), uint64(e.Account.NonceGap))
+	require.False(t, e.Conditions.NoNonceGaps)
+
+	// fee cap below the pending base fee
+	e, err = pool.ExplainRaw(ctx, signed(3, 100000))
+	require.NoError(t, err)
+	require.Equal(t, BaseFeeSubPool.String(), e.SubPool)
+	require.Equal(t, uint64(200000), uint64(e.Fees.PendingBaseFee))
+
+	e, err = pool.ExplainRaw(ctx, signed(1, 300000))
+	require.NoError(t, err)
+	require.Equal(t, txpoolcfg.NonceTooLow.String(), e.DiscardReason)
+
+	// discarded by a replacement
+	slots = TxnSlots{}
+	slot = &TxnSlot{Tip: *uint256.NewInt(400000), FeeCap: *uint256.NewInt(400000), Gas: 100000, Nonce: 2}
+	slot.IDHash[0] = 3
+	slots.Append(slot, addr[:], true)
+	_, err = pool.AddLocalTxns(ctx, slots)
+	require.NoError(t, err)
+	e, err = pool.Explain(ctx, common.Hash{1})
+	require.NoError(t, err)
+	require.False(t, e.Pooled)
+	require.Equal(t, txpoolcfg.ReplacedByHigherTip.String(), e.DiscardReason)
+}
@@ -0,0 +1,259 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/kvcache"
+	"github.com/erigontech/erigon-lib/kv/order"
+	"github.com/erigontech/erigon/txnprovider/txpool/txpoolcfg"
+)
+
+// journaledTxn is a locally submitted transaction which is kept in the pool until
+// it is mined, replaced or expires, and which survives restarts.
+type journaledTxn struct {
+	sender    common.Address
+	nonce     uint64
+	submitted uint64 // unix seconds
+	rlp       []byte
+}
+
+type senderNonce struct {
+	sender common.Address
+	nonce  uint64
+}
+
+// localsJournal - durable set of local transactions and of the senders which submitted them.
+// Transactions of the local senders are exempt from the sub-pool limits, including the ones
+// which arrive from the network.
+type localsJournal struct {
+	limit   int                      // max number of journaled txns
+	txns    map[string]*journaledTxn // txn_hash => journaled txn
+	byNonce map[senderNonce]string   // (sender, nonce) => txn_hash
+	senders map[common.Address]int   // sender => count of its journaled txns
+	dirty   map[string]bool          // txn_hash => added (true) or deleted (false) since last flush
+}
+
+func newLocalsJournal(limit int) *localsJournal {
+	return &localsJournal{
+		limit:   limit,
+		txns:    map[string]*journaledTxn{},
+		byNonce: map[senderNonce]string{},
+		senders: map[common.Address]int{},
+		dirty:   map[string]bool{},
+	}
+}
+
+// add - journals txn, unless journal is full. Replacement of journaled txn (same sender and nonce) is always journaled.
+func (j *localsJournal) add(hash string, txn *journaledTxn) bool {
+	if _, ok := j.txns[hash]; ok {
+		return true
+	}
+	// only the latest of the transactions with same sender and nonce needs to be kept
+	if otherHash, ok := j.byNonce[senderNonce{txn.sender, txn.nonce}]; ok {
+		j.remove(otherHash)
+	} else if len(j.txns) >= j.limit {
+		return false
+	}
+	j.txns[hash] = txn
+	j.byNonce[senderNonce{txn.sender, txn.nonce}] = hash
+	j.senders[txn.sender]++
+	j.dirty[hash] = true
+	return true
+}
+
+func (j *localsJournal) remove(hash string) {
+	txn, ok := j.txns[hash]
+	if !ok {
+		return
+	}
+	delete(j.txns, hash)
+	delete(j.byNonce, senderNonce{txn.sender, txn.nonce})
+	if j.senders[txn.sender]--; j.senders[txn.sender] <= 0 {
+		delete(j.senders, txn.sender)
+	}
+	j.dirty[hash] = false
+}
+
+func (j *localsJournal) isLocalSender(addr common.Address) bool {
+	_, ok := j.senders[addr]
+	return ok
+}
+
+func (j *localsJournal) flush(tx kv.RwTx) error {
+	for hash, added := range j.dirty {
+		if !added {
+			if err := tx.Delete(kv.PoolLocalJournal, []byte(hash)); err != nil {
+				return err
+			}
+			continue
+		}
+		txn := j.txns[hash]
+		v := make([]byte, 8+8+20+len(txn.rlp))
+		binary.BigEndian.PutUint64(v, txn.submitted)
+		binary.BigEndian.PutUint64(v[8:], txn.nonce)
+		copy(v[16:], txn.sender[:])
+		copy(v[36:], txn.rlp)
+		if err := tx.Put(kv.PoolLocalJournal, []byte(hash), v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (j *localsJournal) load(tx kv.Tx) error {
+	it, err := tx.Range(kv.PoolLocalJournal, nil, nil, order.Asc, kv.Unlim)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.HasNext() {
+		k, v, err := it.Next()
+		if err != nil {
+			return err
+		}
+		if len(v) < 36 {
+			return fmt.Errorf("malformed local journal entry %x", k)
+		}
+		txn := &journaledTxn{
+			submitted: binary.BigEndian.Uint64(v),
+			nonce:     binary.BigEndian.Uint64(v[8:]),
+			sender:    common.Address(v[16:36]),
+			rlp:       common.Copy(v[36:]),
+		}
+		j.txns[string(k)] = txn
+		j.byNonce[senderNonce{txn.sender, txn.nonce}] = string(k)
+		j.senders[txn.sender]++
+	}
+	return nil
+}
+
+// journalLocalsLocked - remembers the local transactions which made it to the pool
+func (p *TxPool) journalLocalsLocked(txns TxnSlots) {
+	now := uint64(time.Now().Unix())
+	for i, txn := range txns.Txns {
+		hashStr := string(txn.IDHash[:])
+		if _, ok := p.byHash[hashStr]; !ok || len(txn.Rlp) == 0 {
+			continue
+		}
+		if !p.locals.add(hashStr, &journaledTxn{
+			sender:    txns.Senders.AddressAt(i),
+			nonce:     txn.Nonce,
+			submitted: now,
+			rlp:       common.Copy(txn.Rlp),
+		}) {
+			p.logger.Debug("[txpool] locals journal is full", "hash", fmt.Sprintf("%x", hashStr), "limit", p.locals.limit)
+		}
+	}
+}
+
+// expireLocalsLocked - drops the journaled transactions older than the configured lifetime.
+// Such transactions stay in the pool but lose their local privileges.
+func (p *TxPool) expireLocalsLocked(now time.Time) {
+	if p.cfg.LocalsLifetime == 0 {
+		return
+	}
+	deadline := uint64(now.Add(-p.cfg.LocalsLifetime).Unix())
+	for hashStr, txn := range p.locals.txns {
+		if txn.submitted > deadline {
+			continue
+		}
+		p.locals.remove(hashStr)
+		p.isLocalLRU.Remove(hashStr)
+		mt, ok := p.byHash[hashStr]
+		if !ok || mt.subPool&IsLocal == 0 {
+			continue
+		}
+		mt.subPool &^= IsLocal
+		switch mt.currentSubPool {
+		case PendingSubPool:
+			p.pending.Updated(mt)
+		case BaseFeeSubPool:
+			p.baseFee.Updated(mt)
+		case QueuedSubPool:
+			p.queued.Updated(mt)
+		}
+	}
+}
+
+// loadLocals - loads the journal and restores isLocal flag of the journaled transactions
+func (p *TxPool) loadLocals(tx kv.Tx) error {
+	if err := p.locals.load(tx); err != nil {
+		return err
+	}
+	p.expireLocalsLocked(time.Now())
+	for hashStr := range p.locals.txns {
+		p.isLocalLRU.Add(hashStr, struct{}{})
+	}
+	return nil
+}
+
+// restoreLocals - returns the journaled transactions which are missing in the persisted pool,
+// e.g. because they were not flushed before shutdown
+func (p *TxPool) restoreLocals(tx kv.Tx, parseCtx *TxnParseContext, cacheView kvcache.CacheView) (TxnSlots, error) {
+	var restored TxnSlots
+	for hashStr, journaled := range p.locals.txns {
+		has, err := tx.Has(kv.PoolTransaction, []byte(hashStr))
+		if err != nil {
+			return restored, err
+		}
+		if has {
+			continue
+		}
+
+		txn := &TxnSlot{}
+		if _, err := parseCtx.ParseTransaction(journaled.rlp, 0, txn, nil, false /* hasEnvelope */, true /*wrappedWithBlobs*/, nil); err != nil {
+			p.logger.Warn("[txpool] restoreLocals: parseTransaction", "err", err)
+			p.locals.remove(hashStr)
+			continue
+		}
+		txn.SenderID, txn.Traced = p.senders.getOrCreateID(journaled.sender, p.logger)
+		if reason := p.validateTx(txn, true, cacheView); reason != txpoolcfg.NotSet && reason != txpoolcfg.Success {
+			p.logger.Debug("[txpool] restoreLocals: dropping journaled txn", "hash", fmt.Sprintf("%x", hashStr), "reason", reason)
+			p.locals.remove(hashStr)
+			continue
+		}
+		restored.Append(txn, journaled.sender[:], true)
+	}
+	return restored, nil
+}
+
+// localTxnsToRebroadcast - collects the journaled transactions which are still in the pool.
+// Blob transactions are returned for announcement only.
+func (p *TxPool) localTxnsToRebroadcast() (types []byte, sizes []uint32, hashes Hashes, rlps [][]byte) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for hashStr, journaled := range p.locals.txns {
+		mt, ok := p.byHash[hashStr]
+		if !ok {
+			continue
+		}
+		types = append(types, mt.TxnSlot.Type)
+		sizes = append(sizes, mt.TxnSlot.Size)
+		hashes = append(hashes, hashStr...)
+		// "Nodes MUST NOT automatically broadcast blob transactions to their peers" - EIP-4844
+		if mt.TxnSlot.Type != BlobTxnType {
+			rlps = append(rlps, journaled.rlp)
+		}
+	}
+	return types, sizes, hashes, rlps
+}
@@ -0,0 +1,191 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/chain"
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/datadir"
+	"github.com/erigontech/erigon-lib/crypto"
+	"github.com/erigontech/erigon-lib/gointerfaces"
+	remote "github.com/erigontech/erigon-lib/gointerfaces/remoteproto"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/kvcache"
+	"github.com/erigontech/erigon-lib/kv/memdb"
+	"github.com/erigontech/erigon-lib/kv/temporal/temporaltest"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/types"
+	accounts3 "github.com/erigontech/erigon-lib/types/accounts"
+	"github.com/erigontech/erigon/txnprovider/txpool/txpoolcfg"
+)
+
+type localsTestPool struct {
+	*TxPool
+	ctx      context.Context
+	db       kv.RwDB
+	coreDB   kv.TemporalRwDB
+	cache    kvcache.Cache
+	addr     common.Address
+	addLocal func(nonce, feeCap uint64) common.Hash // adds local txn of addr
+}
+
+// newLocalsTestPool - pool with one funded sender (nonce 2)
+func newLocalsTestPool(t *testing.T, cfg txpoolcfg.Config) *localsTestPool {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	coreDB := temporaltest.NewTestDB(t, datadir.New(t.TempDir()))
+	db := memdb.NewTestPoolDB(t)
+	cache := kvcache.New(kvcache.DefaultCoherentConfig)
+	pool, err := New(ctx, make(chan Announcements, 100), db, coreDB, cfg, cache, chain.TestChainConfig, nil, nil, func() {}, nil, nil, log.New(), WithFeeCalculator(nil))
+	require.NoError(t, err)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	acc := accounts3.Account{Nonce: 2, Balance: *uint256.NewInt(common.Ether)}
+	change := &remote.StateChangeBatch{
+		PendingBlockBaseFee: 200000,
+		BlockGasLimit:       1000000,
+		ChangeBatch: []*remote.StateChange{{
+			BlockHeight: 0,
+			BlockHash:   gointerfaces.ConvertHashToH256([32]byte{}),
+			Changes: []*remote.AccountChange{{
+				Action:  remote.Action_UPSERT,
+				Address: gointerfaces.ConvertAddressToH160(addr),
+				Data:    accounts3.SerialiseV3(&acc),
+			}},
+		}},
+	}
+	require.NoError(t, pool.OnNewBlock(ctx, change, TxnSlots{}, TxnSlots{}, TxnSlots{}))
+
+	signer := types.LatestSignerForChainID(chain.TestChainConfig.ChainID)
+	parseCtx := NewTxnParseContext(*uint256.MustFromBig(chain.TestChainConfig.ChainID))
+	addLocal := func(nonce, feeCap uint64) common.Hash {
+		to := common.Address{1}
+		txn := types.MustSignNewTx(key, *signer, &types.DynamicFeeTransaction{
+			CommonTx: types.CommonTx{Nonce: nonce, GasLimit: 100000, To: &to, Value: uint256.NewInt(0)},
+			ChainID:  uint256.MustFromBig(chain.TestChainConfig.ChainID),
+			TipCap:   uint256.NewInt(feeCap),
+			FeeCap:   uint256.NewInt(feeCap),
+		})
+		var buf bytes.Buffer
+		require.NoError(t, txn.MarshalBinary(&buf))
+		slot, sender := &TxnSlot{}, make([]byte, 20)
+		_, err := parseCtx.ParseTransaction(buf.Bytes(), 0, slot, sender, false /* hasEnvelope */, true /* wrappedWithBlobs */, nil)
+		require.NoError(t, err)
+		var slots TxnSlots
+		slots.Append(slot, sender, true)
+		reasons, err := pool.AddLocalTxns(ctx, slots)
+		require.NoError(t, err)
+		require.Equal(t, txpoolcfg.Success, reasons[0], reasons[0].String())
+		return txn.Hash()
+	}
+	return &localsTestPool{TxPool: pool, ctx: ctx, db: db, coreDB: coreDB, cache: cache, addr: addr, addLocal: addLocal}
+}
+
+func TestLocalsJournal(t *testing.T) {
+	cfg := txpoolcfg.DefaultConfig
+	cfg.QueuedSubPoolLimit = 1
+	tp := newLocalsTestPool(t, cfg)
+	pool, ctx, db, coreDB, cache, addr, addLocal := tp.TxPool, tp.ctx, tp.db, tp.coreDB, tp.cache, tp.addr, tp.addLocal
+
+	pending := addLocal(2, 300000)
+	queued1 := addLocal(4, 300000)
+	queued2 := addLocal(5, 300000)
+	// local transactions are exempt from the sub-pool limits
+	require.Equal(t, 2, pool.queued.Len())
+	require.Len(t, pool.locals.txns, 3)
+	require.True(t, pool.locals.isLocalSender(addr))
+
+	announced, _, hashes, rlps := pool.localTxnsToRebroadcast()
+	require.Len(t, announced, 3)
+	require.Equal(t, 3, hashes.Len())
+	require.Len(t, rlps, 3)
+
+	// replacement takes the place of the journaled txn with same nonce
+	replacement := addLocal(5, 400000)
+	require.Len(t, pool.locals.txns, 3)
+	require.NotContains(t, pool.locals.txns, string(queued2[:]))
+
+	require.NoError(t, db.Update(ctx, func(tx kv.RwTx) error {
+		if err := pool.flushLocked(tx); err != nil {
+			return err
+		}
+		// journaled txns are restored even if the pool lost them
+		return tx.Delete(kv.PoolTransaction, queued1[:])
+	}))
+
+	p2, err := New(ctx, make(chan Announcements, 100), db, coreDB, cfg, cache, chain.TestChainConfig, nil, nil, func() {}, nil, nil, log.New(), WithFeeCalculator(nil))
+	require.NoError(t, err)
+	p2.senders = pool.senders // senders are not persisted
+	p2.blockGasLimit.Store(pool.blockGasLimit.Load())
+	require.NoError(t, db.View(ctx, func(tx kv.Tx) error {
+		return coreDB.ViewTemporal(ctx, func(coreTx kv.TemporalTx) error { return p2.fromDB(ctx, tx, coreTx) })
+	}))
+	require.Len(t, p2.locals.txns, 3)
+	for _, hash := range []common.Hash{pending, queued1, replacement} {
+		mt, ok := p2.byHash[string(hash[:])]
+		require.True(t, ok, hash)
+		require.NotZero(t, mt.subPool&IsLocal)
+		require.True(t, p2.IsLocal(hash[:]))
+	}
+	require.Equal(t, 2, p2.queued.Len())
+
+	p2.expireLocalsLocked(time.Now().Add(cfg.LocalsLifetime + time.Minute))
+	require.Empty(t, p2.locals.txns)
+	require.False(t, p2.locals.isLocalSender(addr))
+	for _, mt := range p2.byHash {
+		require.Zero(t, mt.subPool&IsLocal)
+	}
+}
+
+func TestLocalsLimit(t *testing.T) {
+	j := newLocalsJournal(2)
+	addr := common.Address{1}
+	require.True(t, j.add("a", &journaledTxn{sender: addr, nonce: 1}))
+	require.True(t, j.add("b", &journaledTxn{sender: addr, nonce: 2}))
+	require.False(t, j.add("c", &journaledTxn{sender: addr, nonce: 3}))
+	// replacement is journaled even if journal is full
+	require.True(t, j.add("b2", &journaledTxn{sender: addr, nonce: 2}))
+	require.Len(t, j.txns, 2)
+	require.NotContains(t, j.txns, "b")
+	require.Equal(t, map[senderNonce]string{{addr, 1}: "a", {addr, 2}: "b2"}, j.byNonce)
+	require.Equal(t, 2, j.senders[addr])
+	j.remove("a")
+	require.Equal(t, map[senderNonce]string{{addr, 2}: "b2"}, j.byNonce)
+	require.True(t, j.add("c", &journaledTxn{sender: addr, nonce: 3}))
+
+	// only LocalSlots local txns are kept over the sub-pool limits
+	cfg := txpoolcfg.DefaultConfig
+	cfg.QueuedSubPoolLimit = 1
+	cfg.LocalSlots = 1
+	pool := newLocalsTestPool(t, cfg)
+	pending := pool.addLocal(2, 300000)
+	pool.addLocal(4, 300000)
+	pool.addLocal(5, 300000)
+	require.Equal(t, 1, pool.queued.Len())
+	require.Len(t, pool.locals.txns, 1)
+	require.Contains(t, pool.locals.txns, string(pending[:]))
+}
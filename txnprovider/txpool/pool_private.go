@@ -0,0 +1,64 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"context"
+
+	"github.com/erigontech/erigon/txnprovider/txpool/txpoolcfg"
+)
+
+// AddPrivateTxns - adds local transactions which are only used for our own block building:
+// they are never announced or sent to peers, not returned by txpool_content and pending
+// subscriptions, and not persisted. They are dropped after cfg.PrivateTxnLifetime blocks.
+// Transactions already known to the pool stay public.
+func (p *TxPool) AddPrivateTxns(ctx context.Context, newTxns TxnSlots) ([]txpoolcfg.DiscardReason, error) {
+	return p.addLocalTxns(ctx, newTxns, true, nil)
+}
+
+// keptLocallyLocked - whether the txn must never leave the node: private txns, and conditional txns - their
+// conditions are known to this node only, peers and the pool restored after restart would see them unconditional
+func (p *TxPool) keptLocallyLocked(hashStr string) bool {
+	if _, ok := p.private[hashStr]; ok {
+		return true
+	}
+	mt, ok := p.byHash[hashStr]
+	return ok && mt.conditions != nil
+}
+
+func (p *TxPool) markPrivateLocked(hashes []string) {
+	lastBlock := p.lastSeenBlock.Load() + p.cfg.PrivateTxnLifetime
+	for _, hashStr := range hashes {
+		if _, ok := p.byHash[hashStr]; ok {
+			p.private[hashStr] = lastBlock
+		}
+	}
+}
+
+func (p *TxPool) expirePrivateLocked(blockNum uint64) {
+	for hashStr, lastBlock := range p.private {
+		if blockNum <= lastBlock {
+			continue
+		}
+		mt, ok := p.byHash[hashStr]
+		if !ok {
+			delete(p.private, hashStr)
+			continue
+		}
+		p.dropLocked(mt, txpoolcfg.Expired)
+	}
+}
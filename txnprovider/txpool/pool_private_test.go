@@ -0,0 +1,133 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/chain"
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/datadir"
+	"github.com/erigontech/erigon-lib/crypto"
+	"github.com/erigontech/erigon-lib/gointerfaces"
+	remote "github.com/erigontech/erigon-lib/gointerfaces/remoteproto"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/kvcache"
+	"github.com/erigontech/erigon-lib/kv/memdb"
+	"github.com/erigontech/erigon-lib/kv/temporal/temporaltest"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/types"
+	accounts3 "github.com/erigontech/erigon-lib/types/accounts"
+	"github.com/erigontech/erigon/txnprovider/txpool/txpoolcfg"
+)
+
+func TestPrivateTxns(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	coreDB := temporaltest.NewTestDB(t, datadir.New(t.TempDir()))
+	db := memdb.NewTestPoolDB(t)
+	pool, err := New(ctx, make(chan Announcements, 100), db, coreDB, txpoolcfg.DefaultConfig, kvcache.New(kvcache.DefaultCoherentConfig), chain.TestChainConfig, nil, nil, func() {}, nil, nil, log.New(), WithFeeCalculator(nil))
+	require.NoError(t, err)
+	pool.started.Store(true)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	acc := accounts3.Account{Nonce: 2, Balance: *uint256.NewInt(common.Ether)}
+	newBlock := func(blockNum uint64) {
+		change := &remote.StateChangeBatch{
+			StateVersionId:      blockNum,
+			PendingBlockBaseFee: 200000,
+			BlockGasLimit:       1000000,
+			ChangeBatch: []*remote.StateChange{{
+				BlockHeight: blockNum,
+				BlockHash:   gointerfaces.ConvertHashToH256([32]byte{byte(blockNum)}),
+				Changes: []*remote.AccountChange{{
+					Action:  remote.Action_UPSERT,
+					Address: gointerfaces.ConvertAddressToH160(addr),
+					Data:    accounts3.SerialiseV3(&acc),
+				}},
+			}},
+		}
+		require.NoError(t, pool.OnNewBlock(ctx, change, TxnSlots{}, TxnSlots{}, TxnSlots{}))
+	}
+	newBlock(0)
+
+	signer := types.LatestSignerForChainID(chain.TestChainConfig.ChainID)
+	to := common.Address{1}
+	txn := types.MustSignNewTx(key, *signer, &types.DynamicFeeTransaction{
+		CommonTx: types.CommonTx{Nonce: 2, GasLimit: 100000, To: &to, Value: uint256.NewInt(0)},
+		ChainID:  uint256.MustFromBig(chain.TestChainConfig.ChainID),
+		TipCap:   uint256.NewInt(300000),
+		FeeCap:   uint256.NewInt(300000),
+	})
+	var buf bytes.Buffer
+	require.NoError(t, txn.MarshalBinary(&buf))
+
+	hash, err := NewEthAPI(pool, nil).SendPrivateRawTransaction(ctx, buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, txn.Hash(), hash)
+
+	// available for our own blocks
+	var best TxnsRlp
+	_, err = pool.PeekBest(ctx, 10, &best, 0, math.MaxUint64, math.MaxUint64, math.MaxInt)
+	require.NoError(t, err)
+	require.Len(t, best.Txns, 1)
+
+	// but never leaves the node
+	require.NoError(t, db.Update(ctx, func(tx kv.RwTx) error {
+		if err := pool.flushLocked(tx); err != nil {
+			return err
+		}
+		v, err := tx.GetOne(kv.PoolTransaction, hash[:])
+		require.Nil(t, v)
+		return err
+	}))
+	require.NoError(t, db.View(ctx, func(tx kv.Tx) error {
+		v, err := pool.GetRlp(tx, hash[:])
+		require.Nil(t, v)
+		pool.deprecatedForEach(ctx, func(rlp []byte, sender common.Address, _ SubPoolType) {
+			require.Failf(t, "private txn listed", "%x", rlp)
+		}, tx)
+		return err
+	}))
+	announced, _, _ := pool.AppendAllAnnouncements(nil, nil, nil)
+	require.Empty(t, announced)
+
+	// and is not visible in txpool_dump and txpool_explain
+	dumped, err := pool.Dump(ctx)
+	require.NoError(t, err)
+	require.Empty(t, dumped)
+	explained, err := pool.Explain(ctx, hash)
+	require.NoError(t, err)
+	require.Nil(t, explained)
+
+	newBlock(txpoolcfg.DefaultConfig.PrivateTxnLifetime)
+	require.Contains(t, pool.byHash, string(hash[:]))
+	newBlock(txpoolcfg.DefaultConfig.PrivateTxnLifetime + 1)
+	require.NotContains(t, pool.byHash, string(hash[:]))
+	require.Empty(t, pool.private)
+	reason, ok := pool.discardReasonsLRU.Get(string(hash[:]))
+	require.True(t, ok)
+	require.Equal(t, txpoolcfg.Expired, reason)
+}
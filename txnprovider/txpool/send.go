@@ -22,6 +22,7 @@ import (
 	"math/rand"
 	"slices"
 	"sync"
+	"time"
 
 	"google.golang.org/grpc"
 
@@ -54,6 +55,9 @@ const (
 	// This is the target size for the packs of transactions or announcements. A
 	// pack can get larger than this if a single transactions exceeds this size.
 	p2pTxPacketLimit = 100 * 1024
+
+	// Local transactions are broadcast to this many random peers and announced to twice as many
+	localTxnsBroadcastMaxPeers uint64 = 10
 )
 
 func (f *Send) notifyTests() {
@@ -107,6 +111,29 @@ func (f *Send) BroadcastPooledTxns(rlps [][]byte, maxPeers uint64) (txnSentTo []
 	return
 }
 
+// RebroadcastLocalTxns - periodically broadcasts and announces the local transactions returned by
+// localTxns, until they are mined or replaced. Blob transactions come without rlp and are only announced.
+func (f *Send) RebroadcastLocalTxns(ctx context.Context, every time.Duration, localTxns func() (types []byte, sizes []uint32, hashes Hashes, rlps [][]byte)) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		types, sizes, hashes, rlps := localTxns()
+		if len(types) == 0 {
+			continue
+		}
+		if len(rlps) > 0 {
+			f.BroadcastPooledTxns(rlps, localTxnsBroadcastMaxPeers)
+		}
+		f.AnnouncePooledTxns(types, sizes, hashes, localTxnsBroadcastMaxPeers*2)
+		f.logger.Debug("[txpool.send] Rebroadcast local txns", "count", len(types))
+	}
+}
+
 func (f *Send) AnnouncePooledTxns(types []byte, sizes []uint32, hashes Hashes, maxPeers uint64) (hashSentTo []int) {
 	defer f.notifyTests()
 	hashSentTo = make([]int, len(types))
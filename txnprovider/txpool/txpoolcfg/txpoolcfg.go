@@ -48,6 +48,13 @@ type Config struct {
 	CommitEvery            time.Duration
 	LogEvery               time.Duration
 
+	// local transactions
+	LocalsLifetime   time.Duration // How long local transactions are journaled and kept in the pool
+	LocalSlots       int           // Max number of journaled local transactions, and of local transactions kept over the sub-pool limits
+	RebroadcastEvery time.Duration // How often journaled local transactions are rebroadcast, 0 disables it
+
+	PrivateTxnLifetime uint64 // Number of blocks a private transaction is kept in the pool for
+
 	//txpool db
 	MdbxPageSize    datasize.ByteSize
 	MdbxDBSizeLimit datasize.ByteSize
@@ -66,6 +73,12 @@ var DefaultConfig = Config{
 	CommitEvery:            15 * time.Second,
 	LogEvery:               30 * time.Second,
 
+	LocalsLifetime:   3 * time.Hour,
+	LocalSlots:       1_000,
+	RebroadcastEvery: time.Minute,
+
+	PrivateTxnLifetime: 25,
+
 	PendingSubPoolLimit: 10_000,
 	BaseFeeSubPoolLimit: 30_000,
 	QueuedSubPoolLimit:  30_000,
@@ -121,6 +134,8 @@ const (
 	ErrAuthorityReserved DiscardReason = 34 // EIP-7702 transaction with authority already reserved
 	InvalidAA            DiscardReason = 35 // Invalid RIP-7560 transaction
 	ErrGetCode           DiscardReason = 36 // Error getting code during AA validation
+	Expired              DiscardReason = 37 // Private transaction was not included within its lifetime
+	ConditionsNotMet     DiscardReason = 38 // Preconditions of a conditional transaction can no longer hold
 )
 
 func (r DiscardReason) String() string {
@@ -199,6 +214,10 @@ func (r DiscardReason) String() string {
 		return "RIP-7560 transaction failed validation"
 	case ErrGetCode:
 		return "error getting account code during RIP-7560 validation"
+	case Expired:
+		return "private transaction expired"
+	case ConditionsNotMet:
+		return "transaction conditions not met"
 	default:
 		panic(fmt.Sprintf("discard reason: %d", r))
 	}